@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/firecrown-media/stax/pkg/config"
+	"github.com/firecrown-media/stax/pkg/credentials"
+	"github.com/firecrown-media/stax/pkg/errors"
+	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/firecrown-media/stax/pkg/wpengine"
+	"github.com/spf13/cobra"
+)
+
+// backupCmd represents the backup command group
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "✓ Remote WPEngine backups",
+	Long: `Create and list WPEngine backups (checkpoints) of the remote install.
+
+Pushes to production require a completed backup checkpoint first. The policy
+is configured with wpengine.backup.checkpoint in .stax.yml:
+  production  require a checkpoint before pushing to production (default)
+  always      require a checkpoint before every push
+  never       never require a checkpoint`,
+}
+
+var (
+	backupInstall     string
+	backupDescription string
+	backupWait        bool
+	backupTimeout     time.Duration
+	backupJSON        bool
+)
+
+// backupCreateCmd represents the backup:create command
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a WPEngine backup",
+	Long: `Request a backup of the WPEngine install through the API.
+
+With --wait the command polls the backup status until it completes, fails or
+the timeout expires.`,
+	Example: `  # Request a backup and return immediately
+  stax backup create
+
+  # Create a checkpoint and wait for it to complete
+  stax backup create --wait --description "before plugin update"`,
+	RunE: runBackupCreate,
+}
+
+// backupListCmd represents the backup:list command
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List WPEngine backups",
+	Long:  `List the backups of the WPEngine install.`,
+	Example: `  stax backup list
+  stax backup list --install mysite --json`,
+	RunE: runBackupList,
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupListCmd)
+
+	backupCmd.PersistentFlags().StringVar(&backupInstall, "install", "", "WPEngine install name (default: from config)")
+
+	backupCreateCmd.Flags().StringVar(&backupDescription, "description", "", "backup description (default: created by stax)")
+	backupCreateCmd.Flags().BoolVar(&backupWait, "wait", false, "wait for the backup to complete")
+	backupCreateCmd.Flags().DurationVar(&backupTimeout, "timeout", wpengine.DefaultBackupTimeout, "how long to wait for the backup with --wait")
+
+	backupListCmd.Flags().BoolVar(&backupJSON, "json", false, "output as JSON")
+}
+
+// runBackupCreate creates a backup, optionally waiting for it
+func runBackupCreate(cmd *cobra.Command, args []string) error {
+	install, cfg, err := resolveBackupInstall()
+	if err != nil {
+		return err
+	}
+
+	client, err := newWPEngineAPIClient()
+	if err != nil {
+		return err
+	}
+
+	description := backupDescription
+	if description == "" {
+		description = fmt.Sprintf("Created by stax at %s", time.Now().Format(time.RFC3339))
+	}
+
+	var emails []string
+	if cfg != nil {
+		emails = cfg.WPEngine.Backup.NotificationEmails
+	}
+
+	ctx := cmd.Context()
+	installID, err := client.ResolveInstallIDContext(ctx, install)
+	if err != nil {
+		return err
+	}
+
+	backupID, err := client.CreateBackupContext(ctx, installID, description, emails...)
+	if err != nil {
+		return errors.NewWPEngineAPIError(fmt.Sprintf("failed to create backup of %s", install), err)
+	}
+	ui.Success("Backup requested: %s", backupID)
+
+	if !backupWait {
+		ui.Info("Run 'stax backup list' to check its status")
+		return nil
+	}
+
+	if _, err := waitForBackup(ctx, client, installID, backupID, backupTimeout); err != nil {
+		return err
+	}
+	ui.Success("Backup %s completed", backupID)
+	return nil
+}
+
+// runBackupList lists the backups of the install
+func runBackupList(cmd *cobra.Command, args []string) error {
+	install, _, err := resolveBackupInstall()
+	if err != nil {
+		return err
+	}
+
+	client, err := newWPEngineAPIClient()
+	if err != nil {
+		return err
+	}
+
+	spinner := ui.NewSpinner("Fetching backups...")
+	spinner.Start()
+
+	installID, err := client.ResolveInstallIDContext(cmd.Context(), install)
+	var backups []wpengine.Backup
+	if err == nil {
+		backups, err = client.ListBackupsContext(cmd.Context(), installID)
+	}
+	spinner.Stop()
+
+	if err != nil {
+		return fmt.Errorf("failed to list backups for %s: %w", install, err)
+	}
+
+	if backupJSON {
+		return outputJSON(backups)
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Backups: %s", install))
+
+	if len(backups) == 0 {
+		ui.Warning("No backups found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tCREATED\tDESCRIPTION")
+	fmt.Fprintln(w, "--\t------\t-------\t-----------")
+	for _, backup := range backups {
+		created := ""
+		if !backup.CreatedAt.IsZero() {
+			created = backup.CreatedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", backup.ID, backup.Status, created, backup.Description)
+	}
+	w.Flush()
+
+	fmt.Println()
+	ui.Success("Found %d backup(s)", len(backups))
+	return nil
+}
+
+// resolveBackupInstall returns the install from --install or the project config
+func resolveBackupInstall() (string, *config.Config, error) {
+	if backupInstall != "" {
+		// Config is optional here but still supplies notification emails
+		cfg, _ := config.Load(cfgFile, projectDir)
+		return backupInstall, cfg, nil
+	}
+
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return "", nil, err
+	}
+	if cfg.WPEngine.Install == "" {
+		return "", nil, fmt.Errorf("no WPEngine install configured: set wpengine.install or pass --install")
+	}
+	return cfg.WPEngine.Install, cfg, nil
+}
+
+// waitForBackup polls a backup with a spinner until it completes
+func waitForBackup(ctx context.Context, client *wpengine.Client, installID, backupID string, timeout time.Duration) (*wpengine.Backup, error) {
+	spinner := ui.NewSpinner("Waiting for backup to complete...")
+	spinner.Start()
+
+	start := time.Now()
+	backup, err := client.WaitForBackup(ctx, installID, backupID, wpengine.WaitOptions{
+		Timeout: timeout,
+		OnStatus: func(b *wpengine.Backup) {
+			ui.Verbose("Backup %s: %s (%s elapsed)", b.ID, b.Status, time.Since(start).Round(time.Second))
+		},
+	})
+	spinner.Stop()
+
+	if err != nil {
+		return nil, fmt.Errorf("backup %s did not complete: %w", backupID, err)
+	}
+	return backup, nil
+}
+
+// createBackupCheckpoint creates a backup of install and waits for it before a push
+// When required, any failure aborts the push; otherwise failures are only reported
+func createBackupCheckpoint(ctx context.Context, cfg *config.Config, install, description string, required bool) error {
+	ui.Info("Creating backup checkpoint of %s on WPEngine...", install)
+
+	err := func() error {
+		creds, err := credentials.GetWPEngineCredentialsWithFallback(install)
+		if err != nil {
+			return fmt.Errorf("failed to get WPEngine credentials: %w", err)
+		}
+		client := wpengine.NewClient(creds.APIUser, creds.APIPassword, install)
+
+		installID, err := client.ResolveInstallIDContext(ctx, install)
+		if err != nil {
+			return err
+		}
+
+		backupID, err := client.CreateBackupContext(ctx, installID, description, cfg.WPEngine.Backup.NotificationEmails...)
+		if err != nil {
+			return err
+		}
+
+		timeout := time.Duration(cfg.WPEngine.Backup.CheckpointTimeout) * time.Second
+		if _, err := waitForBackup(ctx, client, installID, backupID, timeout); err != nil {
+			return err
+		}
+
+		ui.Success("Backup checkpoint %s completed", backupID)
+		return nil
+	}()
+
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	if required {
+		return errors.NewWPEngineAPIError("a completed backup checkpoint is required before this push", err)
+	}
+
+	ui.Warning("Failed to create backup checkpoint: %v", err)
+	ui.Info("Continuing without backup...")
+	return nil
+}
+
+// checkpointRequiredError explains why --skip-backup was refused
+func checkpointRequiredError(environment string) error {
+	return errors.NewWithSolution(
+		fmt.Sprintf("A backup checkpoint is required before pushing to %s", environment),
+		"--skip-backup cannot be used while wpengine.backup.checkpoint requires a checkpoint for this environment.",
+		errors.Solution{
+			Description: "Change the checkpoint policy in .stax.yml if you really want to push without a backup",
+			Steps: []string{
+				"Set wpengine.backup.checkpoint to 'never' (or 'production' for non-production pushes)",
+				"Re-run the push with --skip-backup",
+			},
+		},
+	)
+}
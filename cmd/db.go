@@ -74,6 +74,7 @@ var dbPushCmd = &cobra.Command{
 	Long: `Push local database to WPEngine environment.
 
 This command will:
+  - Create a WPEngine backup checkpoint and wait for it (unless --skip-backup)
   - Export the local database from DDEV
   - Run search-replace to update URLs for the target environment
   - Upload the database to WPEngine
@@ -117,7 +118,7 @@ func init() {
 	dbPushCmd.Flags().StringVar(&dbEnvironment, "environment", "", "WPEngine environment (required: staging or production)")
 	dbPushCmd.MarkFlagRequired("environment")
 	dbPushCmd.Flags().BoolVar(&dbDryRun, "dry-run", false, "show what would happen without pushing")
-	dbPushCmd.Flags().BoolVar(&dbSkipBackup, "skip-backup", false, "skip creating remote backup before import")
+	dbPushCmd.Flags().BoolVar(&dbSkipBackup, "skip-backup", false, "skip the WPEngine backup checkpoint (not allowed when the checkpoint policy requires one)")
 	dbPushCmd.Flags().BoolVar(&dbSkipReplace, "skip-replace", false, "skip automatic URL search-replace")
 }
 
@@ -140,12 +141,9 @@ func runDBPull(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get SSH key with fallback
-	sshKey, err := credentials.GetSSHPrivateKeyWithFallback("wpengine")
+	sshKey, err := wpengineSSHKey(cfg.WPEngine.SSHGateway)
 	if err != nil {
-		if keyErr, ok := err.(*credentials.SSHKeyNotFoundError); ok {
-			return errors.NewSSHKeyNotFoundError("", keyErr.Tried, keyErr.LastErr)
-		}
-		return fmt.Errorf("failed to get SSH key: %w", err)
+		return err
 	}
 
 	// Use credentials
@@ -249,8 +247,13 @@ func runDBPush(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("environment must be 'staging' or 'production', got: %s", dbEnvironment)
 	}
 
+	install, installEnv, err := cfg.WPEngine.PushTarget(dbEnvironment)
+	if err != nil {
+		return noWPEngineInstallError(dbEnvironment, err)
+	}
+
 	// Production safety check - require explicit confirmation
-	if dbEnvironment == "production" && !dbDryRun {
+	if installEnv == "production" && !dbDryRun {
 		ui.Warning("You are about to push the local database to PRODUCTION!")
 		ui.Warning("This will OVERWRITE the production database!")
 		ui.Info("")
@@ -270,7 +273,13 @@ func runDBPush(cmd *cobra.Command, args []string) error {
 	}
 
 	ui.Info(fmt.Sprintf("Environment: %s", dbEnvironment))
-	ui.Info(fmt.Sprintf("Install: %s", cfg.WPEngine.Install))
+	ui.Info(fmt.Sprintf("Install: %s", install))
+
+	// The checkpoint policy follows the install written to, not the flag
+	checkpointRequired := cfg.WPEngine.Backup.RequiresCheckpoint(installEnv)
+	if dbSkipBackup && checkpointRequired {
+		return checkpointRequiredError(installEnv)
+	}
 
 	// Check if DDEV is running
 	projectDir := getProjectDir()
@@ -284,7 +293,7 @@ func runDBPush(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get credentials
-	creds, err := credentials.GetWPEngineCredentialsWithFallback(cfg.WPEngine.Install)
+	creds, err := credentials.GetWPEngineCredentialsWithFallback(install)
 	if err != nil {
 		if credErr, ok := err.(*credentials.CredentialsNotFoundError); ok {
 			return errors.NewCredentialsNotFoundError(credErr.Tried, credErr.LastErr)
@@ -293,12 +302,9 @@ func runDBPush(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get SSH key
-	sshKey, err := credentials.GetSSHPrivateKeyWithFallback("wpengine")
+	sshKey, err := wpengineSSHKey(cfg.WPEngine.SSHGateway)
 	if err != nil {
-		if keyErr, ok := err.(*credentials.SSHKeyNotFoundError); ok {
-			return errors.NewSSHKeyNotFoundError("", keyErr.Tried, keyErr.LastErr)
-		}
-		return fmt.Errorf("failed to get SSH key: %w", err)
+		return err
 	}
 
 	// Use credentials
@@ -309,17 +315,25 @@ func runDBPush(cmd *cobra.Command, args []string) error {
 		ui.Info("\n=== DRY RUN MODE ===")
 		ui.Info("The following operations would be performed:")
 		ui.Info("  1. Export local database from DDEV")
-		ui.Info("  2. Run search-replace: %s -> %s", getDDEVURL(cfg), getTargetURL(cfg, dbEnvironment))
+		ui.Info("  2. Run search-replace: %s -> %s", getDDEVURL(cfg), getTargetURL(cfg, install, installEnv))
 		if !dbSkipBackup {
-			ui.Info("  3. Create backup on WPEngine %s environment", dbEnvironment)
+			ui.Info("  3. Create backup checkpoint on WPEngine and wait for it to complete")
 		}
 		ui.Info("  4. Upload database to WPEngine")
-		ui.Info("  5. Import database on WPEngine %s environment", dbEnvironment)
+		ui.Info("  5. Import database on WPEngine %s environment (%s)", installEnv, install)
 		ui.Info("  6. Clean up temporary files")
 		ui.Info("\nNo changes will be made in dry-run mode.")
 		return nil
 	}
 
+	// Back up the remote install before anything is written to it
+	if !dbSkipBackup {
+		description := fmt.Sprintf("stax db push to %s at %s", dbEnvironment, time.Now().Format(time.RFC3339))
+		if err := createBackupCheckpoint(cmd.Context(), cfg, install, description, checkpointRequired); err != nil {
+			return err
+		}
+	}
+
 	// Export local database
 	ui.Info("Exporting local database...")
 	tmpDBPath := fmt.Sprintf("/tmp/stax-db-push-%d.sql", os.Getpid())
@@ -334,32 +348,18 @@ func runDBPush(cmd *cobra.Command, args []string) error {
 	ui.Info("Connecting to WPEngine SSH Gateway...")
 	sshConfig := wpengine.SSHConfig{
 		Host:       cfg.WPEngine.SSHGateway,
-		Port:       22,
 		User:       creds.SSHUser,
 		PrivateKey: sshKey,
-		Install:    cfg.WPEngine.Install,
+		Install:    install,
 	}
 
-	sshClient, err := wpengine.NewSSHClient(sshConfig)
+	sshClient, err := wpengineSSH(sshConfig)
 	if err != nil {
 		return fmt.Errorf("failed to connect to WPEngine: %w", err)
 	}
 	defer sshClient.Close()
 	ui.Success("Connected to WPEngine")
 
-	// Create backup on remote unless skipped
-	if !dbSkipBackup {
-		ui.Info("Creating database backup on WPEngine...")
-		backupPath := fmt.Sprintf("~/db-backup-before-push-%d.sql", time.Now().Unix())
-		backupCmd := fmt.Sprintf("wp db export %s", backupPath)
-		if _, err := sshClient.ExecuteCommand(backupCmd); err != nil {
-			ui.Warning(fmt.Sprintf("Failed to create backup: %v", err))
-			ui.Info("Continuing without backup...")
-		} else {
-			ui.Success(fmt.Sprintf("Backup created: %s", backupPath))
-		}
-	}
-
 	// Upload database file
 	ui.Info("Uploading database to WPEngine...")
 	remoteDBPath := fmt.Sprintf("~/stax-db-push-%d.sql", os.Getpid())
@@ -383,7 +383,7 @@ func runDBPush(cmd *cobra.Command, args []string) error {
 
 		// Get source and target URLs
 		sourceURL := getDDEVURL(cfg)
-		targetURL := getTargetURL(cfg, dbEnvironment)
+		targetURL := getTargetURL(cfg, install, installEnv)
 
 		ui.Info(fmt.Sprintf("  Replacing: %s -> %s", sourceURL, targetURL))
 
@@ -413,10 +413,8 @@ func runDBPush(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// getTargetURL returns the target WPEngine URL for the given environment
-func getTargetURL(cfg *config.Config, environment string) string {
-	install := cfg.WPEngine.Install
-
+// getTargetURL returns the URL of a WPEngine install in the given environment
+func getTargetURL(cfg *config.Config, install, environment string) string {
 	if environment == "production" {
 		// Check if custom domain is configured
 		if cfg.WPEngine.Domains.Production.Primary != "" {
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbTunnelPort int
+	dbTunnelBind string
+	dbTunnelJSON bool
+	dbTunnelEnv  string
+)
+
+// dbTunnelCmd forwards a local port to the remote database
+var dbTunnelCmd = &cobra.Command{
+	Use:   "tunnel",
+	Short: "Forward a local port to the WPEngine database",
+	Long: `Forward a local port to the remote database over SSH so database clients
+such as TablePlus or Sequel Ace can connect to it. The credentials are read
+from the install's wp-config.php and printed for the client.
+
+The tunnel goes to wpengine.install, or with --env staging to
+wpengine.staging_install. It stays open until interrupted with Ctrl+C.`,
+	Example: `  # Tunnel on a random free port
+  stax db tunnel
+
+  # Tunnel to the staging database
+  stax db tunnel --env staging
+
+  # Tunnel on a fixed port for a saved connection
+  stax db tunnel --port 33306`,
+	RunE: runDBTunnel,
+}
+
+func init() {
+	dbCmd.AddCommand(dbTunnelCmd)
+
+	dbTunnelCmd.Flags().IntVar(&dbTunnelPort, "port", 0, "local port to listen on (default: a free port)")
+	dbTunnelCmd.Flags().StringVar(&dbTunnelBind, "bind", "127.0.0.1", "local address to listen on")
+	dbTunnelCmd.Flags().BoolVar(&dbTunnelJSON, "json", false, "print the connection details as JSON")
+	dbTunnelCmd.Flags().StringVar(&dbTunnelEnv, "env", "", "WPEngine environment to connect to: production or staging (default: wpengine.install)")
+}
+
+// dbTunnelInfo is what a database client needs to use the tunnel
+type dbTunnelInfo struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Database string `json:"database"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	URL      string `json:"url"`
+}
+
+func runDBTunnel(cmd *cobra.Command, args []string) error {
+	switch dbTunnelEnv {
+	case "", "production", "staging":
+	default:
+		return fmt.Errorf("--env must be 'staging' or 'production', got: %s", dbTunnelEnv)
+	}
+
+	client, err := connectWPEngineSSH(dbTunnelEnv)
+	if err != nil {
+		return err
+	}
+
+	creds, err := client.GetDatabaseCredentials()
+	if err != nil {
+		return fmt.Errorf("failed to get database credentials: %w", err)
+	}
+	network, remoteAddr := creds.Address()
+
+	// Check the remote end before telling clients to connect
+	probe, err := client.DialRemote(network, remoteAddr)
+	if err != nil {
+		return fmt.Errorf("failed to reach the database at %s: %w", remoteAddr, err)
+	}
+	probe.Close()
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(dbTunnelBind, strconv.Itoa(dbTunnelPort)))
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %d: %w", dbTunnelPort, err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	info := dbTunnelInfo{
+		Host:     dbTunnelBind,
+		Port:     port,
+		Database: creds.Database,
+		Username: creds.Username,
+		Password: creds.Password,
+		URL: (&url.URL{
+			Scheme: "mysql",
+			User:   url.UserPassword(creds.Username, creds.Password),
+			Host:   net.JoinHostPort(dbTunnelBind, strconv.Itoa(port)),
+			Path:   "/" + creds.Database,
+		}).String(),
+	}
+
+	if dbTunnelJSON {
+		if err := outputJSON(info); err != nil {
+			return err
+		}
+	} else {
+		ui.Success("Tunnel open to the %s database", creds.Database)
+		ui.Info("  Host:     %s", info.Host)
+		ui.Info("  Port:     %d", info.Port)
+		ui.Info("  Database: %s", info.Database)
+		ui.Info("  User:     %s", info.Username)
+		ui.Info("  Password: %s", info.Password)
+		ui.Info("  URL:      %s", info.URL)
+		ui.Info("")
+		ui.Info("Press Ctrl+C to close the tunnel")
+	}
+
+	return client.Forward(cmd.Context(), listener, network, remoteAddr, func(err error) {
+		ui.Warning("Tunnel connection failed: %v", err)
+	})
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/firecrown-media/stax/pkg/config"
+	"github.com/firecrown-media/stax/pkg/ddev"
+	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// ddevCmd represents the ddev command group
+var ddevCmd = &cobra.Command{
+	Use:   "ddev",
+	Short: "Manage the DDEV configuration",
+	Long:  `Manage the DDEV configuration generated from .stax.yml.`,
+}
+
+// ddevSyncCmd represents the ddev sync command
+var ddevSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Apply .stax.yml DDEV settings to .ddev/config.yaml",
+	Long: `Apply the ddev settings of .stax.yml to an existing .ddev/config.yaml.
+
+The config is merged rather than rewritten: keys stax does not manage
+(upload_dirs, web_extra_exposed_ports, corepack_enable, ...), comments and key
+order are kept. Each managed key is compared with the value stax applied last
+time, recorded in .ddev/` + ddev.SyncStateFile + `:
+
+  - Changed only in .stax.yml: updated in config.yaml
+  - Edited only in config.yaml: the local edit is kept
+  - Changed on both sides: config.yaml keeps the local edit, and the .stax.yml
+    value is written to .ddev/` + ddev.OverrideConfigFile + `, which DDEV loads after it
+
+Use --force to write every .stax.yml value into config.yaml instead.
+
+The ddev.hooks of .stax.yml are written to the hooks of config.yaml. Pre-start
+and post-stop hooks run on the host, as the containers are not running. Each
+enabled ddev.custom_commands entry is generated as a script under
+.ddev/commands/host or .ddev/commands/web, so 'ddev <name>' works without
+stax installed. Available commands: ` + strings.Join(ddev.CommandTemplateNames(), ", ") + `.
+
+The services section of .stax.yml is written to .ddev/docker-compose.<name>.yaml
+files; see 'stax services'.`,
+	Example: `  # Show what would change
+  stax ddev sync --dry-run
+
+  # Apply without confirmation
+  stax ddev sync --yes
+
+  # Replace local edits with the .stax.yml values
+  stax ddev sync --force`,
+	RunE: runDDEVSync,
+}
+
+var (
+	ddevSyncDryRun bool
+	ddevSyncYes    bool
+	ddevSyncForce  bool
+)
+
+func init() {
+	rootCmd.AddCommand(ddevCmd)
+	ddevCmd.AddCommand(ddevSyncCmd)
+
+	ddevSyncCmd.Flags().BoolVar(&ddevSyncDryRun, "dry-run", false, "show the changes without writing files")
+	ddevSyncCmd.Flags().BoolVarP(&ddevSyncYes, "yes", "y", false, "apply without confirmation")
+	ddevSyncCmd.Flags().BoolVar(&ddevSyncForce, "force", false, "write every value into config.yaml, replacing local edits")
+}
+
+func runDDEVSync(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
+	projectDir := getProjectDir()
+
+	ui.PrintHeader("Syncing DDEV Configuration")
+
+	desired, err := ddev.GenerateConfig(projectDir, ddevConfigOptions(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to generate DDEV config: %w", err)
+	}
+	plan, err := ddev.PlanSync(projectDir, desired, ddev.SyncOptions{Force: ddevSyncForce})
+	if err != nil {
+		return fmt.Errorf("failed to merge DDEV config: %w", err)
+	}
+	files, err := planDDEVFiles(projectDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	fileChanges := 0
+	for _, file := range files {
+		if file.Changed() {
+			fileChanges++
+		}
+	}
+
+	if !plan.HasChanges() && fileChanges == 0 {
+		ui.Success("DDEV configuration already matches .stax.yml")
+		printDDEVSyncConflicts(plan)
+		printDDEVFileChanges(files)
+		return nil
+	}
+
+	if plan.HasChanges() {
+		ui.Section("Changes")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "KEY\tACTION\tCONFIG.YAML\t.STAX.YML")
+		for _, change := range plan.Changes {
+			if change.Action == ddev.SyncUnchanged {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", change.Key, change.Action, orDash(change.Current), change.Desired)
+		}
+		w.Flush()
+
+		diff, err := plan.Diff()
+		if err != nil {
+			return err
+		}
+		ui.Section("Diff")
+		fmt.Print(diff)
+		fmt.Println()
+	}
+
+	if fileChanges > 0 {
+		ui.Section("Generated Files")
+		for _, file := range files {
+			if file.Action != ddev.SyncUnchanged {
+				fmt.Printf("  %-10s %s\n", file.Action, file.Path)
+			}
+		}
+		fmt.Println()
+	}
+
+	if ddevSyncDryRun {
+		ui.Info("Dry run, no files were written")
+		return nil
+	}
+	if !ddevSyncYes && !ui.Confirm("Apply these changes?") {
+		ui.Info("Sync cancelled")
+		return nil
+	}
+
+	if err := plan.Apply(); err != nil {
+		return err
+	}
+	if err := ddev.ApplyGeneratedFiles(projectDir, files); err != nil {
+		return err
+	}
+	ui.Success("DDEV configuration synced")
+	printDDEVSyncConflicts(plan)
+	printDDEVFileChanges(files)
+
+	if status, err := ddev.GetStatus(projectDir); err == nil && status.Running {
+		ui.Info("Restart DDEV to apply the changes: stax restart")
+	}
+	return nil
+}
+
+// printDDEVSyncConflicts lists the .stax.yml values that override local edits
+func printDDEVSyncConflicts(plan *ddev.SyncPlan) {
+	conflicts := plan.Conflicts()
+	if len(conflicts) == 0 {
+		return
+	}
+	ui.Warning("%d setting(s) differ from local edits in config.yaml and are set in .ddev/%s:", len(conflicts), ddev.OverrideConfigFile)
+	for _, change := range conflicts {
+		fmt.Printf("  %s: %s (config.yaml has %s)\n", change.Key, change.Desired, orDash(change.Current))
+	}
+	ui.Info("Update .stax.yml to keep the local value, or run 'stax ddev sync --force' to write it into config.yaml")
+}
+
+// planDDEVFiles plans the custom command scripts and service docker-compose
+// files generated from .stax.yml
+func planDDEVFiles(projectDir string, cfg *config.Config) ([]ddev.GeneratedFile, error) {
+	commands, err := ddev.PlanCustomCommands(projectDir, ddevCustomCommands(cfg), ddevCommandOptions(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate DDEV custom commands: %w", err)
+	}
+	services, err := ddev.PlanServices(projectDir, ddevServiceOptions(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate DDEV services: %w", err)
+	}
+	return append(commands, services...), nil
+}
+
+// printDDEVFileChanges reports the generated files written, and those left
+// alone because stax did not generate them
+func printDDEVFileChanges(files []ddev.GeneratedFile) {
+	for _, file := range files {
+		switch file.Action {
+		case ddev.SyncAdded, ddev.SyncUpdated:
+			ui.Success("Generated %s", file.Path)
+		case ddev.SyncRemoved:
+			ui.Info("Removed %s", file.Path)
+		case ddev.SyncKept:
+			ui.Warning("%s was not generated by stax, leaving it unchanged", file.Path)
+		}
+	}
+}
@@ -100,7 +100,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	// Export to file if requested
 	if doctorExport != "" {
-		return exportReport(report, doctorExport)
+		return exportReport(report, doctorExport, doctorCategories)
 	}
 
 	// Display results
@@ -111,18 +111,50 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	return outputDoctorTable(report)
 }
 
+// doctorCategories is the order local checks are displayed in
+var doctorCategories = []string{
+	"System Requirements",
+	"Configuration",
+	"Credentials",
+	"Network Connectivity",
+	"Environment",
+	"Service Health",
+}
+
 func outputDoctorTable(report *diagnostics.DiagnosticReport) error {
-	// Display checks grouped by category
-	categoryOrder := []string{
-		"System Requirements",
-		"Configuration",
-		"Credentials",
-		"Network Connectivity",
-		"Environment",
-		"Service Health",
+	printDoctorChecks(report, doctorCategories)
+	printDoctorSummary(report)
+
+	// Overall health status
+	if report.IsHealthy() {
+		ui.Success("System is healthy - all checks passed!")
+		fmt.Println()
+		ui.Info("Quick commands:")
+		ui.Info("  stax start     - Start the environment")
+		ui.Info("  stax status    - Show environment status")
+	} else if report.HasCriticalFailures() {
+		ui.Error("Critical issues found that need attention")
+		fmt.Println()
+		ui.Info("Review the failures above and follow the suggestions to fix them.")
+		ui.Info("Run 'stax doctor' again after making changes.")
+	} else if report.HasWarnings() {
+		ui.Warning("System is functional but has warnings")
+		fmt.Println()
+		ui.Info("The environment should work, but you may want to address the warnings.")
+	}
+
+	// Show fix flag info if there are fixable issues
+	if !report.AutoFix && (report.HasCriticalFailures() || report.HasWarnings()) {
+		fmt.Println()
+		ui.Info("Tip: Run 'stax doctor --fix' to automatically fix some issues.")
 	}
 
-	for _, category := range categoryOrder {
+	return nil
+}
+
+// printDoctorChecks displays the checks grouped by category in the given order
+func printDoctorChecks(report *diagnostics.DiagnosticReport, categories []string) {
+	for _, category := range categories {
 		checks, exists := report.Categories[category]
 		if !exists || len(checks) == 0 {
 			continue
@@ -153,8 +185,10 @@ func outputDoctorTable(report *diagnostics.DiagnosticReport) error {
 		}
 		fmt.Println()
 	}
+}
 
-	// Display summary
+// printDoctorSummary displays the check counts and health score
+func printDoctorSummary(report *diagnostics.DiagnosticReport) {
 	ui.Section("Summary")
 	fmt.Printf("  Total Checks:   %d\n", report.Summary.Total)
 	fmt.Printf("  Passed:         %s %d\n", getStatusEmoji(diagnostics.StatusPass), report.Summary.Passed)
@@ -172,32 +206,6 @@ func outputDoctorTable(report *diagnostics.DiagnosticReport) error {
 	healthScore := calculateHealthScore(report)
 	fmt.Printf("  Health Score:   %d%%\n", healthScore)
 	fmt.Println()
-
-	// Overall health status
-	if report.IsHealthy() {
-		ui.Success("System is healthy - all checks passed!")
-		fmt.Println()
-		ui.Info("Quick commands:")
-		ui.Info("  stax start     - Start the environment")
-		ui.Info("  stax status    - Show environment status")
-	} else if report.HasCriticalFailures() {
-		ui.Error("Critical issues found that need attention")
-		fmt.Println()
-		ui.Info("Review the failures above and follow the suggestions to fix them.")
-		ui.Info("Run 'stax doctor' again after making changes.")
-	} else if report.HasWarnings() {
-		ui.Warning("System is functional but has warnings")
-		fmt.Println()
-		ui.Info("The environment should work, but you may want to address the warnings.")
-	}
-
-	// Show fix flag info if there are fixable issues
-	if !report.AutoFix && (report.HasCriticalFailures() || report.HasWarnings()) {
-		fmt.Println()
-		ui.Info("Tip: Run 'stax doctor --fix' to automatically fix some issues.")
-	}
-
-	return nil
 }
 
 func displayCheckResult(check diagnostics.CheckResult, verbose bool) {
@@ -270,7 +278,7 @@ func outputDoctorJSON(report *diagnostics.DiagnosticReport) error {
 	return nil
 }
 
-func exportReport(report *diagnostics.DiagnosticReport, filename string) error {
+func exportReport(report *diagnostics.DiagnosticReport, filename string, categories []string) error {
 	// Determine format based on file extension
 	isJSON := len(filename) > 5 && filename[len(filename)-5:] == ".json"
 
@@ -285,7 +293,7 @@ func exportReport(report *diagnostics.DiagnosticReport, filename string) error {
 		}
 	} else {
 		// Export as text
-		content = []byte(formatReportAsText(report))
+		content = []byte(formatReportAsText(report, categories))
 	}
 
 	// Write to file
@@ -298,7 +306,7 @@ func exportReport(report *diagnostics.DiagnosticReport, filename string) error {
 	return nil
 }
 
-func formatReportAsText(report *diagnostics.DiagnosticReport) string {
+func formatReportAsText(report *diagnostics.DiagnosticReport, categories []string) string {
 	var output string
 
 	output += "========================================\n"
@@ -306,16 +314,7 @@ func formatReportAsText(report *diagnostics.DiagnosticReport) string {
 	output += "========================================\n\n"
 
 	// Display checks grouped by category
-	categoryOrder := []string{
-		"System Requirements",
-		"Configuration",
-		"Credentials",
-		"Network Connectivity",
-		"Environment",
-		"Service Health",
-	}
-
-	for _, category := range categoryOrder {
+	for _, category := range categories {
 		checks, exists := report.Categories[category]
 		if !exists || len(checks) == 0 {
 			continue
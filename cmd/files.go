@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/firecrown-media/stax/pkg/config"
 	"github.com/firecrown-media/stax/pkg/credentials"
@@ -92,6 +93,7 @@ var filesPushCmd = &cobra.Command{
 	Long: `Push files from your local environment to WPEngine.
 
 This command will:
+  - Create a WPEngine backup checkpoint when wpengine.backup.checkpoint requires one
   - Connect to WPEngine via SSH
   - Sync local wp-content directory to remote (or specific subdirectories)
   - Transfer files using rsync over SSH
@@ -199,25 +201,21 @@ func runFilesPull(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get SSH key with fallback
-	sshKey, err := credentials.GetSSHPrivateKeyWithFallback("wpengine")
+	sshKey, err := wpengineSSHKey(cfg.WPEngine.SSHGateway)
 	if err != nil {
-		if keyErr, ok := err.(*credentials.SSHKeyNotFoundError); ok {
-			return errors.NewSSHKeyNotFoundError("", keyErr.Tried, keyErr.LastErr)
-		}
-		return fmt.Errorf("failed to get SSH key: %w", err)
+		return err
 	}
 
 	// Create SSH client
 	ui.Info("Connecting to WPEngine SSH Gateway...")
 	sshConfig := wpengine.SSHConfig{
 		Host:       cfg.WPEngine.SSHGateway,
-		Port:       22,
 		User:       creds.SSHUser,
 		PrivateKey: sshKey,
 		Install:    cfg.WPEngine.Install,
 	}
 
-	sshClient, err := wpengine.NewSSHClient(sshConfig)
+	sshClient, err := wpengineSSH(sshConfig)
 	if err != nil {
 		return fmt.Errorf("failed to connect to WPEngine: %w", err)
 	}
@@ -407,10 +405,10 @@ func runFilesPush(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Determine environment
-	environment := filesEnvironment
-	if environment == "" {
-		environment = cfg.WPEngine.Environment
+	// The install pushed to, and the environment it belongs to
+	install, environment, err := cfg.WPEngine.PushTarget(filesEnvironment)
+	if err != nil {
+		return noWPEngineInstallError(filesEnvironment, err)
 	}
 
 	// Safety check: confirm production pushes
@@ -440,10 +438,10 @@ func runFilesPush(cmd *cobra.Command, args []string) error {
 	}
 
 	ui.Info(fmt.Sprintf("Environment: %s", environment))
-	ui.Info(fmt.Sprintf("Install: %s", cfg.WPEngine.Install))
+	ui.Info(fmt.Sprintf("Install: %s", install))
 
 	// Get credentials with fallback
-	creds, err := credentials.GetWPEngineCredentialsWithFallback(cfg.WPEngine.Install)
+	creds, err := credentials.GetWPEngineCredentialsWithFallback(install)
 	if err != nil {
 		if credErr, ok := err.(*credentials.CredentialsNotFoundError); ok {
 			return errors.NewCredentialsNotFoundError(credErr.Tried, credErr.LastErr)
@@ -452,25 +450,29 @@ func runFilesPush(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get SSH key with fallback
-	sshKey, err := credentials.GetSSHPrivateKeyWithFallback("wpengine")
+	sshKey, err := wpengineSSHKey(cfg.WPEngine.SSHGateway)
 	if err != nil {
-		if keyErr, ok := err.(*credentials.SSHKeyNotFoundError); ok {
-			return errors.NewSSHKeyNotFoundError("", keyErr.Tried, keyErr.LastErr)
+		return err
+	}
+
+	// Back up the remote install when the checkpoint policy requires it
+	if cfg.WPEngine.Backup.RequiresCheckpoint(environment) && !filesDryRun {
+		description := fmt.Sprintf("stax files push to %s at %s", environment, time.Now().Format(time.RFC3339))
+		if err := createBackupCheckpoint(cmd.Context(), cfg, install, description, true); err != nil {
+			return err
 		}
-		return fmt.Errorf("failed to get SSH key: %w", err)
 	}
 
 	// Create SSH client
 	ui.Info("Connecting to WPEngine SSH Gateway...")
 	sshConfig := wpengine.SSHConfig{
 		Host:       cfg.WPEngine.SSHGateway,
-		Port:       22,
 		User:       creds.SSHUser,
 		PrivateKey: sshKey,
-		Install:    cfg.WPEngine.Install,
+		Install:    install,
 	}
 
-	sshClient, err := wpengine.NewSSHClient(sshConfig)
+	sshClient, err := wpengineSSH(sshConfig)
 	if err != nil {
 		return fmt.Errorf("failed to connect to WPEngine: %w", err)
 	}
@@ -485,23 +487,23 @@ func runFilesPush(cmd *cobra.Command, args []string) error {
 	var remotePath, localPath string
 	if filesThemesOnly {
 		ui.Info("Syncing themes only...")
-		remotePath = fmt.Sprintf("/sites/%s/wp-content/themes/", cfg.WPEngine.Install)
+		remotePath = fmt.Sprintf("/sites/%s/wp-content/themes/", install)
 		localPath = getProjectDir() + "/wp-content/themes/"
 	} else if filesPluginsOnly {
 		ui.Info("Syncing plugins only...")
-		remotePath = fmt.Sprintf("/sites/%s/wp-content/plugins/", cfg.WPEngine.Install)
+		remotePath = fmt.Sprintf("/sites/%s/wp-content/plugins/", install)
 		localPath = getProjectDir() + "/wp-content/plugins/"
 	} else if filesMuPluginsOnly {
 		ui.Info("Syncing mu-plugins only...")
-		remotePath = fmt.Sprintf("/sites/%s/wp-content/mu-plugins/", cfg.WPEngine.Install)
+		remotePath = fmt.Sprintf("/sites/%s/wp-content/mu-plugins/", install)
 		localPath = getProjectDir() + "/wp-content/mu-plugins/"
 	} else if filesUploadsOnly {
 		ui.Info("Syncing uploads only...")
-		remotePath = fmt.Sprintf("/sites/%s/wp-content/uploads/", cfg.WPEngine.Install)
+		remotePath = fmt.Sprintf("/sites/%s/wp-content/uploads/", install)
 		localPath = getProjectDir() + "/wp-content/uploads/"
 	} else {
 		ui.Info("Syncing wp-content directory...")
-		remotePath = fmt.Sprintf("/sites/%s/wp-content/", cfg.WPEngine.Install)
+		remotePath = fmt.Sprintf("/sites/%s/wp-content/", install)
 		localPath = getProjectDir() + "/wp-content/"
 	}
 
@@ -591,13 +591,47 @@ func cloneRepository(projectDir string, cfg *config.Config) error {
 func generateDDEVConfig(projectDir string, cfg *config.Config) error {
 	ui.Section("Generating DDEV Configuration")
 
-	// Check if config already exists
-	if ddev.IsConfigured(projectDir) {
-		ui.Info("DDEV configuration already exists, skipping generation")
-		return nil
+	ddevConfig, err := ddev.GenerateConfig(projectDir, ddevConfigOptions(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to generate DDEV config: %w", err)
+	}
+
+	// An existing config is merged rather than replaced, keeping local edits
+	configured := ddev.IsConfigured(projectDir)
+	plan, err := ddev.PlanSync(projectDir, ddevConfig, ddev.SyncOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to merge DDEV config: %w", err)
+	}
+	if err := plan.Apply(); err != nil {
+		return fmt.Errorf("failed to write DDEV config: %w", err)
 	}
 
-	// Prepare DDEV config options
+	// A broken custom command or service should not stop the project from
+	// being set up
+	files, err := planDDEVFiles(projectDir, cfg)
+	if err == nil {
+		err = ddev.ApplyGeneratedFiles(projectDir, files)
+	}
+	if err != nil {
+		ui.Warning("%v", err)
+	} else {
+		printDDEVFileChanges(files)
+	}
+
+	switch {
+	case !configured:
+		ui.Success("Generated DDEV configuration")
+	case plan.HasChanges():
+		ui.Success("Merged .stax.yml settings into the existing DDEV configuration")
+		printDDEVSyncConflicts(plan)
+	default:
+		ui.Info("DDEV configuration already matches .stax.yml")
+	}
+	return nil
+}
+
+// ddevConfigOptions returns the DDEV settings of the project
+func ddevConfigOptions(cfg *config.Config) ddev.ConfigOptions {
 	options := ddev.ConfigOptions{
 		ProjectName:        cfg.Project.Name,
 		Type:               mapProjectTypeToDDEV(cfg.Project.Type),
@@ -619,19 +653,66 @@ func generateDDEVConfig(projectDir string, cfg *config.Config) error {
 		options.AdditionalHostnames = generateMultisiteHostnames(cfg)
 	}
 
-	// Generate config
-	ddevConfig, err := ddev.GenerateConfig(projectDir, options)
-	if err != nil {
-		return fmt.Errorf("failed to generate DDEV config: %w", err)
+	options.PreStartHooks = ddevHookCommands(cfg.DDEV.Hooks.PreStart)
+	options.PostStartHooks = ddevHookCommands(cfg.DDEV.Hooks.PostStart)
+	options.PreStopHooks = ddevHookCommands(cfg.DDEV.Hooks.PreStop)
+	options.PostStopHooks = ddevHookCommands(cfg.DDEV.Hooks.PostStop)
+	return options
+}
+
+func ddevHookCommands(hooks []config.DDEVHook) []string {
+	var commands []string
+	for _, hook := range hooks {
+		if hook.Exec != "" {
+			commands = append(commands, hook.Exec)
+		}
 	}
+	return commands
+}
 
-	// Write config
-	if err := ddev.WriteConfig(projectDir, ddevConfig); err != nil {
-		return fmt.Errorf("failed to write DDEV config: %w", err)
+// ddevCustomCommands returns the custom commands requested in .stax.yml
+func ddevCustomCommands(cfg *config.Config) []ddev.CustomCommand {
+	commands := make([]ddev.CustomCommand, len(cfg.DDEV.CustomCommands))
+	for i, command := range cfg.DDEV.CustomCommands {
+		commands[i] = ddev.CustomCommand{Name: command.Name, Description: command.Description, Enabled: command.Enabled}
 	}
+	return commands
+}
 
-	ui.Success("Generated DDEV configuration")
-	return nil
+// ddevCommandOptions returns the project data written into custom commands
+func ddevCommandOptions(cfg *config.Config) ddev.CommandOptions {
+	options := ddev.CommandOptions{
+		ProjectName:  cfg.Project.Name,
+		Install:      cfg.WPEngine.Install,
+		SSHGateway:   cfg.WPEngine.SSHGateway,
+		LocalURL:     getDDEVURL(cfg),
+		Network:      cfg.Project.Type == "wordpress-multisite",
+		SnapshotDir:  cfg.Snapshots.Directory,
+		BuildCommand: "bash scripts/build.sh",
+	}
+	if options.SSHGateway == "" {
+		options.SSHGateway = "ssh.wpengine.net"
+	}
+	if cfg.WPEngine.Install != "" {
+		options.RemoteURL = getWPEngineURL(cfg)
+	}
+	if cfg.Build.Scripts.Main != "" {
+		options.BuildCommand = "bash " + cfg.Build.Scripts.Main
+	}
+
+	// Subdomain sites are replaced one by one, as stax db pull does
+	if options.Network && cfg.Project.Mode == "subdomain" {
+		for _, site := range cfg.Network.Sites {
+			if site.Active && site.WPEngineDomain != "" && site.Domain != "" {
+				options.Sites = append(options.Sites, ddev.CommandSite{
+					From: "https://" + site.WPEngineDomain,
+					To:   "https://" + site.Domain,
+					URL:  site.Domain,
+				})
+			}
+		}
+	}
+	return options
 }
 
 func generateMultisiteNginxConfig(projectDir string, cfg *config.Config) error {
@@ -11,6 +11,7 @@ import (
 	"github.com/firecrown-media/stax/pkg/provider"
 	"github.com/firecrown-media/stax/pkg/providers/wpengine"
 	"github.com/firecrown-media/stax/pkg/ui"
+	wpengineapi "github.com/firecrown-media/stax/pkg/wpengine"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -20,6 +21,8 @@ var (
 	listProvider     string
 	listFilter       string
 	listEnvironment  string
+	listRefresh      bool
+	listOffline      bool
 )
 
 // listCmd represents the list command
@@ -54,6 +57,8 @@ func init() {
 	listCmd.Flags().StringVarP(&listProvider, "provider", "p", "wpengine", "Provider to list from")
 	listCmd.Flags().StringVarP(&listFilter, "filter", "f", "", "Filter by install name (regex)")
 	listCmd.Flags().StringVarP(&listEnvironment, "environment", "e", "", "Filter by environment")
+	listCmd.Flags().BoolVar(&listRefresh, "refresh", false, "Ignore cached installs and fetch from the API")
+	listCmd.Flags().BoolVar(&listOffline, "offline", false, "Use cached installs without contacting the API")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -66,7 +71,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	}
 
 	// 2. Create provider
-	p, err := createWPEngineProvider(creds)
+	p, client, err := createWPEngineProvider(creds)
 	if err != nil {
 		return err
 	}
@@ -78,6 +83,10 @@ func runList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if listOutputFormat == "table" {
+		reportCacheStatus(client.Status())
+	}
+
 	// 4. Output based on format
 	switch listOutputFormat {
 	case "json":
@@ -116,35 +125,22 @@ func loadGlobalCredentials() (*credentials.WPEngineCredentials, error) {
 }
 
 // createWPEngineProvider creates a WPEngine provider instance without full config
-func createWPEngineProvider(creds *credentials.WPEngineCredentials) (*wpengine.WPEngineProvider, error) {
-	// Create provider instance
-	p := &wpengine.WPEngineProvider{}
-
-	// Authenticate with credentials
-	// For listing, we need to provide install but it won't be used for the API call
-	credMap := map[string]string{
-		"api_user":     creds.APIUser,
-		"api_password": creds.APIPassword,
-		"install":      "temp", // Required by ValidateCredentials but not used for ListSites
-		"ssh_key":      "",     // Empty to skip SSH client creation
-	}
-
-	if creds.SSHGateway != "" {
-		credMap["ssh_gateway"] = creds.SSHGateway
-	} else {
-		credMap["ssh_gateway"] = "ssh.wpengine.net"
-	}
-
-	if err := p.Authenticate(credMap); err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
+// The provider lists installs through the install cache
+func createWPEngineProvider(creds *credentials.WPEngineCredentials) (*wpengine.WPEngineProvider, *wpengineapi.CachedClient, error) {
+	cacheDir, err := wpengineapi.DefaultCacheDir()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Test connection
-	if err := p.TestConnection(); err != nil {
-		return nil, fmt.Errorf("connection test failed: %w", err)
-	}
+	client := wpengineapi.NewCachedClient(
+		wpengineapi.NewClient(creds.APIUser, creds.APIPassword, ""),
+		wpengineapi.NewCache(cacheDir, creds.APIUser, wpengineapi.DefaultCacheTTL),
+	)
+	client.Refresh = listRefresh
+	client.Offline = listOffline
 
-	return p, nil
+	// Listing only needs the API, so no SSH client is created
+	return wpengine.NewWPEngineProviderWithClients("", client, nil), client, nil
 }
 
 // listAndFilterSites retrieves and filters sites
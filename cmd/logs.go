@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/firecrown-media/stax/pkg/ddev"
+	"github.com/firecrown-media/stax/pkg/logs"
+	"github.com/firecrown-media/stax/pkg/wpengine"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsRemote     string
+	logsType       string
+	logsFollow     bool
+	logsGrep       string
+	logsSince      string
+	logsJSON       bool
+	logsTail       int
+	logsService    string
+	logsTimestamps bool
+	logsPath       string
+)
+
+// logsCmd shows DDEV container logs or WPEngine install logs
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "View local or WPEngine logs",
+	Long: `View logs from the DDEV containers, or from a WPEngine install with --remote.
+
+Remote logs are read over SSH:
+  php-error  PHP errors, from the nginx error log (default)
+  access     nginx access log
+  wp-debug   wp-content/debug.log
+
+--remote staging reads the logs of wpengine.staging_install.
+
+--grep, --since, --type and --json work for both. With --json each record is
+printed as one JSON object per line, parsed into fields such as time, level,
+file and line for PHP errors or status, method and path for requests.`,
+	Example: `  # Local container logs
+  stax logs -f
+
+  # Fatal errors on production in the last hour
+  stax logs --remote production --grep "Fatal" --since 1h
+
+  # Follow the production access log as JSON
+  stax logs --remote production --type access -f --json`,
+	RunE: runLogs,
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().StringVar(&logsRemote, "remote", "", "read logs from a WPEngine environment (production or staging)")
+	logsCmd.Flags().StringVar(&logsType, "type", "", "log type: php-error, access or wp-debug (default: php-error for --remote)")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep streaming new log lines")
+	logsCmd.Flags().StringVar(&logsGrep, "grep", "", "only show records matching this regular expression")
+	logsCmd.Flags().StringVar(&logsSince, "since", "", "only show records since a duration (30m, 1h, 2d) or time")
+	logsCmd.Flags().BoolVar(&logsJSON, "json", false, "print records as JSON lines")
+	logsCmd.Flags().IntVar(&logsTail, "tail", 100, "number of lines to start from (0 for all)")
+	logsCmd.Flags().StringVar(&logsService, "service", "", "DDEV service to show (local only)")
+	logsCmd.Flags().BoolVar(&logsTimestamps, "timestamp", false, "show timestamps (local only)")
+	logsCmd.Flags().StringVar(&logsPath, "path", "", "remote log file to read instead of the default for --type")
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	var filter logs.Filter
+
+	if logsType != "" {
+		typ, err := logs.ParseType(logsType)
+		if err != nil {
+			return err
+		}
+		filter.Type = typ
+	}
+	if logsGrep != "" {
+		re, err := regexp.Compile(logsGrep)
+		if err != nil {
+			return fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+		filter.Grep = re
+	}
+	if logsSince != "" {
+		since, err := logs.ParseSince(logsSince, time.Now())
+		if err != nil {
+			return err
+		}
+		filter.Since = since
+		if !cmd.Flags().Changed("tail") {
+			// Older entries may be anywhere in the file
+			logsTail = 0
+		}
+	}
+
+	switch logsRemote {
+	case "":
+		return runLocalLogs(filter)
+	case "production", "staging":
+	default:
+		return fmt.Errorf("--remote must be 'staging' or 'production', got: %s", logsRemote)
+	}
+
+	if filter.Type == "" {
+		filter.Type = logs.TypePHPError
+	}
+
+	client, err := connectWPEngineSSH(logsRemote)
+	if err != nil {
+		return err
+	}
+
+	path := logsPath
+	if path == "" {
+		path = wpengine.LogPath(client.Install(), filter.Type)
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(client.TailLog(cmd.Context(), path, wpengine.TailOptions{Lines: logsTail, Follow: logsFollow}, w))
+	}()
+	return printLogRecords(r, filter.Type, filter)
+}
+
+// runLocalLogs shows DDEV logs, parsing them only when records are needed
+func runLocalLogs(filter logs.Filter) error {
+	mgr := ddev.NewManager(getProjectDir())
+	opts := &ddev.LogOptions{
+		Service:    logsService,
+		Follow:     logsFollow,
+		Tail:       logsTail,
+		Timestamps: logsTimestamps,
+	}
+	if !filter.Since.IsZero() {
+		opts.Since = filter.Since.Format(time.RFC3339)
+	}
+
+	if !logsJSON && filter.Grep == nil && filter.Type == "" {
+		return mgr.Logs(opts)
+	}
+
+	r, w := io.Pipe()
+	opts.Output = w
+	go func() {
+		w.CloseWithError(mgr.Logs(opts))
+	}()
+	// Container output mixes formats, so detect each record's type
+	return printLogRecords(r, "", filter)
+}
+
+// printLogRecords prints the records from r that pass filter
+func printLogRecords(r io.Reader, typ logs.Type, filter logs.Filter) error {
+	encoder := json.NewEncoder(os.Stdout)
+
+	scanner := logs.NewScanner(r, typ)
+	for scanner.Scan() {
+		rec := scanner.Record()
+		if !filter.Match(rec) {
+			continue
+		}
+
+		if logsJSON {
+			if err := encoder.Encode(rec); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Println(rec.Raw)
+	}
+	return scanner.Err()
+}
@@ -4,10 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/firecrown-media/stax/pkg/config"
 	"github.com/firecrown-media/stax/pkg/ddev"
 	"github.com/firecrown-media/stax/pkg/errors"
+	"github.com/firecrown-media/stax/pkg/media"
 	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/firecrown-media/stax/pkg/wordpress"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +21,9 @@ var (
 	mediaProxyCDN      string
 	mediaProxyCache    bool
 	mediaProxyCacheTTL string
+	mediaTestSite      string
+	mediaTestSamples   int
+	mediaTestJSON      bool
 )
 
 // mediaCmd represents the media command group
@@ -25,8 +33,14 @@ var mediaCmd = &cobra.Command{
 	Long: `Manage media proxy configuration for loading remote media files.
 
 This allows you to work with production media files without downloading them
-locally. The media proxy can use BunnyCDN or WPEngine as the source.`,
-	Example: `  # Setup media proxy from WPEngine
+locally. The media proxy can use BunnyCDN or WPEngine as the source.
+
+'stax media serve' runs a built-in proxy with a size- and age-limited cache
+that works with both nginx-fpm and apache-fpm.`,
+	Example: `  # Run the built-in media proxy
+  stax media serve
+
+  # Setup media proxy from WPEngine
   stax media setup-proxy
 
   # Setup media proxy from BunnyCDN
@@ -84,11 +98,30 @@ var mediaTestCmd = &cobra.Command{
 	Short: "Test media proxy is working",
 	Long: `Test that the media proxy configuration is working correctly.
 
-This validates:
-  - Nginx configuration syntax
-  - DDEV is running
-  - Proxy sources are reachable
-  - Cache directory exists (if enabled)`,
+This checks that DDEV is running and how its webserver proxies uploads,
+requests the uploads directory from each source to check it is reachable, and
+requests the newest attachments in the database through the local site URL.
+Each sample shows its status, the source that served it (X-Proxy-Source),
+the cache status (X-Cache-Status) and the latency.
+
+Only attachments missing from the local uploads directory are sampled, as the
+webserver serves local files without the proxy.
+
+The test fails when no sample upload loads, the usual sign of a proxy that is
+configured but not running or not loaded, when no source is reachable, or
+when the nginx configuration is invalid.
+
+With --site, the media route of one network site is checked instead: its
+uploads directory, its sources, and whether they serve one of its
+attachments.`,
+	Example: `  # Test the media proxy
+  stax media test
+
+  # Request 20 attachments and print JSON
+  stax media test --samples 20 --json
+
+  # Test the media route of a network site
+  stax media test --site flyingmag`,
 	RunE: runMediaTest,
 }
 
@@ -105,6 +138,11 @@ func init() {
 	mediaSetupCmd.Flags().StringVar(&mediaProxyURL, "url", "", "WPEngine URL (auto-detected if not provided)")
 	mediaSetupCmd.Flags().BoolVar(&mediaProxyCache, "cache", true, "enable local caching of proxied media")
 	mediaSetupCmd.Flags().StringVar(&mediaProxyCacheTTL, "cache-ttl", "30d", "cache TTL (e.g., 7d, 24h)")
+
+	// Flags for test
+	mediaTestCmd.Flags().StringVar(&mediaTestSite, "site", "", "test the media route of a network site (slug)")
+	mediaTestCmd.Flags().IntVar(&mediaTestSamples, "samples", 5, "number of recent attachments to request")
+	mediaTestCmd.Flags().BoolVar(&mediaTestJSON, "json", false, "output results as JSON")
 }
 
 func runMediaSetup(cmd *cobra.Command, args []string) error {
@@ -191,6 +229,22 @@ func runMediaSetup(cmd *cobra.Command, args []string) error {
 		options.CDNName = "WPEngine"
 	}
 
+	// Network sites with their own origins get their own locations
+	if cfg != nil {
+		routes, unresolved := mediaSiteRoutes(cfg, projectDir)
+		for _, route := range routes {
+			if len(route.Sources) == 0 {
+				continue
+			}
+			src := route.Sources[0]
+			options.Sites = append(options.Sites, ddev.MediaProxySite{Name: route.Site, ID: route.ID, URL: src.URL, Host: src.Host})
+			ui.Info(fmt.Sprintf("Site %s: %s from %s", route.Site, route.Prefix(), src.URL))
+		}
+		if len(unresolved) > 0 {
+			ui.Warning(fmt.Sprintf("No site ID for %s; set network.sites[].id to route their uploads", strings.Join(unresolved, ", ")))
+		}
+	}
+
 	// Generate nginx configuration
 	spinner := ui.NewSpinner("Generating nginx media proxy configuration")
 	spinner.Start()
@@ -365,7 +419,12 @@ func runMediaStatus(cmd *cobra.Command, args []string) error {
 }
 
 func runMediaTest(cmd *cobra.Command, args []string) error {
-	ui.PrintHeader("Testing Media Proxy")
+	if mediaTestSite != "" {
+		return runMediaTestSite(cmd, mediaTestSite)
+	}
+	if mediaTestJSON {
+		ui.SetQuiet(true)
+	}
 
 	projectDir := getProjectDir()
 
@@ -383,98 +442,271 @@ func runMediaTest(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	fmt.Println()
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
 
-	// Test 1: Check nginx config exists
-	ui.Section("Configuration Tests")
-	nginxConfigPath := filepath.Join(projectDir, ".ddev", "nginx_full", "media-proxy.conf")
-	if _, err := os.Stat(nginxConfigPath); err == nil {
-		ui.Success("✓ Nginx configuration file exists")
-	} else {
-		ui.Error("✗ Nginx configuration file not found")
+	status, err := ddev.GetStatus(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to get DDEV status: %w", err)
+	}
+	if !status.Running {
 		return errors.NewWithSolution(
-			"Media proxy not configured",
-			"Nginx configuration file does not exist",
+			"DDEV is not running",
+			"Media proxy requires DDEV to be running",
 			errors.Solution{
-				Description: "Setup media proxy first",
+				Description: "Start DDEV",
 				Steps: []string{
-					"Run: stax media setup-proxy",
+					"Run: stax start",
 				},
 			},
 		)
 	}
 
-	// Test 2: Check DDEV is running
-	ui.Section("Environment Tests")
-	status, err := ddev.GetStatus(projectDir)
+	report := &mediaTestReport{SiteURL: status.PrimaryURL, Integration: mediaIntegration(projectDir, cfg)}
+	if report.Integration == "nginx" {
+		if err := ddev.ValidateNginxConfig(projectDir); err != nil {
+			report.NginxError = err.Error()
+			report.Problems = append(report.Problems, fmt.Sprintf("nginx configuration is invalid: %v", err))
+		}
+	}
+
+	server, err := newMediaServer(cfg, projectDir, false)
 	if err != nil {
-		ui.Error("✗ Cannot get DDEV status")
 		return err
 	}
+	report.Origins = server.CheckOrigins(cmd.Context(), server.AllSources())
 
-	if !status.Running {
+	// Recent uploads are often local, so look further back for missing ones
+	keys, err := sampleAttachments(projectDir, max(mediaTestSamples*mediaTestSampleWindow, 100))
+	if err == nil {
+		keys, report.LocalSkipped = media.MissingLocally(server.UploadsDir, keys, mediaTestSamples)
+	}
+	switch {
+	case err != nil:
+		report.Problems = append(report.Problems, fmt.Sprintf("could not read attachments: %v", err))
+	case len(keys) == 0 && report.LocalSkipped > 0:
+		report.Problems = append(report.Problems, fmt.Sprintf("the %d most recent attachments exist locally, so none tests the proxy", report.LocalSkipped))
+	case len(keys) == 0:
+		report.Problems = append(report.Problems, "the database has no attachments to request")
+	default:
+		report.Samples = media.RequestSamples(cmd.Context(), media.LocalSiteClient(), status.PrimaryURL, keys)
+	}
+
+	if mediaTestJSON {
+		if err := outputJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printMediaTestReport(report)
+	}
+
+	return report.err()
+}
+
+// mediaTestSampleWindow is how many attachments are read per sample, to find
+// ones missing from the local uploads
+const mediaTestSampleWindow = 20
+
+// mediaTestReport is the result of stax media test
+type mediaTestReport struct {
+	SiteURL      string               `json:"site_url"`
+	Integration  string               `json:"integration"` // serve, nginx or none
+	NginxError   string               `json:"nginx_error,omitempty"`
+	Origins      []media.OriginResult `json:"origins"`
+	Samples      []media.SampleResult `json:"samples"`
+	LocalSkipped int                  `json:"local_skipped"` // attachments not sampled because they are local
+	Problems     []string             `json:"problems,omitempty"`
+}
+
+// err fails the test when the nginx config is invalid, every source is
+// unreachable or no sample upload was served
+// Samples are uploads missing locally, so each one goes through the proxy;
+// some may be missing on every source, so partial failures only warn
+func (r *mediaTestReport) err() error {
+	if r.NginxError != "" {
 		return errors.NewWithSolution(
-			"DDEV is not running",
-			"Media proxy requires DDEV to be running",
+			"Media proxy nginx configuration is invalid",
+			r.NginxError,
 			errors.Solution{
-				Description: "Start DDEV",
+				Description: "Regenerate the configuration",
 				Steps: []string{
-					"Run: stax start",
+					"Regenerate the config: stax media setup-proxy",
+					"Restart DDEV: stax restart",
 				},
 			},
 		)
 	}
-	ui.Success("✓ DDEV is running")
 
-	// Test 3: Validate nginx configuration
-	ui.Section("Nginx Validation")
-	if err := ddev.ValidateNginxConfig(projectDir); err != nil {
-		ui.Error("✗ Nginx configuration is invalid")
-		ui.Warning(fmt.Sprintf("Error: %v", err))
+	if len(r.Origins) > 0 && !anyOriginReachable(r.Origins) {
 		return errors.NewWithSolution(
-			"Nginx configuration is invalid",
-			err.Error(),
+			"No media source is reachable",
+			fmt.Sprintf("All %d sources failed to answer", len(r.Origins)),
 			errors.Solution{
-				Description: "Fix the configuration",
+				Description: "Check the source URLs and your connection",
 				Steps: []string{
-					"1. Check .ddev/nginx_full/media-proxy.conf for syntax errors",
-					"2. Regenerate config: stax media setup-proxy",
-					"3. Restart DDEV: stax restart",
+					"Review the sources: stax media status",
+					"Check media.bunnycdn, media.origins and wpengine.install in .stax.yml",
 				},
 			},
 		)
 	}
-	ui.Success("✓ Nginx configuration is valid")
 
-	// Test 4: Check proxy sources
-	if cfg != nil {
-		ui.Section("Proxy Source Tests")
+	if len(r.Samples) == 0 {
+		return nil
+	}
+	for _, sample := range r.Samples {
+		if sample.OK() {
+			return nil
+		}
+	}
 
-		if cfg.Media.BunnyCDN.Hostname != "" {
-			cdnURL := fmt.Sprintf("https://%s", cfg.Media.BunnyCDN.Hostname)
-			ui.Info(fmt.Sprintf("  CDN URL: %s", cdnURL))
-			ui.Success("✓ BunnyCDN configured")
+	solution := errors.Solution{Description: "Make sure the media proxy is running and loaded"}
+	switch r.Integration {
+	case "serve":
+		solution.Steps = []string{
+			"Start the proxy: stax media serve",
+			"Restart DDEV if the webserver snippet is new: stax restart",
+		}
+	case "nginx":
+		solution.Steps = []string{
+			"Regenerate the config: stax media setup-proxy",
+			"Restart DDEV: stax restart",
 		}
+	default:
+		solution.Steps = []string{"Start the media proxy: stax media serve"}
+	}
+	return errors.NewWithSolution(
+		"Media proxy is not serving uploads",
+		fmt.Sprintf("None of the %d sample uploads loaded from %s", len(r.Samples), r.SiteURL),
+		solution,
+	)
+}
 
-		if cfg.WPEngine.Install != "" {
-			wpengineURL := fmt.Sprintf("https://%s.wpengine.com", cfg.WPEngine.Install)
-			ui.Info(fmt.Sprintf("  WPEngine URL: %s", wpengineURL))
-			ui.Success("✓ WPEngine configured")
+// anyOriginReachable reports whether at least one source answered
+func anyOriginReachable(origins []media.OriginResult) bool {
+	for _, origin := range origins {
+		if origin.Reachable() {
+			return true
 		}
 	}
+	return false
+}
+
+// mediaIntegration returns how the DDEV webserver is set up to proxy media
+func mediaIntegration(projectDir string, cfg *config.Config) string {
+	if p, err := ddev.MediaServeConfigPath(projectDir, cfg.DDEV.WebserverType); err == nil {
+		if _, err := os.Stat(p); err == nil {
+			return "serve"
+		}
+	}
+	if ddev.IsMediaProxyConfigured(projectDir) {
+		return "nginx"
+	}
+	return "none"
+}
+
+// sampleAttachments returns the upload paths of the newest attachments
+func sampleAttachments(projectDir string, limit int) ([]string, error) {
+	cli := wordpress.NewCLI(projectDir)
+	prefix, err := cli.GetTablePrefix()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the table prefix: %w", err)
+	}
+	query, err := media.RecentAttachmentsQuery(prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	output, err := cli.ExecuteWithOutput("db", "query", query, "--skip-column-names")
+	if err != nil {
+		return nil, err
+	}
+	return media.ParseAttachmentRows(output), nil
+}
+
+// printMediaTestReport prints origin and sample results as tables
+func printMediaTestReport(report *mediaTestReport) {
+	ui.PrintHeader("Testing Media Proxy")
 
+	ui.Section("Configuration")
+	switch report.Integration {
+	case "serve":
+		ui.Success("DDEV webserver passes missing uploads to 'stax media serve'")
+	case "nginx":
+		ui.Success("nginx media proxy configured by 'stax media setup-proxy'")
+	default:
+		ui.Warning("No media proxy is configured; only local uploads are served")
+	}
+	fmt.Printf("  Site:            %s\n", report.SiteURL)
 	fmt.Println()
-	ui.Success("All media proxy tests passed!")
+
+	ui.Section("Sources")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "  SOURCE\tURL\tSTATUS\tLATENCY")
+	for _, origin := range report.Origins {
+		result := fmt.Sprintf("%d", origin.Status)
+		if !origin.Reachable() {
+			result = "unreachable: " + origin.Error
+		}
+		url := origin.URL
+		if origin.Host != "" {
+			url += " (Host: " + origin.Host + ")"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%dms\n", origin.Name, url, result, origin.LatencyMS)
+	}
+	w.Flush()
 	fmt.Println()
 
-	ui.Info("Manual verification steps:")
-	ui.Info(fmt.Sprintf("  1. Visit: %s", status.PrimaryURL))
-	ui.Info("  2. Navigate to a page with media/images")
-	ui.Info("  3. Check browser DevTools Network tab")
-	ui.Info("  4. Verify images load from remote source")
-	ui.Info("  5. Look for X-Proxy-Source header in response")
+	if len(report.Samples) > 0 {
+		ui.Section("Sample Uploads")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "  UPLOAD\tSTATUS\tSOURCE\tCACHE\tLATENCY")
+		for _, sample := range report.Samples {
+			result := fmt.Sprintf("%d", sample.Status)
+			if sample.Error != "" {
+				result = "error: " + sample.Error
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%dms\n", sample.Key, result, orDash(sample.Source), orDash(sample.CacheStatus), sample.LatencyMS)
+		}
+		w.Flush()
+		fmt.Println()
+	}
 
-	return nil
+	for _, problem := range report.Problems {
+		ui.Warning(problem)
+	}
+	unreachable := 0
+	for _, origin := range report.Origins {
+		if !origin.Reachable() {
+			unreachable++
+		}
+	}
+	if unreachable > 0 {
+		ui.Warning("%d of %d sources are unreachable", unreachable, len(report.Origins))
+	}
+
+	served := 0
+	for _, sample := range report.Samples {
+		if sample.OK() {
+			served++
+		}
+	}
+	switch {
+	case len(report.Samples) == 0:
+	case served == len(report.Samples):
+		ui.Success("All %d sample uploads loaded through %s", served, report.SiteURL)
+	case served > 0:
+		ui.Warning("%d of %d sample uploads loaded; the others may be missing on every source", served, len(report.Samples))
+	}
+}
+
+// orDash returns value, or "-" when it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
 }
 
 // getBoolStatus returns a formatted string for boolean status
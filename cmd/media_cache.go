@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/firecrown-media/stax/pkg/errors"
+	"github.com/firecrown-media/stax/pkg/logs"
+	"github.com/firecrown-media/stax/pkg/media"
+	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/firecrown-media/stax/pkg/wordpress"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mediaCacheJSON   bool
+	mediaCacheTop    int
+	mediaCacheYes    bool
+	mediaWarmFromDB  bool
+	mediaWarmPosts   int
+	mediaWarmSince   string
+	mediaWarmWorkers int
+)
+
+var mediaCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and manage the media cache",
+	Long: `Inspect and manage the cache used by 'stax media serve'.
+
+The cache lives in media.cache.directory and is limited by
+media.cache.max_size and media.cache.ttl in .stax.yml.`,
+}
+
+var mediaCacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache size, hit ratio and largest directories",
+	Long: `Show the cache size, the number of expired entries, the hit ratio of
+requests logged by 'stax media serve' and the directories using most space.`,
+	RunE: runMediaCacheStats,
+}
+
+var mediaCachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove expired and least recently used files",
+	Long: `Remove entries older than media.cache.ttl, then the least recently used
+entries until the cache fits within media.cache.max_size.`,
+	RunE: runMediaCachePrune,
+}
+
+var mediaCacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached file",
+	RunE:  runMediaCacheClear,
+}
+
+var mediaCacheWarmCmd = &cobra.Command{
+	Use:   "warm [upload-path...]",
+	Short: "Prefetch media so the site works offline",
+	Long: `Fetch media into the cache ahead of time, so demos work without a network.
+
+Pass upload paths such as 2024/01/photo.jpg, or use --from-db to warm the
+attachments of the most recently published posts: files attached to each post
+and its featured image, read from the local database.`,
+	Example: `  # Warm media for the 50 newest posts
+  stax media cache warm --from-db
+
+  # Warm media for posts published in the last 30 days
+  stax media cache warm --from-db --since 30d --posts 500
+
+  # Warm specific files
+  stax media cache warm 2024/01/hero.jpg 2024/01/logo.png`,
+	RunE: runMediaCacheWarm,
+}
+
+func init() {
+	mediaCmd.AddCommand(mediaCacheCmd)
+	mediaCacheCmd.AddCommand(mediaCacheStatsCmd)
+	mediaCacheCmd.AddCommand(mediaCachePruneCmd)
+	mediaCacheCmd.AddCommand(mediaCacheClearCmd)
+	mediaCacheCmd.AddCommand(mediaCacheWarmCmd)
+
+	mediaCacheStatsCmd.Flags().BoolVar(&mediaCacheJSON, "json", false, "output stats as JSON")
+	mediaCacheStatsCmd.Flags().IntVar(&mediaCacheTop, "top", 10, "number of directories to list")
+
+	mediaCacheClearCmd.Flags().BoolVarP(&mediaCacheYes, "yes", "y", false, "do not ask for confirmation")
+
+	mediaCacheWarmCmd.Flags().BoolVar(&mediaWarmFromDB, "from-db", false, "warm attachments of recent posts from the local database")
+	mediaCacheWarmCmd.Flags().IntVar(&mediaWarmPosts, "posts", 50, "number of recent posts to warm with --from-db")
+	mediaCacheWarmCmd.Flags().StringVar(&mediaWarmSince, "since", "", "only posts published since a duration (30d) or date, with --from-db")
+	mediaCacheWarmCmd.Flags().IntVar(&mediaWarmWorkers, "workers", 4, "number of parallel downloads")
+}
+
+// loadMediaCache opens the cache configured in .stax.yml
+func loadMediaCache() (*media.Cache, error) {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return nil, err
+	}
+	return openMediaCache(cfg, getProjectDir())
+}
+
+func runMediaCacheStats(cmd *cobra.Command, args []string) error {
+	cache, err := loadMediaCache()
+	if err != nil {
+		return err
+	}
+	stats, err := cache.Stats(mediaCacheTop)
+	if err != nil {
+		return err
+	}
+
+	if mediaCacheJSON {
+		return outputJSON(stats)
+	}
+
+	ui.Section("Media Cache")
+	fmt.Printf("  Directory:       %s\n", stats.Directory)
+	fmt.Printf("  Files:           %d\n", stats.Files)
+	if stats.MaxSize > 0 {
+		fmt.Printf("  Size:            %s of %s (%.0f%%)\n", formatBytes(stats.Size), formatBytes(stats.MaxSize), float64(stats.Size)/float64(stats.MaxSize)*100)
+	} else {
+		fmt.Printf("  Size:            %s (unlimited)\n", formatBytes(stats.Size))
+	}
+	fmt.Printf("  Expired:         %d\n", stats.Expired)
+	if stats.Oldest != nil {
+		fmt.Printf("  Oldest:          %s\n", stats.Oldest.Local().Format(time.DateTime))
+		fmt.Printf("  Newest:          %s\n", stats.Newest.Local().Format(time.DateTime))
+	}
+	fmt.Println()
+
+	ui.Section("Requests")
+	if stats.Requests.Total == 0 {
+		fmt.Println("  No requests logged yet")
+	} else {
+		r := stats.Requests
+		fmt.Printf("  Total:           %d\n", r.Total)
+		fmt.Printf("  Local:           %d\n", r.Local)
+		fmt.Printf("  Cache hits:      %d\n", r.Hits)
+		fmt.Printf("  Cache misses:    %d\n", r.Misses)
+		fmt.Printf("  Not found:       %d\n", r.NotFound)
+		if r.Generated > 0 {
+			fmt.Printf("  Generated:       %d\n", r.Generated)
+		}
+		fmt.Printf("  Hit ratio:       %.1f%%\n", r.HitRatio*100)
+	}
+	fmt.Println()
+
+	if len(stats.TopDirs) > 0 {
+		ui.Section("Top Directories")
+		for _, dir := range stats.TopDirs {
+			fmt.Printf("  %-20s %10s  %d files\n", dir.Path, formatBytes(dir.Size), dir.Files)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func runMediaCachePrune(cmd *cobra.Command, args []string) error {
+	cache, err := loadMediaCache()
+	if err != nil {
+		return err
+	}
+	removed, freed, err := cache.Prune()
+	if err != nil {
+		return err
+	}
+	ui.Success("Removed %d files (%s)", removed, formatBytes(freed))
+	return nil
+}
+
+func runMediaCacheClear(cmd *cobra.Command, args []string) error {
+	cache, err := loadMediaCache()
+	if err != nil {
+		return err
+	}
+	if !mediaCacheYes && !ui.Confirm(fmt.Sprintf("Remove every file in %s?", cache.Dir())) {
+		ui.Info("Cancelled")
+		return nil
+	}
+
+	removed, freed, err := cache.Clear()
+	if err != nil {
+		return err
+	}
+	ui.Success("Removed %d files (%s)", removed, formatBytes(freed))
+	return nil
+}
+
+func runMediaCacheWarm(cmd *cobra.Command, args []string) error {
+	if !mediaWarmFromDB && len(args) == 0 {
+		return errors.NewWithSolution(
+			"Nothing to warm",
+			"Pass upload paths or --from-db",
+			errors.Solution{
+				Description: "Warm media for recent posts",
+				Command:     "stax media cache warm --from-db",
+			},
+		)
+	}
+
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
+	projectDir := getProjectDir()
+
+	server, err := newMediaServer(cfg, projectDir, true)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(args))
+	for _, arg := range args {
+		_, key, found := strings.Cut(arg, media.UploadsPrefix)
+		if !found {
+			key = strings.TrimPrefix(arg, "/")
+		}
+		keys = append(keys, key)
+	}
+
+	if mediaWarmFromDB {
+		var since time.Time
+		if mediaWarmSince != "" {
+			if since, err = logs.ParseSince(mediaWarmSince, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		cli := wordpress.NewCLI(projectDir)
+		prefix, err := cli.GetTablePrefix()
+		if err != nil {
+			return fmt.Errorf("failed to read the table prefix: %w", err)
+		}
+		query, err := media.AttachmentQuery(prefix, mediaWarmPosts, since)
+		if err != nil {
+			return err
+		}
+		output, err := cli.ExecuteWithOutput("db", "query", query, "--skip-column-names")
+		if err != nil {
+			return fmt.Errorf("failed to list attachments: %w", err)
+		}
+		keys = append(keys, media.ParseAttachmentRows(output)...)
+	}
+
+	if len(keys) == 0 {
+		ui.Info("No attachments found")
+		return nil
+	}
+
+	ui.Info("Warming %d files", len(keys))
+	counts := make(map[string]int)
+	var fetched int64
+	done := 0
+	server.WarmAll(cmd.Context(), keys, mediaWarmWorkers, func(r media.WarmResult) {
+		done++
+		counts[r.Status]++
+		switch r.Status {
+		case media.WarmFetched:
+			fetched += r.Size
+			ui.Verbose("[%d/%d] %s from %s", done, len(keys), r.Key, r.Source)
+		case media.WarmMissing:
+			ui.Warning("[%d/%d] %s not found on any source", done, len(keys), r.Key)
+		case media.WarmFailed:
+			ui.Warning("[%d/%d] %s: %v", done, len(keys), r.Key, r.Err)
+		default:
+			ui.Verbose("[%d/%d] %s already %s", done, len(keys), r.Key, r.Status)
+		}
+	})
+	if err := cmd.Context().Err(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	ui.Success("Fetched %d files (%s)", counts[media.WarmFetched], formatBytes(fetched))
+	if n := counts[media.WarmCached] + counts[media.WarmLocal]; n > 0 {
+		ui.Info("%d files were already available", n)
+	}
+	if n := counts[media.WarmMissing] + counts[media.WarmFailed]; n > 0 {
+		return fmt.Errorf("%d files could not be warmed", n)
+	}
+	return nil
+}
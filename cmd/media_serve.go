@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/firecrown-media/stax/pkg/config"
+	"github.com/firecrown-media/stax/pkg/ddev"
+	"github.com/firecrown-media/stax/pkg/errors"
+	"github.com/firecrown-media/stax/pkg/media"
+	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/firecrown-media/stax/pkg/wordpress"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mediaServeListen    string
+	mediaServeNoCache   bool
+	mediaServeNoWebConf bool
+	mediaServeResize    bool
+)
+
+var mediaServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the built-in media proxy",
+	Long: `Run a media proxy on the host that serves WordPress uploads.
+
+Each request for /wp-content/uploads/ is answered from, in order:
+  1. The local wp-content/uploads directory
+  2. The media cache (media.cache in .stax.yml)
+  3. Each remote source: media.primary_source first, then BunnyCDN or
+     WPEngine, then media.origins
+
+Fetched files are cached in media.cache.directory. Entries older than
+media.cache.ttl seconds are fetched again, and the least recently used
+entries are removed once the cache grows past media.cache.max_size. See
+'stax media cache' to inspect and manage it.
+
+A snippet is written to .ddev/nginx or .ddev/apache (matching
+ddev.webserver_type) so the DDEV webserver passes missing uploads to the
+proxy. It replaces the configuration from 'stax media setup-proxy'. Restart
+DDEV after the snippet is first written.
+
+With media.resize.enabled (or --resize), size-suffixed images such as
+photo-300x200.jpg that no source has are generated from the original,
+following the sizes registered in WordPress and media.resize.sizes.
+Generated files are cached, or saved into wp-content/uploads with
+media.resize.write_local. WebP sizes need cwebp.
+
+The proxy listens on all interfaces by default so the DDEV containers can
+reach it through host.docker.internal.`,
+	Example: `  # Serve media until interrupted
+  stax media serve
+
+  # Use another port
+  stax media serve --listen :9000
+
+  # Fetch every file from the sources without caching
+  stax media serve --no-cache
+
+  # Generate image sizes missing on production
+  stax media serve --resize`,
+	RunE: runMediaServe,
+}
+
+func init() {
+	mediaCmd.AddCommand(mediaServeCmd)
+
+	mediaServeCmd.Flags().StringVar(&mediaServeListen, "listen", fmt.Sprintf(":%d", ddev.DefaultMediaServePort), "address to listen on")
+	mediaServeCmd.Flags().BoolVar(&mediaServeNoCache, "no-cache", false, "do not cache fetched media")
+	mediaServeCmd.Flags().BoolVar(&mediaServeNoWebConf, "skip-webserver-config", false, "do not write the DDEV webserver snippet")
+	mediaServeCmd.Flags().BoolVar(&mediaServeResize, "resize", false, "generate missing image sizes (also media.resize.enabled)")
+}
+
+func runMediaServe(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
+	projectDir := getProjectDir()
+
+	server, err := newMediaServer(cfg, projectDir, cfg.Media.Cache.Enabled && !mediaServeNoCache)
+	if err != nil {
+		return err
+	}
+	if server.Cache != nil {
+		accessLog, err := server.Cache.OpenAccessLog()
+		if err != nil {
+			return err
+		}
+		defer accessLog.Close()
+		server.AccessLog = accessLog
+	}
+	if cfg.Media.Resize.Enabled || mediaServeResize {
+		server.Resizer = newMediaResizer(cfg, projectDir)
+	}
+
+	listener, err := net.Listen("tcp", mediaServeListen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", mediaServeListen, err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if !mediaServeNoWebConf {
+		changed, err := ddev.GenerateMediaServeConfig(projectDir, cfg.DDEV.WebserverType, port)
+		if err != nil {
+			return err
+		}
+		if changed {
+			ui.Info("Wrote the DDEV webserver config for the media proxy")
+			ui.Info("Run 'stax restart' so DDEV loads it")
+		}
+	}
+
+	ui.Section("Media Proxy")
+	fmt.Printf("  Listening:       %s\n", listener.Addr())
+	fmt.Printf("  Local uploads:   %s\n", server.UploadsDir)
+	for i, src := range server.Sources {
+		fmt.Printf("  Source %d:        %s (%s)\n", i+1, src.URL, src.Name)
+	}
+	for _, route := range server.Routes {
+		names := make([]string, len(route.Sources))
+		for i, src := range route.Sources {
+			names[i] = src.Name
+		}
+		fmt.Printf("  Site %-11s %s -> %s\n", route.Site+":", route.Prefix(), strings.Join(names, ", "))
+	}
+	if server.Cache != nil {
+		fmt.Printf("  Cache:           %s (max %s, ttl %s)\n", server.Cache.Dir(), orUnlimited(cfg.Media.Cache.MaxSize), time.Duration(cfg.Media.Cache.TTL)*time.Second)
+	} else {
+		fmt.Println("  Cache:           disabled")
+	}
+	if server.Resizer != nil {
+		target := "cache"
+		if server.Resizer.WriteLocal {
+			target = "local uploads"
+		}
+		if len(server.Resizer.Sizes) > 0 {
+			fmt.Printf("  Image sizes:     %d sizes, saved to %s\n", len(server.Resizer.Sizes), target)
+		} else {
+			fmt.Printf("  Image sizes:     any size, saved to %s\n", target)
+		}
+	}
+	fmt.Println()
+	ui.Info("Press Ctrl+C to stop")
+
+	httpServer := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			server.ServeHTTP(w, r)
+			ui.Verbose("%s %s <- %s", r.Method, r.URL.Path, w.Header().Get(media.SourceHeader))
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	stop := context.AfterFunc(cmd.Context(), func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(ctx)
+	})
+	defer stop()
+
+	if err := httpServer.Serve(listener); err != nil && !stderrors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("media proxy failed: %w", err)
+	}
+	return nil
+}
+
+// newMediaServer builds the media proxy from .stax.yml
+func newMediaServer(cfg *config.Config, projectDir string, withCache bool) (*media.Server, error) {
+	sources := media.SourcesFromConfig(cfg)
+	routes, unresolved := mediaSiteRoutes(cfg, projectDir)
+	if len(unresolved) > 0 {
+		ui.Warning("No site ID for %s, so their uploads use the main sources", strings.Join(unresolved, ", "))
+		ui.Info("Set network.sites[].id in .stax.yml, or start DDEV so it can be read from WordPress")
+	}
+	if len(sources) == 0 && len(routes) == 0 {
+		return nil, errors.NewWithSolution(
+			"No media sources configured",
+			"The media proxy needs BunnyCDN, WPEngine or media.origins in .stax.yml",
+			errors.Solution{
+				Description: "Configure a media source",
+				Steps: []string{
+					"Set media.bunnycdn.hostname to your pull zone hostname",
+					"Or set wpengine.install and media.wpengine_fallback: true",
+					"Or list URLs under media.origins",
+				},
+			},
+		)
+	}
+
+	server := &media.Server{
+		UploadsDir: filepath.Join(projectDir, "wp-content", "uploads"),
+		Sources:    sources,
+		Routes:     routes,
+		Logf: func(format string, args ...interface{}) {
+			ui.Warning(format, args...)
+		},
+	}
+	if withCache {
+		cache, err := openMediaCache(cfg, projectDir)
+		if err != nil {
+			return nil, err
+		}
+		server.Cache = cache
+	}
+	return server, nil
+}
+
+// mediaSiteRoutes returns the media routes of network sites, reading blog IDs
+// from WordPress for sites without network.sites[].id
+func mediaSiteRoutes(cfg *config.Config, projectDir string) ([]media.SiteRoute, []string) {
+	var siteIDs map[string]int
+	for _, site := range cfg.Network.Sites {
+		if site.ID != 0 {
+			continue
+		}
+		output, err := wordpress.NewCLI(projectDir).ExecuteWithOutput(media.SiteListArgs...)
+		if err == nil {
+			siteIDs, err = media.ParseSiteList(output)
+		}
+		if err != nil {
+			ui.Verbose("Could not read site IDs from WordPress: %v", err)
+		}
+		break
+	}
+	return media.SiteRoutesFromConfig(cfg, siteIDs)
+}
+
+// newMediaResizer builds the image size generator from .stax.yml and the
+// sizes registered in WordPress, when DDEV is running
+func newMediaResizer(cfg *config.Config, projectDir string) *media.Resizer {
+	resizer := &media.Resizer{
+		Quality:    cfg.Media.Resize.Quality,
+		WriteLocal: cfg.Media.Resize.WriteLocal,
+	}
+
+	output, err := wordpress.NewCLI(projectDir).ExecuteWithOutput("eval", media.ImageSizesPHP)
+	if err == nil {
+		var sizes []media.ImageSize
+		if sizes, err = media.ParseImageSizes(output); err == nil {
+			resizer.Sizes = sizes
+		}
+	}
+	if err != nil {
+		ui.Verbose("Could not read image sizes from WordPress: %v", err)
+		if len(cfg.Media.Resize.Sizes) == 0 {
+			ui.Warning("No image sizes known; any size smaller than the original will be generated")
+		}
+	}
+
+	for _, size := range cfg.Media.Resize.Sizes {
+		resizer.Sizes = append(resizer.Sizes, media.ImageSize{
+			Name:   size.Name,
+			Width:  size.Width,
+			Height: size.Height,
+			Crop:   size.Crop,
+		})
+	}
+	return resizer
+}
+
+// openMediaCache opens the media cache configured in .stax.yml
+func openMediaCache(cfg *config.Config, projectDir string) (*media.Cache, error) {
+	dir := cfg.Media.Cache.Directory
+	if dir == "" {
+		dir = config.Defaults().Media.Cache.Directory
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(projectDir, dir)
+	}
+
+	maxSize, err := media.ParseSize(cfg.Media.Cache.MaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid media.cache.max_size: %w", err)
+	}
+	return media.NewCache(dir, maxSize, time.Duration(cfg.Media.Cache.TTL)*time.Second)
+}
+
+// orUnlimited returns size, or "unlimited" when it is empty
+func orUnlimited(size string) string {
+	if size == "" {
+		return "unlimited"
+	}
+	return size
+}
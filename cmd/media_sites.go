@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/config"
+	"github.com/firecrown-media/stax/pkg/errors"
+	"github.com/firecrown-media/stax/pkg/media"
+	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/firecrown-media/stax/pkg/wordpress"
+	"github.com/spf13/cobra"
+)
+
+// runMediaTestSite checks the media route of one network site: where its
+// uploads live, which sources serve them, and whether they have its newest
+// attachment
+func runMediaTestSite(cmd *cobra.Command, slug string) error {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
+	projectDir := getProjectDir()
+
+	site, err := findMediaSite(cfg, slug)
+	if err != nil {
+		return err
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Testing Media Route: %s", slug))
+
+	ui.Section("Route")
+	routes, unresolved := mediaSiteRoutes(cfg, projectDir)
+	prefix, sources := "", media.SourcesFromConfig(cfg)
+	switch i := slices.IndexFunc(routes, func(r media.SiteRoute) bool { return r.Site == slug }); {
+	case i >= 0:
+		prefix, sources = routes[i].Prefix(), routes[i].Sources
+		ui.Success("✓ Site %d, uploads in %s%s", routes[i].ID, media.UploadsPrefix, prefix)
+	case slices.Contains(unresolved, slug):
+		ui.Error("✗ Site ID unknown")
+		return errors.NewWithSolution(
+			fmt.Sprintf("No site ID for %s", slug),
+			"The blog ID decides which sites/<id>/ uploads directory the site uses",
+			errors.Solution{
+				Description: "Tell stax the site ID",
+				Steps: []string{
+					fmt.Sprintf("Set network.sites[].id for %s in .stax.yml", slug),
+					"Or start DDEV so it can be read from WordPress: stax start",
+				},
+			},
+		)
+	default:
+		ui.Success("✓ Main site, uploads in %s", media.UploadsPrefix)
+	}
+
+	if len(sources) == 0 {
+		return errors.NewWithSolution(
+			fmt.Sprintf("No media sources for %s", slug),
+			"The site needs a CDN hostname or a WPEngine install",
+			errors.Solution{
+				Description: "Configure a media source",
+				Steps: []string{
+					fmt.Sprintf("Set network.sites[].cdn_hostname for %s", slug),
+					"Or set wpengine.install and media.wpengine_fallback: true",
+				},
+			},
+		)
+	}
+	for i, src := range sources {
+		if src.Host != "" {
+			fmt.Printf("  Source %d:        %s (%s, Host: %s)\n", i+1, src.URL, src.Name, src.Host)
+		} else {
+			fmt.Printf("  Source %d:        %s (%s)\n", i+1, src.URL, src.Name)
+		}
+	}
+
+	ui.Section("Source Tests")
+	key := ""
+	if file, err := latestAttachment(projectDir, site.Domain); err != nil {
+		ui.Warning("Could not read an attachment from WordPress: %v", err)
+		ui.Info("Checking that the sources respond instead")
+	} else {
+		key = prefix + file
+		ui.Info("Requesting %s%s", media.UploadsPrefix, key)
+	}
+
+	// Without an attachment, any response for the uploads directory shows the
+	// source is reachable
+	probe := key
+	if probe == "" {
+		probe = prefix
+	}
+
+	server := &media.Server{}
+	reachable, found := 0, 0
+	for _, src := range sources {
+		status, err := server.Probe(cmd.Context(), src, probe)
+		switch {
+		case err != nil:
+			ui.Error("✗ %s: %v", src.Name, err)
+		case key == "":
+			reachable++
+			ui.Success("✓ %s responds (%d)", src.Name, status)
+		case status == http.StatusOK:
+			reachable++
+			found++
+			ui.Success("✓ %s serves the file", src.Name)
+		default:
+			reachable++
+			ui.Warning("! %s returned %d", src.Name, status)
+		}
+	}
+	fmt.Println()
+
+	if reachable == 0 {
+		return fmt.Errorf("no media source for %s is reachable", slug)
+	}
+	if key != "" && found == 0 {
+		return errors.NewWithSolution(
+			fmt.Sprintf("No source serves %s", key),
+			"The sources respond, but none has the site's newest attachment",
+			errors.Solution{
+				Description: "Check the site's media settings",
+				Steps: []string{
+					fmt.Sprintf("Check network.sites[].cdn_hostname for %s", slug),
+					fmt.Sprintf("Check network.sites[].wpengine_domain for %s", slug),
+					"Check the site ID matches 'stax wp site list'",
+				},
+			},
+		)
+	}
+
+	ui.Success("Media route for %s works", slug)
+	return nil
+}
+
+// findMediaSite returns the network site with slug
+func findMediaSite(cfg *config.Config, slug string) (*config.SiteConfig, error) {
+	var slugs []string
+	for i, site := range cfg.Network.Sites {
+		if media.SiteSlug(site) == slug {
+			return &cfg.Network.Sites[i], nil
+		}
+		slugs = append(slugs, media.SiteSlug(site))
+	}
+
+	if len(slugs) == 0 {
+		return nil, errors.NewWithSolution(
+			fmt.Sprintf("Site not found: %s", slug),
+			"No network.sites are configured in .stax.yml",
+			errors.Solution{
+				Description: "Test the media proxy of a single site",
+				Command:     "stax media test",
+			},
+		)
+	}
+	return nil, errors.NewWithSolution(
+		fmt.Sprintf("Site not found: %s", slug),
+		fmt.Sprintf("Configured sites: %s", strings.Join(slugs, ", ")),
+		errors.Solution{
+			Description: "Use the slug of a configured site",
+			Command:     fmt.Sprintf("stax media test --site %s", slugs[0]),
+		},
+	)
+}
+
+// latestAttachment returns the upload path of the newest attachment of the
+// site at domain, relative to the site's uploads directory
+func latestAttachment(projectDir, domain string) (string, error) {
+	var url []string
+	if domain != "" {
+		url = append(url, "--url="+domain)
+	}
+
+	cli := wordpress.NewCLI(projectDir)
+	output, err := cli.ExecuteWithOutput(append([]string{"post", "list", "--post_type=attachment", "--post_status=inherit", "--posts_per_page=1", "--orderby=date", "--order=DESC", "--field=ID"}, url...)...)
+	if err != nil {
+		return "", err
+	}
+	id := strings.TrimSpace(output)
+	if id == "" {
+		return "", fmt.Errorf("the site has no attachments")
+	}
+
+	output, err = cli.ExecuteWithOutput(append([]string{"post", "meta", "get", id, "_wp_attached_file"}, url...)...)
+	if err != nil {
+		return "", err
+	}
+	file := strings.TrimSpace(output)
+	if file == "" {
+		return "", fmt.Errorf("attachment %s has no file", id)
+	}
+	return file, nil
+}
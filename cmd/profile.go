@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/firecrown-media/stax/pkg/config"
+	"github.com/firecrown-media/stax/pkg/ddev"
+	"github.com/firecrown-media/stax/pkg/errors"
+	"github.com/firecrown-media/stax/pkg/snapshot"
+	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// profileCmd represents the profile command group
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Switch PHP, database, Xdebug and Node.js profiles",
+	Long: `Switch the environment between the profiles of .stax.yml.
+
+A profile overrides php_version, mysql_version, mysql_type, xdebug_enabled and
+nodejs_version of the ddev section. It is written to
+.ddev/` + ddev.ProfileConfigFile + `, which DDEV loads after config.yaml, so
+config.yaml itself is never changed and 'stax profile reset' goes back to it.
+
+DDEV cannot start on a database created by another version. When a profile
+changes the database type or version, stax snapshots the database, recreates
+it with the new version and imports the snapshot.`,
+}
+
+// profileUseCmd represents the profile use command
+var profileUseCmd = &cobra.Command{
+	Use:   "use <profile>",
+	Short: "Switch to a profile",
+	Args:  cobra.ExactArgs(1),
+	Example: `  # Test the site on PHP 8.3
+  stax profile use php83`,
+	RunE: runProfileUse,
+}
+
+// profileResetCmd represents the profile reset command
+var profileResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Go back to the ddev settings of .stax.yml",
+	Args:  cobra.NoArgs,
+	RunE:  runProfileReset,
+}
+
+// profileListCmd represents the profile list command
+var profileListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the profiles of .stax.yml",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runProfileList,
+}
+
+var (
+	profileYes bool
+)
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileResetCmd)
+	profileCmd.AddCommand(profileListCmd)
+
+	profileCmd.PersistentFlags().BoolVarP(&profileYes, "yes", "y", false, "recreate the database without confirmation")
+}
+
+func runProfileUse(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
+	projectDir := getProjectDir()
+
+	name := args[0]
+	profileCfg, ok := cfg.Profiles[name]
+	if !ok {
+		return errors.NewWithSolution(
+			fmt.Sprintf("Profile %q not found", name),
+			"Profiles are defined in the profiles section of .stax.yml",
+			errors.Solution{
+				Description: "Use one of the configured profiles",
+				Steps: []string{
+					"Run 'stax profile list' to see the profiles",
+					"Or add it to .stax.yml, e.g. profiles: {php83: {php_version: \"8.3\"}}",
+				},
+			},
+		)
+	}
+	if !ddev.IsConfigured(projectDir) {
+		return fmt.Errorf("DDEV is not configured, run 'stax init' first")
+	}
+
+	plan, err := ddev.PlanProfile(projectDir, ddevProfile(name, profileCfg))
+	if err != nil {
+		return err
+	}
+	return switchProfile(projectDir, cfg, plan)
+}
+
+func runProfileReset(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
+	projectDir := getProjectDir()
+
+	plan, err := ddev.PlanProfile(projectDir, nil)
+	if err != nil {
+		return err
+	}
+	if plan.Current == nil {
+		ui.Info("No profile is active")
+		return nil
+	}
+	return switchProfile(projectDir, cfg, plan)
+}
+
+func runProfileList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
+	active, err := ddev.ActiveProfile(getProjectDir())
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Profiles) == 0 {
+		ui.Info("No profiles in .stax.yml")
+		return nil
+	}
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "PROFILE\tPHP\tDATABASE\tXDEBUG\tNODEJS\tDESCRIPTION")
+	for _, name := range names {
+		profile := cfg.Profiles[name]
+		label := name
+		if active != nil && active.Name == name {
+			label += " *"
+		}
+		database := strings.TrimSpace(profile.MySQLType + " " + profile.MySQLVersion)
+		xdebug := ""
+		if profile.XdebugEnabled != nil {
+			xdebug = map[bool]string{true: "on", false: "off"}[*profile.XdebugEnabled]
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", label, orDash(profile.PHPVersion), orDash(database), orDash(xdebug), orDash(profile.NodeJSVersion), profile.Description)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if active != nil {
+		fmt.Println()
+		ui.Info("* active profile")
+	}
+	return nil
+}
+
+// switchProfile applies a profile plan and restarts what it needs to
+func switchProfile(projectDir string, cfg *config.Config, plan *ddev.ProfilePlan) error {
+	target := profileTarget(plan)
+
+	if !plan.HasChanges() {
+		if err := plan.Apply(); err != nil {
+			return err
+		}
+		ui.Success("Switched to %s, DDEV settings are unchanged", target)
+		return nil
+	}
+
+	ui.Section(fmt.Sprintf("Switching to %s", target))
+	printProfileChanges(plan)
+	fmt.Println()
+
+	running := isDDEVRunning(projectDir)
+
+	if plan.DatabaseChanged() {
+		ui.Warning("The database changes from %s to %s", formatDatabase(plan.From.Database), formatDatabase(plan.To.Database))
+		ui.Info("stax will snapshot it, recreate it with the new version and import the snapshot")
+		if !profileYes && !ui.Confirm("Continue?") {
+			ui.Info("Profile switch cancelled")
+			return nil
+		}
+		return switchProfileDatabase(projectDir, cfg, plan, running)
+	}
+
+	if err := plan.Apply(); err != nil {
+		return err
+	}
+
+	switch {
+	case !running:
+		ui.Success("Switched to %s", target)
+		ui.Info("It applies when the environment starts: stax start")
+		return nil
+	case plan.NeedsRestart():
+		if err := ddev.Restart(projectDir); err != nil {
+			return err
+		}
+	case plan.To.XdebugEnabled:
+		if err := ddev.EnableXdebug(projectDir); err != nil {
+			return fmt.Errorf("failed to enable Xdebug: %w", err)
+		}
+	default:
+		if err := ddev.DisableXdebug(projectDir); err != nil {
+			return fmt.Errorf("failed to disable Xdebug: %w", err)
+		}
+	}
+
+	ui.Success("Switched to %s", target)
+	return nil
+}
+
+// switchProfileDatabase snapshots the database, recreates it with the new
+// version and imports the snapshot
+func switchProfileDatabase(projectDir string, cfg *config.Config, plan *ddev.ProfilePlan, running bool) error {
+	// The export needs the containers running with the current version
+	if !running {
+		ui.Info("Starting DDEV to export the database...")
+		if err := ddev.Start(projectDir); err != nil {
+			return err
+		}
+	}
+
+	snapMgr := snapshot.NewManager(cfg, projectDir)
+	ui.Info("Exporting database...")
+	filename, err := snapMgr.CreateSnapshot(cfg.Project.Name, "auto")
+	if err != nil {
+		return fmt.Errorf("failed to snapshot the database, nothing was changed: %w", err)
+	}
+	snapshotPath := filepath.Join(expandPath(cfg.Snapshots.Directory), filename)
+	ui.Success("Database snapshot saved to %s", snapshotPath)
+
+	recreateFailed := func(err error) error {
+		return errors.NewWithSolution(
+			"Failed to recreate the database",
+			err.Error(),
+			errors.Solution{
+				Description: "The database is in the snapshot taken before the switch",
+				Steps: []string{
+					"Fix the problem above and run: stax start",
+					fmt.Sprintf("Then import it: stax db snapshot restore %s", filename),
+					"Or go back to the previous settings: stax profile reset",
+				},
+			},
+		)
+	}
+
+	if err := plan.Apply(); err != nil {
+		return err
+	}
+
+	// Deleting the project removes the containers and the database volume;
+	// the project files and .ddev are kept
+	ui.Info("Recreating the database with %s...", formatDatabase(plan.To.Database))
+	if err := ddev.Delete(projectDir, true); err != nil {
+		return recreateFailed(err)
+	}
+	if err := ddev.Start(projectDir); err != nil {
+		return recreateFailed(err)
+	}
+
+	ui.Info("Importing the snapshot...")
+	if err := snapMgr.RestoreSnapshot(snapshotPath); err != nil {
+		return recreateFailed(err)
+	}
+
+	ui.Success("Switched to %s", profileTarget(plan))
+	if !running {
+		ui.Info("The environment was started for the switch; stop it with: stax stop")
+	}
+	return nil
+}
+
+// printProfileChanges lists the settings a plan changes
+func printProfileChanges(plan *ddev.ProfilePlan) {
+	from, to := plan.From, plan.To
+	changes := []struct {
+		name     string
+		from, to string
+	}{
+		{"PHP", from.PHPVersion, to.PHPVersion},
+		{"Database", formatDatabase(from.Database), formatDatabase(to.Database)},
+		{"Xdebug", getBoolStatus(from.XdebugEnabled), getBoolStatus(to.XdebugEnabled)},
+		{"Node.js", from.NodeJSVersion, to.NodeJSVersion},
+	}
+	for _, change := range changes {
+		if change.from != change.to {
+			fmt.Printf("  %-10s %s → %s\n", change.name+":", orDash(change.from), orDash(change.to))
+		}
+	}
+}
+
+// profileTarget describes what a plan switches to
+func profileTarget(plan *ddev.ProfilePlan) string {
+	if plan.Next == nil {
+		return "the ddev settings of .stax.yml"
+	}
+	return "profile " + plan.Next.Name
+}
+
+// formatDatabase returns the type and version of a database
+func formatDatabase(database ddev.DatabaseConfig) string {
+	return strings.TrimSpace(database.Type + " " + database.Version)
+}
+
+// ddevProfile converts a profile of .stax.yml to its DDEV settings
+func ddevProfile(name string, profile config.ProfileConfig) *ddev.Profile {
+	result := &ddev.Profile{
+		Name:          name,
+		PHPVersion:    profile.PHPVersion,
+		XdebugEnabled: profile.XdebugEnabled,
+		NodeJSVersion: profile.NodeJSVersion,
+	}
+	if profile.MySQLType != "" || profile.MySQLVersion != "" {
+		result.Database = &ddev.DatabaseConfig{Type: profile.MySQLType, Version: profile.MySQLVersion}
+	}
+	return result
+}
@@ -4,10 +4,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/firecrown-media/stax/pkg/provider"
+	"github.com/firecrown-media/stax/pkg/providers/external"
+	"github.com/firecrown-media/stax/pkg/ui"
 	"github.com/spf13/cobra"
+
+	// Built-in providers register themselves on import
+	// The AWS provider is a skeleton and stays unregistered until it is implemented
+	_ "github.com/firecrown-media/stax/pkg/providers/local"
+	_ "github.com/firecrown-media/stax/pkg/providers/wordpress-vip"
+	_ "github.com/firecrown-media/stax/pkg/providers/wpengine"
 )
 
 var providerCmd = &cobra.Command{
@@ -23,8 +32,11 @@ between providers.`,
 var providerListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available providers",
-	Long:  "List all registered hosting providers and their capabilities.",
-	RunE:  runProviderList,
+	Long: `List all registered hosting providers and their capabilities.
+
+Besides the built-in providers, stax discovers external provider plugins:
+executables named stax-provider-<name> in ~/.stax/providers or on PATH.`,
+	RunE: runProviderList,
 }
 
 var providerShowCmd = &cobra.Command{
@@ -51,16 +63,35 @@ var providerTestCmd = &cobra.Command{
 }
 
 var providerCompareCmd = &cobra.Command{
-	Use:   "compare <provider1> <provider2>",
-	Short: "Compare two providers",
-	Long:  "Compare capabilities and features between two providers.",
-	Args:  cobra.ExactArgs(2),
-	RunE:  runProviderCompare,
+	Use:   "compare <provider1> <provider2> [provider...]",
+	Short: "Compare providers",
+	Long: `Compare features between two or more providers.
+
+Support is determined from each provider's live capabilities and from the
+optional interfaces it implements. A feature that is advertised but whose
+interface is missing is reported as "not implemented".`,
+	Example: `  stax provider compare wpengine wordpress-vip
+  stax provider compare wpengine wordpress-vip local --output json`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runProviderCompare,
+}
+
+var providerRecommendCmd = &cobra.Command{
+	Use:   "recommend",
+	Short: "Recommend a provider for required features",
+	Long: `Rank registered providers by how many of the required features they support.
+
+Features: auth, sites, db-export, db-import, files, deploy, envs, backups,
+remote-exec, media, ssh, api, scaling, monitoring, logs`,
+	Example: `  stax provider recommend --needs db-import,backups,ssh
+  stax provider recommend --needs media,wp-cli --output json`,
+	RunE: runProviderRecommend,
 }
 
 var (
 	providerOutputFormat string // json, yaml, table
 	providerShowAll      bool
+	providerNeeds        []string
 )
 
 func init() {
@@ -70,11 +101,33 @@ func init() {
 	providerCmd.AddCommand(providerSetCmd)
 	providerCmd.AddCommand(providerTestCmd)
 	providerCmd.AddCommand(providerCompareCmd)
+	providerCmd.AddCommand(providerRecommendCmd)
 
 	// Flags
 	providerListCmd.Flags().StringVarP(&providerOutputFormat, "output", "o", "table", "Output format (table, json, yaml)")
 	providerShowCmd.Flags().StringVarP(&providerOutputFormat, "output", "o", "table", "Output format (table, json, yaml)")
 	providerCompareCmd.Flags().StringVarP(&providerOutputFormat, "output", "o", "table", "Output format (table, json)")
+	providerRecommendCmd.Flags().StringVarP(&providerOutputFormat, "output", "o", "table", "Output format (table, json)")
+	providerRecommendCmd.Flags().StringSliceVar(&providerNeeds, "needs", nil, "Required features (comma-separated)")
+	providerRecommendCmd.MarkFlagRequired("needs")
+
+	// Only provider commands scan for plugins; a PersistentPreRunE replaces
+	// the root one, so it runs first
+	providerCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := rootCmd.PersistentPreRunE(cmd, args); err != nil {
+			return err
+		}
+		registerExternalProviders()
+		return nil
+	}
+}
+
+// registerExternalProviders registers the provider plugins found on disk
+// External plugins register after the built-in providers, which keep their names
+func registerExternalProviders() {
+	for _, err := range external.RegisterDiscovered() {
+		ui.Warning("%v", err)
+	}
 }
 
 func runProviderList(cmd *cobra.Command, args []string) error {
@@ -97,8 +150,8 @@ func outputProviderListTable(infos []*provider.ProviderInfo) error {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
 	defer w.Flush()
 
-	fmt.Fprintln(w, "PROVIDER\tDESCRIPTION\tDEFAULT\tCAPABILITIES")
-	fmt.Fprintln(w, "--------\t-----------\t-------\t------------")
+	fmt.Fprintln(w, "PROVIDER\tDESCRIPTION\tSOURCE\tDEFAULT\tCAPABILITIES")
+	fmt.Fprintln(w, "--------\t-----------\t------\t-------\t------------")
 
 	for _, info := range infos {
 		defaultMarker := ""
@@ -145,9 +198,15 @@ func outputProviderListTable(infos []*provider.ProviderInfo) error {
 
 		capsSummary := fmt.Sprintf("%d core, %d optional", coreCount, optionalCount)
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+		source := "built-in"
+		if info.Plugin != "" {
+			source = "plugin"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 			info.Name,
 			info.Description,
+			source,
 			defaultMarker,
 			capsSummary,
 		)
@@ -177,6 +236,9 @@ func runProviderShow(cmd *cobra.Command, args []string) error {
 func outputProviderShowTable(info *provider.ProviderInfo) error {
 	fmt.Printf("Provider: %s\n", info.Name)
 	fmt.Printf("Description: %s\n", info.Description)
+	if info.Plugin != "" {
+		fmt.Printf("Plugin: %s\n", info.Plugin)
+	}
 	fmt.Printf("Default: %v\n\n", info.IsDefault)
 
 	fmt.Println("Capabilities:")
@@ -232,10 +294,7 @@ func runProviderTest(cmd *cobra.Command, args []string) error {
 }
 
 func runProviderCompare(cmd *cobra.Command, args []string) error {
-	provider1 := args[0]
-	provider2 := args[1]
-
-	comparison, err := provider.CompareProviders(provider1, provider2)
+	comparison, err := provider.CompareProviders(args...)
 	if err != nil {
 		return fmt.Errorf("failed to compare providers: %w", err)
 	}
@@ -249,35 +308,82 @@ func runProviderCompare(cmd *cobra.Command, args []string) error {
 }
 
 func outputProviderComparisonTable(comparison *provider.ProviderComparison) error {
-	fmt.Printf("Comparing: %s vs %s\n\n", comparison.Provider1, comparison.Provider2)
+	fmt.Printf("Comparing: %s\n\n", strings.Join(comparison.Providers, " vs "))
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	defer w.Flush()
 
-	fmt.Fprintln(w, "CAPABILITY\t"+comparison.Provider1+"\t"+comparison.Provider2)
-	fmt.Fprintln(w, "----------\t----------\t----------")
+	header := []string{"FEATURE"}
+	divider := []string{"-------"}
+	for _, name := range comparison.Providers {
+		header = append(header, strings.ToUpper(name))
+		divider = append(divider, strings.Repeat("-", len(name)))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	fmt.Fprintln(w, strings.Join(divider, "\t"))
+
+	notImplemented := false
+	for _, row := range comparison.Features {
+		cells := []string{row.Feature.Name}
+		for _, name := range comparison.Providers {
+			support := row.Support[name]
+			switch support.Status {
+			case provider.SupportYes:
+				cells = append(cells, "yes")
+			case provider.SupportNotImplemented:
+				cells = append(cells, "no*")
+				notImplemented = true
+			default:
+				cells = append(cells, "no")
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	w.Flush()
 
-	caps1 := comparison.Capabilities1
-	caps2 := comparison.Capabilities2
+	if notImplemented {
+		fmt.Println("\n* advertised by the provider but its interface is not implemented")
+	}
 
-	printCapabilityRow := func(name string, cap1, cap2 bool) {
-		fmt.Fprintf(w, "%s\t%v\t%v\n", name, cap1, cap2)
+	fmt.Printf("\nShared Features: %d of %d\n", len(comparison.SharedFeatures), len(comparison.Features))
+
+	return nil
+}
+
+func runProviderRecommend(cmd *cobra.Command, args []string) error {
+	recommendations, err := provider.RecommendProviders(providerNeeds)
+	if err != nil {
+		return fmt.Errorf("failed to recommend a provider: %w", err)
+	}
+
+	switch providerOutputFormat {
+	case "json":
+		return outputJSON(recommendations)
+	default:
+		return outputProviderRecommendTable(recommendations)
 	}
+}
+
+func outputProviderRecommendTable(recommendations []provider.Recommendation) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(w, "PROVIDER\tMATCH\tMISSING")
+	fmt.Fprintln(w, "--------\t-----\t-------")
 
-	printCapabilityRow("Authentication", caps1.Authentication, caps2.Authentication)
-	printCapabilityRow("Site Management", caps1.SiteManagement, caps2.SiteManagement)
-	printCapabilityRow("Database Export", caps1.DatabaseExport, caps2.DatabaseExport)
-	printCapabilityRow("Database Import", caps1.DatabaseImport, caps2.DatabaseImport)
-	printCapabilityRow("File Sync", caps1.FileSync, caps2.FileSync)
-	printCapabilityRow("Deployment", caps1.Deployment, caps2.Deployment)
-	printCapabilityRow("Environments", caps1.Environments, caps2.Environments)
-	printCapabilityRow("Backups", caps1.Backups, caps2.Backups)
-	printCapabilityRow("Remote Execution", caps1.RemoteExecution, caps2.RemoteExecution)
-	printCapabilityRow("Media Management", caps1.MediaManagement, caps2.MediaManagement)
-	printCapabilityRow("SSH Access", caps1.SSHAccess, caps2.SSHAccess)
-	printCapabilityRow("API Access", caps1.APIAccess, caps2.APIAccess)
-
-	fmt.Fprintf(w, "\nShared Features: %d\n", len(comparison.SharedFeatures))
+	for _, rec := range recommendations {
+		missing := "-"
+		if len(rec.Missing) > 0 {
+			missing = strings.Join(rec.Missing, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%d/%d\t%s\n", rec.Provider, rec.Score, rec.Total, missing)
+	}
+	w.Flush()
+
+	fmt.Println()
+	if len(recommendations) > 0 && recommendations[0].Matches {
+		ui.Success("Recommended: %s", recommendations[0].Provider)
+	} else {
+		ui.Warning("No provider supports every required feature")
+	}
 
 	return nil
 }
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/firecrown-media/stax/pkg/credentials"
+	"github.com/firecrown-media/stax/pkg/diagnostics"
+	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/firecrown-media/stax/pkg/wpengine"
+	"github.com/spf13/cobra"
+)
+
+var (
+	remoteDoctorJSON   bool
+	remoteDoctorExport string
+	remoteDoctorEnv    string
+)
+
+// remoteCmd groups commands that inspect the WPEngine install
+var remoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "Inspect the WPEngine install",
+	Long:  `Commands that run against the WPEngine install configured in .stax.yml.`,
+}
+
+// remoteDoctorCmd reports on the health of the WPEngine install
+var remoteDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the health of the WPEngine install",
+	Long: `Run health checks against the WPEngine install over SSH and the API.
+
+This command checks:
+  Versions:
+  - PHP, MySQL and WordPress versions against the ddev and wordpress settings in .stax.yml
+
+  Updates:
+  - Plugins with updates available
+
+  Integrity:
+  - WordPress core files against the official checksums
+
+  Performance:
+  - Overdue WP-Cron events
+  - Size of autoloaded options
+  - Persistent object cache
+
+  Resources:
+  - Disk usage reported by the WPEngine API
+
+The install is wpengine.install, or with --env staging
+wpengine.staging_install. The report uses the same table and JSON output as
+'stax doctor'.`,
+	Example: `  # Check the install
+  stax remote doctor
+
+  # Check the staging install
+  stax remote doctor --env staging
+
+  # Show JSON output
+  stax remote doctor --json
+
+  # Export report to file
+  stax remote doctor --export=remote.json`,
+	RunE: runRemoteDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(remoteCmd)
+	remoteCmd.AddCommand(remoteDoctorCmd)
+
+	remoteDoctorCmd.Flags().BoolVar(&remoteDoctorJSON, "json", false, "output results as JSON")
+	remoteDoctorCmd.Flags().StringVar(&remoteDoctorExport, "export", "", "export report to file (supports .json or .txt)")
+	remoteDoctorCmd.Flags().StringVar(&remoteDoctorEnv, "env", "", "WPEngine environment to check: production or staging (default: wpengine.install)")
+}
+
+func runRemoteDoctor(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
+
+	switch remoteDoctorEnv {
+	case "", "production", "staging":
+	default:
+		return fmt.Errorf("--env must be 'staging' or 'production', got: %s", remoteDoctorEnv)
+	}
+
+	client, err := connectWPEngineSSH(remoteDoctorEnv)
+	if err != nil {
+		return err
+	}
+
+	interactive := !remoteDoctorJSON && remoteDoctorExport == ""
+	if interactive {
+		ui.PrintHeader(fmt.Sprintf("Checking WPEngine install %s", client.Install()))
+		fmt.Println()
+	}
+
+	target := diagnostics.RemoteTarget{
+		Install:     client.Install(),
+		Environment: cfg.WPEngine.EnvironmentOf(client.Install()),
+		WPCLI: func(args ...string) (string, error) {
+			return client.GetWPCLI(args)
+		},
+		PHPVersion:       cfg.DDEV.PHPVersion,
+		MySQLVersion:     cfg.DDEV.MySQLVersion,
+		WordPressVersion: cfg.WordPress.Version,
+	}
+
+	// The API adds disk usage and the MySQL version; the SSH checks run without it
+	if creds, err := credentials.GetWPEngineCredentialsWithFallback(client.Install()); err == nil {
+		api := wpengine.NewClient(creds.APIUser, creds.APIPassword, client.Install())
+		details, err := api.GetInstallByNameContext(cmd.Context(), client.Install())
+		if err != nil {
+			ui.Verbose("Install details unavailable: %v", err)
+		}
+		target.Details = details
+	}
+
+	var spinner *ui.Spinner
+	if interactive {
+		spinner = ui.NewSpinner("Running remote checks...")
+		spinner.Start()
+	}
+	report := diagnostics.RunRemoteChecks(target, verbose)
+	if spinner != nil {
+		spinner.Stop()
+	}
+
+	if remoteDoctorExport != "" {
+		return exportReport(report, remoteDoctorExport, diagnostics.RemoteCategories)
+	}
+	if remoteDoctorJSON {
+		return outputDoctorJSON(report)
+	}
+
+	printDoctorChecks(report, diagnostics.RemoteCategories)
+	printDoctorSummary(report)
+
+	switch {
+	case report.IsHealthy():
+		ui.Success("Install is healthy - all checks passed!")
+	case report.HasCriticalFailures():
+		ui.Error("Critical issues found on the install")
+		fmt.Println()
+		ui.Info("Review the failures above and follow the suggestions to fix them.")
+	case report.HasWarnings():
+		ui.Warning("Install is functional but has warnings")
+	}
+
+	return nil
+}
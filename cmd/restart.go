@@ -96,7 +96,7 @@ func runRestart(cmd *cobra.Command, args []string) error {
 	// Wait for services
 	ui.Info("Waiting for services to be ready...")
 	if err := waitForServices(projectDir); err != nil {
-		ui.Warning("Services may not be fully ready yet")
+		ui.Info("You can check status with: stax status")
 	}
 
 	// Enable Xdebug if requested
@@ -1,13 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
 
 	"github.com/firecrown-media/stax/pkg/config"
+	"github.com/firecrown-media/stax/pkg/errors"
 	"github.com/firecrown-media/stax/pkg/ui"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/ssh"
 )
 
 var (
@@ -46,9 +54,9 @@ Features:
 	Version: Version,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Commands that don't require .stax.yml config
-		skipConfigCommands := []string{"setup", "version", "completion", "man", "list", "doctor", "init", "start", "stop", "restart", "status", "wpengine", "config"}
+		skipConfigCommands := []string{"setup", "version", "completion", "man", "list", "doctor", "init", "start", "stop", "restart", "status", "wpengine", "config", "ssh-control", "wp", "logs", "remote"}
 		for _, skipCmd := range skipConfigCommands {
-			if cmd.Name() == skipCmd {
+			if cmd.Name() == skipCmd || isWPEngineCommand(cmd) {
 				// Still initialize UI
 				ui.SetVerbose(verbose)
 				ui.SetDebug(debug)
@@ -77,8 +85,96 @@ Features:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The command context is cancelled on the first interrupt; a second interrupt exits immediately
 func Execute() error {
-	return rootCmd.Execute()
+	rootCmd.SilenceErrors = true
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer closeSSHManager()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	if err != nil {
+		var exitErr *exitCodeError
+		if stderrors.As(err, &exitErr) {
+			// The command has already reported its own failure
+			return err
+		}
+		if stderrors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, "Interrupted")
+			return err
+		}
+		printError(cmd, errors.Enhance(err))
+	}
+
+	return err
+}
+
+// exitCodeError makes stax exit with a wrapped command's exit code
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	var exitErr *exitCodeError
+	if stderrors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return 1
+}
+
+// commandExitError turns a failed local or remote command into the exit
+// code stax should return
+func commandExitError(err error) error {
+	var remote *ssh.ExitError
+	if stderrors.As(err, &remote) {
+		return &exitCodeError{code: remote.ExitStatus()}
+	}
+	var local *exec.ExitError
+	if stderrors.As(err, &local) {
+		return &exitCodeError{code: local.ExitCode()}
+	}
+	return err
+}
+
+// printError reports a command error, as JSON on stdout when the command was asked for JSON output
+func printError(cmd *cobra.Command, err error) {
+	if cmd != nil && wantsJSONOutput(cmd) {
+		var enhanced *errors.EnhancedError
+		if !stderrors.As(err, &enhanced) {
+			enhanced = &errors.EnhancedError{Message: err.Error()}
+		}
+
+		data, marshalErr := json.MarshalIndent(map[string]interface{}{"error": enhanced}, "", "  ")
+		if marshalErr == nil {
+			fmt.Println(string(data))
+			return
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "Error:", err)
+}
+
+// wantsJSONOutput reports whether the command was invoked with --json or --output json
+func wantsJSONOutput(cmd *cobra.Command) bool {
+	if flag := cmd.Flags().Lookup("json"); flag != nil && flag.Value.String() == "true" {
+		return true
+	}
+
+	if flag := cmd.Flags().Lookup("output"); flag != nil && flag.Value.String() == "json" {
+		return true
+	}
+
+	return false
 }
 
 func init() {
@@ -152,3 +248,13 @@ func getProjectDir() string {
 	}
 	return dir
 }
+
+// isWPEngineCommand reports whether cmd is part of the global wpengine command group
+func isWPEngineCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == wpengineCmd {
+			return true
+		}
+	}
+	return false
+}
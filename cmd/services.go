@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/firecrown-media/stax/pkg/config"
+	"github.com/firecrown-media/stax/pkg/ddev"
+	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/firecrown-media/stax/pkg/wordpress"
+	"github.com/spf13/cobra"
+)
+
+// servicesCmd represents the services command group
+var servicesCmd = &cobra.Command{
+	Use:   "services",
+	Short: "Manage extra DDEV services",
+	Long: `Manage the extra services (` + strings.Join(ddev.ServiceNames(), ", ") + `) run
+alongside the web and database containers.
+
+Services are listed in the services section of .stax.yml. Each one is generated
+as .ddev/docker-compose.<name>.yaml, and the WordPress constants that point
+WordPress at it (WP_REDIS_HOST, EP_HOST, ...) are added to wordpress.constants.
+Restart the environment to start or stop a service.`,
+}
+
+// servicesAddCmd represents the services add command
+var servicesAddCmd = &cobra.Command{
+	Use:   "add <service>...",
+	Short: "Add services to the project",
+	Args:  cobra.MinimumNArgs(1),
+	Example: `  # Add Redis object cache
+  stax services add redis
+
+  # Add a specific Elasticsearch version
+  stax services add elasticsearch --version 7.17.24`,
+	RunE: runServicesAdd,
+}
+
+// servicesRemoveCmd represents the services remove command
+var servicesRemoveCmd = &cobra.Command{
+	Use:     "remove <service>...",
+	Short:   "Remove services from the project",
+	Aliases: []string{"rm"},
+	Args:    cobra.MinimumNArgs(1),
+	Example: `  stax services remove redis`,
+	RunE:    runServicesRemove,
+}
+
+// servicesListCmd represents the services list command
+var servicesListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List available and configured services",
+	Aliases: []string{"ls"},
+	RunE:    runServicesList,
+}
+
+var (
+	servicesVersion string
+	servicesJSON    bool
+)
+
+func init() {
+	rootCmd.AddCommand(servicesCmd)
+	servicesCmd.AddCommand(servicesAddCmd)
+	servicesCmd.AddCommand(servicesRemoveCmd)
+	servicesCmd.AddCommand(servicesListCmd)
+
+	servicesAddCmd.Flags().StringVar(&servicesVersion, "version", "", "image tag to run instead of the default")
+	servicesListCmd.Flags().BoolVar(&servicesJSON, "json", false, "output as JSON")
+}
+
+func runServicesAdd(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
+	projectDir := getProjectDir()
+	cfgPath := projectConfigPath(projectDir)
+
+	var added []ddev.ServiceDefinition
+	for _, name := range args {
+		service, err := ddev.LookupService(name)
+		if err != nil {
+			return err
+		}
+		if existing, ok := cfg.Services[name]; ok && (servicesVersion == "" || existing.Version == servicesVersion) {
+			ui.Info("%s is already configured", name)
+			continue
+		}
+
+		serviceCfg := config.ServiceConfig{Version: servicesVersion}
+		if err := config.SetFileValue(cfgPath, "services."+name, serviceCfg); err != nil {
+			return err
+		}
+		if cfg.Services == nil {
+			cfg.Services = make(map[string]config.ServiceConfig)
+		}
+		cfg.Services[name] = serviceCfg
+
+		// Constants set by hand win over the service defaults
+		for _, constant := range sortedKeys(service.Constants) {
+			if _, ok := cfg.WordPress.Constants[constant]; ok {
+				continue
+			}
+			if err := config.SetFileValue(cfgPath, "wordpress.constants."+constant, service.Constants[constant]); err != nil {
+				return err
+			}
+			if cfg.WordPress.Constants == nil {
+				cfg.WordPress.Constants = make(map[string]interface{})
+			}
+			cfg.WordPress.Constants[constant] = service.Constants[constant]
+		}
+		added = append(added, service)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	if err := applyServiceFiles(projectDir, cfg); err != nil {
+		return err
+	}
+
+	running := isDDEVRunning(projectDir)
+	for _, service := range added {
+		ui.Success("Added %s", service.Name)
+		for _, constant := range sortedKeys(service.Constants) {
+			value := cfg.WordPress.Constants[constant]
+			ui.Info("  %s = %v", constant, value)
+			if running {
+				if err := wordpress.NewCLI(projectDir).SetConstant(constant, value); err != nil {
+					ui.Warning("Failed to set %s in wp-config.php: %v", constant, err)
+				}
+			}
+		}
+	}
+
+	printServicesRestartHint(running)
+	return nil
+}
+
+func runServicesRemove(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return err
+	}
+	projectDir := getProjectDir()
+	cfgPath := projectConfigPath(projectDir)
+	running := isDDEVRunning(projectDir)
+
+	removed := 0
+	for _, name := range args {
+		service, err := ddev.LookupService(name)
+		if err != nil {
+			return err
+		}
+		if _, ok := cfg.Services[name]; !ok {
+			ui.Info("%s is not configured", name)
+			continue
+		}
+
+		if err := config.SetFileValue(cfgPath, "services."+name, nil); err != nil {
+			return err
+		}
+		delete(cfg.Services, name)
+
+		// Constants changed by hand are kept, they may point somewhere else
+		for _, constant := range sortedKeys(service.Constants) {
+			value, ok := cfg.WordPress.Constants[constant]
+			if !ok || fmt.Sprint(value) != fmt.Sprint(service.Constants[constant]) {
+				continue
+			}
+			if err := config.SetFileValue(cfgPath, "wordpress.constants."+constant, nil); err != nil {
+				return err
+			}
+			delete(cfg.WordPress.Constants, constant)
+			if running {
+				if err := wordpress.NewCLI(projectDir).DeleteConstant(constant); err != nil {
+					ui.Warning("Failed to remove %s from wp-config.php: %v", constant, err)
+				}
+			}
+		}
+
+		ui.Success("Removed %s", name)
+		removed++
+	}
+	if removed == 0 {
+		return nil
+	}
+
+	if err := applyServiceFiles(projectDir, cfg); err != nil {
+		return err
+	}
+	printServicesRestartHint(running)
+	return nil
+}
+
+// serviceListEntry is a row of stax services list
+type serviceListEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+	Configured  bool   `json:"configured"`
+	State       string `json:"state,omitempty"`
+	Health      string `json:"health,omitempty"`
+}
+
+func runServicesList(cmd *cobra.Command, args []string) error {
+	// The available services are listed even outside a project
+	cfg, _ := loadConfigForCommand()
+	projectDir := getProjectDir()
+
+	states := make(map[string]ddev.ServiceStatus)
+	if ddev.IsConfigured(projectDir) {
+		if status, err := ddev.GetStatus(projectDir); err == nil {
+			for _, service := range status.Services {
+				states[service.Name] = service
+			}
+		}
+	}
+
+	var entries []serviceListEntry
+	for _, name := range ddev.ServiceNames() {
+		service := ddev.Services[name]
+		entry := serviceListEntry{
+			Name:        name,
+			Description: service.Description,
+			Image:       ddev.ServiceImage(service, ddev.ServiceOptions{}),
+		}
+		if cfg != nil {
+			if serviceCfg, ok := cfg.Services[name]; ok {
+				entry.Configured = true
+				entry.Image = ddev.ServiceImage(service, ddev.ServiceOptions{Version: serviceCfg.Version, Image: serviceCfg.Image})
+			}
+		}
+		if state, ok := states[name]; ok {
+			entry.State = state.State
+			entry.Health = state.Health
+		}
+		entries = append(entries, entry)
+	}
+
+	if servicesJSON {
+		return outputJSON(entries)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tDESCRIPTION\tIMAGE\tCONFIGURED\tSTATE")
+	for _, entry := range entries {
+		configured := "-"
+		if entry.Configured {
+			configured = "✓"
+		}
+		state := entry.State
+		if entry.Health != "" {
+			state += " (" + entry.Health + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.Name, entry.Description, entry.Image, configured, orDash(state))
+	}
+	return w.Flush()
+}
+
+// ddevServiceOptions converts the services of .stax.yml to generation options,
+// sorted by name
+func ddevServiceOptions(cfg *config.Config) []ddev.ServiceOptions {
+	var services []ddev.ServiceOptions
+	for name, service := range cfg.Services {
+		services = append(services, ddev.ServiceOptions{
+			Name:    name,
+			Version: service.Version,
+			Image:   service.Image,
+		})
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services
+}
+
+// applyServiceFiles writes the docker-compose files of the configured services
+// and removes those of services no longer configured
+func applyServiceFiles(projectDir string, cfg *config.Config) error {
+	files, err := ddev.PlanServices(projectDir, ddevServiceOptions(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to generate DDEV services: %w", err)
+	}
+	if err := ddev.ApplyGeneratedFiles(projectDir, files); err != nil {
+		return err
+	}
+	printDDEVFileChanges(files)
+	return nil
+}
+
+// applyWordPressConstants defines the wordpress.constants of .stax.yml in
+// wp-config.php
+func applyWordPressConstants(projectDir string, cfg *config.Config) error {
+	cli := wordpress.NewCLI(projectDir)
+	for _, name := range sortedKeys(cfg.WordPress.Constants) {
+		if err := cli.SetConstant(name, cfg.WordPress.Constants[name]); err != nil {
+			return fmt.Errorf("failed to set %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// projectConfigPath returns the .stax.yml that project commands write to
+func projectConfigPath(projectDir string) string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	return config.GetProjectConfigPath(projectDir)
+}
+
+// isDDEVRunning reports whether the project containers are up
+func isDDEVRunning(projectDir string) bool {
+	status, err := ddev.GetStatus(projectDir)
+	return err == nil && status.Running
+}
+
+// printServicesRestartHint tells the user how to apply service changes
+func printServicesRestartHint(running bool) {
+	if running {
+		ui.Info("Run 'stax restart' to start or stop the services")
+	} else {
+		ui.Info("The services start with the environment: stax start")
+	}
+}
+
+// sortedKeys returns the keys of m, sorted
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/ddev"
+	"github.com/firecrown-media/stax/pkg/wpengine"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var sshEnvironment string
+
+// sshCmd opens a shell in the DDEV web container or on WPEngine
+var sshCmd = &cobra.Command{
+	Use:   "ssh [command]",
+	Short: "Open a shell locally or on WPEngine",
+	Long: `Open an interactive shell in the DDEV web container, or on a WPEngine
+environment with --env. A command runs once instead of opening a shell.
+
+Remote shells use stax's SSH connection, so ssh-agent, ~/.ssh/config and the
+stored WPEngine key all work without a separate ssh setup. The remote terminal
+follows local window resizes.
+
+production connects to wpengine.install and staging to wpengine.staging_install.`,
+	Example: `  # Shell in the web container
+  stax ssh
+
+  # Shell on the production install
+  stax ssh --env production
+
+  # Run one command on staging
+  stax ssh --env staging "ls -la wp-content/uploads"`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runSSH,
+}
+
+func init() {
+	rootCmd.AddCommand(sshCmd)
+
+	sshCmd.Flags().StringVar(&sshEnvironment, "env", "", "WPEngine environment to connect to (production or staging)")
+}
+
+func runSSH(cmd *cobra.Command, args []string) error {
+	command := strings.Join(args, " ")
+
+	switch sshEnvironment {
+	case "":
+		mgr := ddev.NewManager(getProjectDir())
+		if command == "" {
+			return commandExitError(mgr.SSH(""))
+		}
+		return commandExitError(mgr.Exec([]string{"bash", "-c", command}, nil))
+	case "production", "staging":
+	default:
+		return fmt.Errorf("--env must be 'staging' or 'production', got: %s", sshEnvironment)
+	}
+
+	client, err := connectWPEngineSSH(sshEnvironment)
+	if err != nil {
+		return err
+	}
+
+	stdin := int(os.Stdin.Fd())
+	if !term.IsTerminal(stdin) {
+		// Without a terminal there is nothing to allocate a PTY for
+		if command == "" {
+			return fmt.Errorf("an interactive shell needs a terminal; pass a command to run instead")
+		}
+		return commandExitError(client.ExecuteCommandWithOutput(command, os.Stdout, os.Stderr))
+	}
+
+	opts := wpengine.ShellOptions{
+		Command: command,
+		Term:    os.Getenv("TERM"),
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+	}
+	if width, height, err := term.GetSize(stdin); err == nil {
+		opts.Size = wpengine.WindowSize{Width: width, Height: height}
+	}
+
+	resize, stopResize := watchWindowSize(stdin)
+	defer stopResize()
+	opts.Resize = resize
+
+	state, err := term.MakeRaw(stdin)
+	if err != nil {
+		return fmt.Errorf("failed to put terminal in raw mode: %w", err)
+	}
+	defer term.Restore(stdin, state)
+
+	return commandExitError(client.Shell(opts))
+}
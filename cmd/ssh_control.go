@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/firecrown-media/stax/pkg/wpengine"
+	"github.com/spf13/cobra"
+)
+
+// sshControlCmd is the remote shell rsync runs to reuse the command's SSH connection
+var sshControlCmd = &cobra.Command{
+	Use:                "ssh-control --socket <path> [options] host command...",
+	Short:              "Run a remote command over a stax SSH control socket",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	SilenceUsage:       true,
+	RunE:               runSSHControl,
+}
+
+func init() {
+	rootCmd.AddCommand(sshControlCmd)
+}
+
+// runSSHControl relays one command through the control socket and exits with its status
+func runSSHControl(cmd *cobra.Command, args []string) error {
+	if len(args) < 2 || args[0] != "--socket" {
+		return fmt.Errorf("usage: stax %s", cmd.Use)
+	}
+
+	command, err := wpengine.ParseControlArgs(args[2:])
+	if err != nil {
+		return err
+	}
+
+	status, err := wpengine.RunControlCommand(args[1], command, os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		return err
+	}
+	os.Exit(status)
+	return nil
+}
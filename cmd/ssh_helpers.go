@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/firecrown-media/stax/pkg/credentials"
+	"github.com/firecrown-media/stax/pkg/errors"
+	"github.com/firecrown-media/stax/pkg/prompts"
+	"github.com/firecrown-media/stax/pkg/wpengine"
+)
+
+var (
+	sshManagerOnce sync.Once
+	sshManager     *wpengine.SSHManager
+)
+
+// wpengineSSH returns a client sharing one SSH connection per install for the whole command
+func wpengineSSH(config wpengine.SSHConfig) (*wpengine.SSHClient, error) {
+	sshManagerOnce.Do(func() {
+		opts := wpengine.SSHManagerOptions{}
+		// rsync reaches the shared connection by running this binary as its remote shell
+		if exe, err := os.Executable(); err == nil {
+			opts.ControlHelper = []string{exe, "ssh-control"}
+		}
+		sshManager = wpengine.NewSSHManager(opts)
+	})
+	if config.Passphrase == nil {
+		config.Passphrase = prompts.PromptPassword
+	}
+	return sshManager.Client(config)
+}
+
+// closeSSHManager closes the shared SSH connections, if any were opened
+func closeSSHManager() {
+	if sshManager != nil {
+		sshManager.Close()
+	}
+}
+
+// wpengineSSHKey loads the stored SSH key; without one, keys may still come
+// from ssh-agent or IdentityFile entries in ~/.ssh/config for the gateway
+func wpengineSSHKey(gateway string) (string, error) {
+	key, err := credentials.GetSSHPrivateKeyWithFallback("wpengine")
+	if err == nil {
+		return key, nil
+	}
+
+	var keyErr *credentials.SSHKeyNotFoundError
+	if !stderrors.As(err, &keyErr) {
+		return "", fmt.Errorf("failed to get SSH key: %w", err)
+	}
+
+	if gateway == "" {
+		gateway = wpengine.DefaultSSHGateway
+	}
+	if wpengine.AgentAvailable() {
+		return "", nil
+	}
+	if settings, err := wpengine.LoadSSHHostSettings(wpengine.DefaultSSHConfigFile(), gateway); err == nil && len(settings.IdentityFiles) > 0 {
+		return "", nil
+	}
+
+	tried := append(keyErr.Tried, "SSH agent (SSH_AUTH_SOCK)", "IdentityFile in ~/.ssh/config")
+	return "", errors.NewSSHKeyNotFoundError("", tried, keyErr.LastErr)
+}
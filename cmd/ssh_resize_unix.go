@@ -0,0 +1,44 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/firecrown-media/stax/pkg/wpengine"
+	"golang.org/x/term"
+)
+
+// watchWindowSize reports the terminal's new size after each SIGWINCH
+func watchWindowSize(fd int) (<-chan wpengine.WindowSize, func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+
+	sizes := make(chan wpengine.WindowSize, 1)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-signals:
+				width, height, err := term.GetSize(fd)
+				if err != nil {
+					continue
+				}
+				select {
+				case sizes <- wpengine.WindowSize{Width: width, Height: height}:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return sizes, func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build windows
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/firecrown-media/stax/pkg/wpengine"
+	"golang.org/x/term"
+)
+
+// watchWindowSize polls the console size, as Windows has no SIGWINCH
+func watchWindowSize(fd int) (<-chan wpengine.WindowSize, func()) {
+	sizes := make(chan wpengine.WindowSize, 1)
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+
+		width, height, _ := term.GetSize(fd)
+		for {
+			select {
+			case <-ticker.C:
+				w, h, err := term.GetSize(fd)
+				if err != nil || (w == width && h == height) {
+					continue
+				}
+				width, height = w, h
+				select {
+				case sizes <- wpengine.WindowSize{Width: w, Height: h}:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return sizes, func() { close(done) }
+}
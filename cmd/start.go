@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"os"
 	"time"
@@ -133,10 +135,16 @@ func runStart(cmd *cobra.Command, args []string) error {
 	// 4. Wait for services to be ready
 	ui.Info("Waiting for services to be ready...")
 	if err := waitForServices(projectDir); err != nil {
-		ui.Warning("Services may not be fully ready yet")
 		ui.Info("You can check status with: stax status")
 	}
 
+	// The wordpress.constants of .stax.yml, such as those of stax services
+	if cfg != nil && len(cfg.WordPress.Constants) > 0 {
+		if err := applyWordPressConstants(projectDir, cfg); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to apply WordPress constants: %v", err))
+		}
+	}
+
 	// 5. Enable Xdebug if requested
 	if startXdebug {
 		spinner = ui.NewSpinner("Enabling Xdebug")
@@ -254,17 +262,54 @@ func validateStartPrerequisites(projectDir string) error {
 	return nil
 }
 
-// waitForServices waits for all services to be ready
+// waitForServices runs the readiness probes, printing each one as it
+// finishes and why any of them failed
 func waitForServices(projectDir string) error {
-	maxAttempts := 30
-	for i := 0; i < maxAttempts; i++ {
-		status, err := ddev.GetStatus(projectDir)
-		if err == nil && status.Running && status.Healthy {
-			return nil
+	err := ddev.NewManager(projectDir).WaitForReadiness(context.Background(), ddev.ReadinessOptions{
+		OnEvent: printProbeEvent,
+	})
+
+	var readiness *ddev.ReadinessError
+	if stderrors.As(err, &readiness) {
+		printReadinessFailures(readiness)
+	} else if err != nil {
+		ui.Warning("Failed to check services: %v", err)
+	}
+	return err
+}
+
+// printProbeEvent prints the progress of a readiness probe
+func printProbeEvent(event ddev.ProbeEvent) {
+	switch event.Type {
+	case ddev.ProbeStarted:
+		ui.Verbose("Checking %s...", event.Probe)
+	case ddev.ProbeRetrying:
+		ui.Verbose("%s not ready (attempt %d): %s", event.Probe, event.Attempt, event.Reason)
+	case ddev.ProbeReady:
+		ui.Success("%s ready (%s)", event.Probe, event.Elapsed.Round(100*time.Millisecond))
+	case ddev.ProbeFailed:
+		ui.Warning("%s not ready: %s", event.Probe, event.Reason)
+	}
+}
+
+// printReadinessFailures suggests what to do about each failed probe
+func printReadinessFailures(readiness *ddev.ReadinessError) {
+	for _, failure := range readiness.Failures {
+		switch {
+		case failure.Skipped:
+			ui.Info("%s: %s", failure.Probe, failure.Reason)
+		case failure.Probe == ddev.ProbeContainers:
+			ui.Info("Check the container logs: stax logs --service <name>")
+		case failure.Probe == ddev.ProbeDatabase:
+			ui.Info("Check the database logs: stax logs --service db")
+		case failure.Probe == ddev.ProbeWeb:
+			ui.Info("Check the web server logs: stax logs --service web")
+		case failure.Probe == ddev.ProbeWordPress && failure.Reason == "WordPress is not installed":
+			ui.Info("Pull the database with: stax db pull")
+		case failure.Probe == ddev.ProbeWordPress:
+			ui.Info("Check WordPress with: stax wp core is-installed")
 		}
-		time.Sleep(1 * time.Second)
 	}
-	return fmt.Errorf("services did not become healthy within timeout")
 }
 
 // runBuildProcess runs the build process (if configured)
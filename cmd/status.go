@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/firecrown-media/stax/pkg/ddev"
 	"github.com/firecrown-media/stax/pkg/errors"
@@ -103,11 +104,23 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Router:      %s\n", getContainerStatus(status.Running))
 	fmt.Println()
 
+	// Extra services, from docker-compose files or stax services
+	if extra := extraServices(status.Services); len(extra) > 0 {
+		ui.Section("Services")
+		for _, service := range extra {
+			fmt.Printf("  %-13s%s\n", service.Name+":", getServiceStatus(service))
+		}
+		fmt.Println()
+	}
+
 	// Configuration
 	ui.Section("Configuration")
 	fmt.Printf("  PHP Version: %s\n", status.PHPVersion)
 	fmt.Printf("  Database:    %s %s\n", status.DatabaseType, status.DatabaseVersion)
 	fmt.Printf("  Webserver:   %s\n", status.Webserver)
+	if profile, err := ddev.ActiveProfile(projectDir); err == nil && profile != nil {
+		fmt.Printf("  Profile:     %s (stax profile reset to go back)\n", profile.Name)
+	}
 	if status.XdebugEnabled {
 		fmt.Println("  Xdebug:      ✓ Enabled")
 	} else {
@@ -172,3 +185,29 @@ func getContainerStatus(running bool) string {
 	}
 	return "⚫ Stopped"
 }
+
+// extraServices returns the services other than web and db
+func extraServices(services []ddev.ServiceStatus) []ddev.ServiceStatus {
+	var extra []ddev.ServiceStatus
+	for _, service := range services {
+		if service.Name != "web" && service.Name != "db" {
+			extra = append(extra, service)
+		}
+	}
+	return extra
+}
+
+// getServiceStatus returns the state, health and ports of a service
+func getServiceStatus(service ddev.ServiceStatus) string {
+	text := getContainerStatus(service.State == "running")
+	if service.State != "running" && service.State != "" && service.State != "stopped" {
+		text = "⚫ " + service.State
+	}
+	if service.Health != "" && service.Health != "healthy" {
+		text += " (" + service.Health + ")"
+	}
+	if len(service.Ports) > 0 {
+		text += "  " + strings.Join(service.Ports, ", ")
+	}
+	return text
+}
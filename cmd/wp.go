@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/credentials"
+	"github.com/firecrown-media/stax/pkg/ddev"
+	"github.com/firecrown-media/stax/pkg/errors"
+	"github.com/firecrown-media/stax/pkg/ui"
+	"github.com/firecrown-media/stax/pkg/wordpress"
+	"github.com/firecrown-media/stax/pkg/wpengine"
+	"github.com/spf13/cobra"
+)
+
+var (
+	wpRemote   string
+	wpAllSites bool
+	wpYes      bool
+)
+
+// wpCmd runs WP-CLI locally in DDEV or on a WPEngine environment
+var wpCmd = &cobra.Command{
+	Use:   "wp [--remote production|staging] [--all-sites] <wp-cli args>",
+	Short: "Run WP-CLI locally or on WPEngine",
+	Long: `Run a WP-CLI command in the local DDEV container, or on a WPEngine
+environment over SSH with --remote. Output is streamed as it is produced and
+stax exits with WP-CLI's exit code.
+
+stax flags must come before the WP-CLI command; everything after it is passed
+to WP-CLI unchanged. Commands that may change a production site ask for
+confirmation unless --yes is given.
+
+production runs on wpengine.install and staging on wpengine.staging_install;
+staging is refused when no staging install is configured.`,
+	Example: `  # List plugins locally
+  stax wp plugin list
+
+  # Check the core version on production
+  stax wp --remote production core version
+
+  # Flush the cache on every staging site of a multisite network
+  stax wp --remote staging --all-sites cache flush`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runWP,
+}
+
+func init() {
+	rootCmd.AddCommand(wpCmd)
+
+	// Stop at the first WP-CLI argument so its flags pass through
+	wpCmd.Flags().SetInterspersed(false)
+	wpCmd.Flags().StringVar(&wpRemote, "remote", "", "run on a WPEngine environment (production or staging)")
+	wpCmd.Flags().BoolVar(&wpAllSites, "all-sites", false, "run once for every site of a multisite network")
+	wpCmd.Flags().BoolVarP(&wpYes, "yes", "y", false, "skip the production confirmation")
+}
+
+// wpRunner runs one WP-CLI command, streaming its output
+type wpRunner func(args []string) error
+
+func runWP(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 && args[0] == "wp" {
+		// Tolerate "stax wp wp ..."
+		args = args[1:]
+	}
+
+	var run wpRunner
+	var listSites func() (string, error)
+
+	switch wpRemote {
+	case "":
+		mgr := ddev.NewManager(getProjectDir())
+		run = func(args []string) error {
+			return mgr.Exec(append([]string{"wp"}, args...), nil)
+		}
+		listSites = func() (string, error) {
+			return wordpress.NewCLI(getProjectDir()).ExecuteWithOutput("site", "list", "--field=url")
+		}
+	case "production", "staging":
+		client, err := connectWPEngineSSH(wpRemote)
+		if err != nil {
+			return err
+		}
+		run = func(args []string) error {
+			return client.StreamWPCLI(args, os.Stdout, os.Stderr)
+		}
+		listSites = func() (string, error) {
+			return client.GetWPCLI([]string{"site", "list", "--field=url"})
+		}
+	default:
+		return fmt.Errorf("--remote must be 'staging' or 'production', got: %s", wpRemote)
+	}
+
+	if wpRemote == "production" && !wpYes && wordpress.IsMutatingCommand(args) {
+		ui.Warning("This WP-CLI command may change the PRODUCTION site:")
+		ui.Info("  wp %s", strings.Join(args, " "))
+		if !ui.Confirm("Continue?") {
+			ui.Info("Cancelled")
+			return nil
+		}
+	}
+
+	if !wpAllSites {
+		return commandExitError(run(args))
+	}
+
+	output, err := listSites()
+	if err != nil {
+		return fmt.Errorf("failed to list network sites (is this a multisite install?): %w", err)
+	}
+	urls := strings.Fields(output)
+	if len(urls) == 0 {
+		return fmt.Errorf("no sites found in the network")
+	}
+
+	// Keep going after a failing site and exit with the last failure
+	var last error
+	failed := 0
+	for _, url := range urls {
+		ui.Section(url)
+		if err := run(append([]string{"--url=" + url}, args...)); err != nil {
+			ui.Error("%s: %v", url, err)
+			last = err
+			failed++
+		}
+	}
+	if failed > 0 {
+		ui.Warning("%d of %d sites failed", failed, len(urls))
+		return commandExitError(last)
+	}
+	ui.Success("Ran on %d sites", len(urls))
+	return nil
+}
+
+// noWPEngineInstallError explains that environment has no install configured
+func noWPEngineInstallError(environment string, err error) error {
+	return errors.NewWithSolution(
+		fmt.Sprintf("No WPEngine %s install", environment),
+		err.Error(),
+		errors.Solution{
+			Description: "Set the install of each environment",
+			Steps: []string{
+				"stax config set wpengine.staging_install <staging install>",
+				"wpengine.install is the production install unless wpengine.environment says otherwise",
+			},
+		},
+	)
+}
+
+// connectWPEngineSSH connects to the WPEngine install of environment, or to
+// the configured install when environment is empty
+func connectWPEngineSSH(environment string) (*wpengine.SSHClient, error) {
+	cfg, err := loadConfigForCommand()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.WPEngine.Install == "" {
+		return nil, errors.NewWithSolution(
+			"No WPEngine install configured",
+			"Remote commands need wpengine.install in .stax.yml",
+			errors.Solution{
+				Description: "Set the WPEngine install name",
+				Command:     "stax config set wpengine.install <install>",
+			},
+		)
+	}
+
+	install, err := cfg.WPEngine.InstallFor(environment)
+	if err != nil {
+		return nil, noWPEngineInstallError(environment, err)
+	}
+
+	creds, err := credentials.GetWPEngineCredentialsWithFallback(install)
+	if err != nil {
+		if credErr, ok := err.(*credentials.CredentialsNotFoundError); ok {
+			return nil, errors.NewCredentialsNotFoundError(credErr.Tried, credErr.LastErr)
+		}
+		return nil, fmt.Errorf("failed to get WPEngine credentials: %w", err)
+	}
+
+	sshKey, err := wpengineSSHKey(cfg.WPEngine.SSHGateway)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := wpengineSSH(wpengine.SSHConfig{
+		Host:       cfg.WPEngine.SSHGateway,
+		User:       creds.SSHUser,
+		PrivateKey: sshKey,
+		Install:    install,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to WPEngine: %w", err)
+	}
+	return client, nil
+}
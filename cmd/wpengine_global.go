@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/firecrown-media/stax/pkg/config"
 	"github.com/firecrown-media/stax/pkg/credentials"
@@ -19,6 +20,15 @@ var (
 	wpengineListEnvironment string
 	wpengineListJSON        bool
 	wpengineInfoJSON        bool
+	wpengineDomainsJSON     bool
+	wpengineDomainPrimary   bool
+	wpengineUsersAccount    string
+	wpengineUsersJSON       bool
+	wpengineSSHKeysJSON     bool
+	wpengineSSHKeyRemoveYes bool
+	wpenginePurgeTypes      []string
+	wpengineRefresh         bool
+	wpengineOffline         bool
 )
 
 // wpengineCmd represents the global wpengine command group
@@ -31,6 +41,8 @@ These commands allow you to:
   - List all available WPEngine installations
   - View detailed information about specific installations
   - Interactively select and configure installations
+  - Manage install domains, account users and SSH keys
+  - Purge install caches
 
 These commands work globally and do not require a .stax.yml configuration file.`,
 }
@@ -44,7 +56,11 @@ var wpengineListCmd = &cobra.Command{
 This command connects to the WPEngine API and retrieves all installations
 you have access to, displaying key information about each one.
 
-By default, output is displayed in a table format. Use --json for machine-readable output.`,
+By default, output is displayed in a table format. Use --json for machine-readable output.
+
+Installations are cached for an hour in ~/.stax/cache/wpengine/. Use --refresh
+to bypass the cache or --offline to use it without contacting the API. When the
+API is unreachable, cached installations are shown instead.`,
 	Example: `  # List all installations
   stax wpengine list
 
@@ -80,7 +96,7 @@ This command retrieves comprehensive details about an installation including:
 
 // wpengineSelectCmd provides interactive installation selection
 var wpengineSelectCmd = &cobra.Command{
-	Use:   "select",
+	Use:   "select [search]",
 	Short: "Interactive installation selector",
 	Long: `Interactive installation selector and configuration generator.
 
@@ -93,10 +109,116 @@ This command provides an interactive wizard that:
 
 This is a quick way to set up Stax for an existing WPEngine site.`,
 	Example: `  # Start interactive selection wizard
-  stax wpengine select`,
+  stax wpengine select
+
+  # Narrow the installations with a fuzzy search
+  stax wpengine select acme prod`,
 	RunE: runWPEngineSelect,
 }
 
+// wpengineDomainsCmd lists the domains of an installation
+var wpengineDomainsCmd = &cobra.Command{
+	Use:   "domains <install>",
+	Short: "List and manage installation domains",
+	Long: `List the domains attached to a WPEngine installation.
+
+Use the add and set-primary subcommands to attach a new domain or change
+which domain is primary.`,
+	Example: `  # List domains
+  stax wpengine domains mywordpresssite
+
+  # Add a domain and make it primary
+  stax wpengine domains add mywordpresssite www.example.com --primary
+
+  # Change the primary domain
+  stax wpengine domains set-primary mywordpresssite example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWPEngineDomains,
+}
+
+// wpengineDomainsAddCmd adds a domain to an installation
+var wpengineDomainsAddCmd = &cobra.Command{
+	Use:   "add <install> <domain>",
+	Short: "Add a domain to an installation",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runWPEngineDomainsAdd,
+}
+
+// wpengineDomainsSetPrimaryCmd sets the primary domain of an installation
+var wpengineDomainsSetPrimaryCmd = &cobra.Command{
+	Use:   "set-primary <install> <domain>",
+	Short: "Set the primary domain of an installation",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runWPEngineDomainsSetPrimary,
+}
+
+// wpengineUsersCmd lists account users
+var wpengineUsersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "List account users",
+	Long: `List the users with access to your WPEngine accounts.
+
+Without --account, users of every account you can access are listed.`,
+	Example: `  # List users of all accounts
+  stax wpengine users
+
+  # List users of one account
+  stax wpengine users --account=<account-id>`,
+	RunE: runWPEngineUsers,
+}
+
+// wpengineSSHKeysCmd lists SSH keys
+var wpengineSSHKeysCmd = &cobra.Command{
+	Use:   "ssh-keys",
+	Short: "List and manage SSH keys",
+	Long: `List the SSH keys registered with your WPEngine API user.
+
+Keys registered here grant SSH gateway access to every installation
+the user can access.`,
+	Example: `  # List keys
+  stax wpengine ssh-keys
+
+  # Register a public key
+  stax wpengine ssh-keys add ~/.ssh/id_ed25519.pub
+
+  # Remove a key
+  stax wpengine ssh-keys remove <uuid>`,
+	Args: cobra.NoArgs,
+	RunE: runWPEngineSSHKeys,
+}
+
+// wpengineSSHKeysAddCmd registers an SSH key
+var wpengineSSHKeysAddCmd = &cobra.Command{
+	Use:   "add <public-key-file>",
+	Short: "Register a public SSH key",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWPEngineSSHKeysAdd,
+}
+
+// wpengineSSHKeysRemoveCmd removes an SSH key
+var wpengineSSHKeysRemoveCmd = &cobra.Command{
+	Use:   "remove <uuid>",
+	Short: "Remove an SSH key",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWPEngineSSHKeysRemove,
+}
+
+// wpenginePurgeCacheCmd purges installation caches
+var wpenginePurgeCacheCmd = &cobra.Command{
+	Use:   "purge-cache <install>",
+	Short: "Purge installation caches",
+	Long: `Purge the object, page and CDN caches of a WPEngine installation.
+
+By default every cache is purged. Use --type to purge specific caches.`,
+	Example: `  # Purge all caches
+  stax wpengine purge-cache mywordpresssite
+
+  # Purge only the CDN
+  stax wpengine purge-cache mywordpresssite --type=cdn`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWPEnginePurgeCache,
+}
+
 func init() {
 	rootCmd.AddCommand(wpengineCmd)
 
@@ -104,6 +226,15 @@ func init() {
 	wpengineCmd.AddCommand(wpengineListCmd)
 	wpengineCmd.AddCommand(wpengineInfoCmd)
 	wpengineCmd.AddCommand(wpengineSelectCmd)
+	wpengineCmd.AddCommand(wpengineDomainsCmd)
+	wpengineCmd.AddCommand(wpengineUsersCmd)
+	wpengineCmd.AddCommand(wpengineSSHKeysCmd)
+	wpengineCmd.AddCommand(wpenginePurgeCacheCmd)
+
+	wpengineDomainsCmd.AddCommand(wpengineDomainsAddCmd)
+	wpengineDomainsCmd.AddCommand(wpengineDomainsSetPrimaryCmd)
+	wpengineSSHKeysCmd.AddCommand(wpengineSSHKeysAddCmd)
+	wpengineSSHKeysCmd.AddCommand(wpengineSSHKeysRemoveCmd)
 
 	// List command flags
 	wpengineListCmd.Flags().StringVar(&wpengineListEnvironment, "environment", "", "filter by environment (production, staging, development)")
@@ -111,27 +242,45 @@ func init() {
 
 	// Info command flags
 	wpengineInfoCmd.Flags().BoolVar(&wpengineInfoJSON, "json", false, "output as JSON")
+
+	// Install cache flags
+	for _, c := range []*cobra.Command{wpengineListCmd, wpengineInfoCmd, wpengineSelectCmd} {
+		c.Flags().BoolVar(&wpengineRefresh, "refresh", false, "ignore cached installations and fetch from the API")
+		c.Flags().BoolVar(&wpengineOffline, "offline", false, "use cached installations without contacting the API")
+	}
+
+	// Domains command flags
+	wpengineDomainsCmd.Flags().BoolVar(&wpengineDomainsJSON, "json", false, "output as JSON")
+	wpengineDomainsAddCmd.Flags().BoolVar(&wpengineDomainPrimary, "primary", false, "make the new domain primary")
+
+	// Users command flags
+	wpengineUsersCmd.Flags().StringVar(&wpengineUsersAccount, "account", "", "account ID (default: all accounts)")
+	wpengineUsersCmd.Flags().BoolVar(&wpengineUsersJSON, "json", false, "output as JSON")
+
+	// SSH keys command flags
+	wpengineSSHKeysCmd.Flags().BoolVar(&wpengineSSHKeysJSON, "json", false, "output as JSON")
+	wpengineSSHKeysRemoveCmd.Flags().BoolVarP(&wpengineSSHKeyRemoveYes, "yes", "y", false, "skip confirmation")
+
+	// Purge cache command flags
+	wpenginePurgeCacheCmd.Flags().StringSliceVar(&wpenginePurgeTypes, "type", wpengine.CacheTypes, "cache types to purge (object, page, cdn)")
 }
 
 // runWPEngineList lists all WPEngine installations
 func runWPEngineList(cmd *cobra.Command, args []string) error {
 	ui.PrintHeader("WPEngine Installations")
 
-	// Get credentials
-	creds, err := credentials.GetWPEngineCredentialsWithFallback("")
+	// Create WPEngine client backed by the install cache
+	client, _, err := newWPEngineCachedClient()
 	if err != nil {
-		return handleCredentialsError(err)
+		return err
 	}
 
-	// Create WPEngine client
-	client := wpengine.NewClient(creds.APIUser, creds.APIPassword, "")
-
 	// Show spinner while fetching
 	spinner := ui.NewSpinner("Fetching installations from WPEngine API...")
 	spinner.Start()
 
 	// Fetch installations
-	installs, err := client.ListInstalls()
+	installs, err := client.ListInstallsContext(cmd.Context())
 	spinner.Stop()
 
 	if err != nil {
@@ -139,6 +288,10 @@ func runWPEngineList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list installations: %w", err)
 	}
 
+	if !wpengineListJSON {
+		reportCacheStatus(client.Status())
+	}
+
 	if len(installs) == 0 {
 		ui.Warning("No installations found")
 		return nil
@@ -174,21 +327,18 @@ func runWPEngineInfo(cmd *cobra.Command, args []string) error {
 
 	ui.PrintHeader(fmt.Sprintf("WPEngine Installation: %s", installName))
 
-	// Get credentials
-	creds, err := credentials.GetWPEngineCredentialsWithFallback("")
+	// Create WPEngine client backed by the install cache
+	client, creds, err := newWPEngineCachedClient()
 	if err != nil {
-		return handleCredentialsError(err)
+		return err
 	}
 
-	// Create WPEngine client
-	client := wpengine.NewClient(creds.APIUser, creds.APIPassword, installName)
-
 	// Show spinner while fetching
 	spinner := ui.NewSpinner("Fetching installation details...")
 	spinner.Start()
 
 	// Fetch installation details
-	details, err := client.GetInstallByName(installName)
+	details, err := client.GetInstallByNameContext(cmd.Context(), installName)
 	spinner.Stop()
 
 	if err != nil {
@@ -196,6 +346,10 @@ func runWPEngineInfo(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get installation info: %w", err)
 	}
 
+	if !wpengineInfoJSON {
+		reportCacheStatus(client.Status())
+	}
+
 	// Output results
 	if wpengineInfoJSON {
 		return outputInstallDetailsJSON(details)
@@ -208,21 +362,18 @@ func runWPEngineInfo(cmd *cobra.Command, args []string) error {
 func runWPEngineSelect(cmd *cobra.Command, args []string) error {
 	ui.PrintHeader("WPEngine Installation Selector")
 
-	// Get credentials
-	creds, err := credentials.GetWPEngineCredentialsWithFallback("")
+	// Create WPEngine client backed by the install cache
+	client, _, err := newWPEngineCachedClient()
 	if err != nil {
-		return handleCredentialsError(err)
+		return err
 	}
 
-	// Create WPEngine client
-	client := wpengine.NewClient(creds.APIUser, creds.APIPassword, "")
-
 	// Show spinner while fetching
 	spinner := ui.NewSpinner("Fetching installations from WPEngine API...")
 	spinner.Start()
 
 	// Fetch installations
-	installs, err := client.ListInstalls()
+	installs, err := client.ListInstallsContext(cmd.Context())
 	spinner.Stop()
 
 	if err != nil {
@@ -230,6 +381,8 @@ func runWPEngineSelect(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list installations: %w", err)
 	}
 
+	reportCacheStatus(client.Status())
+
 	if len(installs) == 0 {
 		ui.Warning("No installations found")
 		return nil
@@ -241,17 +394,11 @@ func runWPEngineSelect(cmd *cobra.Command, args []string) error {
 	// Step 1: Select installation
 	ui.Section("Step 1: Select Installation")
 
-	installOptions := make([]string, len(installs))
-	for i, install := range installs {
-		installOptions[i] = fmt.Sprintf("%s (%s) - PHP %s", install.Name, install.Environment, install.PHPVersion)
-	}
-
-	selectedIdx, _, err := prompts.PromptSelect("Select an installation:", installOptions, 0)
+	query := strings.Join(args, " ")
+	selectedInstall, err := selectWPEngineInstall(installs, query)
 	if err != nil {
 		return err
 	}
-
-	selectedInstall := installs[selectedIdx]
 	ui.Info("Selected: %s", selectedInstall.Name)
 
 	// Step 2: Get project details
@@ -354,6 +501,401 @@ func runWPEngineSelect(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// newWPEngineCachedClient creates an API client backed by the install inventory cache
+func newWPEngineCachedClient() (*wpengine.CachedClient, *credentials.WPEngineCredentials, error) {
+	creds, err := credentials.GetWPEngineCredentialsWithFallback("")
+	if err != nil {
+		return nil, nil, handleCredentialsError(err)
+	}
+
+	cacheDir, err := wpengine.DefaultCacheDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := wpengine.NewCachedClient(
+		wpengine.NewClient(creds.APIUser, creds.APIPassword, ""),
+		wpengine.NewCache(cacheDir, creds.APIUser, wpengine.DefaultCacheTTL),
+	)
+	client.Refresh = wpengineRefresh
+	client.Offline = wpengineOffline
+
+	return client, creds, nil
+}
+
+// reportCacheStatus tells the user when results came from the install cache
+func reportCacheStatus(status wpengine.CacheStatus) {
+	if !status.FromCache {
+		return
+	}
+
+	age := time.Since(status.FetchedAt).Round(time.Second)
+	if age >= time.Minute {
+		age = age.Round(time.Minute)
+	}
+	switch {
+	case status.APIError != nil:
+		ui.Warning("WPEngine API unreachable, showing cached data from %s ago", age)
+		ui.Verbose("API error: %v", status.APIError)
+	case status.Offline:
+		ui.Info("Offline: showing cached data from %s ago", age)
+	default:
+		ui.Info("Using cached data from %s ago (use --refresh to update)", age)
+	}
+}
+
+// newWPEngineAPIClient creates an API client from the stored credentials
+func newWPEngineAPIClient() (*wpengine.Client, error) {
+	creds, err := credentials.GetWPEngineCredentialsWithFallback("")
+	if err != nil {
+		return nil, handleCredentialsError(err)
+	}
+	return wpengine.NewClient(creds.APIUser, creds.APIPassword, ""), nil
+}
+
+// runWPEngineDomains lists the domains of an installation
+func runWPEngineDomains(cmd *cobra.Command, args []string) error {
+	installName := args[0]
+
+	client, err := newWPEngineAPIClient()
+	if err != nil {
+		return err
+	}
+
+	spinner := ui.NewSpinner("Fetching domains...")
+	spinner.Start()
+
+	installID, err := client.ResolveInstallIDContext(cmd.Context(), installName)
+	var domains []wpengine.Domain
+	if err == nil {
+		domains, err = client.ListDomainsContext(cmd.Context(), installID)
+	}
+	spinner.Stop()
+
+	if err != nil {
+		return fmt.Errorf("failed to list domains for %s: %w", installName, err)
+	}
+
+	if wpengineDomainsJSON {
+		return outputJSON(domains)
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Domains: %s", installName))
+
+	if len(domains) == 0 {
+		ui.Warning("No domains found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "DOMAIN\tPRIMARY\tREDIRECTS TO\tID")
+	fmt.Fprintln(w, "------\t-------\t------------\t--")
+	for _, domain := range domains {
+		primary := ""
+		if domain.Primary {
+			primary = "yes"
+		}
+		redirects := make([]string, len(domain.RedirectsTo))
+		for i, r := range domain.RedirectsTo {
+			redirects[i] = r.Name
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", domain.Name, primary, strings.Join(redirects, ", "), domain.ID)
+	}
+	w.Flush()
+
+	fmt.Println()
+	ui.Success("Found %d domain(s)", len(domains))
+	return nil
+}
+
+// runWPEngineDomainsAdd adds a domain to an installation
+func runWPEngineDomainsAdd(cmd *cobra.Command, args []string) error {
+	installName, domainName := args[0], args[1]
+
+	client, err := newWPEngineAPIClient()
+	if err != nil {
+		return err
+	}
+
+	installID, err := client.ResolveInstallIDContext(cmd.Context(), installName)
+	if err != nil {
+		return err
+	}
+
+	domain, err := client.AddDomainContext(cmd.Context(), installID, domainName, wpengineDomainPrimary)
+	if err != nil {
+		return err
+	}
+
+	ui.Success("Added %s to %s", domain.Name, installName)
+	if wpengineDomainPrimary {
+		ui.Info("%s is now the primary domain", domain.Name)
+	}
+	return nil
+}
+
+// runWPEngineDomainsSetPrimary sets the primary domain of an installation
+func runWPEngineDomainsSetPrimary(cmd *cobra.Command, args []string) error {
+	installName, domainName := args[0], args[1]
+
+	client, err := newWPEngineAPIClient()
+	if err != nil {
+		return err
+	}
+
+	installID, err := client.ResolveInstallIDContext(cmd.Context(), installName)
+	if err != nil {
+		return err
+	}
+
+	domain, err := client.FindDomainContext(cmd.Context(), installID, domainName)
+	if err != nil {
+		return err
+	}
+
+	if domain.Primary {
+		ui.Info("%s is already the primary domain of %s", domain.Name, installName)
+		return nil
+	}
+
+	if _, err := client.SetPrimaryDomainContext(cmd.Context(), installID, domain.ID); err != nil {
+		return err
+	}
+
+	ui.Success("%s is now the primary domain of %s", domain.Name, installName)
+	return nil
+}
+
+// runWPEngineUsers lists account users
+func runWPEngineUsers(cmd *cobra.Command, args []string) error {
+	client, err := newWPEngineAPIClient()
+	if err != nil {
+		return err
+	}
+
+	spinner := ui.NewSpinner("Fetching account users...")
+	spinner.Start()
+
+	accountIDs := []string{wpengineUsersAccount}
+	if wpengineUsersAccount == "" {
+		accounts, err := client.ListAccountsContext(cmd.Context())
+		if err != nil {
+			spinner.Stop()
+			return err
+		}
+		accountIDs = make([]string, len(accounts))
+		for i, account := range accounts {
+			accountIDs[i] = account.ID
+		}
+	}
+
+	users := []wpengine.AccountUser{}
+	for _, accountID := range accountIDs {
+		accountUsers, err := client.ListAccountUsersContext(cmd.Context(), accountID)
+		if err != nil {
+			spinner.Stop()
+			return err
+		}
+		users = append(users, accountUsers...)
+	}
+	spinner.Stop()
+
+	if wpengineUsersJSON {
+		return outputJSON(users)
+	}
+
+	ui.PrintHeader("WPEngine Account Users")
+
+	if len(users) == 0 {
+		ui.Warning("No users found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tEMAIL\tROLES\tMFA\tINVITE ACCEPTED\tACCOUNT")
+	fmt.Fprintln(w, "----\t-----\t-----\t---\t---------------\t-------")
+	for _, user := range users {
+		fmt.Fprintf(w, "%s %s\t%s\t%s\t%t\t%t\t%s\n",
+			user.FirstName,
+			user.LastName,
+			user.Email,
+			user.Roles,
+			user.MFAEnabled,
+			user.InviteAccepted,
+			user.AccountID,
+		)
+	}
+	w.Flush()
+
+	fmt.Println()
+	ui.Success("Found %d user(s)", len(users))
+	return nil
+}
+
+// runWPEngineSSHKeys lists SSH keys
+func runWPEngineSSHKeys(cmd *cobra.Command, args []string) error {
+	client, err := newWPEngineAPIClient()
+	if err != nil {
+		return err
+	}
+
+	spinner := ui.NewSpinner("Fetching SSH keys...")
+	spinner.Start()
+	keys, err := client.ListSSHKeysContext(cmd.Context())
+	spinner.Stop()
+
+	if err != nil {
+		return err
+	}
+
+	if wpengineSSHKeysJSON {
+		return outputJSON(keys)
+	}
+
+	ui.PrintHeader("WPEngine SSH Keys")
+
+	if len(keys) == 0 {
+		ui.Warning("No SSH keys found")
+		ui.Info("Register one with: stax wpengine ssh-keys add ~/.ssh/id_ed25519.pub")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "UUID\tCOMMENT\tFINGERPRINT\tCREATED")
+	fmt.Fprintln(w, "----\t-------\t-----------\t-------")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key.UUID, key.Comment, key.Fingerprint, key.CreatedAt.Format("2006-01-02"))
+	}
+	w.Flush()
+
+	fmt.Println()
+	ui.Success("Found %d key(s)", len(keys))
+	return nil
+}
+
+// runWPEngineSSHKeysAdd registers a public key
+func runWPEngineSSHKeysAdd(cmd *cobra.Command, args []string) error {
+	keyFile := args[0]
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	publicKey := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(publicKey, "ssh-") && !strings.HasPrefix(publicKey, "ecdsa-") {
+		return fmt.Errorf("%s does not look like a public key (did you pass the private key?)", keyFile)
+	}
+
+	client, err := newWPEngineAPIClient()
+	if err != nil {
+		return err
+	}
+
+	key, err := client.AddSSHKeyContext(cmd.Context(), publicKey)
+	if err != nil {
+		return err
+	}
+
+	ui.Success("Registered SSH key %s", key.Fingerprint)
+	ui.Info("It may take a few minutes before the key works on the SSH gateway")
+	return nil
+}
+
+// runWPEngineSSHKeysRemove removes an SSH key
+func runWPEngineSSHKeysRemove(cmd *cobra.Command, args []string) error {
+	uuid := args[0]
+
+	if !wpengineSSHKeyRemoveYes {
+		confirm, err := prompts.PromptConfirm(fmt.Sprintf("Remove SSH key %s?", uuid), false)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			ui.Info("Cancelled")
+			return nil
+		}
+	}
+
+	client, err := newWPEngineAPIClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteSSHKeyContext(cmd.Context(), uuid); err != nil {
+		return err
+	}
+
+	ui.Success("Removed SSH key %s", uuid)
+	return nil
+}
+
+// runWPEnginePurgeCache purges installation caches
+func runWPEnginePurgeCache(cmd *cobra.Command, args []string) error {
+	installName := args[0]
+
+	client, err := newWPEngineAPIClient()
+	if err != nil {
+		return err
+	}
+
+	installID, err := client.ResolveInstallIDContext(cmd.Context(), installName)
+	if err != nil {
+		return err
+	}
+
+	for _, cacheType := range wpenginePurgeTypes {
+		if err := client.PurgeCacheContext(cmd.Context(), installID, strings.ToLower(strings.TrimSpace(cacheType))); err != nil {
+			return err
+		}
+		ui.Success("Purged %s cache for %s", cacheType, installName)
+	}
+
+	return nil
+}
+
+// selectWPEngineInstallSearchThreshold is the install count above which the selector asks for a search first
+const selectWPEngineInstallSearchThreshold = 15
+
+// selectWPEngineInstall lets the user pick an install, fuzzy searching by name, domain or environment
+func selectWPEngineInstall(installs []wpengine.Install, query string) (wpengine.Install, error) {
+	for {
+		if query == "" && len(installs) > selectWPEngineInstallSearchThreshold {
+			input, err := prompts.PromptInput("Search installations (name, domain or environment; Enter to show all)", "")
+			if err != nil {
+				return wpengine.Install{}, err
+			}
+			query = input
+		}
+
+		matches := wpengine.SearchInstalls(installs, query)
+		if len(matches) == 0 {
+			ui.Warning("No installations match %q", query)
+			query = ""
+			if len(installs) <= selectWPEngineInstallSearchThreshold {
+				matches = installs
+			} else {
+				continue
+			}
+		}
+
+		if query != "" {
+			ui.Info("%d installation(s) match %q", len(matches), query)
+		}
+
+		options := make([]string, len(matches))
+		for i, install := range matches {
+			options[i] = fmt.Sprintf("%s (%s) - %s - PHP %s", install.Name, install.Environment, install.PrimaryDomain, install.PHPVersion)
+		}
+
+		selectedIdx, _, err := prompts.PromptSelect("Select an installation:", options, 0)
+		if err != nil {
+			return wpengine.Install{}, err
+		}
+		return matches[selectedIdx], nil
+	}
+}
+
 // outputInstallsTable outputs installations in table format
 func outputInstallsTable(installs []wpengine.Install) error {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
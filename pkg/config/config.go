@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,6 +22,12 @@ type Config struct {
 	// DDEV configuration
 	DDEV DDEVConfig `yaml:"ddev"`
 
+	// Extra DDEV services, by name (redis, elasticsearch, memcached, solr)
+	Services map[string]ServiceConfig `yaml:"services,omitempty"`
+
+	// DDEV setting overrides switched on with stax profile use, by name
+	Profiles map[string]ProfileConfig `yaml:"profiles,omitempty"`
+
 	// GitHub repository configuration
 	Repository RepositoryConfig `yaml:"repository,omitempty"`
 
@@ -55,12 +63,63 @@ type ProjectConfig struct {
 
 // WPEngineConfig represents WPEngine integration settings
 type WPEngineConfig struct {
-	Install     string                `yaml:"install"`
-	Environment string                `yaml:"environment"` // production, staging, development
-	AccountName string                `yaml:"account_name,omitempty"`
-	SSHGateway  string                `yaml:"ssh_gateway,omitempty"`
-	Backup      WPEngineBackupConfig  `yaml:"backup,omitempty"`
-	Domains     WPEngineDomainsConfig `yaml:"domains,omitempty"`
+	Install        string                `yaml:"install"`
+	Environment    string                `yaml:"environment"`               // production, staging, development
+	StagingInstall string                `yaml:"staging_install,omitempty"` // staging install, when install is production
+	AccountName    string                `yaml:"account_name,omitempty"`
+	SSHGateway     string                `yaml:"ssh_gateway,omitempty"`
+	Backup         WPEngineBackupConfig  `yaml:"backup,omitempty"`
+	Domains        WPEngineDomainsConfig `yaml:"domains,omitempty"`
+}
+
+// InstallFor returns the install of a WPEngine environment
+// An empty environment is the configured install. Staging never resolves to
+// the production install, so staging commands cannot change production
+func (w WPEngineConfig) InstallFor(environment string) (string, error) {
+	installEnv := w.Environment
+	if installEnv == "" {
+		installEnv = "production"
+	}
+
+	switch environment {
+	case "":
+		return w.Install, nil
+	case installEnv:
+		return w.Install, nil
+	case "staging":
+		if w.StagingInstall == "" {
+			return "", fmt.Errorf("no WPEngine staging install configured; set wpengine.staging_install in .stax.yml")
+		}
+		if w.StagingInstall == w.Install {
+			return "", fmt.Errorf("wpengine.staging_install is the same as the %s install %s", installEnv, w.Install)
+		}
+		return w.StagingInstall, nil
+	case "production":
+		return "", fmt.Errorf("wpengine.install %s is the %s install; stax does not know the production install", w.Install, installEnv)
+	default:
+		return "", fmt.Errorf("environment must be 'staging' or 'production', got: %s", environment)
+	}
+}
+
+// EnvironmentOf returns the environment an install of this config belongs to
+func (w WPEngineConfig) EnvironmentOf(install string) string {
+	if install != "" && install == w.StagingInstall && install != w.Install {
+		return "staging"
+	}
+	if w.Environment == "" {
+		return "production"
+	}
+	return w.Environment
+}
+
+// PushTarget resolves the install a push to environment writes to, and the
+// environment that install belongs to, which decides the checkpoint policy
+func (w WPEngineConfig) PushTarget(environment string) (install, installEnv string, err error) {
+	install, err = w.InstallFor(environment)
+	if err != nil {
+		return "", "", err
+	}
+	return install, w.EnvironmentOf(install), nil
 }
 
 // WPEngineBackupConfig represents backup preferences
@@ -70,6 +129,30 @@ type WPEngineBackupConfig struct {
 	SkipTransients bool     `yaml:"skip_transients"`
 	SkipSpam       bool     `yaml:"skip_spam"`
 	ExcludeTables  []string `yaml:"exclude_tables,omitempty"`
+
+	// Checkpoint controls when pushes require a completed WPEngine backup first
+	Checkpoint         string   `yaml:"checkpoint,omitempty"`         // production, always or never
+	CheckpointTimeout  int      `yaml:"checkpoint_timeout,omitempty"` // seconds
+	NotificationEmails []string `yaml:"notification_emails,omitempty"`
+}
+
+// Backup checkpoint policies
+const (
+	CheckpointProduction = "production"
+	CheckpointAlways     = "always"
+	CheckpointNever      = "never"
+)
+
+// RequiresCheckpoint reports whether pushing to environment requires a backup checkpoint
+func (b WPEngineBackupConfig) RequiresCheckpoint(environment string) bool {
+	switch b.Checkpoint {
+	case CheckpointAlways:
+		return true
+	case CheckpointNever:
+		return false
+	default:
+		return environment == "production"
+	}
 }
 
 // WPEngineDomainsConfig represents domain mapping
@@ -100,6 +183,8 @@ type SiteConfig struct {
 	Domain         string `yaml:"domain"`
 	WPEngineDomain string `yaml:"wpengine_domain"`
 	Active         bool   `yaml:"active"`
+	ID             int    `yaml:"id,omitempty"`           // blog ID; read from WordPress when unset
+	CDNHostname    string `yaml:"cdn_hostname,omitempty"` // CDN serving this site's uploads
 }
 
 // DDEVConfig represents DDEV configuration
@@ -143,6 +228,23 @@ type DDEVHook struct {
 	Exec string `yaml:"exec"`
 }
 
+// ServiceConfig represents an extra DDEV service
+type ServiceConfig struct {
+	Version string `yaml:"version,omitempty"` // image tag
+	Image   string `yaml:"image,omitempty"`   // replaces the default image
+}
+
+// ProfileConfig represents DDEV settings overridden by a profile
+// Settings left empty keep the ddev section values
+type ProfileConfig struct {
+	Description   string `yaml:"description,omitempty"`
+	PHPVersion    string `yaml:"php_version,omitempty"`
+	MySQLVersion  string `yaml:"mysql_version,omitempty"`
+	MySQLType     string `yaml:"mysql_type,omitempty"` // mysql, mariadb
+	XdebugEnabled *bool  `yaml:"xdebug_enabled,omitempty"`
+	NodeJSVersion string `yaml:"nodejs_version,omitempty"`
+}
+
 // RepositoryConfig represents GitHub repository configuration
 type RepositoryConfig struct {
 	URL        string       `yaml:"url"`
@@ -309,7 +411,9 @@ type MediaConfig struct {
 	PrimarySource    string         `yaml:"primary_source,omitempty"`
 	BunnyCDN         BunnyCDNConfig `yaml:"bunnycdn,omitempty"`
 	WPEngineFallback bool           `yaml:"wpengine_fallback"`
+	Origins          []string       `yaml:"origins,omitempty"` // extra source URLs, tried in order
 	Cache            CacheConfig    `yaml:"cache,omitempty"`
+	Resize           ResizeConfig   `yaml:"resize,omitempty"`
 }
 
 // BunnyCDNConfig represents BunnyCDN configuration
@@ -327,6 +431,22 @@ type CacheConfig struct {
 	TTL       int    `yaml:"ttl"`
 }
 
+// ResizeConfig represents on-the-fly generation of WordPress image sizes
+type ResizeConfig struct {
+	Enabled    bool              `yaml:"enabled"`
+	WriteLocal bool              `yaml:"write_local"`       // save generated files into wp-content/uploads
+	Quality    int               `yaml:"quality,omitempty"` // JPEG and WebP quality, 1-100
+	Sizes      []ImageSizeConfig `yaml:"sizes,omitempty"`   // used with the sizes registered in WordPress
+}
+
+// ImageSizeConfig represents an image size, as registered with add_image_size
+type ImageSizeConfig struct {
+	Name   string `yaml:"name"`
+	Width  int    `yaml:"width"`
+	Height int    `yaml:"height"`
+	Crop   bool   `yaml:"crop,omitempty"`
+}
+
 // CredentialsConfig represents credentials references
 type CredentialsConfig struct {
 	WPEngine CredentialRef `yaml:"wpengine,omitempty"`
@@ -415,6 +535,9 @@ func Defaults() *Config {
 				SkipLogs:       true,
 				SkipTransients: true,
 				SkipSpam:       true,
+
+				Checkpoint:        CheckpointProduction,
+				CheckpointTimeout: 1800,
 			},
 		},
 		Repository: RepositoryConfig{
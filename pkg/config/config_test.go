@@ -389,3 +389,81 @@ func TestPerformanceConfig(t *testing.T) {
 		t.Errorf("expected batch size 1000, got %d", config.DatabaseImportBatchSize)
 	}
 }
+
+func TestRequiresCheckpoint(t *testing.T) {
+	tests := []struct {
+		policy      string
+		environment string
+		want        bool
+	}{
+		{"", "production", true},
+		{"", "staging", false},
+		{CheckpointProduction, "production", true},
+		{CheckpointProduction, "staging", false},
+		{CheckpointAlways, "staging", true},
+		{CheckpointNever, "production", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy+"/"+tt.environment, func(t *testing.T) {
+			backup := WPEngineBackupConfig{Checkpoint: tt.policy}
+			if got := backup.RequiresCheckpoint(tt.environment); got != tt.want {
+				t.Errorf("RequiresCheckpoint(%q) with policy %q = %t, want %t", tt.environment, tt.policy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		wpengine    WPEngineConfig
+		environment string
+		want        string
+		wantErr     bool
+	}{
+		{"configured install", WPEngineConfig{Install: "site"}, "", "site", false},
+		{"production by default", WPEngineConfig{Install: "site"}, "production", "site", false},
+		{"staging install", WPEngineConfig{Install: "site", StagingInstall: "sitestg"}, "staging", "sitestg", false},
+		{"no staging install", WPEngineConfig{Install: "site"}, "staging", "", true},
+		{"staging is production", WPEngineConfig{Install: "site", StagingInstall: "site"}, "staging", "", true},
+		{"install is staging", WPEngineConfig{Install: "sitestg", Environment: "staging"}, "staging", "sitestg", false},
+		{"production unknown", WPEngineConfig{Install: "sitestg", Environment: "staging"}, "production", "", true},
+		{"invalid", WPEngineConfig{Install: "site"}, "dev", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.wpengine.InstallFor(tt.environment)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("InstallFor(%q) = %q, %v; want %q, error %t", tt.environment, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPushTarget(t *testing.T) {
+	backup := WPEngineBackupConfig{Checkpoint: CheckpointProduction}
+
+	// The flag says staging, but wpengine.install is the production install:
+	// the push must not reach production without a checkpoint
+	production := WPEngineConfig{Install: "site", Backup: backup}
+	if install, env, err := production.PushTarget("staging"); err == nil {
+		t.Errorf("PushTarget(staging) = %s (%s), want an error without a staging install", install, env)
+	}
+	install, env, err := production.PushTarget("")
+	if err != nil || install != "site" || env != "production" || !production.Backup.RequiresCheckpoint(env) {
+		t.Errorf("PushTarget(\"\") = %s, %s, %v; want site, production with a checkpoint", install, env, err)
+	}
+
+	withStaging := WPEngineConfig{Install: "site", StagingInstall: "sitestg", Backup: backup}
+	install, env, err = withStaging.PushTarget("staging")
+	if err != nil || install != "sitestg" || env != "staging" || withStaging.Backup.RequiresCheckpoint(env) {
+		t.Errorf("PushTarget(staging) = %s, %s, %v; want sitestg, staging", install, env, err)
+	}
+
+	stagingInstall := WPEngineConfig{Install: "sitestg", Environment: "staging", Backup: backup}
+	if install, env, err := stagingInstall.PushTarget(""); err != nil || install != "sitestg" || env != "staging" {
+		t.Errorf("PushTarget(\"\") = %s, %s, %v; want sitestg, staging", install, env, err)
+	}
+}
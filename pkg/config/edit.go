@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetFileValue sets the value at a dotted key in a YAML config file, keeping
+// the rest of the file, its comments and key order, as it is
+// A nil value removes the key
+func SetFileValue(path, key string, value interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to parse config file: top level is not a mapping")
+	}
+
+	var node *yaml.Node
+	if value != nil {
+		node = &yaml.Node{}
+		if err := node.Encode(value); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+	}
+	SetYAMLValue(doc.Content[0], key, node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(YAMLIndent(data))
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// SetYAMLValue sets the value at a dotted key in a YAML mapping, creating
+// mappings as needed, or removes it when value is nil
+// A replaced value keeps its comments, and a replaced scalar its quoting style
+func SetYAMLValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	setNode(mapping, strings.Split(key, "."), value)
+}
+
+func setNode(mapping *yaml.Node, keys []string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != keys[0] {
+			continue
+		}
+		existing := mapping.Content[i+1]
+		switch {
+		case len(keys) > 1 && existing.Kind == yaml.MappingNode:
+			setNode(existing, keys[1:], value)
+			if value == nil && len(existing.Content) == 0 {
+				mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			}
+		case len(keys) > 1:
+			if value != nil {
+				*existing = yaml.Node{Kind: yaml.MappingNode, HeadComment: existing.HeadComment, LineComment: existing.LineComment}
+				setNode(existing, keys[1:], value)
+			}
+		case value == nil:
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		case existing.Kind == yaml.ScalarNode && value.Kind == yaml.ScalarNode:
+			existing.Value, existing.Tag = value.Value, value.Tag
+			if value.Tag != "!!str" {
+				existing.Style = 0
+			}
+		default:
+			value.HeadComment, value.LineComment, value.FootComment = existing.HeadComment, existing.LineComment, existing.FootComment
+			*existing = *value
+		}
+		return
+	}
+
+	if value == nil {
+		return
+	}
+	for _, key := range keys[:len(keys)-1] {
+		child := &yaml.Node{Kind: yaml.MappingNode}
+		mapping.Content = append(mapping.Content, keyNode(key), child)
+		mapping = child
+	}
+	mapping.Content = append(mapping.Content, keyNode(keys[len(keys)-1]), value)
+}
+
+func keyNode(key string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+}
+
+// YAMLIndent returns the smallest indentation used in data, so rewritten
+// files keep their layout
+func YAMLIndent(data []byte) int {
+	indent := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if n := len(line) - len(trimmed); n > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			if indent == 0 || n < indent {
+				indent = n
+			}
+		}
+	}
+	if indent < 2 || indent > 8 {
+		// yaml.Marshal's default, as used by DDEV and Save
+		return 4
+	}
+	return indent
+}
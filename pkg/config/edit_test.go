@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetFileValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".stax.yml")
+	original := `# Project settings
+project:
+  name: site # the DDEV name
+wordpress:
+  constants:
+    WP_DEBUG: true
+`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		key   string
+		value interface{}
+	}{
+		{"services.redis", ServiceConfig{Version: "7"}},
+		{"wordpress.constants.WP_REDIS_HOST", "redis"},
+		{"wordpress.constants.WP_DEBUG", nil},
+		{"services.missing", nil},
+	}
+	for _, step := range steps {
+		if err := SetFileValue(path, step.key, step.value); err != nil {
+			t.Fatalf("SetFileValue(%s): %v", step.key, err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Project settings
+project:
+  name: site # the DDEV name
+wordpress:
+  constants:
+    WP_REDIS_HOST: redis
+services:
+  redis:
+    version: "7"
+`
+	if string(data) != want {
+		t.Errorf("file =\n%s\nwant\n%s", data, want)
+	}
+
+	// Removing the last key removes its parent
+	if err := SetFileValue(path, "services.redis", nil); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Services != nil || cfg.WordPress.Constants["WP_REDIS_HOST"] != "redis" {
+		t.Errorf("services = %v, constants = %v", cfg.Services, cfg.WordPress.Constants)
+	}
+}
+
+func TestSetYAMLValue(t *testing.T) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte("php: \"8.1\" # pinned\nname: site\n"), &doc); err != nil {
+		t.Fatal(err)
+	}
+	SetYAMLValue(doc.Content[0], "php", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "8.2"})
+	SetYAMLValue(doc.Content[0], "web.port", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "80"})
+	SetYAMLValue(doc.Content[0], "name", nil)
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "php: \"8.2\" # pinned\nweb:\n    port: 80\n"
+	if string(out) != want {
+		t.Errorf("document =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestYAMLIndent(t *testing.T) {
+	tests := map[string]int{
+		"a:\n  b: 1\n":           2,
+		"a:\n    b: 1\n  # c\n":  4,
+		"a: 1\n":                 4,
+		"a:\n   b:\n     c: 1\n": 3,
+	}
+	for data, want := range tests {
+		if got := YAMLIndent([]byte(data)); got != want {
+			t.Errorf("YAMLIndent(%q) = %d, want %d", data, got, want)
+		}
+	}
+}
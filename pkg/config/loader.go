@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	"gopkg.in/yaml.v3"
 )
@@ -141,12 +142,24 @@ func mergeConfigs(base, override *Config) *Config {
 	if override.WPEngine.Environment != "" {
 		result.WPEngine.Environment = override.WPEngine.Environment
 	}
+	if override.WPEngine.StagingInstall != "" {
+		result.WPEngine.StagingInstall = override.WPEngine.StagingInstall
+	}
 	if override.WPEngine.AccountName != "" {
 		result.WPEngine.AccountName = override.WPEngine.AccountName
 	}
 	if override.WPEngine.SSHGateway != "" {
 		result.WPEngine.SSHGateway = override.WPEngine.SSHGateway
 	}
+	if override.WPEngine.Backup.Checkpoint != "" {
+		result.WPEngine.Backup.Checkpoint = override.WPEngine.Backup.Checkpoint
+	}
+	if override.WPEngine.Backup.CheckpointTimeout != 0 {
+		result.WPEngine.Backup.CheckpointTimeout = override.WPEngine.Backup.CheckpointTimeout
+	}
+	if len(override.WPEngine.Backup.NotificationEmails) > 0 {
+		result.WPEngine.Backup.NotificationEmails = override.WPEngine.Backup.NotificationEmails
+	}
 
 	// Override DDEV config
 	if override.DDEV.PHPVersion != "" {
@@ -158,6 +171,35 @@ func mergeConfigs(base, override *Config) *Config {
 	if override.DDEV.WebserverType != "" {
 		result.DDEV.WebserverType = override.DDEV.WebserverType
 	}
+	if override.DDEV.MySQLType != "" {
+		result.DDEV.MySQLType = override.DDEV.MySQLType
+	}
+	if override.DDEV.NodeJSVersion != "" {
+		result.DDEV.NodeJSVersion = override.DDEV.NodeJSVersion
+	}
+	if override.DDEV.ComposerVersion != "" {
+		result.DDEV.ComposerVersion = override.DDEV.ComposerVersion
+	}
+	if override.DDEV.XdebugEnabled {
+		result.DDEV.XdebugEnabled = true
+	}
+	if len(override.DDEV.CustomCommands) > 0 {
+		result.DDEV.CustomCommands = override.DDEV.CustomCommands
+	}
+	if !reflect.DeepEqual(override.DDEV.Hooks, DDEVHooks{}) {
+		result.DDEV.Hooks = override.DDEV.Hooks
+	}
+
+	// Override services, profiles and WordPress constants
+	if len(override.Services) > 0 {
+		result.Services = override.Services
+	}
+	if len(override.Profiles) > 0 {
+		result.Profiles = override.Profiles
+	}
+	if len(override.WordPress.Constants) > 0 {
+		result.WordPress.Constants = override.WordPress.Constants
+	}
 
 	// Override network config
 	if override.Network.Domain != "" {
@@ -173,6 +215,41 @@ func mergeConfigs(base, override *Config) *Config {
 		result.Network.Sites = override.Network.Sites
 	}
 
+	// Override media config
+	// Flags are only taken from a project that has a media section, so the
+	// defaults survive when it is left out
+	if !reflect.DeepEqual(override.Media, MediaConfig{}) {
+		result.Media.ProxyEnabled = override.Media.ProxyEnabled
+		result.Media.WPEngineFallback = override.Media.WPEngineFallback
+		result.Media.Cache.Enabled = override.Media.Cache.Enabled
+		result.Media.Resize.Enabled = override.Media.Resize.Enabled
+		result.Media.Resize.WriteLocal = override.Media.Resize.WriteLocal
+	}
+	if override.Media.PrimarySource != "" {
+		result.Media.PrimarySource = override.Media.PrimarySource
+	}
+	if override.Media.BunnyCDN.Hostname != "" {
+		result.Media.BunnyCDN = override.Media.BunnyCDN
+	}
+	if len(override.Media.Origins) > 0 {
+		result.Media.Origins = override.Media.Origins
+	}
+	if override.Media.Cache.Directory != "" {
+		result.Media.Cache.Directory = override.Media.Cache.Directory
+	}
+	if override.Media.Cache.MaxSize != "" {
+		result.Media.Cache.MaxSize = override.Media.Cache.MaxSize
+	}
+	if override.Media.Cache.TTL != 0 {
+		result.Media.Cache.TTL = override.Media.Cache.TTL
+	}
+	if override.Media.Resize.Quality != 0 {
+		result.Media.Resize.Quality = override.Media.Resize.Quality
+	}
+	if len(override.Media.Resize.Sizes) > 0 {
+		result.Media.Resize.Sizes = override.Media.Resize.Sizes
+	}
+
 	// Override repository config
 	if override.Repository.URL != "" {
 		result.Repository.URL = override.Repository.URL
@@ -80,6 +80,38 @@ func TestLoad(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "backup checkpoint settings from the project config",
+			setupFunc: func(t *testing.T) (string, string) {
+				dir := t.TempDir()
+				content := `project:
+  name: test-project
+wpengine:
+  install: testinstall
+  backup:
+    checkpoint: always
+    checkpoint_timeout: 600
+    notification_emails:
+      - ops@example.com
+`
+				cfgPath := filepath.Join(dir, ".stax.yml")
+				if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write test config: %v", err)
+				}
+
+				return cfgPath, dir
+			},
+			wantErr: false,
+			validate: func(t *testing.T, cfg *Config) {
+				backup := cfg.WPEngine.Backup
+				if !backup.RequiresCheckpoint("staging") {
+					t.Errorf("expected checkpoint: always to require a staging checkpoint, got %q", backup.Checkpoint)
+				}
+				if backup.CheckpointTimeout != 600 || len(backup.NotificationEmails) != 1 {
+					t.Errorf("unexpected backup config: %+v", backup)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -315,6 +347,74 @@ func TestMergeConfigs(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "merge media config",
+			base: Defaults(),
+			override: &Config{
+				Media: MediaConfig{
+					Origins: []string{"https://media.example.com"},
+					Cache:   CacheConfig{Enabled: true, MaxSize: "5GB"},
+				},
+			},
+			check: func(t *testing.T, result *Config) {
+				if len(result.Media.Origins) != 1 || result.Media.Cache.MaxSize != "5GB" {
+					t.Errorf("media settings not merged: %+v", result.Media)
+				}
+				if result.Media.Cache.TTL != 86400 || result.Media.Cache.Directory != ".stax/media-cache" {
+					t.Errorf("unset cache settings lost their defaults: %+v", result.Media.Cache)
+				}
+				// The media section is present, so its flags win
+				if result.Media.WPEngineFallback {
+					t.Error("expected wpengine_fallback from the project config")
+				}
+			},
+		},
+		{
+			name: "merge ddev hooks and custom commands",
+			base: Defaults(),
+			override: &Config{
+				DDEV: DDEVConfig{
+					CustomCommands: []DDEVCustomCommand{{Name: "stax-pull", Enabled: true}},
+					Hooks:          DDEVHooks{PostStart: []DDEVHook{{Exec: "composer install"}}},
+				},
+			},
+			check: func(t *testing.T, result *Config) {
+				if len(result.DDEV.CustomCommands) != 1 || len(result.DDEV.Hooks.PostStart) != 1 {
+					t.Errorf("ddev hooks and commands not merged: %+v", result.DDEV)
+				}
+			},
+		},
+		{
+			name: "merge ddev versions and profiles",
+			base: Defaults(),
+			override: &Config{
+				DDEV: DDEVConfig{MySQLType: "mariadb", NodeJSVersion: "20", XdebugEnabled: true},
+				Profiles: map[string]ProfileConfig{
+					"php83": {PHPVersion: "8.3"},
+				},
+			},
+			check: func(t *testing.T, result *Config) {
+				if result.DDEV.MySQLType != "mariadb" || result.DDEV.NodeJSVersion != "20" || !result.DDEV.XdebugEnabled {
+					t.Errorf("ddev settings not merged: %+v", result.DDEV)
+				}
+				if result.DDEV.PHPVersion != Defaults().DDEV.PHPVersion {
+					t.Errorf("php_version = %q, want the default", result.DDEV.PHPVersion)
+				}
+				if result.Profiles["php83"].PHPVersion != "8.3" {
+					t.Errorf("profiles not merged: %+v", result.Profiles)
+				}
+			},
+		},
+		{
+			name:     "keep media defaults without a media section",
+			base:     Defaults(),
+			override: &Config{},
+			check: func(t *testing.T, result *Config) {
+				if !result.Media.ProxyEnabled || !result.Media.WPEngineFallback || !result.Media.Cache.Enabled {
+					t.Errorf("media defaults lost: %+v", result.Media)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
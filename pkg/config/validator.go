@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -293,6 +294,38 @@ func validateFormats(cfg *Config, result *ValidationResult) {
 		}
 	}
 
+	// Validate media origins
+	for i, origin := range cfg.Media.Origins {
+		if u, err := url.Parse(origin); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    fmt.Sprintf("media.origins[%d]", i),
+				Message:  "must be an http or https URL",
+				Severity: SeverityError,
+				Fix:      "Use a URL like 'https://media.example.com'",
+			})
+		}
+	}
+
+	// Validate media resize settings
+	if q := cfg.Media.Resize.Quality; q < 0 || q > 100 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    "media.resize.quality",
+			Message:  "must be between 1 and 100",
+			Severity: SeverityError,
+			Fix:      "Use 82, the WordPress default",
+		})
+	}
+	for i, size := range cfg.Media.Resize.Sizes {
+		if size.Width < 0 || size.Height < 0 || (size.Width == 0 && size.Height == 0) {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    fmt.Sprintf("media.resize.sizes[%d]", i),
+				Message:  "needs a positive width or height",
+				Severity: SeverityError,
+				Fix:      "Set width and height in pixels, using 0 for no limit on one of them",
+			})
+		}
+	}
+
 	// Validate PHP version
 	validPHPVersions := []string{"7.4", "8.0", "8.1", "8.2", "8.3"}
 	if cfg.DDEV.PHPVersion != "" && !contains(validPHPVersions, cfg.DDEV.PHPVersion) {
@@ -303,6 +336,24 @@ func validateFormats(cfg *Config, result *ValidationResult) {
 			Fix:      "Use a supported PHP version: 7.4, 8.0, 8.1, 8.2, or 8.3",
 		})
 	}
+	for name, profile := range cfg.Profiles {
+		if profile.PHPVersion != "" && !contains(validPHPVersions, profile.PHPVersion) {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    fmt.Sprintf("profiles.%s.php_version", name),
+				Message:  fmt.Sprintf("must be one of: %s", strings.Join(validPHPVersions, ", ")),
+				Severity: SeverityError,
+				Fix:      "Use a supported PHP version: 7.4, 8.0, 8.1, 8.2, or 8.3",
+			})
+		}
+		if profile.MySQLType != "" && profile.MySQLType != "mysql" && profile.MySQLType != "mariadb" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    fmt.Sprintf("profiles.%s.mysql_type", name),
+				Message:  "must be mysql or mariadb",
+				Severity: SeverityError,
+				Fix:      "Use mysql or mariadb",
+			})
+		}
+	}
 }
 
 // validateConstraints checks cross-field constraints
@@ -344,6 +395,30 @@ func validateConstraints(cfg *Config, result *ValidationResult) {
 		}
 	}
 
+	// Check for duplicate site IDs
+	idMap := make(map[int]bool)
+	for i, site := range cfg.Network.Sites {
+		if site.ID < 0 {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:    fmt.Sprintf("network.sites[%d].id", i),
+				Message:  "must be a positive blog ID",
+				Severity: SeverityError,
+				Fix:      "Use the ID shown by 'wp site list', or remove it to detect it",
+			})
+		}
+		if site.ID > 0 {
+			if idMap[site.ID] {
+				result.Errors = append(result.Errors, ValidationError{
+					Field:    fmt.Sprintf("network.sites[%d].id", i),
+					Message:  fmt.Sprintf("duplicate site ID %d", site.ID),
+					Severity: SeverityError,
+					Fix:      "Use a unique ID for each site",
+				})
+			}
+			idMap[site.ID] = true
+		}
+	}
+
 	// Check for duplicate site slugs
 	slugMap := make(map[string]bool)
 	for i, site := range cfg.Network.Sites {
@@ -427,6 +502,33 @@ func validateWPEngine(cfg *Config, result *ValidationResult) {
 			Fix:      "",
 		})
 	}
+
+	switch cfg.WPEngine.Backup.Checkpoint {
+	case "", CheckpointProduction, CheckpointAlways:
+	case CheckpointNever:
+		result.Warnings = append(result.Warnings, ValidationError{
+			Field:    "wpengine.backup.checkpoint",
+			Message:  "pushes will not wait for a WPEngine backup, even to production",
+			Severity: SeverityWarning,
+			Fix:      "Set checkpoint to 'production' to require a backup before production pushes",
+		})
+	default:
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    "wpengine.backup.checkpoint",
+			Message:  fmt.Sprintf("invalid checkpoint policy '%s'", cfg.WPEngine.Backup.Checkpoint),
+			Severity: SeverityError,
+			Fix:      "Use one of: production, always, never",
+		})
+	}
+
+	if cfg.WPEngine.Backup.CheckpointTimeout < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:    "wpengine.backup.checkpoint_timeout",
+			Message:  "must not be negative",
+			Severity: SeverityError,
+			Fix:      "Set a timeout in seconds, e.g. 1800",
+		})
+	}
 }
 
 // validateNetwork validates network-specific configuration
@@ -0,0 +1,214 @@
+package ddev
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// CommandTemplate is a DDEV custom command stax can generate
+type CommandTemplate struct {
+	Name        string
+	Location    string // web or host
+	Description string
+	Usage       string
+	Example     string
+	Script      string
+}
+
+// CustomCommand is a custom command requested in .stax.yml
+type CustomCommand struct {
+	Name        string
+	Description string // replaces the template description when set
+	Enabled     bool
+}
+
+// CommandOptions is the project data the command scripts are built from
+// The scripts run without stax, so everything they need is written into them
+type CommandOptions struct {
+	ProjectName  string
+	Install      string
+	SSHGateway   string
+	RemoteURL    string // URL of the WPEngine environment pulled from
+	LocalURL     string
+	Network      bool // run search-replace across the network
+	Sites        []CommandSite
+	SnapshotDir  string
+	BuildCommand string
+}
+
+// CommandSite is the search-replace of one network site
+type CommandSite struct {
+	From string
+	To   string
+	URL  string // local domain, passed to wp --url
+}
+
+const commandHeader = `#!/usr/bin/env bash
+## ` + generatedMarker + ` from .stax.yml, run 'stax ddev sync' to update
+## Description: {{.Description}}
+## Usage: {{.Usage}}
+## Example: {{.Example}}
+
+set -euo pipefail
+`
+
+const staxPullScript = `
+INSTALL="${1:-{{.Options.Install}}}"
+GATEWAY="${WPENGINE_SSH_GATEWAY:-{{.Options.SSHGateway}}}"
+if [ -z "$INSTALL" ]; then
+  echo "Usage: ddev stax-pull <install>" >&2
+  exit 1
+fi
+
+# ddev import-db detects gzip by the file extension
+DUMP_DIR="$(mktemp -d "${TMPDIR:-/tmp}/stax-pull.XXXXXX")"
+trap 'rm -rf "$DUMP_DIR"' EXIT
+DUMP="$DUMP_DIR/$INSTALL.sql.gz"
+
+echo "Exporting the database from $INSTALL..."
+ssh "$INSTALL@$INSTALL.$GATEWAY" "wp db export --add-drop-table - | gzip" > "$DUMP"
+
+echo "Importing the database..."
+ddev import-db --file="$DUMP"
+{{if .Options.RemoteURL}}
+if [ "$INSTALL" = "{{.Options.Install}}" ]; then
+  echo "Replacing {{.Options.RemoteURL}} with {{.Options.LocalURL}}..."
+  ddev wp search-replace "{{.Options.RemoteURL}}" "{{.Options.LocalURL}}" --skip-columns=guid{{if .Options.Network}} --network{{end}}
+{{- range .Options.Sites}}
+  ddev wp search-replace "{{.From}}" "{{.To}}" --url="{{.URL}}" --skip-columns=guid || echo "Search-replace failed for {{.URL}}" >&2
+{{- end}}
+fi
+{{end}}
+ddev wp cache flush || true
+echo "Database pulled from $INSTALL"
+`
+
+const staxSnapshotScript = `
+NAME="${1:-manual}"
+DIR="${STAX_SNAPSHOT_DIR:-{{.Options.SnapshotDir}}}"
+DIR="${DIR/#\~/$HOME}"
+mkdir -p "$DIR"
+
+FILE="$DIR/{{.Options.ProjectName}}-$(date +%Y%m%d-%H%M%S)-$NAME.sql.gz"
+ddev export-db --gzip --file="$FILE"
+echo "Snapshot saved to $FILE"
+`
+
+const staxBuildScript = `
+cd /var/www/html
+{{.Options.BuildCommand}} "$@"
+`
+
+// CommandTemplates are the custom commands stax can generate, by name
+var CommandTemplates = map[string]CommandTemplate{
+	"stax-pull": {
+		Name:        "stax-pull",
+		Location:    "host",
+		Description: "Pull the database from WPEngine and replace its URLs",
+		Usage:       "stax-pull [install]",
+		Example:     `"ddev stax-pull" or "ddev stax-pull mysite-staging"`,
+		Script:      staxPullScript,
+	},
+	"stax-snapshot": {
+		Name:        "stax-snapshot",
+		Location:    "host",
+		Description: "Save a gzipped database snapshot where stax keeps them",
+		Usage:       "stax-snapshot [name]",
+		Example:     `"ddev stax-snapshot" or "ddev stax-snapshot before-upgrade"`,
+		Script:      staxSnapshotScript,
+	},
+	"stax-build": {
+		Name:        "stax-build",
+		Location:    "web",
+		Description: "Run the project build scripts",
+		Usage:       "stax-build",
+		Example:     `"ddev stax-build"`,
+		Script:      staxBuildScript,
+	},
+}
+
+// CommandTemplateNames returns the names of the command templates, sorted
+func CommandTemplateNames() []string {
+	names := make([]string, 0, len(CommandTemplates))
+	for name := range CommandTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RenderCommand renders the script of a custom command
+func RenderCommand(command CustomCommand, options CommandOptions) ([]byte, error) {
+	tmpl, ok := CommandTemplates[command.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown custom command %q (available: %s)", command.Name, strings.Join(CommandTemplateNames(), ", "))
+	}
+	if command.Description != "" {
+		tmpl.Description = command.Description
+	}
+
+	t, err := template.New(command.Name).Parse(commandHeader + tmpl.Script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	data := struct {
+		CommandTemplate
+		Options CommandOptions
+	}{tmpl, options}
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// PlanCustomCommands compares the enabled commands with .ddev/commands
+// Scripts generated for commands that are no longer enabled are removed;
+// scripts stax did not generate are never touched
+func PlanCustomCommands(projectPath string, commands []CustomCommand, options CommandOptions) ([]GeneratedFile, error) {
+	var files []GeneratedFile
+	wanted := make(map[string]bool)
+
+	for _, command := range commands {
+		if !command.Enabled {
+			continue
+		}
+		content, err := RenderCommand(command, options)
+		if err != nil {
+			return nil, err
+		}
+		path := commandPath(command.Name)
+		wanted[path] = true
+
+		file, err := planGeneratedFile(projectPath, command.Name, path, content, 0755)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	for _, name := range CommandTemplateNames() {
+		path := commandPath(name)
+		if wanted[path] {
+			continue
+		}
+		file, err := planGeneratedRemoval(projectPath, name, path)
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			files = append(files, *file)
+		}
+	}
+
+	return files, nil
+}
+
+// commandPath returns where DDEV looks for the command script
+func commandPath(name string) string {
+	return filepath.Join(".ddev", "commands", CommandTemplates[name].Location, name)
+}
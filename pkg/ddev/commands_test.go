@@ -0,0 +1,149 @@
+package ddev
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderCommand(t *testing.T) {
+	options := CommandOptions{
+		ProjectName:  "site",
+		Install:      "siteinstall",
+		SSHGateway:   "ssh.wpengine.net",
+		RemoteURL:    "https://site.com",
+		LocalURL:     "https://site.ddev.site",
+		Network:      true,
+		Sites:        []CommandSite{{From: "https://blog.site.com", To: "https://blog.site.local", URL: "blog.site.local"}},
+		SnapshotDir:  "~/.stax/snapshots",
+		BuildCommand: "bash scripts/build.sh",
+	}
+
+	tests := map[string][]string{
+		"stax-pull": {
+			`INSTALL="${1:-siteinstall}"`,
+			`ssh "$INSTALL@$INSTALL.$GATEWAY" "wp db export --add-drop-table - | gzip"`,
+			`ddev wp search-replace "https://site.com" "https://site.ddev.site" --skip-columns=guid --network`,
+			`--url="blog.site.local"`,
+		},
+		"stax-snapshot": {`DIR="${STAX_SNAPSHOT_DIR:-~/.stax/snapshots}"`, `site-$(date +%Y%m%d-%H%M%S)-$NAME.sql.gz`},
+		"stax-build":    {`bash scripts/build.sh "$@"`},
+	}
+	for name, wants := range tests {
+		script, err := RenderCommand(CustomCommand{Name: name, Enabled: true}, options)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, want := range append(wants, "#!/usr/bin/env bash", "## Description: ", "## "+generatedMarker) {
+			if !strings.Contains(string(script), want) {
+				t.Errorf("%s missing %q:\n%s", name, want, script)
+			}
+		}
+		if bash, err := exec.LookPath("bash"); err == nil {
+			cmd := exec.Command(bash, "-n")
+			cmd.Stdin = strings.NewReader(string(script))
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("%s is not valid bash: %v\n%s", name, err, out)
+			}
+		}
+	}
+
+	if _, err := RenderCommand(CustomCommand{Name: "stax-unknown"}, options); err == nil {
+		t.Error("expected an unknown command to fail")
+	}
+}
+
+func TestStaxPullImportsGzip(t *testing.T) {
+	script, err := RenderCommand(CustomCommand{Name: "stax-pull", Enabled: true}, CommandOptions{Install: "siteinstall"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dump, imported string
+	for _, line := range strings.Split(string(script), "\n") {
+		if strings.HasPrefix(line, "DUMP=") {
+			dump = strings.Trim(strings.TrimPrefix(line, "DUMP="), `"`)
+		}
+		if strings.HasPrefix(line, "ddev import-db ") {
+			imported = line
+		}
+	}
+	// Without the extension ddev import-db reads the gzip as plain SQL
+	if !strings.HasSuffix(dump, ".sql.gz") {
+		t.Errorf("dump file %q has no .sql.gz extension", dump)
+	}
+	if imported != `ddev import-db --file="$DUMP"` {
+		t.Errorf("import = %q", imported)
+	}
+}
+
+func TestPlanCustomCommands(t *testing.T) {
+	dir := t.TempDir()
+	commands := []CustomCommand{
+		{Name: "stax-pull", Enabled: true},
+		{Name: "stax-build", Description: "Build the theme", Enabled: true},
+		{Name: "stax-snapshot", Enabled: false},
+	}
+
+	// A team-owned script with a stax name is left alone
+	writeDDEVFile(t, dir, filepath.Join("commands", "web", "stax-build"), "#!/bin/bash\nnpm run build\n")
+
+	files, err := PlanCustomCommands(dir, commands, CommandOptions{ProjectName: "site"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyGeneratedFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, ".ddev", "commands", "host", "stax-pull"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("stax-pull is not executable: %v", info.Mode())
+	}
+	if got := readDDEVFile(t, dir, filepath.Join("commands", "web", "stax-build")); got != "#!/bin/bash\nnpm run build\n" {
+		t.Errorf("team script was overwritten:\n%s", got)
+	}
+
+	// Disabling a command removes the generated script
+	commands[0].Enabled = false
+	files, err = PlanCustomCommands(dir, commands, CommandOptions{ProjectName: "site"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := make(map[string]SyncAction)
+	for _, file := range files {
+		actions[file.Name] = file.Action
+	}
+	if actions["stax-pull"] != SyncRemoved || actions["stax-build"] != SyncKept {
+		t.Errorf("actions = %v", actions)
+	}
+	if err := ApplyGeneratedFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ddev", "commands", "host", "stax-pull")); !os.IsNotExist(err) {
+		t.Errorf("stax-pull should be removed, got %v", err)
+	}
+}
+
+func TestGenerateConfigHooks(t *testing.T) {
+	config, err := GenerateConfig(t.TempDir(), ConfigOptions{
+		PreStartHooks: []string{"./scripts/check-docker.sh"},
+		PreStopHooks:  []string{"wp cache flush"},
+		PostStopHooks: []string{"echo stopped"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks := config.Hooks
+	if hooks == nil || len(hooks.PreStart) != 1 || hooks.PreStart[0].ExecHost == "" || hooks.PreStop[0].Exec == "" || hooks.PostStop[0].ExecHost == "" {
+		t.Errorf("hooks = %+v", hooks)
+	}
+	if len(hooks.PostStart) != 0 {
+		t.Errorf("no post-start hooks expected, got %+v", hooks.PostStart)
+	}
+}
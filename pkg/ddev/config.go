@@ -103,6 +103,23 @@ func GenerateConfig(projectPath string, options ConfigOptions) (*DDEVConfig, err
 		}
 	}
 
+	// Pre-start and post-stop hooks run while the containers are down, so
+	// they can only run on the host
+	if len(options.PreStartHooks) > 0 || len(options.PreStopHooks) > 0 || len(options.PostStopHooks) > 0 {
+		if config.Hooks == nil {
+			config.Hooks = &DDEVHooks{}
+		}
+		for _, hook := range options.PreStartHooks {
+			config.Hooks.PreStart = append(config.Hooks.PreStart, HookCommand{ExecHost: hook})
+		}
+		for _, hook := range options.PreStopHooks {
+			config.Hooks.PreStop = append(config.Hooks.PreStop, HookCommand{Exec: hook})
+		}
+		for _, hook := range options.PostStopHooks {
+			config.Hooks.PostStop = append(config.Hooks.PostStop, HookCommand{ExecHost: hook})
+		}
+	}
+
 	return config, nil
 }
 
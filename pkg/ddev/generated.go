@@ -0,0 +1,84 @@
+package ddev
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// generatedMarker identifies files stax may rewrite or remove
+const generatedMarker = "Generated by Stax"
+
+// GeneratedFile is one file stax generates under .ddev from .stax.yml
+type GeneratedFile struct {
+	Name    string
+	Path    string // relative to the project
+	Action  SyncAction
+	content []byte
+	mode    os.FileMode
+}
+
+// Changed reports whether applying the file writes or removes anything
+func (f GeneratedFile) Changed() bool {
+	return f.Action == SyncAdded || f.Action == SyncUpdated || f.Action == SyncRemoved
+}
+
+// planGeneratedFile compares content with the file at path
+// A file stax did not generate is kept as it is
+func planGeneratedFile(projectPath, name, path string, content []byte, mode os.FileMode) (GeneratedFile, error) {
+	file := GeneratedFile{Name: name, Path: path, content: content, mode: mode}
+	existing, err := readOptional(filepath.Join(projectPath, path))
+	switch {
+	case err != nil:
+		return file, fmt.Errorf("failed to read %s: %w", path, err)
+	case existing == nil:
+		file.Action = SyncAdded
+	case !bytes.Contains(existing, []byte(generatedMarker)):
+		file.Action = SyncKept
+	case bytes.Equal(existing, content):
+		file.Action = SyncUnchanged
+	default:
+		file.Action = SyncUpdated
+	}
+	return file, nil
+}
+
+// planGeneratedRemoval returns the removal of the file at path, or nil if it
+// does not exist or stax did not generate it
+func planGeneratedRemoval(projectPath, name, path string) (*GeneratedFile, error) {
+	existing, err := readOptional(filepath.Join(projectPath, path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if existing == nil || !bytes.Contains(existing, []byte(generatedMarker)) {
+		return nil, nil
+	}
+	return &GeneratedFile{Name: name, Path: path, Action: SyncRemoved}, nil
+}
+
+// ApplyGeneratedFiles writes and removes the planned files
+func ApplyGeneratedFiles(projectPath string, files []GeneratedFile) error {
+	for _, file := range files {
+		path := filepath.Join(projectPath, file.Path)
+		switch file.Action {
+		case SyncAdded, SyncUpdated:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(file.Path), err)
+			}
+			if err := os.WriteFile(path, file.content, file.mode); err != nil {
+				return fmt.Errorf("failed to write %s: %w", file.Path, err)
+			}
+			// WriteFile keeps the mode of an existing file
+			if err := os.Chmod(path, file.mode); err != nil {
+				return fmt.Errorf("failed to set permissions of %s: %w", file.Path, err)
+			}
+		case SyncRemoved:
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove %s: %w", file.Path, err)
+			}
+		}
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package ddev
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
 	"time"
 )
@@ -136,6 +138,7 @@ func (m *Manager) GetStatus() (*DDEVStatus, error) {
 	if err := json.Unmarshal(output, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse describe output: %w", err)
 	}
+	result = describeResult(result)
 
 	status := &DDEVStatus{
 		ProjectName: getStringValue(result, "name"),
@@ -161,7 +164,12 @@ func (m *Manager) GetStatus() (*DDEVStatus, error) {
 
 // Describe returns detailed project information
 func (m *Manager) Describe() (*ProjectInfo, error) {
-	cmd := exec.Command("ddev", "describe", "-j")
+	return m.describe(context.Background())
+}
+
+// describe runs ddev describe, stopping when ctx is done
+func (m *Manager) describe(ctx context.Context) (*ProjectInfo, error) {
+	cmd := exec.CommandContext(ctx, "ddev", "describe", "-j")
 	cmd.Dir = m.ProjectDir
 
 	output, err := cmd.Output()
@@ -173,6 +181,7 @@ func (m *Manager) Describe() (*ProjectInfo, error) {
 	if err := json.Unmarshal(output, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse describe output: %w", err)
 	}
+	result = describeResult(result)
 
 	urls := getURLs(result)
 	status := getStringValue(result, "status")
@@ -294,6 +303,9 @@ func (m *Manager) Logs(options *LogOptions) error {
 	cmd.Dir = m.ProjectDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if options.Output != nil {
+		cmd.Stdout = options.Output
+	}
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to get logs: %w", err)
@@ -419,30 +431,12 @@ func (m *Manager) Config(options ConfigOptions) error {
 	return nil
 }
 
-// WaitForReady waits for DDEV services to be ready
+// WaitForReady waits for the containers, database and web server to be ready
+// WordPress is not probed, as it may not be installed yet
 func (m *Manager) WaitForReady(timeout time.Duration) error {
-	start := time.Now()
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			running, err := m.IsRunning()
-			if err != nil {
-				return err
-			}
-			if running {
-				// Give it a bit more time for services to fully initialize
-				time.Sleep(2 * time.Second)
-				return nil
-			}
-
-			if time.Since(start) > timeout {
-				return fmt.Errorf("timeout waiting for DDEV to be ready")
-			}
-		}
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return m.WaitForReadiness(ctx, ReadinessOptions{SkipWordPress: true})
 }
 
 // Helper functions
@@ -520,11 +514,47 @@ func getHostnames(result map[string]interface{}) []string {
 	return hostnames
 }
 
+// describeResult returns the project description of 'ddev describe -j',
+// which DDEV wraps in a log entry under "raw"
+func describeResult(result map[string]interface{}) map[string]interface{} {
+	if raw, ok := result["raw"].(map[string]interface{}); ok {
+		return raw
+	}
+	return result
+}
+
 func parseServices(result map[string]interface{}) []ServiceStatus {
 	services := []ServiceStatus{}
 
-	// DDEV doesn't provide detailed service info in JSON,
-	// so we return basic info
+	// DDEV lists every container, extra services included, under "services"
+	if list, ok := result["services"].(map[string]interface{}); ok && len(list) > 0 {
+		names := make([]string, 0, len(list))
+		for name := range list {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			service, _ := list[name].(map[string]interface{})
+			status := ServiceStatus{
+				Name:  name,
+				State: getStringValue(service, "status"),
+				Image: getStringValue(service, "image"),
+			}
+			if ports := getStringValue(service, "exposed_ports"); ports != "" {
+				status.Ports = strings.Split(ports, ",")
+			}
+			switch status.State {
+			case "healthy", "unhealthy", "starting":
+				status.Health = status.State
+				status.State = "running"
+			}
+			services = append(services, status)
+		}
+		return services
+	}
+
+	// Older DDEV versions don't list services, so we return basic info
 	services = append(services, ServiceStatus{
 		Name:  "web",
 		State: getStringValue(result, "status"),
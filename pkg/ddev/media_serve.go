@@ -0,0 +1,111 @@
+package ddev
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// DefaultMediaServePort is the host port stax media serve listens on
+const DefaultMediaServePort = 8099
+
+// mediaServeFile is the name of the generated webserver snippet
+const mediaServeFile = "stax-media-serve.conf"
+
+// nginxMediaServeTemplate is included in the DDEV nginx server block
+const nginxMediaServeTemplate = `# Generated by Stax for 'stax media serve' - do not edit
+# Uploads missing locally are served by the media proxy on the host
+
+location ^~ /wp-content/uploads/ {
+    try_files $uri @stax_media;
+}
+
+location @stax_media {
+    proxy_pass http://host.docker.internal:{{.Port}};
+    proxy_set_header Host $host;
+    proxy_set_header X-Forwarded-Proto $scheme;
+    proxy_connect_timeout 5s;
+}
+`
+
+// apacheMediaServeTemplate is loaded into the DDEV Apache site configuration
+const apacheMediaServeTemplate = `# Generated by Stax for 'stax media serve' - do not edit
+# Uploads missing locally are served by the media proxy on the host
+
+<Directory "/var/www/html/wp-content/uploads">
+    RewriteEngine On
+    RewriteCond %{REQUEST_FILENAME} !-f
+    <IfModule proxy_http_module>
+        RewriteRule ^(.*)$ http://host.docker.internal:{{.Port}}/wp-content/uploads/$1 [P,L]
+    </IfModule>
+    <IfModule !proxy_http_module>
+        RewriteRule ^(.*)$ http://127.0.0.1:{{.Port}}/wp-content/uploads/$1 [R=302,L]
+    </IfModule>
+</Directory>
+`
+
+// MediaServeConfigPath returns where the snippet for a webserver type lives
+func MediaServeConfigPath(projectPath, webserverType string) (string, error) {
+	switch webserverType {
+	case "", "nginx-fpm":
+		return filepath.Join(projectPath, ".ddev", "nginx", mediaServeFile), nil
+	case "apache-fpm":
+		return filepath.Join(projectPath, ".ddev", "apache", mediaServeFile), nil
+	}
+	return "", fmt.Errorf("unsupported webserver type for media serve: %s", webserverType)
+}
+
+// GenerateMediaServeConfig writes the webserver snippet that sends missing
+// uploads to stax media serve, replacing the nginx-only media proxy config
+// It reports whether the file changed, in which case DDEV needs a restart
+func GenerateMediaServeConfig(projectPath, webserverType string, port int) (bool, error) {
+	configPath, err := MediaServeConfigPath(projectPath, webserverType)
+	if err != nil {
+		return false, err
+	}
+
+	text := nginxMediaServeTemplate
+	if webserverType == "apache-fpm" {
+		text = apacheMediaServeTemplate
+	}
+	tmpl, err := template.New("media-serve").Parse(text)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Port int }{port}); err != nil {
+		return false, fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	// Both configs claim /wp-content/uploads/, so only one can be active
+	if IsMediaProxyConfigured(projectPath) {
+		if err := RemoveMediaProxyConfig(projectPath); err != nil {
+			return false, err
+		}
+	}
+
+	if existing, err := os.ReadFile(configPath); err == nil && bytes.Equal(existing, buf.Bytes()) {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", filepath.Dir(configPath), err)
+	}
+	if err := os.WriteFile(configPath, buf.Bytes(), 0644); err != nil {
+		return false, fmt.Errorf("failed to write media serve config: %w", err)
+	}
+	return true, nil
+}
+
+// RemoveMediaServeConfig removes the media serve snippets for every webserver type
+func RemoveMediaServeConfig(projectPath string) error {
+	for _, webserverType := range []string{"nginx-fpm", "apache-fpm"} {
+		configPath, _ := MediaServeConfigPath(projectPath, webserverType)
+		if err := os.Remove(configPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove media serve config: %w", err)
+		}
+	}
+	return nil
+}
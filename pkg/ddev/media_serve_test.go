@@ -0,0 +1,61 @@
+package ddev
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateMediaServeConfig(t *testing.T) {
+	tests := []struct {
+		webserverType string
+		path          string
+		contains      string
+	}{
+		{"nginx-fpm", ".ddev/nginx/stax-media-serve.conf", "proxy_pass http://host.docker.internal:8099;"},
+		{"", ".ddev/nginx/stax-media-serve.conf", "try_files $uri @stax_media;"},
+		{"apache-fpm", ".ddev/apache/stax-media-serve.conf", "http://host.docker.internal:8099/wp-content/uploads/$1 [P,L]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.webserverType, func(t *testing.T) {
+			dir := t.TempDir()
+
+			// The nginx-only proxy config is replaced
+			if err := GenerateMediaProxyConfig(dir, GetDefaultMediaProxyOptions()); err != nil {
+				t.Fatal(err)
+			}
+
+			changed, err := GenerateMediaServeConfig(dir, tt.webserverType, DefaultMediaServePort)
+			if err != nil || !changed {
+				t.Fatalf("GenerateMediaServeConfig() = %v, %v", changed, err)
+			}
+			content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(tt.path)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(content), tt.contains) {
+				t.Errorf("config missing %q:\n%s", tt.contains, content)
+			}
+			if IsMediaProxyConfigured(dir) {
+				t.Error("nginx media proxy config was not removed")
+			}
+
+			if changed, err := GenerateMediaServeConfig(dir, tt.webserverType, DefaultMediaServePort); err != nil || changed {
+				t.Errorf("regenerating = %v, %v; want unchanged", changed, err)
+			}
+
+			if err := RemoveMediaServeConfig(dir); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(tt.path))); !os.IsNotExist(err) {
+				t.Error("config was not removed")
+			}
+		})
+	}
+
+	if _, err := GenerateMediaServeConfig(t.TempDir(), "caddy", DefaultMediaServePort); err == nil {
+		t.Error("expected an unsupported webserver type to fail")
+	}
+}
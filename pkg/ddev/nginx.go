@@ -12,6 +12,32 @@ const nginxMediaProxyTemplate = `# Remote media proxy configuration
 # This allows loading media from {{.CDNName}} without local storage
 # Generated by Stax
 
+{{range .Sites}}
+# {{.Name}} (site {{.ID}})
+location ^~ /wp-content/uploads/sites/{{.ID}}/ {
+    try_files $uri @proxy_media_site_{{.ID}};
+}
+
+location @proxy_media_site_{{.ID}} {
+    proxy_pass {{.URL}}$request_uri;
+    proxy_ssl_server_name on;
+    proxy_ssl_verify off;
+    {{if .Host}}
+    proxy_set_header Host {{.Host}};
+    {{end}}
+    {{if $.CacheEnabled}}
+    proxy_cache media_cache;
+    proxy_cache_valid 200 {{$.CacheTTL}};
+    proxy_cache_key "$scheme$request_method$host$request_uri";
+    expires {{$.CacheTTL}};
+    add_header X-Cache-Status $upstream_cache_status;
+    {{end}}
+    add_header X-Proxy-Source "{{.Name}}";
+    proxy_hide_header Set-Cookie;
+    proxy_ignore_headers Set-Cookie;
+}
+{{end}}
+
 location ~ ^/wp-content/uploads/(.*)$ {
     # Try local file first
     try_files $uri @proxy_media;
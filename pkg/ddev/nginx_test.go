@@ -0,0 +1,36 @@
+package ddev
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateMediaProxyConfigSites(t *testing.T) {
+	dir := t.TempDir()
+	options := GetDefaultMediaProxyOptions()
+	options.CDNURL = "https://network.b-cdn.net"
+	options.WPEngineURL = "https://network.wpengine.com"
+	options.Sites = []MediaProxySite{
+		{Name: "flying", ID: 3, URL: "https://network.wpengine.com", Host: "flyingmag.com"},
+	}
+
+	if err := GenerateMediaProxyConfig(dir, options); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ReadNginxConfig(dir, "media-proxy.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"location ^~ /wp-content/uploads/sites/3/ {",
+		"try_files $uri @proxy_media_site_3;",
+		"proxy_set_header Host flyingmag.com;",
+		`add_header X-Proxy-Source "flying";`,
+		"proxy_pass https://network.b-cdn.net$request_uri;",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("config missing %q:\n%s", want, content)
+		}
+	}
+}
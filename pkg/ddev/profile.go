@@ -0,0 +1,221 @@
+package ddev
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileConfigFile is the DDEV override file of the active profile
+// DDEV loads config.*.yaml in name order after config.yaml, so the zz prefix
+// lets the profile win over config.stax.yaml and any team override files
+const ProfileConfigFile = "config.zz-stax-profile.yaml"
+
+const profileMarker = "# Stax profile: "
+
+// Profile is a set of DDEV settings overridden by stax profile use
+// Empty settings are left as config.yaml has them
+type Profile struct {
+	Name          string          `yaml:"-"`
+	PHPVersion    string          `yaml:"php_version,omitempty"`
+	Database      *DatabaseConfig `yaml:"database,omitempty"`
+	XdebugEnabled *bool           `yaml:"xdebug_enabled,omitempty"`
+	NodeJSVersion string          `yaml:"nodejs_version,omitempty"`
+}
+
+// ProfileSettings are the values DDEV runs with for the settings a profile
+// can override
+type ProfileSettings struct {
+	PHPVersion    string
+	Database      DatabaseConfig
+	XdebugEnabled bool
+	NodeJSVersion string
+}
+
+// ProfilePlan is a switch from the active profile to another, or back to
+// config.yaml
+type ProfilePlan struct {
+	Current *Profile // nil when no profile is active
+	Next    *Profile // nil on reset
+	From    ProfileSettings
+	To      ProfileSettings
+
+	projectPath string
+	content     []byte
+}
+
+// PlanProfile compares the settings DDEV runs with now to those it runs with
+// once next is active
+// A nil next plans a reset to config.yaml and its override files
+func PlanProfile(projectPath string, next *Profile) (*ProfilePlan, error) {
+	current, err := ActiveProfile(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	base, err := baseSettings(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ProfilePlan{
+		Current:     current,
+		projectPath: projectPath,
+		From:        base.with(current),
+	}
+	if next != nil {
+		// DDEV needs the type and version of the database together
+		resolved := *next
+		if next.Database != nil {
+			database := *next.Database
+			if database.Type == "" {
+				database.Type = base.Database.Type
+			}
+			if database.Version == "" {
+				database.Version = base.Database.Version
+			}
+			resolved.Database = &database
+		}
+		plan.Next = &resolved
+
+		plan.content, err = generateProfileConfig(resolved)
+		if err != nil {
+			return nil, err
+		}
+	}
+	plan.To = base.with(plan.Next)
+
+	return plan, nil
+}
+
+// HasChanges reports whether DDEV settings change
+func (p *ProfilePlan) HasChanges() bool {
+	return p.From != p.To
+}
+
+// DatabaseChanged reports whether the database type or version changes
+// DDEV does not start on a database created by another version, so the
+// database has to be exported and recreated
+func (p *ProfilePlan) DatabaseChanged() bool {
+	return p.From.Database != p.To.Database
+}
+
+// NeedsRestart reports whether the containers must be restarted to apply the
+// plan; Xdebug alone can be switched in the running container
+func (p *ProfilePlan) NeedsRestart() bool {
+	from, to := p.From, p.To
+	from.XdebugEnabled, to.XdebugEnabled = false, false
+	return from != to
+}
+
+// Apply writes the override file of the next profile, or removes it on reset
+func (p *ProfilePlan) Apply() error {
+	path := filepath.Join(p.projectPath, ".ddev", ProfileConfigFile)
+	if p.Next == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", ProfileConfigFile, err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create .ddev directory: %w", err)
+	}
+	if err := os.WriteFile(path, p.content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ProfileConfigFile, err)
+	}
+	return nil
+}
+
+// ActiveProfile returns the profile whose override file is in .ddev, or nil
+func ActiveProfile(projectPath string) (*Profile, error) {
+	data, err := readOptional(filepath.Join(projectPath, ".ddev", ProfileConfigFile))
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var profile Profile
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ProfileConfigFile, err)
+	}
+	firstLine := strings.SplitN(string(data), "\n", 2)[0]
+	profile.Name = strings.TrimSpace(strings.TrimPrefix(firstLine, profileMarker))
+	if !strings.HasPrefix(firstLine, profileMarker) || profile.Name == "" {
+		profile.Name = "unknown"
+	}
+	return &profile, nil
+}
+
+// generateProfileConfig renders the override file of a profile
+func generateProfileConfig(profile Profile) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(profileMarker + profile.Name + "\n")
+	buf.WriteString("# " + generatedMarker + ", run 'stax profile reset' to remove it\n")
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(profile); err != nil {
+		return nil, fmt.Errorf("failed to marshal profile: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal profile: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// baseSettings reads the settings of config.yaml and the override files DDEV
+// loads after it, other than the profile file
+func baseSettings(projectPath string) (ProfileSettings, error) {
+	ddevDir := filepath.Join(projectPath, ".ddev")
+	overrides, err := filepath.Glob(filepath.Join(ddevDir, "config.*.yaml"))
+	if err != nil {
+		return ProfileSettings{}, fmt.Errorf("failed to list DDEV override files: %w", err)
+	}
+	sort.Strings(overrides)
+
+	var settings ProfileSettings
+	for _, path := range append([]string{filepath.Join(ddevDir, "config.yaml")}, overrides...) {
+		if filepath.Base(path) == ProfileConfigFile {
+			continue
+		}
+		data, err := readOptional(path)
+		if err != nil {
+			return ProfileSettings{}, err
+		}
+		var file Profile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return ProfileSettings{}, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+		}
+		settings = settings.with(&file)
+	}
+	return settings, nil
+}
+
+// with returns the settings with the non-empty values of profile applied
+func (s ProfileSettings) with(profile *Profile) ProfileSettings {
+	if profile == nil {
+		return s
+	}
+	if profile.PHPVersion != "" {
+		s.PHPVersion = profile.PHPVersion
+	}
+	if profile.Database != nil {
+		if profile.Database.Type != "" {
+			s.Database.Type = profile.Database.Type
+		}
+		if profile.Database.Version != "" {
+			s.Database.Version = profile.Database.Version
+		}
+	}
+	if profile.XdebugEnabled != nil {
+		s.XdebugEnabled = *profile.XdebugEnabled
+	}
+	if profile.NodeJSVersion != "" {
+		s.NodeJSVersion = profile.NodeJSVersion
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+package ddev
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeDDEVFile(t, dir, "config.yaml", `name: site
+php_version: "8.1"
+database:
+  type: mysql
+  version: "8.0"
+xdebug_enabled: false
+`)
+	// Team overrides apply below the profile
+	writeDDEVFile(t, dir, "config.local.yaml", "nodejs_version: \"20\"\n")
+
+	enabled := true
+	plan, err := PlanProfile(dir, &Profile{Name: "php83", PHPVersion: "8.3", XdebugEnabled: &enabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Current != nil {
+		t.Errorf("no profile should be active, got %+v", plan.Current)
+	}
+	if plan.From.PHPVersion != "8.1" || plan.From.NodeJSVersion != "20" {
+		t.Errorf("from = %+v", plan.From)
+	}
+	if plan.To.PHPVersion != "8.3" || !plan.To.XdebugEnabled || plan.DatabaseChanged() || !plan.NeedsRestart() {
+		t.Errorf("to = %+v", plan.To)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	active, err := ActiveProfile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active == nil || active.Name != "php83" || active.PHPVersion != "8.3" {
+		t.Fatalf("active = %+v", active)
+	}
+	if content := readDDEVFile(t, dir, ProfileConfigFile); !strings.Contains(content, generatedMarker) || strings.Contains(content, "database") {
+		t.Errorf("profile file =\n%s", content)
+	}
+
+	// A database version alone keeps the configured type
+	plan, err = PlanProfile(dir, &Profile{Name: "mysql84", Database: &DatabaseConfig{Version: "8.4"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.DatabaseChanged() || plan.To.Database != (DatabaseConfig{Type: "mysql", Version: "8.4"}) {
+		t.Errorf("database %+v -> %+v", plan.From.Database, plan.To.Database)
+	}
+	if plan.From.PHPVersion != "8.3" || plan.To.PHPVersion != "8.1" {
+		t.Errorf("php %s -> %s, want the profile replaced", plan.From.PHPVersion, plan.To.PHPVersion)
+	}
+
+	// Reset goes back to config.yaml and removes the file
+	plan, err = PlanProfile(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Current == nil || plan.To.PHPVersion != "8.1" || plan.To.XdebugEnabled {
+		t.Errorf("reset to = %+v", plan.To)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ddev", ProfileConfigFile)); !os.IsNotExist(err) {
+		t.Errorf("profile file should be removed, got %v", err)
+	}
+}
+
+func TestPlanProfileXdebugOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeDDEVFile(t, dir, "config.yaml", "php_version: \"8.2\"\nxdebug_enabled: false\n")
+
+	enabled := true
+	plan, err := PlanProfile(dir, &Profile{Name: "debug", XdebugEnabled: &enabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.HasChanges() || plan.NeedsRestart() {
+		t.Errorf("xdebug only: changes = %v, restart = %v", plan.HasChanges(), plan.NeedsRestart())
+	}
+}
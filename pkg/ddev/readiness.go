@@ -0,0 +1,341 @@
+package ddev
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Readiness probes, in the order they run
+const (
+	ProbeContainers = "containers"
+	ProbeDatabase   = "db"
+	ProbeWeb        = "web"
+	ProbeWordPress  = "wordpress"
+)
+
+// DefaultProbeTimeouts are how long each probe is retried before it fails
+var DefaultProbeTimeouts = map[string]time.Duration{
+	ProbeContainers: 60 * time.Second,
+	ProbeDatabase:   60 * time.Second,
+	ProbeWeb:        30 * time.Second,
+	ProbeWordPress:  20 * time.Second,
+}
+
+// Probe checks that one part of the environment is ready
+// Check returns an error describing why it is not; errors from
+// FinalProbeError are not retried
+type Probe struct {
+	Name    string
+	Timeout time.Duration
+	Check   func(ctx context.Context) error
+}
+
+// ProbeEventType is what happened to a probe
+type ProbeEventType string
+
+const (
+	ProbeStarted  ProbeEventType = "started"
+	ProbeRetrying ProbeEventType = "retrying"
+	ProbeReady    ProbeEventType = "ready"
+	ProbeFailed   ProbeEventType = "failed"
+	ProbeSkipped  ProbeEventType = "skipped"
+)
+
+// ProbeEvent reports the progress of a probe
+type ProbeEvent struct {
+	Probe   string
+	Type    ProbeEventType
+	Attempt int
+	Elapsed time.Duration
+	Reason  string // why the last attempt failed
+}
+
+// ProbeFailure is a probe that did not pass
+type ProbeFailure struct {
+	Probe    string
+	Reason   string
+	Attempts int
+	Elapsed  time.Duration
+	Skipped  bool // not run because an earlier probe failed
+}
+
+// ReadinessError lists the probes that did not pass
+type ReadinessError struct {
+	Failures []ProbeFailure
+}
+
+func (e *ReadinessError) Error() string {
+	reasons := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		if !failure.Skipped {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", failure.Probe, failure.Reason))
+		}
+	}
+	return "environment is not ready: " + strings.Join(reasons, "; ")
+}
+
+// ReadinessOptions configures the readiness probes
+type ReadinessOptions struct {
+	Interval      time.Duration            // between attempts, 1s by default
+	Timeouts      map[string]time.Duration // replace DefaultProbeTimeouts per probe
+	SkipWordPress bool                     // for projects without WordPress installed yet
+	OnEvent       func(ProbeEvent)
+}
+
+// finalProbeError is a probe result that retrying will not change
+type finalProbeError struct {
+	reason string
+}
+
+func (e *finalProbeError) Error() string {
+	return e.reason
+}
+
+// FinalProbeError returns a probe error that fails the probe without retrying
+func FinalProbeError(format string, args ...interface{}) error {
+	return &finalProbeError{reason: fmt.Sprintf(format, args...)}
+}
+
+// WaitForReadiness runs the readiness probes: the containers are up, the
+// database accepts connections, the web server answers on the primary URL and
+// WordPress is installed
+func (m *Manager) WaitForReadiness(ctx context.Context, options ReadinessOptions) error {
+	return RunProbes(ctx, m.readinessProbes(options), options)
+}
+
+// RunProbes runs probes in order, retrying each until it passes or its
+// timeout ends
+// Once a probe fails, the probes after it are skipped, as they depend on it
+func RunProbes(ctx context.Context, probes []Probe, options ReadinessOptions) error {
+	interval := options.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	emit := func(event ProbeEvent) {
+		if options.OnEvent != nil {
+			options.OnEvent(event)
+		}
+	}
+
+	var failures []ProbeFailure
+	failed := ""
+	for _, probe := range probes {
+		if failed != "" {
+			reason := fmt.Sprintf("not checked, %s is not ready", failed)
+			failures = append(failures, ProbeFailure{Probe: probe.Name, Reason: reason, Skipped: true})
+			emit(ProbeEvent{Probe: probe.Name, Type: ProbeSkipped, Reason: reason})
+			continue
+		}
+
+		if failure := runProbe(ctx, probe, interval, emit); failure != nil {
+			failures = append(failures, *failure)
+			failed = probe.Name
+		}
+	}
+
+	if len(failures) > 0 {
+		return &ReadinessError{Failures: failures}
+	}
+	return nil
+}
+
+// runProbe retries a probe until it passes, returning why it did not
+func runProbe(ctx context.Context, probe Probe, interval time.Duration, emit func(ProbeEvent)) *ProbeFailure {
+	start := time.Now()
+	probeCtx, cancel := context.WithTimeout(ctx, probe.Timeout)
+	defer cancel()
+
+	emit(ProbeEvent{Probe: probe.Name, Type: ProbeStarted})
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		err := probe.Check(probeCtx)
+		if err == nil {
+			emit(ProbeEvent{Probe: probe.Name, Type: ProbeReady, Attempt: attempt, Elapsed: time.Since(start)})
+			return nil
+		}
+		// An attempt cut off by the timeout says less than the one before it
+		if probeCtx.Err() == nil || lastErr == nil {
+			lastErr = err
+		}
+
+		var final *finalProbeError
+		if !errors.As(err, &final) {
+			if probeCtx.Err() == nil {
+				emit(ProbeEvent{Probe: probe.Name, Type: ProbeRetrying, Attempt: attempt, Elapsed: time.Since(start), Reason: err.Error()})
+				select {
+				case <-probeCtx.Done():
+				case <-time.After(interval):
+					continue
+				}
+			}
+			if ctx.Err() == nil {
+				lastErr = fmt.Errorf("%w (timed out after %s)", lastErr, probe.Timeout)
+			}
+		}
+
+		failure := &ProbeFailure{
+			Probe:    probe.Name,
+			Reason:   lastErr.Error(),
+			Attempts: attempt,
+			Elapsed:  time.Since(start),
+		}
+		emit(ProbeEvent{Probe: probe.Name, Type: ProbeFailed, Attempt: attempt, Elapsed: failure.Elapsed, Reason: failure.Reason})
+		return failure
+	}
+}
+
+// readinessProbes returns the probes of the project
+func (m *Manager) readinessProbes(options ReadinessOptions) []Probe {
+	timeout := func(name string) time.Duration {
+		if t, ok := options.Timeouts[name]; ok && t > 0 {
+			return t
+		}
+		return DefaultProbeTimeouts[name]
+	}
+
+	// The web probe uses the primary URL found by the containers probe
+	var primaryURL string
+	client := &http.Client{
+		// Local certificates are not always trusted by Go
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	probes := []Probe{
+		{
+			Name:    ProbeContainers,
+			Timeout: timeout(ProbeContainers),
+			Check: func(ctx context.Context) error {
+				info, err := m.describe(ctx)
+				if err != nil {
+					return err
+				}
+				primaryURL = info.PrimaryURL
+				return checkContainers(info)
+			},
+		},
+		{
+			Name:    ProbeDatabase,
+			Timeout: timeout(ProbeDatabase),
+			Check: func(ctx context.Context) error {
+				output, err := m.output(ctx, "mysql", "--batch", "-e", "SELECT 1")
+				if err != nil {
+					return fmt.Errorf("database does not accept connections: %s", commandReason(output, err))
+				}
+				return nil
+			},
+		},
+		{
+			Name:    ProbeWeb,
+			Timeout: timeout(ProbeWeb),
+			Check: func(ctx context.Context) error {
+				if primaryURL == "" {
+					return FinalProbeError("DDEV reports no primary URL")
+				}
+				return checkWeb(ctx, client, primaryURL)
+			},
+		},
+	}
+
+	if !options.SkipWordPress {
+		probes = append(probes, Probe{
+			Name:    ProbeWordPress,
+			Timeout: timeout(ProbeWordPress),
+			Check: func(ctx context.Context) error {
+				output, err := m.output(ctx, "wp", "core", "is-installed")
+				return checkWordPressInstalled(output, err)
+			},
+		})
+	}
+
+	return probes
+}
+
+// checkContainers reports the services that are not running and healthy
+func checkContainers(info *ProjectInfo) error {
+	if !info.Running {
+		return fmt.Errorf("project is %s", orUnknown(info.Status))
+	}
+
+	var waiting []string
+	for _, service := range info.Services {
+		switch {
+		case service.State != "running":
+			waiting = append(waiting, fmt.Sprintf("%s is %s", service.Name, orUnknown(service.State)))
+		case service.Health == "starting" || service.Health == "unhealthy":
+			waiting = append(waiting, fmt.Sprintf("%s is %s", service.Name, service.Health))
+		}
+	}
+	if len(waiting) > 0 {
+		return errors.New(strings.Join(waiting, ", "))
+	}
+	return nil
+}
+
+// checkWeb requests url, accepting any 2xx or 3xx response without following
+// redirects: a redirect, such as to the canonical domain, means the web
+// server answered
+func checkWeb(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return FinalProbeError("invalid primary URL %s: %v", url, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s did not respond: %v", url, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("%s returned HTTP %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// checkWordPressInstalled interprets wp core is-installed
+// It exits 1 without output when WordPress is not installed, which waiting
+// will not change; other failures, such as the database still starting, are
+// retried
+func checkWordPressInstalled(output []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && strings.TrimSpace(string(output)) == "" {
+		return FinalProbeError("WordPress is not installed")
+	}
+	return fmt.Errorf("wp core is-installed failed: %s", commandReason(output, err))
+}
+
+// output runs a ddev command, returning its combined output
+func (m *Manager) output(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "ddev", args...)
+	cmd.Dir = m.ProjectDir
+	return cmd.CombinedOutput()
+}
+
+// commandReason returns the last line a failed command printed, or its error
+func commandReason(output []byte, err error) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return last
+	}
+	return err.Error()
+}
+
+// orUnknown returns state, or "unknown" when it is empty
+func orUnknown(state string) string {
+	if state == "" {
+		return "unknown"
+	}
+	return state
+}
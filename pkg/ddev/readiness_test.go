@@ -0,0 +1,144 @@
+package ddev
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunProbes(t *testing.T) {
+	attempts := 0
+	probes := []Probe{
+		{Name: "db", Timeout: time.Second, Check: func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("connection refused")
+			}
+			return nil
+		}},
+		{Name: "web", Timeout: time.Second, Check: func(ctx context.Context) error {
+			return FinalProbeError("no primary URL")
+		}},
+		{Name: "wordpress", Timeout: time.Second, Check: func(ctx context.Context) error {
+			t.Error("probes after a failure should not run")
+			return nil
+		}},
+	}
+
+	var events []string
+	err := RunProbes(context.Background(), probes, ReadinessOptions{
+		Interval: time.Millisecond,
+		OnEvent: func(event ProbeEvent) {
+			events = append(events, event.Probe+":"+string(event.Type))
+		},
+	})
+
+	want := "db:started db:retrying db:retrying db:ready web:started web:failed wordpress:skipped"
+	if got := strings.Join(events, " "); got != want {
+		t.Errorf("events = %s\nwant %s", got, want)
+	}
+
+	var readiness *ReadinessError
+	if !errors.As(err, &readiness) || len(readiness.Failures) != 2 {
+		t.Fatalf("err = %v", err)
+	}
+	web, wordpress := readiness.Failures[0], readiness.Failures[1]
+	if web.Probe != "web" || web.Reason != "no primary URL" || web.Attempts != 1 || web.Skipped {
+		t.Errorf("web failure = %+v", web)
+	}
+	if !wordpress.Skipped || !strings.Contains(wordpress.Reason, "web is not ready") {
+		t.Errorf("wordpress failure = %+v", wordpress)
+	}
+	if strings.Contains(err.Error(), "wordpress") {
+		t.Errorf("skipped probes should not be in the error: %v", err)
+	}
+}
+
+func TestRunProbesTimeout(t *testing.T) {
+	probes := []Probe{{Name: "web", Timeout: 50 * time.Millisecond, Check: func(ctx context.Context) error {
+		return errors.New("HTTP 502")
+	}}}
+
+	err := RunProbes(context.Background(), probes, ReadinessOptions{Interval: 10 * time.Millisecond})
+	var readiness *ReadinessError
+	if !errors.As(err, &readiness) {
+		t.Fatalf("err = %v", err)
+	}
+	failure := readiness.Failures[0]
+	if !strings.HasPrefix(failure.Reason, "HTTP 502 (timed out after") || failure.Attempts < 2 {
+		t.Errorf("failure = %+v", failure)
+	}
+}
+
+func TestCheckWeb(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status >= 300 && status < 400 {
+			http.Redirect(w, r, "/wp-admin/install.php", status)
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	for _, tt := range []struct {
+		status int
+		ok     bool
+	}{
+		{http.StatusOK, true},
+		{http.StatusNoContent, true},
+		{http.StatusMovedPermanently, true},
+		{http.StatusFound, true},
+		{http.StatusNotFound, false},
+		{http.StatusBadGateway, false},
+	} {
+		status = tt.status
+		err := checkWeb(context.Background(), client, server.URL)
+		if (err == nil) != tt.ok {
+			t.Errorf("HTTP %d: err = %v", tt.status, err)
+		}
+	}
+}
+
+func TestCheckContainers(t *testing.T) {
+	info := &ProjectInfo{Running: true, Services: []ServiceStatus{
+		{Name: "web", State: "running"},
+		{Name: "db", State: "running", Health: "starting"},
+		{Name: "redis", State: "exited"},
+	}}
+	err := checkContainers(info)
+	if err == nil || err.Error() != "db is starting, redis is exited" {
+		t.Errorf("err = %v", err)
+	}
+
+	if err := checkContainers(&ProjectInfo{Status: "stopped"}); err == nil || err.Error() != "project is stopped" {
+		t.Errorf("stopped err = %v", err)
+	}
+}
+
+func TestCheckWordPressInstalled(t *testing.T) {
+	if err := checkWordPressInstalled(nil, nil); err != nil {
+		t.Errorf("installed: %v", err)
+	}
+
+	// exit 1 without output: not installed, and not retried
+	notInstalled := exec.Command("sh", "-c", "exit 1").Run()
+	var final *finalProbeError
+	if err := checkWordPressInstalled(nil, notInstalled); !errors.As(err, &final) {
+		t.Errorf("not installed: %v", err)
+	}
+
+	// The database still starting is retried
+	err := checkWordPressInstalled([]byte("Error: Error establishing a database connection.\n"), notInstalled)
+	if err == nil || errors.As(err, &final) || !strings.Contains(err.Error(), "database connection") {
+		t.Errorf("database starting: %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package ddev
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ServiceDefinition is an extra service stax can add to DDEV
+type ServiceDefinition struct {
+	Name        string
+	Description string
+	Image       string // default image, without a tag
+	Version     string // default image tag
+	Port        int
+
+	// Constants are the WordPress constants that point WordPress at the service
+	Constants map[string]interface{}
+
+	compose string
+}
+
+// ServiceOptions is a service requested in .stax.yml
+type ServiceOptions struct {
+	Name    string
+	Version string // replaces the default tag
+	Image   string // replaces the default image
+}
+
+const serviceHeader = `# ` + generatedMarker + ` from the services section of .stax.yml
+# Run 'stax services' to change it, or remove that line to manage it yourself
+`
+
+const redisCompose = `services:
+  redis:
+    container_name: ddev-${DDEV_SITENAME}-redis
+    image: {{.Image}}
+    restart: "no"
+    command: ["redis-server", "--appendonly", "yes"]
+    expose:
+      - "6379"
+    labels:
+      com.ddev.site-name: ${DDEV_SITENAME}
+      com.ddev.approot: ${DDEV_APPROOT}
+    volumes:
+      - redis:/data
+    healthcheck:
+      test: ["CMD", "redis-cli", "ping"]
+      interval: 5s
+      timeout: 3s
+      retries: 10
+
+volumes:
+  redis:
+`
+
+const elasticsearchCompose = `services:
+  elasticsearch:
+    container_name: ddev-${DDEV_SITENAME}-elasticsearch
+    image: {{.Image}}
+    restart: "no"
+    expose:
+      - "9200"
+    environment:
+      - discovery.type=single-node
+      - xpack.security.enabled=false
+      - ES_JAVA_OPTS=-Xms512m -Xmx512m
+      - VIRTUAL_HOST=$DDEV_HOSTNAME
+      - HTTP_EXPOSE=9200:9200
+      - HTTPS_EXPOSE=9201:9200
+    labels:
+      com.ddev.site-name: ${DDEV_SITENAME}
+      com.ddev.approot: ${DDEV_APPROOT}
+    volumes:
+      - elasticsearch:/usr/share/elasticsearch/data
+    healthcheck:
+      test: ["CMD-SHELL", "curl --fail -s localhost:9200/_cluster/health"]
+      interval: 10s
+      timeout: 5s
+      retries: 30
+
+volumes:
+  elasticsearch:
+`
+
+const memcachedCompose = `services:
+  memcached:
+    container_name: ddev-${DDEV_SITENAME}-memcached
+    image: {{.Image}}
+    restart: "no"
+    command: ["memcached", "-m", "128"]
+    expose:
+      - "11211"
+    labels:
+      com.ddev.site-name: ${DDEV_SITENAME}
+      com.ddev.approot: ${DDEV_APPROOT}
+`
+
+const solrCompose = `services:
+  solr:
+    container_name: ddev-${DDEV_SITENAME}-solr
+    image: {{.Image}}
+    restart: "no"
+    command: ["solr-precreate", "wordpress"]
+    expose:
+      - "8983"
+    environment:
+      - VIRTUAL_HOST=$DDEV_HOSTNAME
+      - HTTP_EXPOSE=8983:8983
+      - HTTPS_EXPOSE=8984:8983
+    labels:
+      com.ddev.site-name: ${DDEV_SITENAME}
+      com.ddev.approot: ${DDEV_APPROOT}
+    volumes:
+      - solr:/var/solr
+
+volumes:
+  solr:
+`
+
+// Services are the extra services stax can add, by name
+var Services = map[string]ServiceDefinition{
+	"redis": {
+		Name:        "redis",
+		Description: "Redis object cache",
+		Image:       "redis",
+		Version:     "7",
+		Port:        6379,
+		Constants:   map[string]interface{}{"WP_REDIS_HOST": "redis", "WP_REDIS_PORT": 6379},
+		compose:     redisCompose,
+	},
+	"elasticsearch": {
+		Name:        "elasticsearch",
+		Description: "Elasticsearch for ElasticPress",
+		Image:       "docker.elastic.co/elasticsearch/elasticsearch",
+		Version:     "8.15.3",
+		Port:        9200,
+		Constants:   map[string]interface{}{"EP_HOST": "http://elasticsearch:9200"},
+		compose:     elasticsearchCompose,
+	},
+	"memcached": {
+		Name:        "memcached",
+		Description: "Memcached object cache",
+		Image:       "memcached",
+		Version:     "1.6",
+		Port:        11211,
+		compose:     memcachedCompose,
+	},
+	"solr": {
+		Name:        "solr",
+		Description: "Apache Solr search, with a wordpress core",
+		Image:       "solr",
+		Version:     "9",
+		Port:        8983,
+		compose:     solrCompose,
+	},
+}
+
+// ServiceNames returns the names of the services stax can add, sorted
+func ServiceNames() []string {
+	names := make([]string, 0, len(Services))
+	for name := range Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupService returns the definition of a service
+func LookupService(name string) (ServiceDefinition, error) {
+	service, ok := Services[name]
+	if !ok {
+		return ServiceDefinition{}, fmt.Errorf("unknown service %q (available: %s)", name, strings.Join(ServiceNames(), ", "))
+	}
+	return service, nil
+}
+
+// ServiceComposePath returns the docker-compose file of a service, relative to
+// the project
+func ServiceComposePath(name string) string {
+	return filepath.Join(".ddev", fmt.Sprintf("docker-compose.%s.yaml", name))
+}
+
+// ServiceImage returns the image and tag a service runs
+// An image given with a tag is used as it is
+func ServiceImage(service ServiceDefinition, options ServiceOptions) string {
+	image := options.Image
+	if image == "" {
+		image = service.Image
+	}
+	if strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") {
+		return image
+	}
+	version := options.Version
+	if version == "" {
+		version = service.Version
+	}
+	return image + ":" + version
+}
+
+// GenerateServiceCompose renders the docker-compose file of a service
+func GenerateServiceCompose(options ServiceOptions) ([]byte, error) {
+	service, err := LookupService(options.Name)
+	if err != nil {
+		return nil, err
+	}
+	image := ServiceImage(service, options)
+
+	tmpl, err := template.New(service.Name).Parse(serviceHeader + service.compose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Image string }{image}); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// PlanServices compares the requested services with the docker-compose files
+// in .ddev
+// Files generated for services no longer requested are removed; files stax
+// did not generate are never touched
+func PlanServices(projectPath string, services []ServiceOptions) ([]GeneratedFile, error) {
+	var files []GeneratedFile
+	wanted := make(map[string]bool)
+
+	for _, service := range services {
+		content, err := GenerateServiceCompose(service)
+		if err != nil {
+			return nil, err
+		}
+		wanted[service.Name] = true
+
+		file, err := planGeneratedFile(projectPath, service.Name, ServiceComposePath(service.Name), content, 0644)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	for _, name := range ServiceNames() {
+		if wanted[name] {
+			continue
+		}
+		file, err := planGeneratedRemoval(projectPath, name, ServiceComposePath(name))
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			files = append(files, *file)
+		}
+	}
+
+	return files, nil
+}
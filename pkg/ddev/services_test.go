@@ -0,0 +1,126 @@
+package ddev
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateServiceCompose(t *testing.T) {
+	tests := []struct {
+		options ServiceOptions
+		image   string
+	}{
+		{ServiceOptions{Name: "redis"}, "redis:7"},
+		{ServiceOptions{Name: "redis", Version: "6.2"}, "redis:6.2"},
+		{ServiceOptions{Name: "elasticsearch", Image: "opensearchproject/opensearch:2"}, "opensearchproject/opensearch:2"},
+		{ServiceOptions{Name: "solr", Image: "localhost:5000/solr", Version: "8"}, "localhost:5000/solr:8"},
+	}
+	for _, tt := range tests {
+		content, err := GenerateServiceCompose(tt.options)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.options.Name, err)
+		}
+
+		var compose struct {
+			Services map[string]struct {
+				Image  string            `yaml:"image"`
+				Labels map[string]string `yaml:"labels"`
+			} `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(content, &compose); err != nil {
+			t.Fatalf("%s is not valid YAML: %v\n%s", tt.options.Name, err, content)
+		}
+		service, ok := compose.Services[tt.options.Name]
+		if !ok {
+			t.Fatalf("%s missing its service:\n%s", tt.options.Name, content)
+		}
+		if service.Image != tt.image {
+			t.Errorf("%s image = %q, want %q", tt.options.Name, service.Image, tt.image)
+		}
+		if service.Labels["com.ddev.site-name"] == "" {
+			t.Errorf("%s is missing the DDEV labels", tt.options.Name)
+		}
+		if !strings.Contains(string(content), generatedMarker) {
+			t.Errorf("%s is missing the generated marker", tt.options.Name)
+		}
+	}
+
+	if _, err := GenerateServiceCompose(ServiceOptions{Name: "mongodb"}); err == nil {
+		t.Error("expected an unknown service to fail")
+	}
+}
+
+func TestPlanServices(t *testing.T) {
+	dir := t.TempDir()
+
+	// A team-owned compose file for a stax service is left alone
+	writeDDEVFile(t, dir, "docker-compose.solr.yaml", "services:\n  solr:\n    image: solr:8\n")
+
+	files, err := PlanServices(dir, []ServiceOptions{{Name: "redis"}, {Name: "elasticsearch"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyGeneratedFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"redis", "elasticsearch"} {
+		if _, err := os.Stat(filepath.Join(dir, ServiceComposePath(name))); err != nil {
+			t.Errorf("%s compose file: %v", name, err)
+		}
+	}
+
+	// Removing a service removes its generated file only
+	files, err = PlanServices(dir, []ServiceOptions{{Name: "elasticsearch"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := make(map[string]SyncAction)
+	for _, file := range files {
+		actions[file.Name] = file.Action
+	}
+	if _, ok := actions["solr"]; ok {
+		t.Errorf("solr was not generated by stax and should not be planned, got %v", actions["solr"])
+	}
+	if actions["redis"] != SyncRemoved || actions["elasticsearch"] != SyncUnchanged {
+		t.Errorf("actions = %v", actions)
+	}
+	if err := ApplyGeneratedFiles(dir, files); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ServiceComposePath("redis"))); !os.IsNotExist(err) {
+		t.Errorf("redis compose file should be removed, got %v", err)
+	}
+	if got := readDDEVFile(t, dir, "docker-compose.solr.yaml"); !strings.Contains(got, "solr:8") {
+		t.Errorf("team compose file was changed:\n%s", got)
+	}
+}
+
+func TestParseServices(t *testing.T) {
+	result := map[string]interface{}{
+		"status": "running",
+		"services": map[string]interface{}{
+			"web":   map[string]interface{}{"status": "running", "image": "ddev/ddev-webserver"},
+			"redis": map[string]interface{}{"status": "healthy", "image": "redis:7", "exposed_ports": "6379"},
+			"db":    map[string]interface{}{"status": "running"},
+		},
+	}
+
+	services := parseServices(result)
+	if len(services) != 3 || services[0].Name != "db" || services[1].Name != "redis" {
+		t.Fatalf("services = %+v", services)
+	}
+	redis := services[1]
+	if redis.State != "running" || redis.Health != "healthy" || redis.Image != "redis:7" || len(redis.Ports) != 1 {
+		t.Errorf("redis = %+v", redis)
+	}
+
+	// Without a services map, web and db are reported from the project status
+	services = parseServices(map[string]interface{}{"status": "stopped"})
+	if len(services) != 2 || services[0].State != "stopped" {
+		t.Errorf("fallback services = %+v", services)
+	}
+}
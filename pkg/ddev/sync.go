@@ -0,0 +1,481 @@
+package ddev
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/config"
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// OverrideConfigFile holds .stax.yml values that conflict with local edits
+	// DDEV loads config.*.yaml files after config.yaml, so they take precedence
+	OverrideConfigFile = "config.stax.yaml"
+
+	// SyncStateFile records the values stax last applied, the base of the merge
+	SyncStateFile = ".stax-sync.yaml"
+
+	configHeader   = "DDEV configuration generated by Stax\nSee https://ddev.readthedocs.io/en/stable/users/configuration/config/"
+	overrideHeader = "Generated by 'stax ddev sync' from .stax.yml, do not edit\nThese values differ from config.yaml and take precedence over it"
+)
+
+// SyncAction is what a sync does with one config key
+type SyncAction string
+
+const (
+	SyncUnchanged  SyncAction = "unchanged"  // config.yaml already has the value
+	SyncAdded      SyncAction = "added"      // the key is added to config.yaml
+	SyncUpdated    SyncAction = "updated"    // the value in config.yaml is replaced
+	SyncKept       SyncAction = "kept"       // a local edit is kept, .stax.yml did not change
+	SyncOverridden SyncAction = "overridden" // the value is written to config.stax.yaml
+	SyncRemoved    SyncAction = "removed"    // a generated file is no longer wanted
+)
+
+// SyncChange is the result of merging one config key
+type SyncChange struct {
+	Key     string     `json:"key"`
+	Action  SyncAction `json:"action"`
+	Current string     `json:"current,omitempty"` // value in config.yaml
+	Desired string     `json:"desired"`           // value from .stax.yml
+}
+
+// SyncOptions controls how conflicts are resolved
+type SyncOptions struct {
+	// Force writes every value into config.yaml, replacing local edits
+	Force bool
+}
+
+// SyncPlan is a merge of .stax.yml settings onto the DDEV config, ready to apply
+type SyncPlan struct {
+	Changes []SyncChange
+
+	configPath   string
+	overridePath string
+	statePath    string
+
+	oldConfig, newConfig     []byte
+	oldOverride, newOverride []byte
+	state                    []byte
+}
+
+// PlanSync merges desired onto .ddev/config.yaml
+//
+// Each key is merged three ways, against the values stax applied last time:
+// keys changed only in .stax.yml are updated in place, keys edited only
+// locally are kept, and keys changed on both sides are written to
+// config.stax.yaml so config.yaml keeps the local edit. Keys stax does not
+// manage, comments and key order are preserved
+func PlanSync(projectPath string, desired *DDEVConfig, options SyncOptions) (*SyncPlan, error) {
+	ddevDir := filepath.Join(projectPath, ".ddev")
+	plan := &SyncPlan{
+		configPath:   filepath.Join(ddevDir, "config.yaml"),
+		overridePath: filepath.Join(ddevDir, OverrideConfigFile),
+		statePath:    filepath.Join(ddevDir, SyncStateFile),
+	}
+
+	var err error
+	if plan.oldConfig, err = readOptional(plan.configPath); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if plan.oldOverride, err = readOptional(plan.overridePath); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", OverrideConfigFile, err)
+	}
+	stateData, err := readOptional(plan.statePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	doc, err := parseDocument(plan.oldConfig, configHeader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	root := doc.Content[0]
+
+	want, err := flattenValue(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	// Without a recorded state, a config.yaml written by stax is its own base
+	var base map[string]*yaml.Node
+	switch {
+	case stateData != nil:
+		if base, err = parseFlat(stateData); err != nil {
+			return nil, fmt.Errorf("failed to parse sync state: %w", err)
+		}
+	case bytes.Contains(plan.oldConfig, []byte("generated by Stax")):
+		base = flatten(root, "", nil, nil)
+	}
+
+	overridden := make(map[string]bool)
+	if plan.oldOverride != nil {
+		previous, err := parseFlat(plan.oldOverride)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", OverrideConfigFile, err)
+		}
+		for key := range previous {
+			overridden[key] = true
+		}
+	}
+
+	overrides := &yaml.Node{Kind: yaml.MappingNode}
+	state := &yaml.Node{Kind: yaml.MappingNode}
+	needsReplace, edited := false, false
+
+	for _, entry := range want {
+		key, value := entry.key, entry.value
+		current := lookup(root, key)
+		change := SyncChange{Key: key, Desired: nodeString(value), Current: nodeString(current)}
+
+		baseValue, hasBase := base[key]
+		switch {
+		case current != nil && nodesEqual(current, value):
+			change.Action = SyncUnchanged
+		case current == nil:
+			change.Action = SyncAdded
+		case options.Force:
+			change.Action = SyncUpdated
+		case overridden[key]:
+			change.Action = SyncOverridden
+		case hasBase && nodesEqual(current, baseValue):
+			change.Action = SyncUpdated
+		case hasBase && nodesEqual(value, baseValue):
+			change.Action = SyncKept
+		default:
+			change.Action = SyncOverridden
+		}
+
+		switch change.Action {
+		case SyncAdded, SyncUpdated:
+			config.SetYAMLValue(root, key, copyNode(value))
+			edited = true
+		case SyncOverridden:
+			config.SetYAMLValue(overrides, key, copyNode(value))
+			if value.Kind == yaml.SequenceNode || value.Kind == yaml.MappingNode {
+				needsReplace = true
+			}
+		}
+		state.Content = append(state.Content, scalarNode(key), value)
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	plan.newConfig = plan.oldConfig
+	if edited {
+		if plan.newConfig, err = encodeDocument(doc, config.YAMLIndent(plan.oldConfig)); err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+	}
+
+	if len(overrides.Content) > 0 {
+		// DDEV appends lists from override files unless told to replace them
+		if needsReplace {
+			overrides.Content = append(overrides.Content, scalarNode("override_config"), &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		}
+		overrideDoc := &yaml.Node{Kind: yaml.DocumentNode, HeadComment: overrideHeader, Content: []*yaml.Node{overrides}}
+		if plan.newOverride, err = encodeDocument(overrideDoc, 2); err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", OverrideConfigFile, err)
+		}
+	}
+
+	stateDoc := &yaml.Node{Kind: yaml.DocumentNode, HeadComment: "Values last applied by 'stax ddev sync', used to detect local edits", Content: []*yaml.Node{state}}
+	if plan.state, err = encodeDocument(stateDoc, 2); err != nil {
+		return nil, fmt.Errorf("failed to marshal sync state: %w", err)
+	}
+
+	return plan, nil
+}
+
+// HasChanges reports whether applying the plan changes any DDEV config file
+func (p *SyncPlan) HasChanges() bool {
+	return !bytes.Equal(p.oldConfig, p.newConfig) || !bytes.Equal(p.oldOverride, p.newOverride)
+}
+
+// Conflicts returns the keys written to config.stax.yaml
+func (p *SyncPlan) Conflicts() []SyncChange {
+	var conflicts []SyncChange
+	for _, change := range p.Changes {
+		if change.Action == SyncOverridden {
+			conflicts = append(conflicts, change)
+		}
+	}
+	return conflicts
+}
+
+// Diff returns a unified diff of config.yaml and config.stax.yaml
+func (p *SyncPlan) Diff() (string, error) {
+	var out strings.Builder
+	for _, file := range []struct {
+		name     string
+		old, new []byte
+	}{
+		{"config.yaml", p.oldConfig, p.newConfig},
+		{OverrideConfigFile, p.oldOverride, p.newOverride},
+	} {
+		if bytes.Equal(file.old, file.new) {
+			continue
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(file.old),
+			B:        splitLines(file.new),
+			FromFile: filepath.Join(".ddev", file.name),
+			ToFile:   filepath.Join(".ddev", file.name),
+			Context:  3,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to diff %s: %w", file.name, err)
+		}
+		out.WriteString(diff)
+	}
+	return out.String(), nil
+}
+
+// Apply writes the merged config files and records the applied values
+func (p *SyncPlan) Apply() error {
+	if err := os.MkdirAll(filepath.Dir(p.configPath), 0755); err != nil {
+		return fmt.Errorf("failed to create .ddev directory: %w", err)
+	}
+
+	if !bytes.Equal(p.oldConfig, p.newConfig) {
+		if err := os.WriteFile(p.configPath, p.newConfig, 0644); err != nil {
+			return fmt.Errorf("failed to write config file: %w", err)
+		}
+	}
+
+	switch {
+	case p.newOverride != nil:
+		if err := os.WriteFile(p.overridePath, p.newOverride, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", OverrideConfigFile, err)
+		}
+	case p.oldOverride != nil:
+		if err := os.Remove(p.overridePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", OverrideConfigFile, err)
+		}
+	}
+
+	if err := os.WriteFile(p.statePath, p.state, 0644); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	return nil
+}
+
+// splitLines splits data into lines for difflib, keeping line endings
+func splitLines(data []byte) []string {
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// readOptional reads path, returning nil if it does not exist
+func readOptional(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// parseDocument parses YAML into a document whose content is a mapping
+func parseDocument(data []byte, header string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		// Empty or missing file
+		return &yaml.Node{Kind: yaml.DocumentNode, HeadComment: header, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}, nil
+	}
+	if len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("top level is not a mapping")
+	}
+	return &doc, nil
+}
+
+type flatEntry struct {
+	key   string
+	value *yaml.Node
+}
+
+// flattenValue encodes v and returns its leaf values keyed by dotted path,
+// in document order
+// Empty strings are left out, as settings .stax.yml does not set
+func flattenValue(v interface{}) ([]flatEntry, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	var all, entries []flatEntry
+	flatten(&node, "", nil, &all)
+	for _, entry := range all {
+		if entry.value.Kind == yaml.ScalarNode && entry.value.Tag == "!!str" && entry.value.Value == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// parseFlat parses a YAML file into its leaf values keyed by dotted path
+func parseFlat(data []byte) (map[string]*yaml.Node, error) {
+	doc, err := parseDocument(data, "")
+	if err != nil {
+		return nil, err
+	}
+	values := flatten(doc.Content[0], "", nil, nil)
+	delete(values, "override_config")
+	return values, nil
+}
+
+// flatten walks nested mappings, collecting leaves into values and entries
+// Keys that contain dots are kept whole, as written by the sync state
+func flatten(node *yaml.Node, prefix string, values map[string]*yaml.Node, entries *[]flatEntry) map[string]*yaml.Node {
+	if values == nil {
+		values = make(map[string]*yaml.Node)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, resolve(node.Content[i+1])
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if value.Kind == yaml.MappingNode && len(value.Content) > 0 {
+			flatten(value, key, values, entries)
+			continue
+		}
+		values[key] = value
+		if entries != nil {
+			*entries = append(*entries, flatEntry{key, value})
+		}
+	}
+	return values
+}
+
+// lookup returns the value at a dotted path, or nil
+func lookup(node *yaml.Node, path string) *yaml.Node {
+	for _, part := range strings.Split(path, ".") {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		_, value := mappingEntry(node, part)
+		if value == nil {
+			return nil
+		}
+		node = resolve(value)
+	}
+	return node
+}
+
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func resolve(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// copyNode deep-copies node without position information
+func copyNode(node *yaml.Node) *yaml.Node {
+	node = resolve(node)
+	out := &yaml.Node{Kind: node.Kind, Tag: node.Tag, Value: node.Value, Style: node.Style}
+	for _, child := range node.Content {
+		out.Content = append(out.Content, copyNode(child))
+	}
+	return out
+}
+
+// nodesEqual compares values, treating scalars by their text so that
+// php_version: 8.1 and php_version: "8.1" are the same
+func nodesEqual(a, b *yaml.Node) bool {
+	a, b = resolve(a), resolve(b)
+	if isEmpty(a) && isEmpty(b) {
+		return true
+	}
+	if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+	switch a.Kind {
+	case yaml.ScalarNode:
+		return a.Value == b.Value
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			_, other := mappingEntry(b, a.Content[i].Value)
+			if other == nil || !nodesEqual(a.Content[i+1], other) {
+				return false
+			}
+		}
+		return true
+	default:
+		for i := range a.Content {
+			if !nodesEqual(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// isEmpty reports whether node is null or an empty list or mapping
+func isEmpty(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Tag == "!!null"
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
+
+// nodeString renders a value on one line for display
+func nodeString(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	node = resolve(node)
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.SequenceNode:
+		items := make([]string, len(node.Content))
+		for i, item := range node.Content {
+			items[i] = nodeString(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		items := make([]string, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			items = append(items, node.Content[i].Value+": "+nodeString(node.Content[i+1]))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+}
+
+func encodeDocument(doc *yaml.Node, indent int) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,180 @@
+package ddev
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const teamConfig = `name: site
+type: wordpress
+docroot: public
+# Pinned for the payments plugin
+php_version: "8.1"
+database:
+  type: mysql
+  version: "8.0"
+upload_dirs:
+  - wp-content/uploads
+corepack_enable: true
+`
+
+func writeDDEVFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, ".ddev", name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readDDEVFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, ".ddev", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func syncActions(plan *SyncPlan) map[string]SyncAction {
+	actions := make(map[string]SyncAction)
+	for _, change := range plan.Changes {
+		actions[change.Key] = change.Action
+	}
+	return actions
+}
+
+func TestPlanSyncPreservesUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeDDEVFile(t, dir, "config.yaml", teamConfig)
+
+	desired := &DDEVConfig{Name: "site", Type: "wordpress", DocRoot: "public", PHPVersion: "8.1", Database: DatabaseConfig{Type: "mysql", Version: "8.0"}, NodeJSVersion: "20"}
+	plan, err := PlanSync(dir, desired, SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := syncActions(plan); got["nodejs_version"] != SyncAdded || got["php_version"] != SyncUnchanged {
+		t.Errorf("actions = %v", got)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	config := readDDEVFile(t, dir, "config.yaml")
+	for _, want := range []string{"# Pinned for the payments plugin\nphp_version: \"8.1\"", "upload_dirs:\n  - wp-content/uploads", "corepack_enable: true", "nodejs_version: \"20\""} {
+		if !strings.Contains(config, want) {
+			t.Errorf("config.yaml missing %q:\n%s", want, config)
+		}
+	}
+	if strings.Index(config, "corepack_enable") > strings.Index(config, "nodejs_version") {
+		t.Errorf("new keys should be appended:\n%s", config)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ddev", OverrideConfigFile)); !os.IsNotExist(err) {
+		t.Errorf("no override file expected, got %v", err)
+	}
+}
+
+func TestPlanSyncThreeWay(t *testing.T) {
+	dir := t.TempDir()
+	writeDDEVFile(t, dir, "config.yaml", teamConfig)
+	writeDDEVFile(t, dir, SyncStateFile, "php_version: \"8.1\"\ndatabase.version: \"5.7\"\nnodejs_version: \"18\"\n")
+
+	// php_version changed only in .stax.yml, database.version only locally,
+	// and the missing nodejs_version is added
+	desired := &DDEVConfig{PHPVersion: "8.2", Database: DatabaseConfig{Version: "5.7"}, NodeJSVersion: "18"}
+	plan, err := PlanSync(dir, desired, SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := syncActions(plan)
+	if got["php_version"] != SyncUpdated || got["database.version"] != SyncKept || got["nodejs_version"] != SyncAdded {
+		t.Errorf("actions = %v", got)
+	}
+
+	diff, err := plan.Diff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "-php_version: \"8.1\"\n+php_version: \"8.2\"") {
+		t.Errorf("diff = %s", diff)
+	}
+
+	// Both sides changed: the local value stays in config.yaml
+	desired.Database.Version = "8.4"
+	plan, err = PlanSync(dir, desired, SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := syncActions(plan)["database.version"]; got != SyncOverridden {
+		t.Fatalf("database.version = %s, want overridden", got)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if config := readDDEVFile(t, dir, "config.yaml"); !strings.Contains(config, "version: \"8.0\"") {
+		t.Errorf("local database version lost:\n%s", config)
+	}
+	if override := readDDEVFile(t, dir, OverrideConfigFile); !strings.Contains(override, "database:\n  version: \"8.4\"") {
+		t.Errorf("override = %s", override)
+	}
+
+	// The override sticks until config.yaml agrees, then is removed
+	plan, err = PlanSync(dir, desired, SyncOptions{Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ddev", OverrideConfigFile)); !os.IsNotExist(err) {
+		t.Errorf("override file should be removed, got %v", err)
+	}
+	plan, err = PlanSync(dir, desired, SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.HasChanges() {
+		t.Error("a second sync should change nothing")
+	}
+}
+
+func TestPlanSyncOverrideLists(t *testing.T) {
+	dir := t.TempDir()
+	writeDDEVFile(t, dir, "config.yaml", "additional_hostnames:\n  - team.site\n")
+
+	plan, err := PlanSync(dir, &DDEVConfig{AdditionalHostnames: []string{"*.site"}}, SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if override := readDDEVFile(t, dir, OverrideConfigFile); !strings.Contains(override, "override_config: true") {
+		t.Errorf("lists in overrides must replace, got:\n%s", override)
+	}
+}
+
+func TestPlanSyncNewConfig(t *testing.T) {
+	dir := t.TempDir()
+	plan, err := PlanSync(dir, &DDEVConfig{Name: "site", PHPVersion: "8.3"}, SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ReadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "site" || cfg.PHPVersion != "8.3" {
+		t.Errorf("ReadConfig() = %+v", cfg)
+	}
+	if config := readDDEVFile(t, dir, "config.yaml"); !strings.HasPrefix(config, "# DDEV configuration generated by Stax") {
+		t.Errorf("config.yaml missing header:\n%s", config)
+	}
+}
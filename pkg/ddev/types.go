@@ -1,6 +1,9 @@
 package ddev
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 // DDEVConfig represents the DDEV configuration structure
 type DDEVConfig struct {
@@ -32,8 +35,10 @@ type DatabaseConfig struct {
 
 // DDEVHooks represents DDEV lifecycle hooks
 type DDEVHooks struct {
+	PreStart   []HookCommand `yaml:"pre-start,omitempty"`
 	PostStart  []HookCommand `yaml:"post-start,omitempty"`
 	PreStop    []HookCommand `yaml:"pre-stop,omitempty"`
+	PostStop   []HookCommand `yaml:"post-stop,omitempty"`
 	PostImport []HookCommand `yaml:"post-import,omitempty"`
 }
 
@@ -84,7 +89,10 @@ type ConfigOptions struct {
 	WebEnvironment        []string // Environment variables for web container
 	NodeJSVersion         string
 	ComposerVersion       string
+	PreStartHooks         []string // Run on the host, before the containers start
 	PostStartHooks        []string
+	PreStopHooks          []string
+	PostStopHooks         []string // Run on the host, after the containers stop
 	PostImportHooks       []string
 	XdebugEnabled         bool
 	UseDNSWhenPossible    bool // Use DNS for .ddev.site domains
@@ -101,6 +109,15 @@ type MediaProxyOptions struct {
 	CacheMaxSize string // e.g., "10g", "1g"
 	CacheEnabled bool
 	ProxyHeaders map[string]string
+	Sites        []MediaProxySite // network sites proxied from their own origins
+}
+
+// MediaProxySite routes the uploads of one network site, stored in sites/<ID>/
+type MediaProxySite struct {
+	Name string
+	ID   int
+	URL  string // origin of the site's uploads
+	Host string // Host header sent to the origin
 }
 
 // ProjectInfo represents detailed information about a DDEV project
@@ -141,9 +158,10 @@ type ExecOptions struct {
 
 // LogOptions contains options for viewing logs
 type LogOptions struct {
-	Service    string // web, db, etc. (empty for all)
-	Follow     bool   // Tail logs
-	Tail       int    // Number of lines to show
-	Timestamps bool   // Show timestamps
-	Since      string // Show logs since timestamp (e.g., "1h", "30m")
+	Service    string    // web, db, etc. (empty for all)
+	Follow     bool      // Tail logs
+	Tail       int       // Number of lines to show
+	Timestamps bool      // Show timestamps
+	Since      string    // Show logs since timestamp (e.g., "1h", "30m")
+	Output     io.Writer // Where logs are written (default: stdout)
 }
@@ -0,0 +1,425 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/firecrown-media/stax/pkg/wpengine"
+)
+
+// Remote check categories, in display order
+var RemoteCategories = []string{"Versions", "Updates", "Integrity", "Performance", "Resources"}
+
+const (
+	// AutoloadWarnBytes is the autoloaded options size that slows every request
+	AutoloadWarnBytes = 800 * 1024
+	// AutoloadFailBytes is the autoloaded options size that needs cleanup
+	AutoloadFailBytes = 3 * 1024 * 1024
+
+	// CronOverdueAfter is how late a cron event may run before it counts as backlog
+	CronOverdueAfter = time.Hour
+	// CronBacklogWarn and CronBacklogFail are overdue event counts
+	CronBacklogWarn = 5
+	CronBacklogFail = 50
+
+	// DiskWarnPercent and DiskFailPercent are install disk usage thresholds
+	DiskWarnPercent = 80
+	DiskFailPercent = 95
+)
+
+// RemoteTarget is the install remote checks run against
+type RemoteTarget struct {
+	Install string
+	// Environment is the WPEngine environment of the install, used in
+	// suggested commands (default production)
+	Environment string
+
+	// WPCLI runs WP-CLI on the install and returns its output
+	WPCLI func(args ...string) (string, error)
+
+	// Details are the install's API details; nil when the API is unavailable
+	Details *wpengine.InstallDetails
+
+	// Versions from .stax.yml the install should match
+	PHPVersion       string
+	MySQLVersion     string
+	WordPressVersion string // a version or "latest"
+
+	// Now is the current time (default time.Now)
+	Now func() time.Time
+}
+
+// RunRemoteChecks runs every remote check concurrently
+func RunRemoteChecks(target RemoteTarget, verbose bool) *DiagnosticReport {
+	if target.Now == nil {
+		target.Now = time.Now
+	}
+
+	checks := []func(RemoteTarget) CheckResult{
+		CheckRemotePHPVersion,
+		CheckRemoteDatabaseVersion,
+		CheckRemoteWordPressVersion,
+		CheckRemotePluginUpdates,
+		CheckRemoteChecksums,
+		CheckRemoteCron,
+		CheckRemoteAutoload,
+		CheckRemoteObjectCache,
+		CheckRemoteDiskUsage,
+	}
+
+	report := &DiagnosticReport{
+		ProjectPath: target.Install,
+		Checks:      make([]CheckResult, len(checks)),
+		Verbose:     verbose,
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = check(target)
+		}()
+	}
+	wg.Wait()
+
+	report.groupByCategory()
+	report.Summary = calculateSummary(report.Checks)
+	return report
+}
+
+// CheckRemotePHPVersion compares the install's PHP version with .stax.yml
+func CheckRemotePHPVersion(target RemoteTarget) CheckResult {
+	result := CheckResult{Name: "PHP Version", Category: "Versions"}
+
+	remote := ""
+	if target.Details != nil {
+		remote = target.Details.PHPVersion
+	}
+	if remote == "" {
+		output, err := target.WPCLI("cli", "info", "--format=json")
+		if err != nil {
+			return remoteFailure(target, result, "Could not read the PHP version", err)
+		}
+		var info struct {
+			PHPVersion string `json:"php_version"`
+		}
+		if err := json.Unmarshal([]byte(output), &info); err != nil {
+			return remoteFailure(target, result, "Could not parse WP-CLI info", err)
+		}
+		remote = info.PHPVersion
+	}
+
+	return compareVersions(result, "PHP", remote, target.PHPVersion, "ddev.php_version")
+}
+
+// CheckRemoteDatabaseVersion compares the install's MySQL version with .stax.yml
+func CheckRemoteDatabaseVersion(target RemoteTarget) CheckResult {
+	result := CheckResult{Name: "MySQL Version", Category: "Versions"}
+	if target.Details == nil || target.Details.MySQLVersion == "" {
+		result.Status = StatusSkip
+		result.Message = "MySQL version not available without the WPEngine API"
+		return result
+	}
+	return compareVersions(result, "MySQL", target.Details.MySQLVersion, target.MySQLVersion, "ddev.mysql_version")
+}
+
+// compareVersions matches major.minor versions, which is what DDEV pins
+func compareVersions(result CheckResult, what, remote, local, key string) CheckResult {
+	result.Details = map[string]string{"remote": remote, "local": local}
+
+	switch {
+	case local == "":
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("Remote %s %s", what, remote)
+	case majorMinor(remote) == majorMinor(local):
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("%s %s matches local %s", what, remote, local)
+	default:
+		result.Status = StatusWarning
+		result.Message = fmt.Sprintf("Remote %s %s differs from local %s", what, remote, local)
+		result.Suggestion = fmt.Sprintf("Set %s: \"%s\" in .stax.yml so local matches the install", key, majorMinor(remote))
+	}
+	return result
+}
+
+// majorMinor trims a version such as 8.1.27 to 8.1
+func majorMinor(version string) string {
+	parts := strings.SplitN(strings.TrimSpace(version), ".", 3)
+	if len(parts) < 2 {
+		return strings.TrimSpace(version)
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// CheckRemoteWordPressVersion reports the core version and available core updates
+func CheckRemoteWordPressVersion(target RemoteTarget) CheckResult {
+	result := CheckResult{Name: "WordPress Version", Category: "Versions"}
+
+	output, err := target.WPCLI("core", "version")
+	if err != nil {
+		return remoteFailure(target, result, "Could not read the WordPress version", err)
+	}
+	version := strings.TrimSpace(output)
+	result.Details = map[string]string{"version": version}
+
+	var updates []struct {
+		Version    string `json:"version"`
+		UpdateType string `json:"update_type"`
+	}
+	if output, err := target.WPCLI("core", "check-update", "--format=json"); err == nil && strings.TrimSpace(output) != "" {
+		// No updates prints a success message rather than JSON
+		json.Unmarshal([]byte(output), &updates)
+	}
+
+	switch {
+	case len(updates) > 0:
+		result.Status = StatusWarning
+		result.Message = fmt.Sprintf("WordPress %s, %s update to %s available", version, updates[0].UpdateType, updates[0].Version)
+		result.Suggestion = "Update core on WPEngine, then pull the database to test locally"
+		result.Details["update"] = updates[0].Version
+	case target.WordPressVersion != "" && target.WordPressVersion != "latest" && target.WordPressVersion != version:
+		result.Status = StatusWarning
+		result.Message = fmt.Sprintf("WordPress %s differs from wordpress.version %s in .stax.yml", version, target.WordPressVersion)
+		result.Suggestion = fmt.Sprintf("Set wordpress.version: \"%s\" in .stax.yml", version)
+	default:
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("WordPress %s is up to date", version)
+	}
+	return result
+}
+
+// CheckRemotePluginUpdates lists plugins with updates available
+func CheckRemotePluginUpdates(target RemoteTarget) CheckResult {
+	result := CheckResult{Name: "Plugin Updates", Category: "Updates"}
+
+	output, err := target.WPCLI("plugin", "list", "--update=available", "--fields=name,version,update_version", "--format=json")
+	if err != nil {
+		return remoteFailure(target, result, "Could not list plugins", err)
+	}
+
+	var plugins []struct {
+		Name          string `json:"name"`
+		Version       string `json:"version"`
+		UpdateVersion string `json:"update_version"`
+	}
+	if err := json.Unmarshal([]byte(output), &plugins); err != nil {
+		return remoteFailure(target, result, "Could not parse the plugin list", err)
+	}
+
+	if len(plugins) == 0 {
+		result.Status = StatusPass
+		result.Message = "All plugins are up to date"
+		return result
+	}
+
+	result.Status = StatusWarning
+	result.Message = fmt.Sprintf("%d plugin update(s) available", len(plugins))
+	result.Suggestion = "Update plugins locally first: stax wp plugin update --all"
+	result.Details = make(map[string]string, len(plugins))
+	for _, p := range plugins {
+		result.Details[p.Name] = fmt.Sprintf("%s → %s", p.Version, p.UpdateVersion)
+	}
+	return result
+}
+
+// CheckRemoteChecksums verifies core files against WordPress.org checksums
+func CheckRemoteChecksums(target RemoteTarget) CheckResult {
+	result := CheckResult{Name: "Core Checksums", Category: "Integrity"}
+
+	if _, err := target.WPCLI("core", "verify-checksums"); err != nil {
+		// Only WP-CLI's own verdict is a mismatch; SSH and other failures
+		// mean the check could not run
+		if !strings.Contains(err.Error(), "verify against checksum") {
+			return remoteFailure(target, result, "Could not verify core checksums", err)
+		}
+		result.Status = StatusFail
+		result.Message = "Core files don't match WordPress.org checksums"
+		result.Suggestion = remoteWP(target, "core verify-checksums", "Inspect the changed files")
+		result.Details = map[string]string{"error": err.Error()}
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = "Core files match WordPress.org checksums"
+	return result
+}
+
+// CheckRemoteCron counts cron events that are long overdue
+func CheckRemoteCron(target RemoteTarget) CheckResult {
+	result := CheckResult{Name: "Cron Backlog", Category: "Performance"}
+
+	output, err := target.WPCLI("cron", "event", "list", "--fields=hook,next_run_gmt", "--format=json")
+	if err != nil {
+		return remoteFailure(target, result, "Could not list cron events", err)
+	}
+
+	var events []struct {
+		Hook       string `json:"hook"`
+		NextRunGMT string `json:"next_run_gmt"`
+	}
+	if err := json.Unmarshal([]byte(output), &events); err != nil {
+		return remoteFailure(target, result, "Could not parse cron events", err)
+	}
+
+	cutoff := target.Now().Add(-CronOverdueAfter)
+	overdue := map[string]int{}
+	total := 0
+	for _, e := range events {
+		next, err := time.Parse("2006-01-02 15:04:05", e.NextRunGMT)
+		if err == nil && next.Before(cutoff) {
+			overdue[e.Hook]++
+			total++
+		}
+	}
+
+	result.Details = map[string]string{"events": strconv.Itoa(len(events)), "overdue": strconv.Itoa(total)}
+	switch {
+	case total >= CronBacklogFail:
+		result.Status = StatusFail
+	case total >= CronBacklogWarn:
+		result.Status = StatusWarning
+	default:
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("%d scheduled events, %d overdue", len(events), total)
+		return result
+	}
+
+	hooks := make([]string, 0, len(overdue))
+	for hook := range overdue {
+		hooks = append(hooks, hook)
+	}
+	sort.Strings(hooks)
+	result.Details["hooks"] = strings.Join(hooks, ", ")
+	result.Message = fmt.Sprintf("%d cron events are more than %s overdue", total, CronOverdueAfter)
+	result.Suggestion = remoteWP(target, "cron test", "Check that WP-Cron or WPEngine Alternate Cron runs")
+	return result
+}
+
+// CheckRemoteAutoload measures autoloaded options, which load on every request
+func CheckRemoteAutoload(target RemoteTarget) CheckResult {
+	result := CheckResult{Name: "Autoloaded Options", Category: "Performance"}
+
+	output, err := target.WPCLI("option", "list", "--autoload=on", "--format=total_bytes")
+	if err != nil {
+		return remoteFailure(target, result, "Could not measure autoloaded options", err)
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(output), 10, 64)
+	if err != nil {
+		return remoteFailure(target, result, "Could not parse the autoloaded options size", err)
+	}
+
+	result.Details = map[string]string{"bytes": strconv.FormatInt(size, 10)}
+	result.Message = fmt.Sprintf("Autoloaded options total %s", formatBytes(size))
+	switch {
+	case size >= AutoloadFailBytes:
+		result.Status = StatusFail
+	case size >= AutoloadWarnBytes:
+		result.Status = StatusWarning
+	default:
+		result.Status = StatusPass
+		return result
+	}
+	result.Suggestion = remoteWP(target, "option list --autoload=on --orderby=size_bytes --order=desc --fields=option_name,size_bytes", "Find the largest")
+	return result
+}
+
+// CheckRemoteObjectCache reports whether a persistent object cache is in use
+func CheckRemoteObjectCache(target RemoteTarget) CheckResult {
+	result := CheckResult{Name: "Object Cache", Category: "Performance"}
+
+	output, err := target.WPCLI("cache", "type")
+	if err != nil {
+		return remoteFailure(target, result, "Could not read the object cache type", err)
+	}
+	cacheType := strings.TrimSpace(output)
+	result.Details = map[string]string{"type": cacheType}
+
+	if cacheType == "" || strings.EqualFold(cacheType, "Default") {
+		result.Status = StatusWarning
+		result.Message = "No persistent object cache"
+		result.Suggestion = "Enable Object Caching for the install in the WPEngine User Portal"
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("Persistent object cache: %s", cacheType)
+	return result
+}
+
+// CheckRemoteDiskUsage reports disk usage from the install's API details
+func CheckRemoteDiskUsage(target RemoteTarget) CheckResult {
+	result := CheckResult{Name: "Disk Usage", Category: "Resources"}
+
+	if target.Details == nil {
+		result.Status = StatusSkip
+		result.Message = "Disk usage not available without the WPEngine API"
+		result.Suggestion = "Configure API credentials: stax setup"
+		return result
+	}
+
+	used, total := target.Details.DiskUsage.Used, target.Details.DiskUsage.Total
+	result.Details = map[string]string{"used": formatBytes(used)}
+	if total <= 0 {
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("Using %s", formatBytes(used))
+		return result
+	}
+
+	percent := int(used * 100 / total)
+	result.Details["total"] = formatBytes(total)
+	result.Details["percent"] = strconv.Itoa(percent)
+	result.Message = fmt.Sprintf("Using %s of %s (%d%%)", formatBytes(used), formatBytes(total), percent)
+	switch {
+	case percent >= DiskFailPercent:
+		result.Status = StatusFail
+	case percent >= DiskWarnPercent:
+		result.Status = StatusWarning
+	default:
+		result.Status = StatusPass
+		return result
+	}
+	result.Suggestion = "Remove old backups, logs and unused media, or upgrade the plan"
+	return result
+}
+
+// remoteFailure reports a check that could not run
+func remoteFailure(target RemoteTarget, result CheckResult, message string, err error) CheckResult {
+	result.Status = StatusFail
+	result.Message = message
+	result.Suggestion = "Check SSH access with: stax ssh --env " + target.environment()
+	result.Details = map[string]string{"error": err.Error()}
+	return result
+}
+
+// remoteWP suggests running a WP-CLI command on the checked environment
+func remoteWP(target RemoteTarget, command, description string) string {
+	return fmt.Sprintf("%s: stax wp --remote %s %s", description, target.environment(), command)
+}
+
+// environment returns the environment of the install, production by default
+func (t RemoteTarget) environment() string {
+	if t.Environment == "" {
+		return "production"
+	}
+	return t.Environment
+}
+
+// formatBytes formats a size with binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,165 @@
+package diagnostics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/firecrown-media/stax/pkg/wpengine"
+)
+
+// fakeWPCLI answers WP-CLI commands from a map; missing commands fail
+func fakeWPCLI(outputs map[string]string) func(args ...string) (string, error) {
+	return func(args ...string) (string, error) {
+		output, ok := outputs[strings.Join(args, " ")]
+		if !ok {
+			return "", errors.New("command failed: exit status 1")
+		}
+		return output, nil
+	}
+}
+
+func healthyOutputs() map[string]string {
+	return map[string]string{
+		"cli info --format=json":          `{"php_version":"8.2.20"}`,
+		"core version":                    "6.6.2\n",
+		"core check-update --format=json": "",
+		"plugin list --update=available --fields=name,version,update_version --format=json": "[]",
+		"core verify-checksums": "Success: WordPress installation verifies against checksums.\n",
+		"cron event list --fields=hook,next_run_gmt --format=json": `[{"hook":"wp_version_check","next_run_gmt":"2026-10-18 12:30:00"}]`,
+		"option list --autoload=on --format=total_bytes":           "204800\n",
+		"cache type": "Memcached\n",
+	}
+}
+
+func remoteTarget(outputs map[string]string) RemoteTarget {
+	return RemoteTarget{
+		Install:          "mysite",
+		WPCLI:            fakeWPCLI(outputs),
+		PHPVersion:       "8.2",
+		WordPressVersion: "latest",
+		Now:              func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) },
+	}
+}
+
+func TestRunRemoteChecksHealthy(t *testing.T) {
+	target := remoteTarget(healthyOutputs())
+	target.Details = &wpengine.InstallDetails{PHPVersion: "8.2.20", MySQLVersion: "8.0.36"}
+	target.Details.DiskUsage.Used = 2 << 30
+	target.Details.DiskUsage.Total = 10 << 30
+	target.MySQLVersion = "8.0"
+
+	report := RunRemoteChecks(target, false)
+
+	for _, check := range report.Checks {
+		if check.Status != StatusPass {
+			t.Errorf("%s: %s - %s", check.Name, check.Status, check.Message)
+		}
+	}
+	if !report.IsHealthy() {
+		t.Errorf("expected a healthy report, got %+v", report.Summary)
+	}
+	for _, category := range RemoteCategories {
+		if len(report.Categories[category]) == 0 {
+			t.Errorf("no checks in category %s", category)
+		}
+	}
+}
+
+func TestRunRemoteChecksProblems(t *testing.T) {
+	outputs := healthyOutputs()
+	outputs["cli info --format=json"] = `{"php_version":"7.4.33"}`
+	outputs["core check-update --format=json"] = `[{"version":"6.6.3","update_type":"minor","package_url":"x"}]`
+	outputs["plugin list --update=available --fields=name,version,update_version --format=json"] = `[{"name":"akismet","version":"5.0","update_version":"5.3"}]`
+	delete(outputs, "core verify-checksums")
+	outputs["option list --autoload=on --format=total_bytes"] = "4194304"
+	outputs["cache type"] = "Default"
+
+	var events []string
+	for i := 0; i < CronBacklogWarn; i++ {
+		events = append(events, `{"hook":"action_scheduler_run_queue","next_run_gmt":"2026-10-17 09:00:00"}`)
+	}
+	outputs["cron event list --fields=hook,next_run_gmt --format=json"] = "[" + strings.Join(events, ",") + "]"
+
+	report := RunRemoteChecks(remoteTarget(outputs), false)
+
+	want := map[string]CheckStatus{
+		"PHP Version":        StatusWarning,
+		"MySQL Version":      StatusSkip,
+		"WordPress Version":  StatusWarning,
+		"Plugin Updates":     StatusWarning,
+		"Core Checksums":     StatusFail,
+		"Cron Backlog":       StatusWarning,
+		"Autoloaded Options": StatusFail,
+		"Object Cache":       StatusWarning,
+		"Disk Usage":         StatusSkip,
+	}
+	for _, check := range report.Checks {
+		if check.Status != want[check.Name] {
+			t.Errorf("%s: status %s, want %s (%s)", check.Name, check.Status, want[check.Name], check.Message)
+		}
+	}
+
+	if report.Checks[0].Suggestion != `Set ddev.php_version: "7.4" in .stax.yml so local matches the install` {
+		t.Errorf("unexpected PHP suggestion: %s", report.Checks[0].Suggestion)
+	}
+	if report.Summary.Failed != 2 || report.Summary.Skipped != 2 {
+		t.Errorf("unexpected summary: %+v", report.Summary)
+	}
+}
+
+func TestCheckRemoteChecksums(t *testing.T) {
+	target := remoteTarget(nil)
+	target.Environment = "staging"
+
+	target.WPCLI = func(args ...string) (string, error) {
+		return "", errors.New("command failed: Process exited with status 1 (stderr: Warning: File doesn't verify against checksum: wp-includes/version.php\nError: WordPress installation doesn't verify against checksums.)")
+	}
+	result := CheckRemoteChecksums(target)
+	if result.Message != "Core files don't match WordPress.org checksums" || result.Suggestion != "Inspect the changed files: stax wp --remote staging core verify-checksums" {
+		t.Errorf("mismatch: %s (%s)", result.Message, result.Suggestion)
+	}
+
+	// A connection failure says nothing about the files
+	target.WPCLI = func(args ...string) (string, error) {
+		return "", errors.New("failed to create session: EOF")
+	}
+	result = CheckRemoteChecksums(target)
+	if result.Message != "Could not verify core checksums" || result.Suggestion != "Check SSH access with: stax ssh --env staging" {
+		t.Errorf("connection failure: %s (%s)", result.Message, result.Suggestion)
+	}
+}
+
+func TestCheckRemoteDiskUsage(t *testing.T) {
+	tests := []struct {
+		used, total int64
+		want        CheckStatus
+	}{
+		{50, 100, StatusPass},
+		{80, 100, StatusWarning},
+		{99, 100, StatusFail},
+		{12345, 0, StatusPass},
+	}
+
+	for _, tt := range tests {
+		details := &wpengine.InstallDetails{}
+		details.DiskUsage.Used, details.DiskUsage.Total = tt.used, tt.total
+		if got := CheckRemoteDiskUsage(RemoteTarget{Details: details}); got.Status != tt.want {
+			t.Errorf("disk %d/%d: status %s, want %s", tt.used, tt.total, got.Status, tt.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512 B",
+		800 * 1024:      "800.0 KiB",
+		3 * 1024 * 1024: "3.0 MiB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
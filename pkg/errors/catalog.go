@@ -1,5 +1,12 @@
 package errors
 
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/firecrown-media/stax/pkg/provider"
+)
+
 // NewConfigNotFoundError creates an error for missing configuration file
 func NewConfigNotFoundError(path string, err error) *EnhancedError {
 	return &EnhancedError{
@@ -261,3 +268,59 @@ func NewWPEngineAPIError(message string, err error) *EnhancedError {
 		Err:     err,
 	}
 }
+
+// providerDocsURLs maps provider names to their documentation
+var providerDocsURLs = map[string]string{
+	"wpengine":      "https://github.com/Firecrown-Media/stax/blob/main/docs/PROVIDER_WPENGINE.md",
+	"wordpress-vip": "https://docs.wpvip.com/",
+}
+
+// NewUnsupportedOperationError creates an error for operations a provider cannot perform
+func NewUnsupportedOperationError(unsupported *provider.ErrUnsupported) *EnhancedError {
+	solutions := []Solution{}
+
+	if unsupported.Alternative != "" {
+		solutions = append(solutions, Solution{
+			Description: "Use the provider's alternative: " + unsupported.Alternative,
+		})
+	}
+
+	solutions = append(solutions, Solution{
+		Description: "Check which operations the provider supports",
+		Command:     "stax provider show " + unsupported.Provider,
+	})
+
+	docsURL := providerDocsURLs[unsupported.Provider]
+	if docsURL == "" {
+		docsURL = "https://github.com/Firecrown-Media/stax/blob/main/docs/MULTI_PROVIDER.md"
+	}
+
+	return &EnhancedError{
+		Code:      ErrCodeUnsupportedOperation,
+		Message:   fmt.Sprintf("Operation not supported by %s", unsupported.Provider),
+		Details:   fmt.Sprintf("The %s provider does not support %s. This is a limitation of the hosting platform or of the provider integration, not a problem with your configuration.", unsupported.Provider, unsupported.Operation),
+		Solutions: solutions,
+		DocsURL:   docsURL,
+		Err:       unsupported,
+	}
+}
+
+// Enhance converts known error types into enhanced errors
+// Errors that are already enhanced or have no enhanced form are returned unchanged
+func Enhance(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var enhanced *EnhancedError
+	if stderrors.As(err, &enhanced) {
+		return err
+	}
+
+	var unsupported *provider.ErrUnsupported
+	if stderrors.As(err, &unsupported) {
+		return NewUnsupportedOperationError(unsupported)
+	}
+
+	return err
+}
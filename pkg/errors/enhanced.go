@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -17,6 +18,7 @@ const (
 	ErrCodeCommandNotImplemented = "STX-006"
 	ErrCodeInvalidConfig         = "STX-007"
 	ErrCodeWPEngineAPI           = "STX-008"
+	ErrCodeUnsupportedOperation  = "STX-009"
 )
 
 // Solution represents a proposed solution to an error
@@ -65,6 +67,47 @@ func (e *EnhancedError) Unwrap() error {
 	return e.Err
 }
 
+// MarshalJSON renders the error in a stable machine-readable form for --json output
+func (e *EnhancedError) MarshalJSON() ([]byte, error) {
+	type jsonSolution struct {
+		Description string   `json:"description"`
+		Command     string   `json:"command,omitempty"`
+		Steps       []string `json:"steps,omitempty"`
+	}
+
+	solutions := make([]jsonSolution, len(e.Solutions))
+	for i, sol := range e.Solutions {
+		solutions[i] = jsonSolution{
+			Description: sol.Description,
+			Command:     sol.Command,
+			Steps:       sol.Steps,
+		}
+	}
+
+	cause := ""
+	if e.Err != nil {
+		cause = e.Err.Error()
+	}
+
+	return json.Marshal(struct {
+		Code      string         `json:"code"`
+		Message   string         `json:"message"`
+		Details   string         `json:"details,omitempty"`
+		Tried     []string       `json:"tried,omitempty"`
+		Solutions []jsonSolution `json:"solutions,omitempty"`
+		DocsURL   string         `json:"docs_url,omitempty"`
+		Cause     string         `json:"cause,omitempty"`
+	}{
+		Code:      e.Code,
+		Message:   e.Message,
+		Details:   e.Details,
+		Tried:     e.Tried,
+		Solutions: solutions,
+		DocsURL:   e.DocsURL,
+		Cause:     cause,
+	})
+}
+
 // formatEnhancedError formats an enhanced error for display
 func formatEnhancedError(e *EnhancedError) string {
 	var sb strings.Builder
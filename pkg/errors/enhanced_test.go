@@ -1,9 +1,13 @@
 package errors
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/firecrown-media/stax/pkg/provider"
 )
 
 func TestNewEnhancedError(t *testing.T) {
@@ -245,3 +249,88 @@ func TestNewWPEngineAPIError(t *testing.T) {
 		t.Errorf("expected solutions, got none")
 	}
 }
+
+func TestNewUnsupportedOperationError(t *testing.T) {
+	unsupported := provider.NewUnsupportedError("wpengine", "database import", "import through the User Portal")
+	err := NewUnsupportedOperationError(unsupported)
+
+	if err.Code != ErrCodeUnsupportedOperation {
+		t.Errorf("expected code %s, got %s", ErrCodeUnsupportedOperation, err.Code)
+	}
+
+	if len(err.Solutions) != 2 {
+		t.Errorf("expected 2 solutions, got %d", len(err.Solutions))
+	}
+
+	if !strings.Contains(err.DocsURL, "PROVIDER_WPENGINE") {
+		t.Errorf("expected WPEngine docs URL, got %s", err.DocsURL)
+	}
+
+	if !provider.IsUnsupported(err) {
+		t.Error("expected enhanced error to unwrap to ErrUnsupported")
+	}
+}
+
+func TestEnhance(t *testing.T) {
+	unsupported := provider.NewUnsupportedError("local", "file upload", "")
+	wrapped := fmt.Errorf("push failed: %w", unsupported)
+
+	var enhanced *EnhancedError
+	if !errors.As(Enhance(wrapped), &enhanced) {
+		t.Fatal("expected wrapped ErrUnsupported to be enhanced")
+	}
+	if enhanced.Code != ErrCodeUnsupportedOperation {
+		t.Errorf("expected code %s, got %s", ErrCodeUnsupportedOperation, enhanced.Code)
+	}
+
+	plain := errors.New("plain error")
+	if Enhance(plain) != plain {
+		t.Error("expected plain error to be returned unchanged")
+	}
+
+	existing := NewInvalidConfigError("bad", nil)
+	if Enhance(existing) != existing {
+		t.Error("expected enhanced error to be returned unchanged")
+	}
+
+	if Enhance(nil) != nil {
+		t.Error("expected nil for nil error")
+	}
+}
+
+func TestEnhancedErrorMarshalJSON(t *testing.T) {
+	err := NewEnhancedError(
+		ErrCodeUnsupportedOperation,
+		"Operation not supported",
+		"details",
+		[]Solution{{Description: "Do this", Command: "stax provider show local"}},
+		"https://example.com/docs",
+		errors.New("cause"),
+	)
+
+	data, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatalf("Marshal() failed: %v", marshalErr)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+
+	for key, want := range map[string]string{
+		"code":     ErrCodeUnsupportedOperation,
+		"message":  "Operation not supported",
+		"docs_url": "https://example.com/docs",
+		"cause":    "cause",
+	} {
+		if decoded[key] != want {
+			t.Errorf("expected %s %q, got %v", key, want, decoded[key])
+		}
+	}
+
+	solutions, ok := decoded["solutions"].([]interface{})
+	if !ok || len(solutions) != 1 {
+		t.Errorf("expected 1 solution, got %v", decoded["solutions"])
+	}
+}
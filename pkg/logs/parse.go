@@ -0,0 +1,206 @@
+package logs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is a kind of log
+type Type string
+
+// Log types
+const (
+	TypeAccess   Type = "access"    // nginx access log (combined format)
+	TypePHPError Type = "php-error" // PHP error log or nginx error log
+	TypeWPDebug  Type = "wp-debug"  // wp-content/debug.log
+)
+
+// Types lists the supported log types
+var Types = []Type{TypePHPError, TypeAccess, TypeWPDebug}
+
+// ParseType validates a --type value
+func ParseType(s string) (Type, error) {
+	for _, t := range Types {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown log type %q (expected php-error, access or wp-debug)", s)
+}
+
+// Record is one structured log entry
+type Record struct {
+	Time    *time.Time `json:"time,omitempty"`
+	Type    Type       `json:"type,omitempty"`
+	Level   string     `json:"level,omitempty"`
+	Message string     `json:"message,omitempty"`
+
+	// PHP errors
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+
+	// Access log entries
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	Method     string `json:"method,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Protocol   string `json:"protocol,omitempty"`
+	Status     int    `json:"status,omitempty"`
+	Bytes      int64  `json:"bytes,omitempty"`
+	Referer    string `json:"referer,omitempty"`
+	UserAgent  string `json:"user_agent,omitempty"`
+
+	// Raw is the original text, including continuation lines
+	Raw string `json:"raw"`
+}
+
+var (
+	accessLine   = regexp.MustCompile(`^(\S+) \S+ \S+ \[([^\]]+)\] "([^"]*)" (\d{3}) (\d+|-)(?: "([^"]*)" "([^"]*)")?`)
+	phpLine      = regexp.MustCompile(`^\[(\d{2}-\w{3}-\d{4} \d{2}:\d{2}:\d{2})(?: ([^\]]+))?\] (.*)$`)
+	phpLevel     = regexp.MustCompile(`^PHP ([A-Za-z ]+?):\s+(.*)$`)
+	phpLocation  = regexp.MustCompile(`^(.*) in (\S+?)(?: on line |:)(\d+)$`)
+	nginxErrLine = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}) \[(\w+)\] (.*)$`)
+)
+
+// ParseLine parses one line as typ, or as whichever format matches when typ
+// is empty; nil means the line doesn't start a record
+func ParseLine(line string, typ Type) *Record {
+	switch typ {
+	case TypeAccess:
+		return parseAccess(line)
+	case TypePHPError:
+		if rec := parsePHP(line, TypePHPError); rec != nil {
+			return rec
+		}
+		return parseNginxError(line)
+	case TypeWPDebug:
+		return parsePHP(line, TypeWPDebug)
+	}
+
+	if rec := parseAccess(line); rec != nil {
+		return rec
+	}
+	if rec := parsePHP(line, TypePHPError); rec != nil {
+		return rec
+	}
+	return parseNginxError(line)
+}
+
+// parseAccess parses an nginx combined log line
+func parseAccess(line string) *Record {
+	m := accessLine.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+
+	rec := &Record{Type: TypeAccess, RemoteAddr: m[1], Referer: dash(m[6]), UserAgent: dash(m[7]), Raw: line}
+	if t, err := time.Parse("02/Jan/2006:15:04:05 -0700", m[2]); err == nil {
+		rec.Time = &t
+	}
+
+	request := strings.SplitN(m[3], " ", 3)
+	rec.Method = request[0]
+	if len(request) > 1 {
+		rec.Path = request[1]
+	}
+	if len(request) > 2 {
+		rec.Protocol = request[2]
+	}
+	rec.Message = m[3]
+
+	rec.Status, _ = strconv.Atoi(m[4])
+	rec.Bytes, _ = strconv.ParseInt(m[5], 10, 64)
+	return rec
+}
+
+// parsePHP parses an error_log line: [18-Oct-2026 12:34:56 UTC] PHP Warning:  ...
+func parsePHP(line string, typ Type) *Record {
+	m := phpLine.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+
+	rec := &Record{Type: typ, Message: m[3], Raw: line}
+	if t, ok := parsePHPTime(m[1], m[2]); ok {
+		rec.Time = &t
+	}
+
+	if lm := phpLevel.FindStringSubmatch(m[3]); lm != nil {
+		rec.Level = phpLevelName(lm[1])
+		rec.Message = lm[2]
+	}
+	if loc := phpLocation.FindStringSubmatch(rec.Message); loc != nil {
+		rec.Message = loc[1]
+		rec.File = loc[2]
+		rec.Line, _ = strconv.Atoi(loc[3])
+	}
+	return rec
+}
+
+// parsePHPTime parses a PHP log timestamp in its zone, an abbreviation or a
+// location name
+func parsePHPTime(value, zone string) (time.Time, bool) {
+	const layout = "02-Jan-2006 15:04:05"
+	if zone == "" {
+		zone = "UTC"
+	}
+	if loc, err := time.LoadLocation(zone); err == nil {
+		t, err := time.ParseInLocation(layout, value, loc)
+		return t, err == nil
+	}
+	t, err := time.Parse(layout+" MST", value+" "+zone)
+	return t, err == nil
+}
+
+// phpLevelName maps "Fatal error" and friends to a short level
+func phpLevelName(level string) string {
+	switch strings.ToLower(level) {
+	case "fatal error", "parse error", "recoverable fatal error":
+		return "fatal"
+	case "warning":
+		return "warning"
+	case "notice":
+		return "notice"
+	case "deprecated":
+		return "deprecated"
+	}
+	return strings.ToLower(level)
+}
+
+// parseNginxError parses an nginx error log line
+func parseNginxError(line string) *Record {
+	m := nginxErrLine.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+
+	rec := &Record{Type: TypePHPError, Level: m[2], Message: m[3], Raw: line}
+	if t, err := time.Parse("2006/01/02 15:04:05", m[1]); err == nil {
+		rec.Time = &t
+	}
+
+	// FastCGI errors carry the PHP message, which is more useful
+	if _, php, ok := strings.Cut(m[3], "PHP message: "); ok {
+		php = strings.TrimSuffix(strings.SplitN(php, `" while reading`, 2)[0], `"`)
+		if lm := phpLevel.FindStringSubmatch(php); lm != nil {
+			rec.Level = phpLevelName(lm[1])
+			php = lm[2]
+		}
+		rec.Message = php
+		if loc := phpLocation.FindStringSubmatch(php); loc != nil {
+			rec.Message = loc[1]
+			rec.File = loc[2]
+			rec.Line, _ = strconv.Atoi(loc[3])
+		}
+	}
+	return rec
+}
+
+func dash(s string) string {
+	if s == "-" {
+		return ""
+	}
+	return s
+}
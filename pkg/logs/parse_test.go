@@ -0,0 +1,101 @@
+package logs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		typ  Type
+		want Record
+		time string // RFC 3339, empty for none
+	}{
+		{
+			name: "nginx access",
+			line: `203.0.113.9 - - [18/Oct/2026:12:34:56 +0000] "GET /wp-login.php?action=lostpassword HTTP/1.1" 404 1520 "https://example.com/" "Mozilla/5.0 (X11)"`,
+			typ:  TypeAccess,
+			want: Record{Type: TypeAccess, Message: "GET /wp-login.php?action=lostpassword HTTP/1.1", RemoteAddr: "203.0.113.9", Method: "GET", Path: "/wp-login.php?action=lostpassword", Protocol: "HTTP/1.1", Status: 404, Bytes: 1520, Referer: "https://example.com/", UserAgent: "Mozilla/5.0 (X11)"},
+			time: "2026-10-18T12:34:56Z",
+		},
+		{
+			name: "access without referer",
+			line: `10.0.0.1 - - [18/Oct/2026:12:34:56 -0400] "POST /xmlrpc.php HTTP/2.0" 200 - "-" "-"`,
+			want: Record{Type: TypeAccess, Message: "POST /xmlrpc.php HTTP/2.0", RemoteAddr: "10.0.0.1", Method: "POST", Path: "/xmlrpc.php", Protocol: "HTTP/2.0", Status: 200},
+			time: "2026-10-18T12:34:56-04:00",
+		},
+		{
+			name: "php fatal",
+			line: `[18-Oct-2026 12:34:56 UTC] PHP Fatal error:  Uncaught Error: Call to undefined function foo() in /nas/content/live/mysite/wp-content/themes/site/functions.php:42`,
+			typ:  TypePHPError,
+			want: Record{Type: TypePHPError, Level: "fatal", Message: "Uncaught Error: Call to undefined function foo()", File: "/nas/content/live/mysite/wp-content/themes/site/functions.php", Line: 42},
+			time: "2026-10-18T12:34:56Z",
+		},
+		{
+			name: "php warning with location name",
+			line: `[18-Oct-2026 08:00:00 America/New_York] PHP Warning:  Undefined array key "id" in /sites/mysite/wp-content/plugins/x/x.php on line 7`,
+			typ:  TypeWPDebug,
+			want: Record{Type: TypeWPDebug, Level: "warning", Message: `Undefined array key "id"`, File: "/sites/mysite/wp-content/plugins/x/x.php", Line: 7},
+			time: "2026-10-18T08:00:00-04:00",
+		},
+		{
+			name: "debug.log message",
+			line: `[18-Oct-2026 12:00:00 UTC] Cron reschedule event error for hook: wp_version_check`,
+			typ:  TypeWPDebug,
+			want: Record{Type: TypeWPDebug, Message: "Cron reschedule event error for hook: wp_version_check"},
+			time: "2026-10-18T12:00:00Z",
+		},
+		{
+			name: "nginx fastcgi error",
+			line: `2026/10/18 12:34:56 [error] 1234#1234: *99 FastCGI sent in stderr: "PHP message: PHP Notice:  Trying to get property of non-object in /sites/mysite/index.php on line 3" while reading response header from upstream`,
+			typ:  TypePHPError,
+			want: Record{Type: TypePHPError, Level: "notice", Message: "Trying to get property of non-object", File: "/sites/mysite/index.php", Line: 3},
+			time: "2026-10-18T12:34:56Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseLine(tt.line, tt.typ)
+			if got == nil {
+				t.Fatal("ParseLine() = nil")
+			}
+
+			if tt.time == "" {
+				if got.Time != nil {
+					t.Errorf("Time = %v, want none", got.Time)
+				}
+			} else {
+				want, _ := time.Parse(time.RFC3339, tt.time)
+				if got.Time == nil || !got.Time.Equal(want) {
+					t.Errorf("Time = %v, want %v", got.Time, want)
+				}
+			}
+
+			got.Time = nil
+			tt.want.Raw = tt.line
+			if *got != tt.want {
+				t.Errorf("ParseLine() =\n%+v\nwant\n%+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLineContinuation(t *testing.T) {
+	for _, line := range []string{"Stack trace:", "#0 /sites/mysite/wp-settings.php(545): include()", "  thrown in /x.php on line 2"} {
+		if rec := ParseLine(line, TypePHPError); rec != nil {
+			t.Errorf("ParseLine(%q) = %+v, want a continuation", line, rec)
+		}
+	}
+}
+
+func TestParseType(t *testing.T) {
+	if typ, err := ParseType("php-error"); err != nil || typ != TypePHPError {
+		t.Errorf("ParseType(php-error) = %q, %v", typ, err)
+	}
+	if _, err := ParseType("error"); err == nil {
+		t.Error("expected an unknown type to fail")
+	}
+}
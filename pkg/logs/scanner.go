@@ -0,0 +1,146 @@
+package logs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultFlushDelay is how long a record waits for continuation lines
+// before it is emitted while following a log
+const DefaultFlushDelay = 200 * time.Millisecond
+
+// Scanner reads records from a log stream, joining continuation lines such
+// as PHP stack traces to the record they belong to
+type Scanner struct {
+	typ        Type
+	flushDelay time.Duration
+	lines      chan string
+	err        error
+
+	pending *Record
+	current *Record
+}
+
+// NewScanner starts reading r; typ may be empty to detect each line's format
+func NewScanner(r io.Reader, typ Type) *Scanner {
+	s := &Scanner{typ: typ, flushDelay: DefaultFlushDelay, lines: make(chan string, 64)}
+
+	go func() {
+		defer close(s.lines)
+		lines := bufio.NewScanner(r)
+		lines.Buffer(make([]byte, 64*1024), 1024*1024)
+		for lines.Scan() {
+			s.lines <- lines.Text()
+		}
+		// Read after lines is closed, so there is no race
+		s.err = lines.Err()
+	}()
+
+	return s
+}
+
+// Scan advances to the next record, returning false at the end of the stream
+func (s *Scanner) Scan() bool {
+	for {
+		var flush <-chan time.Time
+		var timer *time.Timer
+		if s.pending != nil {
+			timer = time.NewTimer(s.flushDelay)
+			flush = timer.C
+		}
+
+		line, ok, flushed := "", false, false
+		select {
+		case line, ok = <-s.lines:
+		case <-flush:
+			flushed = true
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+
+		if flushed {
+			// Nothing more arrived; a followed log may stay quiet for long
+			s.current, s.pending = s.pending, nil
+			return true
+		}
+		if !ok {
+			s.current, s.pending = s.pending, nil
+			return s.current != nil
+		}
+		if line == "" {
+			continue
+		}
+
+		rec := ParseLine(line, s.typ)
+		if rec == nil && s.pending != nil && s.typ != TypeAccess {
+			s.pending.Raw += "\n" + line
+			continue
+		}
+		if rec == nil {
+			rec = &Record{Type: s.typ, Message: line, Raw: line}
+		}
+
+		previous := s.pending
+		s.pending = rec
+		if previous != nil {
+			s.current = previous
+			return true
+		}
+	}
+}
+
+// Record returns the record read by the last Scan
+func (s *Scanner) Record() *Record {
+	return s.current
+}
+
+// Err returns the error that ended the stream, if any
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// Filter selects records
+type Filter struct {
+	Type  Type           // empty matches every type
+	Grep  *regexp.Regexp // matched against the raw text
+	Since time.Time      // records without a time always match
+}
+
+// Match reports whether rec passes the filter
+func (f Filter) Match(rec *Record) bool {
+	if f.Type != "" && rec.Type != "" && rec.Type != f.Type {
+		return false
+	}
+	if f.Grep != nil && !f.Grep.MatchString(rec.Raw) {
+		return false
+	}
+	if !f.Since.IsZero() && rec.Time != nil && rec.Time.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// ParseSince parses a --since value: a duration such as 30m, 1h or 2d, a
+// date, or an RFC 3339 time
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if d, err := time.ParseDuration(days + "h"); err == nil {
+			return now.Add(-24 * d), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value %q: use a duration (30m, 1h, 2d), a date (2006-01-02) or an RFC 3339 time", value)
+}
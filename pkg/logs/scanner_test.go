@@ -0,0 +1,121 @@
+package logs
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScannerJoinsContinuationLines(t *testing.T) {
+	input := strings.Join([]string{
+		"[18-Oct-2026 12:00:00 UTC] PHP Fatal error:  Uncaught Exception: boom in /sites/mysite/a.php:3",
+		"Stack trace:",
+		"#0 {main}",
+		"  thrown in /sites/mysite/a.php on line 3",
+		"",
+		"[18-Oct-2026 12:00:01 UTC] PHP Notice:  Undefined variable in /sites/mysite/b.php on line 9",
+	}, "\n")
+
+	s := NewScanner(strings.NewReader(input), TypePHPError)
+	var records []*Record
+	for s.Scan() {
+		records = append(records, s.Record())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if !strings.HasSuffix(records[0].Raw, "\n  thrown in /sites/mysite/a.php on line 3") || records[0].Level != "fatal" {
+		t.Errorf("unexpected first record: %+v", records[0])
+	}
+	if records[1].Level != "notice" || records[1].Line != 9 {
+		t.Errorf("unexpected second record: %+v", records[1])
+	}
+}
+
+func TestScannerFlushesWhileFollowing(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+
+	s := NewScanner(r, TypeWPDebug)
+	s.flushDelay = 10 * time.Millisecond
+
+	go io.WriteString(w, "[18-Oct-2026 12:00:00 UTC] waiting for more\n")
+
+	done := make(chan bool)
+	go func() { done <- s.Scan() }()
+
+	select {
+	case ok := <-done:
+		if !ok || s.Record().Message != "waiting for more" {
+			t.Errorf("Scan() = %t, %+v", ok, s.Record())
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("a quiet followed log held back its last record")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	at := func(s string) *time.Time {
+		t, _ := time.Parse(time.RFC3339, s)
+		return &t
+	}
+	since, _ := time.Parse(time.RFC3339, "2026-10-18T12:00:00Z")
+
+	tests := []struct {
+		name   string
+		filter Filter
+		rec    Record
+		want   bool
+	}{
+		{"empty filter", Filter{}, Record{Raw: "x"}, true},
+		{"type mismatch", Filter{Type: TypeAccess}, Record{Type: TypePHPError}, false},
+		{"grep match", Filter{Grep: regexp.MustCompile(`(?i)fatal`)}, Record{Raw: "PHP Fatal error"}, true},
+		{"grep miss", Filter{Grep: regexp.MustCompile(`wp-login`)}, Record{Raw: "GET /"}, false},
+		{"too old", Filter{Since: since}, Record{Time: at("2026-10-18T11:59:59Z")}, false},
+		{"recent", Filter{Since: since}, Record{Time: at("2026-10-18T12:00:00Z")}, true},
+		{"no time", Filter{Since: since}, Record{Raw: "Stack trace:"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(&tt.rec); got != tt.want {
+				t.Errorf("Match() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"30m", now.Add(-30 * time.Minute), false},
+		{"1h", now.Add(-time.Hour), false},
+		{"2d", now.Add(-48 * time.Hour), false},
+		{"2026-10-01", time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2026-10-17T08:00:00Z", time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSince() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
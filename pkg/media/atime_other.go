@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package media
+
+import (
+	"os"
+	"time"
+)
+
+// accessTime returns the modification time, as access times are not read here
+func accessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
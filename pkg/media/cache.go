@@ -0,0 +1,314 @@
+package media
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tempPrefix marks partially written cache files
+	tempPrefix = ".tmp-"
+
+	// accessLogName is the request log kept in the cache directory
+	accessLogName = ".access.log"
+)
+
+// Cache stores fetched media on disk, keyed by upload path
+// Entries older than the TTL are misses, and the least recently used entries
+// are evicted once the cache grows past its maximum size
+// Dot files in the cache directory are bookkeeping, never entries
+type Cache struct {
+	dir     string
+	maxSize int64         // 0 for unlimited
+	ttl     time.Duration // 0 to never expire
+
+	// now is the current time (default time.Now)
+	now func() time.Time
+
+	mu   sync.Mutex
+	size int64
+}
+
+// NewCache opens the cache in dir, creating it if needed
+func NewCache(dir string, maxSize int64, ttl time.Duration) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c := &Cache{dir: dir, maxSize: maxSize, ttl: ttl, now: time.Now}
+	entries, err := c.entries()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		c.size += e.size
+	}
+	return c, nil
+}
+
+// Dir returns the cache directory
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Path returns the file an upload path is cached in
+func (c *Cache) Path(key string) (string, error) {
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+	if key == "" || key == "." {
+		return "", fmt.Errorf("invalid cache key")
+	}
+	for _, part := range strings.Split(key, "/") {
+		if strings.HasPrefix(part, ".") {
+			return "", fmt.Errorf("invalid cache key: %s", key)
+		}
+	}
+	return filepath.Join(c.dir, filepath.FromSlash(key)), nil
+}
+
+// Open returns the cached file for key if it exists and has not expired
+func (c *Cache) Open(key string) (*os.File, os.FileInfo, bool) {
+	p, err := c.Path(key)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, nil, false
+	}
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() || c.expired(info.ModTime()) {
+		f.Close()
+		return nil, nil, false
+	}
+
+	// The access time orders eviction; the modification time stays the fetch time
+	os.Chtimes(p, c.now(), info.ModTime())
+	return f, info, true
+}
+
+// Put stores the contents of r under key
+// The entry only becomes visible once it has been written completely
+func (c *Cache) Put(key string, r io.Reader) (int64, error) {
+	p, err := c.Path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), tempPrefix+"*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create cache file: %w", err)
+	}
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// Expiry is measured from when the file was fetched
+		now := c.now()
+		err = os.Chtimes(tmp.Name(), now, now)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return 0, fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var replaced int64
+	if info, err := os.Stat(p); err == nil {
+		replaced = info.Size()
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		os.Remove(tmp.Name())
+		return 0, fmt.Errorf("failed to store cache file: %w", err)
+	}
+
+	c.size += n - replaced
+	if c.maxSize > 0 && c.size > c.maxSize {
+		// The new entry is kept even when it alone exceeds the maximum size,
+		// so the caller can still serve it; the next prune evicts it
+		if _, _, err := c.prune(p); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Prune removes expired entries, then the least recently used entries until
+// the cache fits within its maximum size
+func (c *Cache) Prune() (removed int, freed int64, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.prune("")
+}
+
+// Fits reports whether an entry of size bytes can be cached without
+// exceeding the maximum size on its own; a negative size is unknown and fits
+func (c *Cache) Fits(size int64) bool {
+	return c.maxSize <= 0 || size <= c.maxSize
+}
+
+// prune evicts expired and least recently used entries, never removing the
+// entry at keep
+func (c *Cache) prune(keep string) (removed int, freed int64, err error) {
+	entries, err := c.entries()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var total int64
+	for _, e := range entries {
+		total += e.size
+	}
+
+	remove := func(e cacheEntry) error {
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove cache file: %w", err)
+		}
+		removed++
+		freed += e.size
+		total -= e.size
+		return nil
+	}
+
+	var live []cacheEntry
+	for _, e := range entries {
+		if e.path == keep {
+			continue
+		}
+		if !c.expired(e.modTime) {
+			live = append(live, e)
+			continue
+		}
+		if err := remove(e); err != nil {
+			return removed, freed, err
+		}
+	}
+
+	sort.Slice(live, func(i, j int) bool { return live[i].accessed.Before(live[j].accessed) })
+	for _, e := range live {
+		if c.maxSize <= 0 || total <= c.maxSize {
+			break
+		}
+		if err := remove(e); err != nil {
+			return removed, freed, err
+		}
+	}
+
+	c.size = total
+	return removed, freed, nil
+}
+
+// Clear removes every entry and the access log
+func (c *Cache) Clear() (removed int, freed int64, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.entries()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, e := range entries {
+		removed++
+		freed += e.size
+	}
+
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	for _, d := range dirEntries {
+		if err := os.RemoveAll(filepath.Join(c.dir, d.Name())); err != nil {
+			return 0, 0, fmt.Errorf("failed to clear cache: %w", err)
+		}
+	}
+
+	c.size = 0
+	return removed, freed, nil
+}
+
+// expired reports whether an entry written at modTime has outlived the TTL
+func (c *Cache) expired(modTime time.Time) bool {
+	return c.ttl > 0 && c.now().Sub(modTime) > c.ttl
+}
+
+// cacheEntry is a file in the cache
+type cacheEntry struct {
+	path     string
+	size     int64
+	modTime  time.Time // when it was fetched
+	accessed time.Time // when it was last served
+}
+
+// entries lists the cached files, skipping bookkeeping and partial writes
+func (c *Cache) entries() ([]cacheEntry, error) {
+	var entries []cacheEntry
+	err := filepath.WalkDir(c.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		entries = append(entries, cacheEntry{path: p, size: info.Size(), modTime: info.ModTime(), accessed: accessTime(info)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	return entries, nil
+}
+
+// ParseSize parses sizes such as "512MB", "10g" or "1GiB" into bytes
+// Units are powers of 1024, as in nginx
+func ParseSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	if s == "" {
+		return 0, nil
+	}
+
+	multipliers := []struct {
+		suffix string
+		factor int64
+	}{
+		{"TIB", 1 << 40}, {"GIB", 1 << 30}, {"MIB", 1 << 20}, {"KIB", 1 << 10},
+		{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+		{"B", 1},
+	}
+
+	factor := int64(1)
+	for _, m := range multipliers {
+		if rest, ok := strings.CutSuffix(s, m.suffix); ok {
+			s, factor = strings.TrimSpace(rest), m.factor
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size: %q", value)
+	}
+	return int64(n * float64(factor)), nil
+}
@@ -0,0 +1,219 @@
+package media
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewCache(dir, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	for i, key := range []string{"2024/a.jpg", "2024/b.jpg", "c.jpg"} {
+		if _, err := cache.Put(key, strings.NewReader("1234")); err != nil {
+			t.Fatalf("Put(%s) failed: %v", key, err)
+		}
+		p, _ := cache.Path(key)
+		mod := base.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(p, mod, mod)
+	}
+
+	// The third entry pushed the cache past 10 bytes, evicting the oldest
+	if _, _, ok := cache.Open("2024/a.jpg"); ok {
+		t.Error("oldest entry was not evicted")
+	}
+	for _, key := range []string{"2024/b.jpg", "c.jpg"} {
+		f, _, ok := cache.Open(key)
+		if !ok {
+			t.Errorf("%s was evicted", key)
+			continue
+		}
+		f.Close()
+	}
+
+	// Sizes survive reopening the cache
+	reopened, err := NewCache(dir, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.size != 8 {
+		t.Errorf("reopened size = %d, want 8", reopened.size)
+	}
+}
+
+func TestCachePutLargerThanMax(t *testing.T) {
+	cache, err := NewCache(t.TempDir(), 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache.Fits(5) || !cache.Fits(4) || !cache.Fits(-1) {
+		t.Error("Fits does not match the maximum size")
+	}
+
+	if _, err := cache.Put("small.jpg", strings.NewReader("1234")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Put("large.jpg", strings.NewReader("123456")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The oversized entry evicts the others but survives its own Put
+	if _, _, ok := cache.Open("small.jpg"); ok {
+		t.Error("small entry was not evicted")
+	}
+	f, _, ok := cache.Open("large.jpg")
+	if !ok {
+		t.Fatal("large entry was evicted by its own Put")
+	}
+	f.Close()
+}
+
+func TestCachePrune(t *testing.T) {
+	cache, err := NewCache(t.TempDir(), 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"old.jpg", "new.jpg"} {
+		if _, err := cache.Put(key, strings.NewReader("data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p, _ := cache.Path("old.jpg")
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(p, old, old)
+
+	// Partial writes are never counted or pruned
+	os.WriteFile(filepath.Join(cache.Dir(), tempPrefix+"123"), []byte("partial"), 0644)
+
+	removed, freed, err := cache.Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 || freed != 4 {
+		t.Errorf("Prune() = %d, %d; want 1, 4", removed, freed)
+	}
+	if _, err := os.Stat(p); !os.IsNotExist(err) {
+		t.Error("expired entry still exists")
+	}
+}
+
+func TestCachePath(t *testing.T) {
+	cache := &Cache{dir: "/cache"}
+	tests := map[string]string{
+		"2024/01/a.jpg":     filepath.Join("/cache", "2024", "01", "a.jpg"),
+		"../../etc/passwd":  filepath.Join("/cache", "etc", "passwd"),
+		"2024/.tmp-1/a.jpg": "",
+		".access.log":       "",
+		"":                  "",
+	}
+	for key, want := range tests {
+		got, err := cache.Path(key)
+		if want == "" {
+			if err == nil {
+				t.Errorf("Path(%q) = %s, want an error", key, got)
+			}
+			continue
+		}
+		if got != want {
+			t.Errorf("Path(%q) = %s, want %s", key, got, want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{
+		"":       0,
+		"512":    512,
+		"512B":   512,
+		"10k":    10 << 10,
+		"1GB":    1 << 30,
+		"10g":    10 << 30,
+		"1.5MiB": 3 << 19,
+		" 2 TB ": 2 << 40,
+	}
+	for value, want := range tests {
+		got, err := ParseSize(value)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"lots", "-1GB", "GB"} {
+		if _, err := ParseSize(value); err == nil {
+			t.Errorf("ParseSize(%q) should fail", value)
+		}
+	}
+}
+
+func TestCacheLeastRecentlyUsed(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("access times are not read on " + runtime.GOOS)
+	}
+
+	cache, err := NewCache(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now().Add(-time.Hour)
+	for i, key := range []string{"a.jpg", "b.jpg", "c.jpg"} {
+		if _, err := cache.Put(key, strings.NewReader("1234")); err != nil {
+			t.Fatal(err)
+		}
+		p, _ := cache.Path(key)
+		mod := base.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(p, mod, mod)
+	}
+
+	// Reading the oldest fetch makes it the most recently used
+	f, _, ok := cache.Open("a.jpg")
+	if !ok {
+		t.Fatal("a.jpg not cached")
+	}
+	f.Close()
+
+	cache.maxSize = 8
+	if removed, _, err := cache.Prune(); err != nil || removed != 1 {
+		t.Fatalf("Prune() = %d, %v", removed, err)
+	}
+	if _, _, ok := cache.Open("b.jpg"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if f, _, ok := cache.Open("a.jpg"); !ok {
+		t.Error("recently used entry was evicted")
+	} else {
+		f.Close()
+	}
+}
+
+func TestCacheClear(t *testing.T) {
+	cache, err := NewCache(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"2024/01/a.jpg", "b.jpg"} {
+		if _, err := cache.Put(key, strings.NewReader("data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.WriteFile(cache.AccessLogPath(), []byte("log"), 0644)
+
+	removed, freed, err := cache.Clear()
+	if err != nil || removed != 2 || freed != 8 {
+		t.Fatalf("Clear() = %d, %d, %v", removed, freed, err)
+	}
+	left, _ := os.ReadDir(cache.Dir())
+	if len(left) != 0 {
+		t.Errorf("cache directory not empty: %v", left)
+	}
+	if _, err := os.Stat(cache.Dir()); err != nil {
+		t.Errorf("cache directory removed: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package media
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SampleResult is one upload requested through the local site
+type SampleResult struct {
+	Key         string `json:"key"`
+	URL         string `json:"url"`
+	Status      int    `json:"status"`
+	Source      string `json:"source,omitempty"`       // X-Proxy-Source
+	CacheStatus string `json:"cache_status,omitempty"` // X-Cache-Status
+	LatencyMS   int64  `json:"latency_ms"`
+	Error       string `json:"error,omitempty"`
+}
+
+// OK reports whether the upload was served
+func (r SampleResult) OK() bool {
+	return r.Error == "" && r.Status == http.StatusOK
+}
+
+// OriginResult is the reachability of one source
+type OriginResult struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	Host      string `json:"host,omitempty"`
+	Status    int    `json:"status,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Reachable reports whether the source answered over HTTP
+func (r OriginResult) Reachable() bool {
+	return r.Error == ""
+}
+
+// LocalSiteClient returns a client for the local DDEV site
+// DDEV certificates are only trusted once mkcert is installed, and the site is
+// local, so certificates are not verified
+func LocalSiteClient() *http.Client {
+	return &http.Client{
+		Timeout: DefaultFetchTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+}
+
+// RequestSamples requests each upload path from siteURL, as a browser would,
+// and records where the proxy served it from
+func RequestSamples(ctx context.Context, client *http.Client, siteURL string, keys []string) []SampleResult {
+	results := make([]SampleResult, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = requestSample(ctx, client, siteURL, key)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func requestSample(ctx context.Context, client *http.Client, siteURL, key string) SampleResult {
+	result := SampleResult{Key: key, URL: strings.TrimRight(siteURL, "/") + UploadsPrefix + key}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, result.URL, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("User-Agent", "stax-media-test")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	result.LatencyMS = time.Since(start).Milliseconds()
+	result.Status = resp.StatusCode
+	result.Source = resp.Header.Get(SourceHeader)
+	result.CacheStatus = resp.Header.Get(CacheStatusHeader)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to read response: %v", err)
+	}
+	return result
+}
+
+// CheckOrigins requests the uploads directory from each source
+// Any HTTP response shows the source is reachable
+func (s *Server) CheckOrigins(ctx context.Context, sources []Source) []OriginResult {
+	results := make([]OriginResult, len(sources))
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := OriginResult{Name: src.Name, URL: src.URL, Host: src.Host}
+			start := time.Now()
+			status, err := s.Probe(ctx, src, "")
+			result.LatencyMS = time.Since(start).Milliseconds()
+			if err != nil {
+				result.Error = err.Error()
+			}
+			result.Status = status
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// AllSources returns every source of the server, network site routes included,
+// without duplicates
+func (s *Server) AllSources() []Source {
+	var sources []Source
+	seen := make(map[Source]bool)
+	add := func(list []Source) {
+		for _, src := range list {
+			if !seen[src] {
+				seen[src] = true
+				sources = append(sources, src)
+			}
+		}
+	}
+	add(s.Sources)
+	for _, route := range s.Routes {
+		add(route.Sources)
+	}
+	return sources
+}
+
+// MissingLocally returns up to limit keys that are not files in uploadsDir,
+// and how many keys were skipped because they are
+// The webserver serves local files itself, so only missing uploads test the
+// media proxy
+func MissingLocally(uploadsDir string, keys []string, limit int) (missing []string, local int) {
+	for _, key := range keys {
+		if len(missing) == limit {
+			break
+		}
+		if info, err := os.Stat(filepath.Join(uploadsDir, filepath.FromSlash(key))); err == nil && info.Mode().IsRegular() {
+			local++
+			continue
+		}
+		missing = append(missing, key)
+	}
+	return missing, local
+}
+
+// RecentAttachmentsQuery returns SQL listing the newest attachments, in the
+// format read by ParseAttachmentRows
+func RecentAttachmentsQuery(tablePrefix string, limit int) (string, error) {
+	if !tablePrefixPattern.MatchString(tablePrefix) {
+		return "", fmt.Errorf("invalid table prefix: %q", tablePrefix)
+	}
+	if limit < 1 {
+		return "", fmt.Errorf("sample count must be at least 1")
+	}
+	return fmt.Sprintf("SELECT a.guid, m.meta_value FROM %[1]sposts a"+
+		" LEFT JOIN %[1]spostmeta m ON m.post_id = a.ID AND m.meta_key = '_wp_attached_file'"+
+		" WHERE a.post_type = 'attachment'"+
+		" ORDER BY a.post_date_gmt DESC, a.ID DESC LIMIT %d", tablePrefix, limit), nil
+}
@@ -0,0 +1,102 @@
+package media
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRequestSamples(t *testing.T) {
+	origin := newTestOrigin(t, map[string]string{"/wp-content/uploads/2024/01/a.jpg": "image"}, 0)
+	cache, err := NewCache(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	site := httptest.NewServer(&Server{Sources: []Source{{Name: "origin", URL: origin.URL}}, Cache: cache})
+	defer site.Close()
+
+	keys := []string{"2024/01/a.jpg", "2024/01/a.jpg", "2024/01/missing.jpg"}
+	results := RequestSamples(context.Background(), http.DefaultClient, site.URL+"/", keys[:1])
+	results = append(results, RequestSamples(context.Background(), http.DefaultClient, site.URL, keys[1:])...)
+
+	want := []struct {
+		ok     bool
+		source string
+		cache  string
+	}{
+		{true, "origin", CacheMiss},
+		{true, "cache", CacheHit},
+		{false, "none", CacheNotFound},
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.OK() != w.ok || r.Source != w.source || r.CacheStatus != w.cache {
+			t.Errorf("sample %d = %+v, want ok=%v source=%s cache=%s", i, r, w.ok, w.source, w.cache)
+		}
+		if !strings.HasPrefix(r.URL, site.URL+UploadsPrefix) {
+			t.Errorf("sample %d URL = %s", i, r.URL)
+		}
+	}
+}
+
+func TestCheckOrigins(t *testing.T) {
+	up := newTestOrigin(t, nil, http.StatusForbidden)
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	server := &Server{
+		Sources: []Source{{Name: "up", URL: up.URL}},
+		Routes:  []SiteRoute{{Site: "flying", ID: 3, Sources: []Source{{Name: "up", URL: up.URL}, {Name: "down", URL: down.URL}}}},
+	}
+	sources := server.AllSources()
+	if len(sources) != 2 {
+		t.Fatalf("AllSources() = %+v, want 2 sources", sources)
+	}
+
+	results := server.CheckOrigins(context.Background(), sources)
+	if !results[0].Reachable() || results[0].Status != http.StatusForbidden {
+		t.Errorf("up = %+v, want reachable with 403", results[0])
+	}
+	if results[1].Reachable() {
+		t.Errorf("down = %+v, want unreachable", results[1])
+	}
+}
+
+func TestRecentAttachmentsQuery(t *testing.T) {
+	query, err := RecentAttachmentsQuery("wp_3_", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"FROM wp_3_posts a", "wp_3_postmeta m", "LIMIT 5"} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q: %s", want, query)
+		}
+	}
+
+	if _, err := RecentAttachmentsQuery("wp_; DROP TABLE", 5); err == nil {
+		t.Error("expected an invalid prefix to fail")
+	}
+	if _, err := RecentAttachmentsQuery("wp_", 0); err == nil {
+		t.Error("expected a zero limit to fail")
+	}
+}
+
+func TestMissingLocally(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "2024", "01"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "2024", "01", "local.jpg"), []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"2024/01/local.jpg", "2024/01/a.jpg", "2024/01", "2024/01/b.jpg", "2024/01/c.jpg"}
+	missing, local := MissingLocally(dir, keys, 2)
+	if strings.Join(missing, ",") != "2024/01/a.jpg,2024/01" || local != 1 {
+		t.Errorf("MissingLocally() = %v, %d local", missing, local)
+	}
+}
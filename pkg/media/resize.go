@@ -0,0 +1,285 @@
+package media
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the WebP decoder
+)
+
+const (
+	// DefaultQuality matches the WordPress JPEG and WebP quality
+	DefaultQuality = 82
+
+	// maxPixels bounds the originals decoded for resizing
+	maxPixels = 100_000_000
+)
+
+// ErrNoWebPEncoder is returned when a WebP size is requested and cwebp is not
+// installed; Go has no WebP encoder of its own
+var ErrNoWebPEncoder = errors.New("generating WebP images needs cwebp (install libwebp)")
+
+// ImageSizesPHP prints the sizes registered in WordPress as JSON, for wp eval
+const ImageSizesPHP = `echo wp_json_encode(wp_get_registered_image_subsizes());`
+
+// ImageSize is a WordPress image size, as registered with add_image_size
+type ImageSize struct {
+	Name   string
+	Width  int // 0 for no limit
+	Height int // 0 for no limit
+	Crop   bool
+}
+
+// Resizer generates WordPress image sizes that exist on no source
+type Resizer struct {
+	// Sizes are the registered sizes; when empty any size smaller than the
+	// original is generated, cropped from the centre
+	Sizes []ImageSize
+
+	// Quality is the JPEG and WebP quality (default DefaultQuality)
+	Quality int
+
+	// WriteLocal saves generated files into the uploads directory instead of
+	// the cache, as WordPress would have
+	WriteLocal bool
+}
+
+var sizedNamePattern = regexp.MustCompile(`(?i)^(.+)-([0-9]+)x([0-9]+)\.(jpe?g|png|webp)$`)
+
+// ParseSizedName splits a size-suffixed upload such as 2024/01/photo-300x200.jpg
+// into the original, 2024/01/photo.jpg, and the requested dimensions
+func ParseSizedName(key string) (original string, width, height int, ok bool) {
+	m := sizedNamePattern.FindStringSubmatch(key)
+	if m == nil {
+		return "", 0, 0, false
+	}
+	width, err := strconv.Atoi(m[2])
+	if err != nil || width == 0 {
+		return "", 0, 0, false
+	}
+	height, err = strconv.Atoi(m[3])
+	if err != nil || height == 0 {
+		return "", 0, 0, false
+	}
+	return m[1] + "." + m[4], width, height, true
+}
+
+// originalKeys returns the uploads a sized file may be generated from
+// Since WordPress 5.3 large originals are also stored as -scaled copies
+func originalKeys(original string) []string {
+	ext := filepath.Ext(original)
+	return []string{original, strings.TrimSuffix(original, ext) + "-scaled" + ext}
+}
+
+// Resize decodes the original in src and returns it as a width x height image
+// encoded in the format of ext
+// It fails when no registered size produces those dimensions from the original
+func (r *Resizer) Resize(src io.Reader, ext string, width, height int) ([]byte, error) {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read original: %w", err)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read original: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("original is too large to resize (%dx%d)", cfg.Width, cfg.Height)
+	}
+
+	crop, ok := r.match(cfg.Width, cfg.Height, width, height)
+	if !ok {
+		return nil, fmt.Errorf("no image size produces %dx%d from a %dx%d original", width, height, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode original: %w", err)
+	}
+	crop = crop.Add(img.Bounds().Min)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+
+	quality := r.Quality
+	if quality <= 0 {
+		quality = DefaultQuality
+	}
+
+	var buf bytes.Buffer
+	switch strings.ToLower(ext) {
+	case ".jpg", ".jpeg":
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: quality})
+	case ".png":
+		err = png.Encode(&buf, dst)
+	case ".webp":
+		err = encodeWebP(&buf, dst, quality)
+	default:
+		err = fmt.Errorf("unsupported image format: %s", ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// match returns the part of the original to scale into a width x height file
+func (r *Resizer) match(origW, origH, width, height int) (image.Rectangle, bool) {
+	sizes := r.Sizes
+	if len(sizes) == 0 {
+		sizes = []ImageSize{{Width: width, Height: height, Crop: true}}
+	}
+	for _, size := range sizes {
+		crop, w, h, ok := ResizeDimensions(origW, origH, size)
+		if ok && w == width && h == height {
+			return crop, true
+		}
+	}
+	return image.Rectangle{}, false
+}
+
+// ResizeDimensions ports image_resize_dimensions from WordPress: the part of
+// the original that is scaled and the dimensions of the generated file
+// It reports false when WordPress would not generate the size, because the
+// original is not larger than it
+func ResizeDimensions(origW, origH int, size ImageSize) (crop image.Rectangle, width, height int, ok bool) {
+	if origW <= 0 || origH <= 0 || (size.Width <= 0 && size.Height <= 0) {
+		return image.Rectangle{}, 0, 0, false
+	}
+
+	if size.Crop {
+		aspect := float64(origW) / float64(origH)
+		width, height = min(size.Width, origW), min(size.Height, origH)
+		if width <= 0 {
+			width = int(math.Round(float64(height) * aspect))
+		}
+		if height <= 0 {
+			height = int(math.Round(float64(width) / aspect))
+		}
+
+		ratio := max(float64(width)/float64(origW), float64(height)/float64(origH))
+		cropW := int(math.Round(float64(width) / ratio))
+		cropH := int(math.Round(float64(height) / ratio))
+		x, y := (origW-cropW)/2, (origH-cropH)/2
+		crop = image.Rect(x, y, x+cropW, y+cropH)
+	} else {
+		crop = image.Rect(0, 0, origW, origH)
+		width, height = constrainDimensions(origW, origH, size.Width, size.Height)
+	}
+
+	if width >= origW && height >= origH {
+		return image.Rectangle{}, 0, 0, false
+	}
+	return crop, width, height, true
+}
+
+// constrainDimensions ports wp_constrain_dimensions
+func constrainDimensions(w, h, maxW, maxH int) (int, int) {
+	widthRatio, heightRatio := 1.0, 1.0
+	didWidth, didHeight := false, false
+	if maxW > 0 && w > maxW {
+		widthRatio, didWidth = float64(maxW)/float64(w), true
+	}
+	if maxH > 0 && h > maxH {
+		heightRatio, didHeight = float64(maxH)/float64(h), true
+	}
+
+	smaller, larger := min(widthRatio, heightRatio), max(widthRatio, heightRatio)
+	ratio := larger
+	if int(math.Round(float64(w)*larger)) > maxW || int(math.Round(float64(h)*larger)) > maxH {
+		ratio = smaller
+	}
+
+	newW := max(1, int(math.Round(float64(w)*ratio)))
+	newH := max(1, int(math.Round(float64(h)*ratio)))
+
+	// Rounding can leave a size one pixel short of the limit
+	if didWidth && newW == maxW-1 {
+		newW = maxW
+	}
+	if didHeight && newH == maxH-1 {
+		newH = maxH
+	}
+	return newW, newH
+}
+
+// encodeWebP encodes img with cwebp
+func encodeWebP(w io.Writer, img image.Image, quality int) error {
+	cwebp, err := exec.LookPath("cwebp")
+	if err != nil {
+		return ErrNoWebPEncoder
+	}
+
+	dir, err := os.MkdirTemp("", "stax-webp-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	in, out := filepath.Join(dir, "in.png"), filepath.Join(dir, "out.webp")
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	if err := os.WriteFile(in, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write temp image: %w", err)
+	}
+
+	cmd := exec.Command(cwebp, "-quiet", "-q", strconv.Itoa(quality), in, "-o", out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cwebp failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		return fmt.Errorf("failed to read WebP image: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// ParseImageSizes reads the JSON printed by ImageSizesPHP
+func ParseImageSizes(output string) ([]ImageSize, error) {
+	var raw map[string]struct {
+		Width  int             `json:"width"`
+		Height int             `json:"height"`
+		Crop   json.RawMessage `json:"crop"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse image sizes: %w", err)
+	}
+
+	sizes := make([]ImageSize, 0, len(raw))
+	for name, s := range raw {
+		sizes = append(sizes, ImageSize{Name: name, Width: s.Width, Height: s.Height, Crop: cropEnabled(s.Crop)})
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i].Name < sizes[j].Name })
+	return sizes, nil
+}
+
+// cropEnabled reads a crop setting, which WordPress stores as a boolean or a
+// position such as ["left", "top"]
+// Positions are cropped from the centre
+func cropEnabled(raw json.RawMessage) bool {
+	var crop bool
+	if err := json.Unmarshal(raw, &crop); err == nil {
+		return crop
+	}
+	var position []string
+	return json.Unmarshal(raw, &position) == nil && len(position) > 0
+}
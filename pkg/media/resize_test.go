@@ -0,0 +1,212 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSizedName(t *testing.T) {
+	tests := []struct {
+		key      string
+		original string
+		width    int
+		height   int
+		ok       bool
+	}{
+		{"2024/01/photo-300x200.jpg", "2024/01/photo.jpg", 300, 200, true},
+		{"2024/01/my-photo-1024x768.JPEG", "2024/01/my-photo.JPEG", 1024, 768, true},
+		{"logo-150x150.webp", "logo.webp", 150, 150, true},
+		{"2024/01/photo.jpg", "", 0, 0, false},
+		{"2024/01/photo-300x200.gif", "", 0, 0, false},
+		{"2024/01/photo-0x200.png", "", 0, 0, false},
+		{"-300x200.png", "", 0, 0, false},
+	}
+	for _, tt := range tests {
+		original, width, height, ok := ParseSizedName(tt.key)
+		if original != tt.original || width != tt.width || height != tt.height || ok != tt.ok {
+			t.Errorf("ParseSizedName(%q) = %q, %d, %d, %v; want %q, %d, %d, %v",
+				tt.key, original, width, height, ok, tt.original, tt.width, tt.height, tt.ok)
+		}
+	}
+}
+
+func TestResizeDimensions(t *testing.T) {
+	tests := []struct {
+		name   string
+		w, h   int
+		size   ImageSize
+		crop   image.Rectangle
+		width  int
+		height int
+		ok     bool
+	}{
+		{"thumbnail", 1920, 1080, ImageSize{Width: 150, Height: 150, Crop: true}, image.Rect(420, 0, 1500, 1080), 150, 150, true},
+		{"medium", 1920, 1080, ImageSize{Width: 300, Height: 300}, image.Rect(0, 0, 1920, 1080), 300, 169, true},
+		{"medium_large", 1920, 1080, ImageSize{Width: 768}, image.Rect(0, 0, 1920, 1080), 768, 432, true},
+		{"portrait", 1000, 1500, ImageSize{Width: 1024, Height: 1024}, image.Rect(0, 0, 1000, 1500), 683, 1024, true},
+		{"crop width only", 1000, 500, ImageSize{Width: 400, Crop: true}, image.Rect(0, 0, 1000, 500), 400, 200, true},
+		{"larger than original", 800, 600, ImageSize{Width: 1024, Height: 1024}, image.Rectangle{}, 0, 0, false},
+		{"no limits", 800, 600, ImageSize{}, image.Rectangle{}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crop, width, height, ok := ResizeDimensions(tt.w, tt.h, tt.size)
+			if crop != tt.crop || width != tt.width || height != tt.height || ok != tt.ok {
+				t.Errorf("ResizeDimensions() = %v, %d, %d, %v; want %v, %d, %d, %v",
+					crop, width, height, ok, tt.crop, tt.width, tt.height, tt.ok)
+			}
+		})
+	}
+}
+
+func TestResizerResize(t *testing.T) {
+	original := encodeTestImage(t, 400, 200)
+	resizer := &Resizer{Sizes: []ImageSize{
+		{Name: "thumbnail", Width: 100, Height: 100, Crop: true},
+		{Name: "medium", Width: 300, Height: 300},
+	}}
+
+	for _, tt := range []struct {
+		ext           string
+		width, height int
+	}{
+		{".png", 100, 100},
+		{".jpg", 300, 150},
+	} {
+		data, err := resizer.Resize(bytes.NewReader(original), tt.ext, tt.width, tt.height)
+		if err != nil {
+			t.Fatalf("Resize(%s) failed: %v", tt.ext, err)
+		}
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Width != tt.width || cfg.Height != tt.height {
+			t.Errorf("Resize(%s) = %dx%d, want %dx%d", tt.ext, cfg.Width, cfg.Height, tt.width, tt.height)
+		}
+		if want := map[string]string{".png": "png", ".jpg": "jpeg"}[tt.ext]; format != want {
+			t.Errorf("Resize(%s) encoded %s", tt.ext, format)
+		}
+	}
+
+	// Only registered sizes are generated
+	if _, err := resizer.Resize(bytes.NewReader(original), ".png", 120, 80); err == nil {
+		t.Error("Resize() generated an unregistered size")
+	}
+
+	// Without registered sizes any smaller size is cropped
+	data, err := (&Resizer{}).Resize(bytes.NewReader(original), ".jpg", 120, 80)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("generated JPEG does not decode: %v", err)
+	}
+}
+
+func TestServerGeneratesSizes(t *testing.T) {
+	original := encodeTestImage(t, 400, 200)
+	origin := newTestOrigin(t, map[string]string{
+		"/wp-content/uploads/2024/01/photo.png": string(original),
+	}, 0)
+
+	uploads := t.TempDir()
+	cache, err := NewCache(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &Server{
+		UploadsDir: uploads,
+		Sources:    []Source{{Name: "origin", URL: origin.URL}},
+		Cache:      cache,
+		Resizer:    &Resizer{Sizes: []ImageSize{{Name: "thumbnail", Width: 100, Height: 100, Crop: true}}},
+	}
+
+	rec := get(t, server, http.MethodGet, "/wp-content/uploads/2024/01/photo-100x100.png")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get(CacheStatusHeader); got != CacheGenerated {
+		t.Errorf("%s = %s, want %s", CacheStatusHeader, got, CacheGenerated)
+	}
+	if cfg, err := png.DecodeConfig(rec.Body); err != nil || cfg.Width != 100 || cfg.Height != 100 {
+		t.Errorf("generated image = %+v, %v", cfg, err)
+	}
+
+	// The size and its original are now cached
+	before := origin.requests.Load()
+	rec = get(t, server, http.MethodGet, "/wp-content/uploads/2024/01/photo-100x100.png")
+	if got := rec.Header().Get(CacheStatusHeader); got != CacheHit {
+		t.Errorf("second request %s = %s, want %s", CacheStatusHeader, got, CacheHit)
+	}
+	rec = get(t, server, http.MethodGet, "/wp-content/uploads/2024/01/photo.png")
+	if got := rec.Header().Get(CacheStatusHeader); got != CacheHit {
+		t.Errorf("original %s = %s, want %s", CacheStatusHeader, got, CacheHit)
+	}
+	if n := origin.requests.Load() - before; n != 0 {
+		t.Errorf("cached requests reached the origin %d times", n)
+	}
+
+	// Unregistered sizes are not generated
+	if rec := get(t, server, http.MethodGet, "/wp-content/uploads/2024/01/photo-120x80.png"); rec.Code != http.StatusNotFound {
+		t.Errorf("unregistered size status = %d, want 404", rec.Code)
+	}
+
+	// Sizes can be written into the uploads directory instead
+	server.Resizer.WriteLocal = true
+	server.Resizer.Sizes = append(server.Resizer.Sizes, ImageSize{Name: "small", Width: 200})
+	if rec := get(t, server, http.MethodGet, "/wp-content/uploads/2024/01/photo-200x100.png"); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if _, err := os.Stat(filepath.Join(uploads, "2024", "01", "photo-200x100.png")); err != nil {
+		t.Errorf("generated file not written locally: %v", err)
+	}
+}
+
+func TestParseImageSizes(t *testing.T) {
+	output := `{"thumbnail":{"width":150,"height":150,"crop":true},"medium":{"width":300,"height":300,"crop":false},"hero":{"width":1600,"height":600,"crop":["center","top"]}}`
+	sizes, err := ParseImageSizes(output + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ImageSize{
+		{Name: "hero", Width: 1600, Height: 600, Crop: true},
+		{Name: "medium", Width: 300, Height: 300},
+		{Name: "thumbnail", Width: 150, Height: 150, Crop: true},
+	}
+	if len(sizes) != len(want) {
+		t.Fatalf("ParseImageSizes() = %+v", sizes)
+	}
+	for i := range want {
+		if sizes[i] != want[i] {
+			t.Errorf("sizes[%d] = %+v, want %+v", i, sizes[i], want[i])
+		}
+	}
+
+	if _, err := ParseImageSizes("Error: This does not seem to be a WordPress installation."); err == nil {
+		t.Error("ParseImageSizes() should fail on non-JSON output")
+	}
+}
+
+// encodeTestImage returns a PNG with a horizontal gradient
+func encodeTestImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / width), G: 128, B: 64, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
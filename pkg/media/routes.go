@@ -0,0 +1,120 @@
+package media
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/config"
+)
+
+// SiteListArgs lists the sites of a network with WP-CLI, for ParseSiteList
+var SiteListArgs = []string{"site", "list", "--fields=blog_id,domain,path", "--format=json"}
+
+// SiteRoute sends the uploads of one network site, stored in sites/<id>/, to
+// the sources of that site
+type SiteRoute struct {
+	Site    string   `json:"site"` // slug, or name when the slug is unset
+	ID      int      `json:"id"`
+	Sources []Source `json:"sources"`
+}
+
+// Prefix returns the upload path the route answers for
+func (r SiteRoute) Prefix() string {
+	return fmt.Sprintf("sites/%d/", r.ID)
+}
+
+// SourcesFor returns the sources tried for an upload path
+func (s *Server) SourcesFor(key string) []Source {
+	for _, route := range s.Routes {
+		if strings.HasPrefix(key, route.Prefix()) {
+			return route.Sources
+		}
+	}
+	return s.Sources
+}
+
+// SiteSlug returns the slug a site is selected by
+func SiteSlug(site config.SiteConfig) string {
+	if site.Slug != "" {
+		return site.Slug
+	}
+	return site.Name
+}
+
+// SiteRoutesFromConfig returns a route for each network site with a known
+// blog ID, from network.sites[].id or siteIDs, which maps domains to IDs
+// The main site keeps the top-level uploads directory and needs no route
+// Sites without an ID are returned as unresolved
+func SiteRoutesFromConfig(cfg *config.Config, siteIDs map[string]int) (routes []SiteRoute, unresolved []string) {
+	for _, site := range cfg.Network.Sites {
+		id := site.ID
+		if id == 0 {
+			for _, domain := range []string{site.Domain, site.WPEngineDomain} {
+				if n, ok := siteIDs[strings.ToLower(domain)]; ok && domain != "" {
+					id = n
+					break
+				}
+			}
+		}
+		switch {
+		case id == 0:
+			unresolved = append(unresolved, SiteSlug(site))
+			continue
+		case id == 1:
+			continue
+		}
+
+		routes = append(routes, SiteRoute{Site: SiteSlug(site), ID: id, Sources: siteSources(cfg, site)})
+	}
+	return routes, unresolved
+}
+
+// siteSources returns the sources of one network site: its own CDN and the
+// WPEngine install addressed with the site's production domain
+func siteSources(cfg *config.Config, site config.SiteConfig) []Source {
+	var bunny, wpe []Source
+	switch {
+	case site.CDNHostname != "":
+		bunny = append(bunny, Source{Name: SiteSlug(site) + "/bunnycdn", URL: hostURL(site.CDNHostname)})
+	case cfg.Media.BunnyCDN.Hostname != "":
+		bunny = append(bunny, Source{Name: "bunnycdn", URL: hostURL(cfg.Media.BunnyCDN.Hostname)})
+	}
+	if cfg.WPEngine.Install != "" && (cfg.Media.WPEngineFallback || cfg.Media.PrimarySource == "wpengine") {
+		wpe = append(wpe, Source{
+			Name: SiteSlug(site) + "/wpengine",
+			URL:  fmt.Sprintf("https://%s.wpengine.com", cfg.WPEngine.Install),
+			Host: site.WPEngineDomain,
+		})
+	}
+	return orderSources(cfg, bunny, wpe)
+}
+
+// ParseSiteList maps the domains in SiteListArgs output to blog IDs
+// Subdirectory sites are keyed by domain and path, as in example.com/blog
+func ParseSiteList(output string) (map[string]int, error) {
+	var sites []struct {
+		BlogID json.RawMessage `json:"blog_id"`
+		Domain string          `json:"domain"`
+		Path   string          `json:"path"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &sites); err != nil {
+		return nil, fmt.Errorf("failed to parse site list: %w", err)
+	}
+
+	ids := make(map[string]int, len(sites))
+	for _, site := range sites {
+		// WP-CLI prints IDs as strings
+		id, err := strconv.Atoi(strings.Trim(string(site.BlogID), `"`))
+		if err != nil {
+			return nil, fmt.Errorf("invalid blog ID %s for %s", site.BlogID, site.Domain)
+		}
+		key := strings.ToLower(site.Domain)
+		if p := strings.Trim(site.Path, "/"); p != "" {
+			key += "/" + p
+		}
+		ids[key] = id
+	}
+	return ids, nil
+}
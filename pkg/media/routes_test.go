@@ -0,0 +1,93 @@
+package media
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/firecrown-media/stax/pkg/config"
+)
+
+func TestSiteRoutesFromConfig(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.WPEngine.Install = "network"
+	cfg.Media = config.MediaConfig{BunnyCDN: config.BunnyCDNConfig{Hostname: "network.b-cdn.net"}, WPEngineFallback: true}
+	cfg.Network.Sites = []config.SiteConfig{
+		{Name: "main", Slug: "main", Domain: "network.local", WPEngineDomain: "network.com"},
+		{Name: "flying", Slug: "flying", Domain: "flying.network.local", WPEngineDomain: "flyingmag.com", CDNHostname: "flying.b-cdn.net"},
+		{Name: "pilot", Domain: "pilot.network.local", WPEngineDomain: "pilotmag.com", ID: 7},
+		{Name: "new", Slug: "new", Domain: "new.network.local"},
+	}
+	siteIDs := map[string]int{"network.local": 1, "flyingmag.com": 3}
+
+	routes, unresolved := SiteRoutesFromConfig(cfg, siteIDs)
+	want := []SiteRoute{
+		{Site: "flying", ID: 3, Sources: []Source{
+			{Name: "flying/bunnycdn", URL: "https://flying.b-cdn.net"},
+			{Name: "flying/wpengine", URL: "https://network.wpengine.com", Host: "flyingmag.com"},
+		}},
+		{Site: "pilot", ID: 7, Sources: []Source{
+			{Name: "bunnycdn", URL: "https://network.b-cdn.net"},
+			{Name: "pilot/wpengine", URL: "https://network.wpengine.com", Host: "pilotmag.com"},
+		}},
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("routes = %+v\nwant %+v", routes, want)
+	}
+	if !reflect.DeepEqual(unresolved, []string{"new"}) {
+		t.Errorf("unresolved = %v, want [new]", unresolved)
+	}
+}
+
+func TestServerRoutes(t *testing.T) {
+	var mu sync.Mutex
+	var hosts []string
+	main := newTestOrigin(t, map[string]string{"/wp-content/uploads/2024/01/a.jpg": "main"}, 0)
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hosts = append(hosts, r.Host)
+		mu.Unlock()
+		w.Write([]byte("site 3"))
+	}))
+	defer site.Close()
+
+	server := &Server{
+		Sources: []Source{{Name: "main", URL: main.URL}},
+		Routes: []SiteRoute{{Site: "flying", ID: 3, Sources: []Source{
+			{Name: "flying/wpengine", URL: site.URL, Host: "flyingmag.com"},
+		}}},
+	}
+
+	tests := map[string]string{
+		"/wp-content/uploads/2024/01/a.jpg":          "main",
+		"/wp-content/uploads/sites/3/2024/01/b.jpg":  "flying/wpengine",
+		"/wp-content/uploads/sites/30/2024/01/c.jpg": "none",
+	}
+	for target, want := range tests {
+		rec := get(t, server, http.MethodGet, target)
+		if got := rec.Header().Get(SourceHeader); got != want {
+			t.Errorf("%s served by %s, want %s", target, got, want)
+		}
+	}
+	if !reflect.DeepEqual(hosts, []string{"flyingmag.com"}) {
+		t.Errorf("site origin saw Host %v, want [flyingmag.com]", hosts)
+	}
+}
+
+func TestParseSiteList(t *testing.T) {
+	output := `[{"blog_id":"1","domain":"network.local","path":"/"},{"blog_id":"2","domain":"Network.local","path":"/blog/"},{"blog_id":3,"domain":"flying.network.local","path":"/"}]`
+	ids, err := ParseSiteList(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"network.local": 1, "network.local/blog": 2, "flying.network.local": 3}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("ParseSiteList() = %v, want %v", ids, want)
+	}
+
+	if _, err := ParseSiteList("Error: This is not a multisite installation."); err == nil {
+		t.Error("ParseSiteList() should fail on non-JSON output")
+	}
+}
@@ -0,0 +1,356 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// UploadsPrefix is the URL path the server answers for
+	UploadsPrefix = "/wp-content/uploads/"
+
+	// SourceHeader names where a response came from: local, cache or a source name
+	SourceHeader = "X-Proxy-Source"
+
+	// DefaultFetchTimeout bounds each request to a source
+	DefaultFetchTimeout = 30 * time.Second
+)
+
+// Server serves WordPress uploads from the local uploads directory, then the
+// cache, then each source in order
+type Server struct {
+	// UploadsDir is the local wp-content/uploads directory
+	UploadsDir string
+
+	// Sources are tried in order when a file is neither local nor cached
+	Sources []Source
+
+	// Routes send the uploads of network sites to their own sources
+	Routes []SiteRoute
+
+	// Cache stores fetched files; nil disables caching
+	Cache *Cache
+
+	// Client makes source requests (default: a client with DefaultFetchTimeout)
+	Client *http.Client
+
+	// Logf reports source failures; nil discards them
+	Logf func(format string, args ...interface{})
+
+	// AccessLog records each upload request for cache statistics; nil disables it
+	AccessLog io.Writer
+
+	// Resizer generates image sizes that no source has; nil disables it
+	Resizer *Resizer
+
+	logMu sync.Mutex
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key, ok := uploadKey(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if s.serveLocal(w, r, key) {
+		s.logAccess(CacheLocal, "local", key)
+		return
+	}
+	if s.serveCached(w, r, key) {
+		s.logAccess(CacheHit, "cache", key)
+		return
+	}
+
+	status := http.StatusNotFound
+	for _, src := range s.SourcesFor(key) {
+		w.Header().Set(CacheStatusHeader, CacheMiss)
+		ok, err := s.serveSource(w, r, key, src)
+		if ok {
+			s.logAccess(CacheMiss, src.Name, key)
+			return
+		}
+		if err != nil {
+			s.logf("%s: %v", src.Name, err)
+			status = http.StatusBadGateway
+		}
+	}
+
+	// Sizes registered locally may not exist anywhere yet
+	if s.serveResized(w, r, key) {
+		s.logAccess(CacheGenerated, "generated", key)
+		return
+	}
+
+	w.Header().Set(SourceHeader, "none")
+	w.Header().Set(CacheStatusHeader, CacheNotFound)
+	s.logAccess(CacheNotFound, "none", key)
+	http.Error(w, http.StatusText(status), status)
+}
+
+// uploadKey returns the path of a request below the uploads directory
+func uploadKey(urlPath string) (string, bool) {
+	key, ok := strings.CutPrefix(path.Clean(urlPath), UploadsPrefix)
+	if !ok || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+// serveLocal serves the file from the local uploads directory if it exists
+func (s *Server) serveLocal(w http.ResponseWriter, r *http.Request, key string) bool {
+	if s.UploadsDir == "" {
+		return false
+	}
+	f, err := os.Open(filepath.Join(s.UploadsDir, filepath.FromSlash(key)))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	w.Header().Set(SourceHeader, "local")
+	w.Header().Set(CacheStatusHeader, CacheLocal)
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+	return true
+}
+
+// serveCached serves the file from the cache if it holds a fresh copy
+func (s *Server) serveCached(w http.ResponseWriter, r *http.Request, key string) bool {
+	if s.Cache == nil {
+		return false
+	}
+	f, info, ok := s.Cache.Open(key)
+	if !ok {
+		return false
+	}
+	defer f.Close()
+
+	w.Header().Set(SourceHeader, "cache")
+	w.Header().Set(CacheStatusHeader, CacheHit)
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+	return true
+}
+
+// serveSource fetches the file from src, caching it when a cache is set
+// It reports false with a nil error when the source does not have the file
+func (s *Server) serveSource(w http.ResponseWriter, r *http.Request, key string, src Source) (bool, error) {
+	resp, err := s.fetch(r.Context(), src, key)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	w.Header().Set(SourceHeader, src.Name)
+
+	// Files too large for the cache are streamed straight through
+	if s.Cache != nil && s.Cache.Fits(resp.ContentLength) {
+		if _, err := s.Cache.Put(key, resp.Body); err != nil {
+			return false, err
+		}
+		f, info, ok := s.Cache.Open(key)
+		if !ok {
+			return false, fmt.Errorf("cached file for %s disappeared", key)
+		}
+		defer f.Close()
+		http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+		return true, nil
+	}
+
+	for _, h := range []string{"Content-Type", "Content-Length", "Last-Modified", "ETag"} {
+		if v := resp.Header.Get(h); v != "" {
+			w.Header().Set(h, v)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		io.Copy(w, resp.Body)
+	}
+	return true, nil
+}
+
+// serveResized generates a size-suffixed image from its original, storing it
+// in the uploads directory or the cache
+func (s *Server) serveResized(w http.ResponseWriter, r *http.Request, key string) bool {
+	if s.Resizer == nil {
+		return false
+	}
+	original, width, height, ok := ParseSizedName(key)
+	if !ok {
+		return false
+	}
+
+	var data []byte
+	for _, k := range originalKeys(original) {
+		f, ok := s.openOriginal(r.Context(), k)
+		if !ok {
+			continue
+		}
+		var err error
+		data, err = s.Resizer.Resize(f, path.Ext(key), width, height)
+		f.Close()
+		if err != nil {
+			s.logf("%s: %v", key, err)
+			return false
+		}
+		break
+	}
+	if data == nil {
+		return false
+	}
+
+	w.Header().Set(SourceHeader, "generated")
+	w.Header().Set(CacheStatusHeader, CacheGenerated)
+
+	switch {
+	case s.Resizer.WriteLocal && s.UploadsDir != "":
+		if err := writeFileAtomic(filepath.Join(s.UploadsDir, filepath.FromSlash(key)), data); err != nil {
+			s.logf("%s: %v", key, err)
+		}
+	case s.Cache != nil:
+		if _, err := s.Cache.Put(key, bytes.NewReader(data)); err != nil {
+			s.logf("%s: %v", key, err)
+		}
+	}
+	http.ServeContent(w, r, path.Base(key), time.Now(), bytes.NewReader(data))
+	return true
+}
+
+// openOriginal opens an upload from the local directory, the cache or the
+// first source that has it, caching fetched files
+func (s *Server) openOriginal(ctx context.Context, key string) (io.ReadCloser, bool) {
+	if s.UploadsDir != "" {
+		if f, err := os.Open(filepath.Join(s.UploadsDir, filepath.FromSlash(key))); err == nil {
+			return f, true
+		}
+	}
+	if s.Cache != nil {
+		if f, _, ok := s.Cache.Open(key); ok {
+			return f, true
+		}
+	}
+
+	for _, src := range s.SourcesFor(key) {
+		resp, err := s.fetch(ctx, src, key)
+		if err != nil {
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			continue
+		}
+		if s.Cache == nil {
+			return resp.Body, true
+		}
+
+		_, err = s.Cache.Put(key, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			s.logf("%s: %v", key, err)
+			return nil, false
+		}
+		if f, _, ok := s.Cache.Open(key); ok {
+			return f, true
+		}
+		return nil, false
+	}
+	return nil, false
+}
+
+// writeFileAtomic writes data to p, creating its directory
+func writeFileAtomic(p string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), tempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), p)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", p, err)
+	}
+	return nil
+}
+
+// fetch requests the upload key from src
+func (s *Server) fetch(ctx context.Context, src Source, key string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src.RequestURL(UploadsPrefix+key), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "stax-media-proxy")
+	if src.Host != "" {
+		req.Host = src.Host
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultFetchTimeout}
+	}
+	return client.Do(req)
+}
+
+// Probe requests key from src and returns the response status
+func (s *Server) Probe(ctx context.Context, src Source, key string) (int, error) {
+	resp, err := s.fetch(ctx, src, key)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// logAccess appends a request to the access log
+func (s *Server) logAccess(status, source, key string) {
+	if s.AccessLog == nil {
+		return
+	}
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	writeAccessLog(s.AccessLog, time.Now(), status, source, key)
+}
+
+func (s *Server) logf(format string, args ...interface{}) {
+	if s.Logf != nil {
+		s.Logf(format, args...)
+	}
+}
@@ -0,0 +1,186 @@
+package media
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testOrigin serves files from a map and counts requests
+type testOrigin struct {
+	*httptest.Server
+	requests atomic.Int32
+}
+
+func newTestOrigin(t *testing.T, files map[string]string, status int) *testOrigin {
+	t.Helper()
+	o := &testOrigin{}
+	o.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		o.requests.Add(1)
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(o.Close)
+	return o
+}
+
+func get(t *testing.T, handler http.Handler, method, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestServerSourceOrder(t *testing.T) {
+	uploads := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(uploads, "2024", "01"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(uploads, "2024", "01", "local.jpg"), []byte("local"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cdn := newTestOrigin(t, map[string]string{"/wp-content/uploads/2024/01/cdn.jpg": "from cdn"}, 0)
+	wpe := newTestOrigin(t, map[string]string{
+		"/wp-content/uploads/2024/01/cdn.jpg": "from wpengine",
+		"/wp-content/uploads/2024/01/wpe.jpg": "from wpengine",
+	}, 0)
+
+	cache, err := NewCache(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &Server{
+		UploadsDir: uploads,
+		Sources:    []Source{{Name: "bunnycdn", URL: cdn.URL}, {Name: "wpengine", URL: wpe.URL + "/"}},
+		Cache:      cache,
+	}
+
+	tests := []struct {
+		path   string
+		status int
+		source string
+		body   string
+	}{
+		{"/wp-content/uploads/2024/01/local.jpg", http.StatusOK, "local", "local"},
+		{"/wp-content/uploads/2024/01/cdn.jpg", http.StatusOK, "bunnycdn", "from cdn"},
+		{"/wp-content/uploads/2024/01/cdn.jpg", http.StatusOK, "cache", "from cdn"},
+		{"/wp-content/uploads/2024/01/wpe.jpg?ver=2", http.StatusOK, "wpengine", "from wpengine"},
+		{"/wp-content/uploads/2024/01/missing.jpg", http.StatusNotFound, "none", ""},
+		{"/wp-content/themes/style.css", http.StatusNotFound, "", ""},
+		{"/wp-content/uploads/../../wp-config.php", http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		rec := get(t, server, http.MethodGet, tt.path)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.path, rec.Code, tt.status)
+		}
+		if got := rec.Header().Get(SourceHeader); got != tt.source {
+			t.Errorf("%s: source %q, want %q", tt.path, got, tt.source)
+		}
+		if tt.body != "" && rec.Body.String() != tt.body {
+			t.Errorf("%s: body %q, want %q", tt.path, rec.Body.String(), tt.body)
+		}
+	}
+
+	if n := cdn.requests.Load(); n != 3 {
+		t.Errorf("CDN received %d requests, want 3", n)
+	}
+	if ct := get(t, server, http.MethodGet, "/wp-content/uploads/2024/01/wpe.jpg").Header().Get("Content-Type"); ct != "image/jpeg" {
+		t.Errorf("cached Content-Type = %q", ct)
+	}
+	if rec := get(t, server, http.MethodPost, "/wp-content/uploads/2024/01/local.jpg"); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status %d", rec.Code)
+	}
+}
+
+func TestServerWithoutCache(t *testing.T) {
+	origin := newTestOrigin(t, map[string]string{"/wp-content/uploads/a.png": "png"}, 0)
+	server := &Server{Sources: []Source{{Name: "origin", URL: origin.URL}}}
+
+	rec := get(t, server, http.MethodGet, "/wp-content/uploads/a.png")
+	if rec.Code != http.StatusOK || rec.Body.String() != "png" || rec.Header().Get("Content-Type") != "image/jpeg" {
+		t.Errorf("unexpected response: %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+
+	rec = get(t, server, http.MethodHead, "/wp-content/uploads/a.png")
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("HEAD response: %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestServerLargerThanCache(t *testing.T) {
+	origin := newTestOrigin(t, map[string]string{"/wp-content/uploads/big.jpg": "0123456789"}, 0)
+	cache, err := NewCache(t.TempDir(), 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &Server{Sources: []Source{{Name: "origin", URL: origin.URL}}, Cache: cache}
+
+	rec := get(t, server, http.MethodGet, "/wp-content/uploads/big.jpg")
+	if rec.Code != http.StatusOK || rec.Body.String() != "0123456789" {
+		t.Fatalf("unexpected response: %d %q", rec.Code, rec.Body.String())
+	}
+	if _, _, ok := cache.Open("big.jpg"); ok {
+		t.Error("file larger than the cache was cached")
+	}
+}
+
+func TestServerSourceErrors(t *testing.T) {
+	broken := newTestOrigin(t, nil, http.StatusInternalServerError)
+	origin := newTestOrigin(t, map[string]string{"/wp-content/uploads/a.png": "png"}, 0)
+
+	var logged []string
+	server := &Server{
+		Sources: []Source{{Name: "broken", URL: broken.URL}, {Name: "origin", URL: origin.URL}},
+		Logf: func(format string, args ...interface{}) {
+			logged = append(logged, format)
+		},
+	}
+
+	// A failing source falls through to the next one
+	if rec := get(t, server, http.MethodGet, "/wp-content/uploads/a.png"); rec.Header().Get(SourceHeader) != "origin" {
+		t.Errorf("source = %q, want origin", rec.Header().Get(SourceHeader))
+	}
+	if len(logged) != 1 {
+		t.Errorf("expected the broken source to be logged, got %v", logged)
+	}
+
+	// When every source fails the response is a gateway error, not a 404
+	if rec := get(t, server, http.MethodGet, "/wp-content/uploads/b.png"); rec.Code != http.StatusBadGateway {
+		t.Errorf("status %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+}
+
+func TestServerCacheTTL(t *testing.T) {
+	origin := newTestOrigin(t, map[string]string{"/wp-content/uploads/a.png": "png"}, 0)
+	cache, err := NewCache(t.TempDir(), 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &Server{Sources: []Source{{Name: "origin", URL: origin.URL}}, Cache: cache}
+
+	get(t, server, http.MethodGet, "/wp-content/uploads/a.png")
+	if src := get(t, server, http.MethodGet, "/wp-content/uploads/a.png").Header().Get(SourceHeader); src != "cache" {
+		t.Errorf("second request source = %q, want cache", src)
+	}
+
+	cache.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if src := get(t, server, http.MethodGet, "/wp-content/uploads/a.png").Header().Get(SourceHeader); src != "origin" {
+		t.Errorf("expired request source = %q, want origin", src)
+	}
+}
@@ -0,0 +1,61 @@
+package media
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/config"
+)
+
+// Source is a remote origin media is fetched from
+type Source struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`            // base URL, upload paths are appended
+	Host string `json:"host,omitempty"` // Host header, when the origin routes by domain
+}
+
+// RequestURL returns the URL of path on the source
+func (s Source) RequestURL(path string) string {
+	return strings.TrimRight(s.URL, "/") + path
+}
+
+// SourcesFromConfig returns the configured sources in the order they are tried:
+// the primary source, the other of BunnyCDN and WPEngine, then media.origins
+func SourcesFromConfig(cfg *config.Config) []Source {
+	var bunny, wpe []Source
+	if cfg.Media.BunnyCDN.Hostname != "" {
+		bunny = append(bunny, Source{Name: "bunnycdn", URL: hostURL(cfg.Media.BunnyCDN.Hostname)})
+	}
+	if cfg.WPEngine.Install != "" && (cfg.Media.WPEngineFallback || cfg.Media.PrimarySource == "wpengine") {
+		wpe = append(wpe, Source{Name: "wpengine", URL: fmt.Sprintf("https://%s.wpengine.com", cfg.WPEngine.Install)})
+	}
+
+	return orderSources(cfg, bunny, wpe)
+}
+
+// orderSources puts the primary source first and media.origins last
+func orderSources(cfg *config.Config, bunny, wpe []Source) []Source {
+	sources := append(bunny, wpe...)
+	if cfg.Media.PrimarySource == "wpengine" {
+		sources = append(wpe, bunny...)
+	}
+
+	for _, origin := range cfg.Media.Origins {
+		name := origin
+		if u, err := url.Parse(origin); err == nil && u.Host != "" {
+			name = u.Host
+		}
+		sources = append(sources, Source{Name: name, URL: origin})
+	}
+
+	return sources
+}
+
+// hostURL turns a bare hostname into an https URL
+func hostURL(host string) string {
+	if strings.Contains(host, "://") {
+		return host
+	}
+	return "https://" + host
+}
@@ -0,0 +1,53 @@
+package media
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/firecrown-media/stax/pkg/config"
+)
+
+func TestSourcesFromConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		media config.MediaConfig
+		want  []Source
+	}{
+		{
+			name:  "bunnycdn then wpengine",
+			media: config.MediaConfig{BunnyCDN: config.BunnyCDNConfig{Hostname: "mysite.b-cdn.net"}, WPEngineFallback: true},
+			want: []Source{
+				{Name: "bunnycdn", URL: "https://mysite.b-cdn.net"},
+				{Name: "wpengine", URL: "https://mysite.wpengine.com"},
+			},
+		},
+		{
+			name: "wpengine primary",
+			media: config.MediaConfig{
+				PrimarySource: "wpengine",
+				BunnyCDN:      config.BunnyCDNConfig{Hostname: "mysite.b-cdn.net"},
+				Origins:       []string{"https://media.example.com/"},
+			},
+			want: []Source{
+				{Name: "wpengine", URL: "https://mysite.wpengine.com"},
+				{Name: "bunnycdn", URL: "https://mysite.b-cdn.net"},
+				{Name: "media.example.com", URL: "https://media.example.com/"},
+			},
+		},
+		{
+			name:  "no wpengine fallback",
+			media: config.MediaConfig{Origins: []string{"http://localhost:9000"}},
+			want:  []Source{{Name: "localhost:9000", URL: "http://localhost:9000"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Media: tt.media}
+			cfg.WPEngine.Install = "mysite"
+			if got := SourcesFromConfig(cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SourcesFromConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
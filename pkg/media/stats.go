@@ -0,0 +1,174 @@
+package media
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cache statuses, sent in the X-Cache-Status header and the access log
+const (
+	CacheLocal     = "LOCAL"     // served from the local uploads directory
+	CacheHit       = "HIT"       // served from the cache
+	CacheMiss      = "MISS"      // fetched from a source
+	CacheNotFound  = "NOTFOUND"  // no source had the file
+	CacheGenerated = "GENERATED" // resized from the original
+)
+
+// CacheStatusHeader reports how a request was answered, as nginx does
+const CacheStatusHeader = "X-Cache-Status"
+
+// CacheStats describes the cache contents and how well it is serving requests
+type CacheStats struct {
+	Directory string      `json:"directory"`
+	Files     int         `json:"files"`
+	Size      int64       `json:"size"`
+	MaxSize   int64       `json:"max_size"`
+	Expired   int         `json:"expired"`
+	Oldest    *time.Time  `json:"oldest,omitempty"`
+	Newest    *time.Time  `json:"newest,omitempty"`
+	TopDirs   []DirUsage  `json:"top_directories"`
+	Requests  AccessStats `json:"requests"`
+}
+
+// DirUsage is the cache usage of one upload directory, such as 2024/01
+type DirUsage struct {
+	Path  string `json:"path"`
+	Files int    `json:"files"`
+	Size  int64  `json:"size"`
+}
+
+// AccessStats counts logged requests by cache status
+type AccessStats struct {
+	Total     int     `json:"total"`
+	Local     int     `json:"local"`
+	Hits      int     `json:"hits"`
+	Misses    int     `json:"misses"`
+	NotFound  int     `json:"not_found"`
+	Generated int     `json:"generated"`
+	HitRatio  float64 `json:"hit_ratio"` // hits / (hits + misses)
+}
+
+// Stats summarises the cache, listing the top directories by size
+func (c *Cache) Stats(top int) (*CacheStats, error) {
+	c.mu.Lock()
+	entries, err := c.entries()
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CacheStats{Directory: c.dir, MaxSize: c.maxSize}
+	dirs := make(map[string]*DirUsage)
+	for _, e := range entries {
+		stats.Files++
+		stats.Size += e.size
+		if c.expired(e.modTime) {
+			stats.Expired++
+		}
+		if stats.Oldest == nil || e.modTime.Before(*stats.Oldest) {
+			t := e.modTime
+			stats.Oldest = &t
+		}
+		if stats.Newest == nil || e.modTime.After(*stats.Newest) {
+			t := e.modTime
+			stats.Newest = &t
+		}
+
+		rel, err := filepath.Rel(c.dir, filepath.Dir(e.path))
+		if err != nil {
+			continue
+		}
+		dir := filepath.ToSlash(rel)
+		usage, ok := dirs[dir]
+		if !ok {
+			usage = &DirUsage{Path: dir}
+			dirs[dir] = usage
+		}
+		usage.Files++
+		usage.Size += e.size
+	}
+
+	for _, usage := range dirs {
+		stats.TopDirs = append(stats.TopDirs, *usage)
+	}
+	sort.Slice(stats.TopDirs, func(i, j int) bool {
+		if stats.TopDirs[i].Size != stats.TopDirs[j].Size {
+			return stats.TopDirs[i].Size > stats.TopDirs[j].Size
+		}
+		return stats.TopDirs[i].Path < stats.TopDirs[j].Path
+	})
+	if top > 0 && len(stats.TopDirs) > top {
+		stats.TopDirs = stats.TopDirs[:top]
+	}
+
+	if f, err := os.Open(c.AccessLogPath()); err == nil {
+		defer f.Close()
+		stats.Requests, err = ReadAccessLog(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+// AccessLogPath returns the request log kept in the cache directory
+func (c *Cache) AccessLogPath() string {
+	return filepath.Join(c.dir, accessLogName)
+}
+
+// OpenAccessLog opens the access log for appending
+func (c *Cache) OpenAccessLog() (*os.File, error) {
+	f, err := os.OpenFile(c.AccessLogPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open access log: %w", err)
+	}
+	return f, nil
+}
+
+// writeAccessLog appends one request to w
+func writeAccessLog(w io.Writer, t time.Time, status, source, key string) {
+	fmt.Fprintf(w, "%s %s %s %s\n", t.UTC().Format(time.RFC3339), status, source, path.Clean(key))
+}
+
+// ReadAccessLog counts the requests in an access log
+func ReadAccessLog(r io.Reader) (AccessStats, error) {
+	var stats AccessStats
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[1] {
+		case CacheLocal:
+			stats.Local++
+		case CacheHit:
+			stats.Hits++
+		case CacheMiss:
+			stats.Misses++
+		case CacheNotFound:
+			stats.NotFound++
+		case CacheGenerated:
+			stats.Generated++
+		default:
+			continue
+		}
+		stats.Total++
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, fmt.Errorf("failed to read access log: %w", err)
+	}
+
+	if fetched := stats.Hits + stats.Misses; fetched > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(fetched)
+	}
+	return stats, nil
+}
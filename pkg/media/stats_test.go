@@ -0,0 +1,71 @@
+package media
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCacheStats(t *testing.T) {
+	uploads := t.TempDir()
+	os.WriteFile(filepath.Join(uploads, "local.jpg"), []byte("local"), 0644)
+
+	origin := newTestOrigin(t, map[string]string{
+		"/wp-content/uploads/2024/01/a.jpg": "aaaaaaaa",
+		"/wp-content/uploads/2024/01/b.jpg": "bb",
+		"/wp-content/uploads/2023/12/c.jpg": "cccc",
+	}, 0)
+	cache, err := NewCache(t.TempDir(), 1<<20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := cache.OpenAccessLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+
+	server := &Server{UploadsDir: uploads, Sources: []Source{{Name: "origin", URL: origin.URL}}, Cache: cache, AccessLog: log}
+	for _, p := range []string{"2024/01/a.jpg", "2024/01/a.jpg", "2024/01/a.jpg", "2024/01/b.jpg", "2023/12/c.jpg", "local.jpg", "missing.jpg"} {
+		rec := get(t, server, http.MethodGet, UploadsPrefix+p)
+		if p == "2024/01/a.jpg" && rec.Header().Get(CacheStatusHeader) == "" {
+			t.Errorf("%s: no %s header", p, CacheStatusHeader)
+		}
+	}
+
+	stats, err := cache.Stats(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Files != 3 || stats.Size != 14 || stats.MaxSize != 1<<20 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	if len(stats.TopDirs) != 1 || stats.TopDirs[0] != (DirUsage{Path: "2024/01", Files: 2, Size: 10}) {
+		t.Errorf("unexpected top directories: %+v", stats.TopDirs)
+	}
+	want := AccessStats{Total: 7, Local: 1, Hits: 2, Misses: 3, NotFound: 1, HitRatio: 0.4}
+	if stats.Requests != want {
+		t.Errorf("requests = %+v, want %+v", stats.Requests, want)
+	}
+}
+
+func TestReadAccessLog(t *testing.T) {
+	log := strings.Join([]string{
+		"2026-10-18T12:00:00Z HIT cache 2024/a.jpg",
+		"2026-10-18T12:00:01Z MISS bunnycdn 2024/b.jpg",
+		"garbage",
+		"",
+		"2026-10-18T12:00:02Z HIT cache 2024/b.jpg",
+		"2026-10-18T12:00:03Z HIT cache 2024/b.jpg",
+	}, "\n")
+
+	stats, err := ReadAccessLog(strings.NewReader(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Total != 4 || stats.Hits != 3 || stats.Misses != 1 || stats.HitRatio != 0.75 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,164 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Warm statuses
+const (
+	WarmLocal   = "local"   // already in the local uploads directory
+	WarmCached  = "cached"  // already cached and fresh
+	WarmFetched = "fetched" // fetched from a source into the cache
+	WarmMissing = "missing" // no source had the file
+	WarmFailed  = "failed"  // a source or the cache failed
+)
+
+// WarmResult is the outcome of prefetching one upload
+type WarmResult struct {
+	Key    string
+	Status string
+	Source string
+	Size   int64
+	Err    error
+}
+
+// Warm makes sure key is available offline, fetching it into the cache when
+// it is neither local nor cached
+func (s *Server) Warm(ctx context.Context, key string) WarmResult {
+	result := WarmResult{Key: key}
+	if s.Cache == nil {
+		result.Status, result.Err = WarmFailed, fmt.Errorf("caching is disabled")
+		return result
+	}
+
+	if s.UploadsDir != "" {
+		if info, err := os.Stat(filepath.Join(s.UploadsDir, filepath.FromSlash(key))); err == nil && info.Mode().IsRegular() {
+			result.Status, result.Size = WarmLocal, info.Size()
+			return result
+		}
+	}
+	if f, info, ok := s.Cache.Open(key); ok {
+		f.Close()
+		result.Status, result.Size = WarmCached, info.Size()
+		return result
+	}
+
+	result.Status = WarmMissing
+	for _, src := range s.SourcesFor(key) {
+		resp, err := s.fetch(ctx, src, key)
+		if err != nil {
+			result.Status, result.Err = WarmFailed, fmt.Errorf("%s: %w", src.Name, err)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusGone {
+				result.Status, result.Err = WarmFailed, fmt.Errorf("%s: unexpected status %s", src.Name, resp.Status)
+			}
+			continue
+		}
+
+		n, err := s.Cache.Put(key, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			result.Status, result.Err = WarmFailed, err
+			return result
+		}
+		return WarmResult{Key: key, Status: WarmFetched, Source: src.Name, Size: n}
+	}
+	return result
+}
+
+// WarmAll warms keys with up to workers requests at a time, calling progress
+// after each one
+func (s *Server) WarmAll(ctx context.Context, keys []string, workers int, progress func(WarmResult)) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range jobs {
+				result := s.Warm(ctx, key)
+				if progress != nil {
+					mu.Lock()
+					progress(result)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, key := range keys {
+		select {
+		case jobs <- key:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+var tablePrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// AttachmentQuery returns SQL listing the attachments of the newest published
+// posts, both attached to and used as the featured image of each post
+// Rows hold the attachment GUID and its _wp_attached_file path
+func AttachmentQuery(tablePrefix string, posts int, since time.Time) (string, error) {
+	if !tablePrefixPattern.MatchString(tablePrefix) {
+		return "", fmt.Errorf("invalid table prefix: %q", tablePrefix)
+	}
+	if posts < 1 {
+		return "", fmt.Errorf("post count must be at least 1")
+	}
+
+	recent := fmt.Sprintf("SELECT ID FROM %[1]sposts WHERE post_status = 'publish' AND post_type NOT IN ('attachment', 'revision', 'nav_menu_item')", tablePrefix)
+	if !since.IsZero() {
+		recent += fmt.Sprintf(" AND post_date_gmt >= '%s'", since.UTC().Format("2006-01-02 15:04:05"))
+	}
+	recent += fmt.Sprintf(" ORDER BY post_date_gmt DESC LIMIT %d", posts)
+
+	return fmt.Sprintf("SELECT DISTINCT a.guid, m.meta_value FROM %[1]sposts a"+
+		" LEFT JOIN %[1]spostmeta m ON m.post_id = a.ID AND m.meta_key = '_wp_attached_file'"+
+		" JOIN (%[2]s) r ON a.post_parent = r.ID"+
+		" OR a.ID IN (SELECT t.meta_value FROM %[1]spostmeta t WHERE t.meta_key = '_thumbnail_id' AND t.post_id = r.ID)"+
+		" WHERE a.post_type = 'attachment'", tablePrefix, recent), nil
+}
+
+// ParseAttachmentRows turns tab separated AttachmentQuery output into upload
+// paths, preferring _wp_attached_file over the GUID
+func ParseAttachmentRows(output string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		guid, file, _ := strings.Cut(strings.TrimRight(line, "\r"), "\t")
+
+		key := strings.TrimSpace(file)
+		if key == "" || key == "NULL" {
+			_, key, _ = strings.Cut(guid, UploadsPrefix)
+		}
+		key = strings.TrimPrefix(key, "/")
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,100 @@
+package media
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWarmAll(t *testing.T) {
+	uploads := t.TempDir()
+	os.WriteFile(filepath.Join(uploads, "local.jpg"), []byte("local"), 0644)
+
+	broken := newTestOrigin(t, nil, 503)
+	origin := newTestOrigin(t, map[string]string{"/wp-content/uploads/2024/a.jpg": "aaaa"}, 0)
+	cache, err := NewCache(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Put("cached.jpg", strings.NewReader("cc")); err != nil {
+		t.Fatal(err)
+	}
+
+	server := &Server{
+		UploadsDir: uploads,
+		Sources:    []Source{{Name: "origin", URL: origin.URL}},
+		Cache:      cache,
+	}
+
+	got := make(map[string]WarmResult)
+	server.WarmAll(context.Background(), []string{"local.jpg", "cached.jpg", "2024/a.jpg", "missing.jpg"}, 3, func(r WarmResult) {
+		got[r.Key] = r
+	})
+
+	want := map[string]WarmResult{
+		"local.jpg":   {Key: "local.jpg", Status: WarmLocal, Size: 5},
+		"cached.jpg":  {Key: "cached.jpg", Status: WarmCached, Size: 2},
+		"2024/a.jpg":  {Key: "2024/a.jpg", Status: WarmFetched, Source: "origin", Size: 4},
+		"missing.jpg": {Key: "missing.jpg", Status: WarmMissing},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WarmAll() = %+v, want %+v", got, want)
+	}
+	if f, _, ok := cache.Open("2024/a.jpg"); !ok {
+		t.Error("fetched file was not cached")
+	} else {
+		f.Close()
+	}
+
+	// A failing source is reported rather than treated as missing
+	server.Sources = []Source{{Name: "broken", URL: broken.URL}}
+	if r := server.Warm(context.Background(), "other.jpg"); r.Status != WarmFailed || r.Err == nil {
+		t.Errorf("Warm() with a broken source = %+v", r)
+	}
+
+	server.Cache = nil
+	if r := server.Warm(context.Background(), "2024/a.jpg"); r.Status != WarmFailed {
+		t.Errorf("Warm() without a cache = %+v", r)
+	}
+}
+
+func TestAttachmentQuery(t *testing.T) {
+	query, err := AttachmentQuery("wp_", 20, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"FROM wp_posts a",
+		"m.meta_key = '_wp_attached_file'",
+		"post_date_gmt >= '2026-09-01 00:00:00'",
+		"LIMIT 20",
+		"t.meta_key = '_thumbnail_id'",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query missing %q:\n%s", want, query)
+		}
+	}
+
+	if _, err := AttachmentQuery("wp_'; DROP TABLE x; --", 20, time.Time{}); err == nil {
+		t.Error("expected an unsafe prefix to be rejected")
+	}
+	if _, err := AttachmentQuery("wp_", 0, time.Time{}); err == nil {
+		t.Error("expected a zero post count to be rejected")
+	}
+}
+
+func TestParseAttachmentRows(t *testing.T) {
+	output := "https://example.com/wp-content/uploads/2024/01/a.jpg\t2024/01/a.jpg\n" +
+		"https://example.com/wp-content/uploads/2024/01/b.png\tNULL\r\n" +
+		"https://example.com/?attachment_id=5\t\n" +
+		"https://example.com/wp-content/uploads/2024/01/a.jpg\t2024/01/a.jpg\n"
+
+	want := []string{"2024/01/a.jpg", "2024/01/b.png"}
+	if got := ParseAttachmentRows(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAttachmentRows() = %v, want %v", got, want)
+	}
+}
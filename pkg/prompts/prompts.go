@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/firecrown-media/stax/pkg/ui"
+	"golang.org/x/term"
 )
 
 // PromptInput prompts for a text input with a default value
@@ -139,8 +140,16 @@ func PromptMultiSelect(prompt string, options []string) ([]int, []string, error)
 func PromptPassword(prompt string) (string, error) {
 	fmt.Print(prompt + ": ")
 
-	// Note: This is a simple implementation
-	// For production, consider using golang.org/x/term for proper password input
+	// Read without echo when attached to a terminal
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		password, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return string(password), nil
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 	password, err := reader.ReadString('\n')
 	if err != nil {
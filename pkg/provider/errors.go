@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotImplemented is returned for operations stax itself does not implement
+// yet, so no provider is at fault
+var ErrNotImplemented = errors.New("not implemented in stax yet")
+
+// ErrUnsupported is returned when a provider cannot perform an operation at all,
+// as opposed to failing while performing it
+type ErrUnsupported struct {
+	Provider    string // Provider name (e.g., "wpengine")
+	Operation   string // Operation that was attempted (e.g., "database import")
+	Alternative string // Suggested workaround (optional)
+}
+
+// Error implements the error interface
+func (e *ErrUnsupported) Error() string {
+	msg := fmt.Sprintf("%s not supported by %s provider", e.Operation, e.Provider)
+	if e.Alternative != "" {
+		msg += fmt.Sprintf(" (%s)", e.Alternative)
+	}
+	return msg
+}
+
+// NewUnsupportedError creates a new unsupported operation error
+func NewUnsupportedError(provider, operation, alternative string) *ErrUnsupported {
+	return &ErrUnsupported{
+		Provider:    provider,
+		Operation:   operation,
+		Alternative: alternative,
+	}
+}
+
+// IsUnsupported reports whether err (or any error it wraps) is an ErrUnsupported
+func IsUnsupported(err error) bool {
+	var unsupported *ErrUnsupported
+	return errors.As(err, &unsupported)
+}
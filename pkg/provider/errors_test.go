@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestErrUnsupported(t *testing.T) {
+	err := NewUnsupportedError("wpengine", "database import", "use the WPEngine User Portal")
+
+	want := "database import not supported by wpengine provider (use the WPEngine User Portal)"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	noAlt := NewUnsupportedError("local", "file sync", "")
+	if noAlt.Error() != "file sync not supported by local provider" {
+		t.Errorf("unexpected message without alternative: %q", noAlt.Error())
+	}
+}
+
+func TestIsUnsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"direct", NewUnsupportedError("wpengine", "file upload", ""), true},
+		{"wrapped", fmt.Errorf("push failed: %w", NewUnsupportedError("wpengine", "file upload", "")), true},
+		{"plain error", fmt.Errorf("database import not supported"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnsupported(tt.err); got != tt.want {
+				t.Errorf("IsUnsupported() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
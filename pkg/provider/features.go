@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Feature describes a provider feature that can be compared and required
+type Feature struct {
+	Key     string   `json:"key"`                 // Capability key (e.g., "database_import")
+	Name    string   `json:"name"`                // Human-readable name
+	Aliases []string `json:"aliases,omitempty"`   // Short names accepted by --needs
+	Iface   string   `json:"interface,omitempty"` // Optional interface the feature needs, if any
+}
+
+// Features lists every comparable feature in display order
+var Features = []Feature{
+	{Key: "authentication", Name: "Authentication", Aliases: []string{"auth"}},
+	{Key: "site_management", Name: "Site Management", Aliases: []string{"sites"}},
+	{Key: "database_export", Name: "Database Export", Aliases: []string{"db-export", "db-pull"}},
+	{Key: "database_import", Name: "Database Import", Aliases: []string{"db-import", "db-push"}},
+	{Key: "file_sync", Name: "File Sync", Aliases: []string{"files", "file-sync"}},
+	{Key: "deployment", Name: "Deployment", Aliases: []string{"deploy", "deployments"}, Iface: InterfaceDeployer},
+	{Key: "environments", Name: "Environments", Aliases: []string{"envs"}, Iface: InterfaceEnvironmentManager},
+	{Key: "backups", Name: "Backups", Aliases: []string{"backup"}, Iface: InterfaceBackupManager},
+	{Key: "remote_execution", Name: "Remote Execution", Aliases: []string{"remote-exec", "wp-cli"}, Iface: InterfaceRemoteExecutor},
+	{Key: "media_management", Name: "Media Management", Aliases: []string{"media", "cdn"}, Iface: InterfaceMediaManager},
+	{Key: "ssh_access", Name: "SSH Access", Aliases: []string{"ssh"}},
+	{Key: "api_access", Name: "API Access", Aliases: []string{"api"}},
+	{Key: "scaling", Name: "Scaling"},
+	{Key: "monitoring", Name: "Monitoring"},
+	{Key: "logging", Name: "Logging", Aliases: []string{"logs"}},
+}
+
+// LookupFeature finds a feature by key or alias
+// Dashes and underscores are interchangeable
+func LookupFeature(name string) (*Feature, error) {
+	normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+
+	for i := range Features {
+		feature := &Features[i]
+		if feature.Key == normalized {
+			return feature, nil
+		}
+		for _, alias := range feature.Aliases {
+			if strings.ReplaceAll(alias, "-", "_") == normalized {
+				return feature, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unknown feature: %s (valid features: %s)", name, strings.Join(FeatureNames(), ", "))
+}
+
+// FeatureNames returns the short name of every feature
+func FeatureNames() []string {
+	names := make([]string, 0, len(Features))
+	for _, feature := range Features {
+		if len(feature.Aliases) > 0 {
+			names = append(names, feature.Aliases[0])
+		} else {
+			names = append(names, feature.Key)
+		}
+	}
+	return names
+}
+
+// Support status values
+const (
+	SupportYes            = "supported"
+	SupportNo             = "unsupported"
+	SupportNotImplemented = "advertised_not_implemented" // Capability flag set but interface missing
+)
+
+// FeatureSupport describes whether one provider supports one feature
+type FeatureSupport struct {
+	Status     string `json:"status"`
+	Advertised bool   `json:"advertised"`           // Capability flag value
+	Implements *bool  `json:"implements,omitempty"` // Interface check, for interface-backed features
+}
+
+// Supported reports whether the feature is usable
+func (s FeatureSupport) Supported() bool {
+	return s.Status == SupportYes
+}
+
+// CheckFeature determines a provider's live support for a feature
+// Interface-backed features need both the capability flag and the interface
+func CheckFeature(p Provider, feature Feature) FeatureSupport {
+	support := FeatureSupport{
+		Advertised: hasCapability(p.Capabilities(), feature.Key),
+	}
+
+	implements := true
+	if feature.Iface != "" {
+		implements = ImplementsInterface(p, feature.Iface)
+		support.Implements = &implements
+	}
+
+	switch {
+	case support.Advertised && implements:
+		support.Status = SupportYes
+	case support.Advertised:
+		support.Status = SupportNotImplemented
+	default:
+		support.Status = SupportNo
+	}
+
+	return support
+}
+
+// ===== Comparison =====
+
+// ProviderComparison contains comparison results between providers
+type ProviderComparison struct {
+	Providers      []string            `json:"providers"`
+	Features       []FeatureComparison `json:"features"`
+	SharedFeatures []string            `json:"shared_features"`
+}
+
+// FeatureComparison is one row of a provider comparison
+type FeatureComparison struct {
+	Feature Feature                   `json:"feature"`
+	Support map[string]FeatureSupport `json:"support"` // Keyed by provider name
+}
+
+// CompareProviders compares live feature support between providers
+func CompareProviders(names ...string) (*ProviderComparison, error) {
+	if len(names) < 2 {
+		return nil, fmt.Errorf("at least two providers are required for a comparison")
+	}
+
+	providers := make([]Provider, 0, len(names))
+	for _, name := range names {
+		p, err := GetProvider(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get provider %s: %w", name, err)
+		}
+		providers = append(providers, p)
+	}
+
+	comparison := &ProviderComparison{
+		Providers:      names,
+		Features:       make([]FeatureComparison, 0, len(Features)),
+		SharedFeatures: []string{},
+	}
+
+	for _, feature := range Features {
+		row := FeatureComparison{
+			Feature: feature,
+			Support: make(map[string]FeatureSupport, len(providers)),
+		}
+
+		shared := true
+		for i, p := range providers {
+			support := CheckFeature(p, feature)
+			row.Support[names[i]] = support
+			if !support.Supported() {
+				shared = false
+			}
+		}
+
+		if shared {
+			comparison.SharedFeatures = append(comparison.SharedFeatures, feature.Key)
+		}
+		comparison.Features = append(comparison.Features, row)
+	}
+
+	return comparison, nil
+}
+
+// ===== Recommendation =====
+
+// Recommendation scores one provider against a set of required features
+type Recommendation struct {
+	Provider string   `json:"provider"`
+	Score    int      `json:"score"` // Number of required features supported
+	Total    int      `json:"total"` // Number of required features
+	Matches  bool     `json:"matches"`
+	Missing  []string `json:"missing"`
+}
+
+// RecommendProviders ranks all registered providers against required features
+// Providers meeting every need come first, then by score, then by name
+func RecommendProviders(needs []string) ([]Recommendation, error) {
+	if len(needs) == 0 {
+		return nil, fmt.Errorf("at least one required feature is needed")
+	}
+
+	features := make([]*Feature, 0, len(needs))
+	for _, need := range needs {
+		feature, err := LookupFeature(need)
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, feature)
+	}
+
+	providers := GetAllProviders()
+	recommendations := make([]Recommendation, 0, len(providers))
+
+	for name, p := range providers {
+		rec := Recommendation{
+			Provider: name,
+			Total:    len(features),
+			Missing:  []string{},
+		}
+
+		for _, feature := range features {
+			if CheckFeature(p, *feature).Supported() {
+				rec.Score++
+			} else {
+				rec.Missing = append(rec.Missing, feature.Key)
+			}
+		}
+		rec.Matches = rec.Score == rec.Total
+
+		recommendations = append(recommendations, rec)
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.Matches != b.Matches {
+			return a.Matches
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Provider < b.Provider
+	})
+
+	return recommendations, nil
+}
+
+// GetProviderRecommendation recommends the best provider for the requirements
+func GetProviderRecommendation(requirements []string) (string, error) {
+	recommendations, err := RecommendProviders(requirements)
+	if err != nil {
+		return "", err
+	}
+
+	if len(recommendations) == 0 || recommendations[0].Score == 0 {
+		return "", fmt.Errorf("no provider matches the requirements")
+	}
+
+	return recommendations[0].Provider, nil
+}
@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"io"
+	"testing"
+)
+
+// stubProvider is a provider whose capabilities are set by the test
+type stubProvider struct {
+	name string
+	caps ProviderCapabilities
+}
+
+func (p *stubProvider) Name() string                                 { return p.name }
+func (p *stubProvider) Description() string                          { return "stub" }
+func (p *stubProvider) Capabilities() ProviderCapabilities           { return p.caps }
+func (p *stubProvider) Authenticate(map[string]string) error         { return nil }
+func (p *stubProvider) TestConnection() error                        { return nil }
+func (p *stubProvider) ValidateCredentials(map[string]string) error  { return nil }
+func (p *stubProvider) ListSites() ([]Site, error)                   { return nil, nil }
+func (p *stubProvider) GetSite(string) (*Site, error)                { return nil, nil }
+func (p *stubProvider) GetSiteMetadata(*Site) (*SiteMetadata, error) { return nil, nil }
+func (p *stubProvider) GetDatabaseCredentials(*Site) (*DatabaseCredentials, error) {
+	return nil, nil
+}
+func (p *stubProvider) ExportDatabase(*Site, DatabaseExportOptions) (io.ReadCloser, error) {
+	return nil, nil
+}
+func (p *stubProvider) ImportDatabase(*Site, io.Reader, DatabaseImportOptions) error { return nil }
+func (p *stubProvider) SyncFiles(*Site, string, SyncOptions) error                   { return nil }
+func (p *stubProvider) DownloadFile(*Site, string) (io.ReadCloser, error)            { return nil, nil }
+func (p *stubProvider) UploadFile(*Site, string, string) error                       { return nil }
+func (p *stubProvider) GetPHPVersion(*Site) (string, error)                          { return "", nil }
+func (p *stubProvider) GetMySQLVersion(*Site) (string, error)                        { return "", nil }
+func (p *stubProvider) GetWordPressVersion(*Site) (string, error)                    { return "", nil }
+
+// stubBackupProvider also implements BackupManager
+type stubBackupProvider struct {
+	stubProvider
+}
+
+func (p *stubBackupProvider) ListBackups(*Site) ([]Backup, error)               { return nil, nil }
+func (p *stubBackupProvider) CreateBackup(*Site, string) (*Backup, error)       { return nil, nil }
+func (p *stubBackupProvider) RestoreBackup(*Site, string, RestoreOptions) error { return nil }
+func (p *stubBackupProvider) DeleteBackup(*Site, string) error                  { return nil }
+func (p *stubBackupProvider) DownloadBackup(*Site, string) (io.ReadCloser, error) {
+	return nil, nil
+}
+
+// checkerProvider reports its interfaces at runtime like an external plugin
+type checkerProvider struct {
+	stubBackupProvider
+	interfaces map[string]bool
+}
+
+func (p *checkerProvider) Implements(iface string) bool { return p.interfaces[iface] }
+
+// registerStubs registers providers for the duration of a test
+func registerStubs(t *testing.T, providers ...Provider) {
+	t.Helper()
+
+	for _, p := range providers {
+		if err := RegisterProvider(p.Name(), p); err != nil {
+			t.Fatalf("RegisterProvider(%s) failed: %v", p.Name(), err)
+		}
+		name := p.Name()
+		t.Cleanup(func() { UnregisterProvider(name) })
+	}
+}
+
+func TestLookupFeature(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"db-import", "database_import", false},
+		{"database_import", "database_import", false},
+		{"database-import", "database_import", false},
+		{"SSH", "ssh_access", false},
+		{" backups ", "backups", false},
+		{"wp-cli", "remote_execution", false},
+		{"teleport", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feature, err := LookupFeature(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LookupFeature(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if err == nil && feature.Key != tt.want {
+				t.Errorf("LookupFeature(%q) = %s, want %s", tt.name, feature.Key, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFeature(t *testing.T) {
+	backups, _ := LookupFeature("backups")
+	ssh, _ := LookupFeature("ssh")
+
+	flagOnly := &stubProvider{name: "flag-only", caps: ProviderCapabilities{Backups: true, SSHAccess: true}}
+	implemented := &stubBackupProvider{stubProvider{name: "implemented", caps: ProviderCapabilities{Backups: true}}}
+	checker := &checkerProvider{
+		stubBackupProvider: stubBackupProvider{stubProvider{name: "checker", caps: ProviderCapabilities{Backups: true}}},
+		interfaces:         map[string]bool{},
+	}
+
+	tests := []struct {
+		name    string
+		p       Provider
+		feature Feature
+		want    string
+	}{
+		{"flag without interface", flagOnly, *backups, SupportNotImplemented},
+		{"flag and interface", implemented, *backups, SupportYes},
+		{"flag-only feature", flagOnly, *ssh, SupportYes},
+		{"no flag", implemented, *ssh, SupportNo},
+		{"checker overrides type assertion", checker, *backups, SupportNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckFeature(tt.p, tt.feature).Status; got != tt.want {
+				t.Errorf("CheckFeature() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareProviders(t *testing.T) {
+	registerStubs(t,
+		&stubBackupProvider{stubProvider{name: "cmp-a", caps: ProviderCapabilities{Backups: true, DatabaseExport: true}}},
+		&stubProvider{name: "cmp-b", caps: ProviderCapabilities{Backups: true, DatabaseExport: true}},
+	)
+
+	comparison, err := CompareProviders("cmp-a", "cmp-b")
+	if err != nil {
+		t.Fatalf("CompareProviders() failed: %v", err)
+	}
+
+	if len(comparison.Features) != len(Features) {
+		t.Errorf("expected %d feature rows, got %d", len(Features), len(comparison.Features))
+	}
+
+	if len(comparison.SharedFeatures) != 1 || comparison.SharedFeatures[0] != "database_export" {
+		t.Errorf("expected only database_export to be shared, got %v", comparison.SharedFeatures)
+	}
+
+	for _, row := range comparison.Features {
+		if row.Feature.Key == "backups" && row.Support["cmp-b"].Status != SupportNotImplemented {
+			t.Errorf("expected cmp-b backups to be advertised but not implemented, got %s", row.Support["cmp-b"].Status)
+		}
+	}
+
+	if _, err := CompareProviders("cmp-a"); err == nil {
+		t.Error("expected error comparing a single provider")
+	}
+	if _, err := CompareProviders("cmp-a", "missing"); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestRecommendProviders(t *testing.T) {
+	ClearRegistry()
+	t.Cleanup(ClearRegistry)
+
+	registerStubs(t,
+		&stubBackupProvider{stubProvider{name: "full", caps: ProviderCapabilities{Backups: true, DatabaseImport: true, SSHAccess: true}}},
+		&stubProvider{name: "partial", caps: ProviderCapabilities{DatabaseImport: true, SSHAccess: true}},
+		&stubProvider{name: "none"},
+	)
+
+	recommendations, err := RecommendProviders([]string{"db-import", "backups", "ssh"})
+	if err != nil {
+		t.Fatalf("RecommendProviders() failed: %v", err)
+	}
+
+	order := []string{"full", "partial", "none"}
+	for i, name := range order {
+		if recommendations[i].Provider != name {
+			t.Fatalf("expected ranking %v, got %+v", order, recommendations)
+		}
+	}
+
+	if !recommendations[0].Matches || recommendations[0].Score != 3 {
+		t.Errorf("expected full match, got %+v", recommendations[0])
+	}
+	if len(recommendations[1].Missing) != 1 || recommendations[1].Missing[0] != "backups" {
+		t.Errorf("expected partial to miss backups, got %v", recommendations[1].Missing)
+	}
+
+	best, err := GetProviderRecommendation([]string{"backups"})
+	if err != nil || best != "full" {
+		t.Errorf("GetProviderRecommendation() = %q, %v; want full", best, err)
+	}
+
+	if _, err := RecommendProviders([]string{"teleport"}); err == nil {
+		t.Error("expected error for unknown feature")
+	}
+}
@@ -195,6 +195,49 @@ type ProviderCapabilities struct {
 
 // ===== Optional Capability Interfaces =====
 
+// Optional interface names used for introspection
+const (
+	InterfaceDeployer           = "deployer"
+	InterfaceEnvironmentManager = "environment_manager"
+	InterfaceBackupManager      = "backup_manager"
+	InterfaceRemoteExecutor     = "remote_executor"
+	InterfaceMediaManager       = "media_manager"
+)
+
+// InterfaceChecker is implemented by providers that only know at runtime which
+// optional interfaces they support, such as external plugins
+type InterfaceChecker interface {
+	// Implements reports whether the named optional interface is supported
+	Implements(iface string) bool
+}
+
+// ImplementsInterface reports whether p implements the named optional interface
+func ImplementsInterface(p Provider, iface string) bool {
+	if checker, ok := p.(InterfaceChecker); ok {
+		return checker.Implements(iface)
+	}
+
+	switch iface {
+	case InterfaceDeployer:
+		_, ok := p.(Deployer)
+		return ok
+	case InterfaceEnvironmentManager:
+		_, ok := p.(EnvironmentManager)
+		return ok
+	case InterfaceBackupManager:
+		_, ok := p.(BackupManager)
+		return ok
+	case InterfaceRemoteExecutor:
+		_, ok := p.(RemoteExecutor)
+		return ok
+	case InterfaceMediaManager:
+		_, ok := p.(MediaManager)
+		return ok
+	default:
+		return false
+	}
+}
+
 // Deployer interface for providers that support deployments
 type Deployer interface {
 	Provider
@@ -128,7 +128,7 @@ func (m *Manager) SyncFiles(site *Site, destination string, options SyncOptions)
 func (m *Manager) Deploy(site *Site, options DeployOptions) (*Deployment, error) {
 	deployer, ok := m.currentProvider.(Deployer)
 	if !ok {
-		return nil, fmt.Errorf("provider %s does not support deployment", m.providerName)
+		return nil, NewUnsupportedError(m.providerName, "deployment", "")
 	}
 
 	return deployer.Deploy(site, options)
@@ -138,7 +138,7 @@ func (m *Manager) Deploy(site *Site, options DeployOptions) (*Deployment, error)
 func (m *Manager) ListEnvironments(site *Site) ([]Environment, error) {
 	envManager, ok := m.currentProvider.(EnvironmentManager)
 	if !ok {
-		return nil, fmt.Errorf("provider %s does not support environment management", m.providerName)
+		return nil, NewUnsupportedError(m.providerName, "environment management", "")
 	}
 
 	return envManager.ListEnvironments(site)
@@ -148,7 +148,7 @@ func (m *Manager) ListEnvironments(site *Site) ([]Environment, error) {
 func (m *Manager) CreateBackup(site *Site, description string) (*Backup, error) {
 	backupManager, ok := m.currentProvider.(BackupManager)
 	if !ok {
-		return nil, fmt.Errorf("provider %s does not support backups", m.providerName)
+		return nil, NewUnsupportedError(m.providerName, "backups", "")
 	}
 
 	return backupManager.CreateBackup(site, description)
@@ -158,7 +158,7 @@ func (m *Manager) CreateBackup(site *Site, description string) (*Backup, error)
 func (m *Manager) ExecuteWPCLI(site *Site, args []string) (string, error) {
 	executor, ok := m.currentProvider.(RemoteExecutor)
 	if !ok {
-		return "", fmt.Errorf("provider %s does not support remote execution", m.providerName)
+		return "", NewUnsupportedError(m.providerName, "remote execution", "")
 	}
 
 	return executor.ExecuteWPCLI(site, args)
@@ -168,7 +168,7 @@ func (m *Manager) ExecuteWPCLI(site *Site, args []string) (string, error) {
 func (m *Manager) GetMediaURL(site *Site) (string, error) {
 	mediaManager, ok := m.currentProvider.(MediaManager)
 	if !ok {
-		return "", fmt.Errorf("provider %s does not support media management", m.providerName)
+		return "", NewUnsupportedError(m.providerName, "media management", "")
 	}
 
 	return mediaManager.GetMediaURL(site)
@@ -233,111 +233,8 @@ func (m *Manager) manualMigration(options MigrateOptions) error {
 	// 3. Sync files from source to local
 	// 4. Upload files to target
 
-	return fmt.Errorf("manual migration not yet implemented")
-}
-
-// CompareProviders compares capabilities between two providers
-func CompareProviders(provider1Name, provider2Name string) (*ProviderComparison, error) {
-	p1, err := GetProvider(provider1Name)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get provider %s: %w", provider1Name, err)
-	}
-
-	p2, err := GetProvider(provider2Name)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get provider %s: %w", provider2Name, err)
-	}
-
-	return &ProviderComparison{
-		Provider1:      provider1Name,
-		Provider2:      provider2Name,
-		Capabilities1:  p1.Capabilities(),
-		Capabilities2:  p2.Capabilities(),
-		SharedFeatures: findSharedCapabilities(p1.Capabilities(), p2.Capabilities()),
-	}, nil
-}
-
-// ProviderComparison contains comparison results between two providers
-type ProviderComparison struct {
-	Provider1      string               `json:"provider1"`
-	Provider2      string               `json:"provider2"`
-	Capabilities1  ProviderCapabilities `json:"capabilities1"`
-	Capabilities2  ProviderCapabilities `json:"capabilities2"`
-	SharedFeatures []string             `json:"shared_features"`
-}
-
-// findSharedCapabilities finds capabilities that both providers support
-func findSharedCapabilities(caps1, caps2 ProviderCapabilities) []string {
-	shared := []string{}
-
-	if caps1.Authentication && caps2.Authentication {
-		shared = append(shared, "authentication")
-	}
-	if caps1.SiteManagement && caps2.SiteManagement {
-		shared = append(shared, "site_management")
-	}
-	if caps1.DatabaseExport && caps2.DatabaseExport {
-		shared = append(shared, "database_export")
-	}
-	if caps1.DatabaseImport && caps2.DatabaseImport {
-		shared = append(shared, "database_import")
-	}
-	if caps1.FileSync && caps2.FileSync {
-		shared = append(shared, "file_sync")
-	}
-	if caps1.Deployment && caps2.Deployment {
-		shared = append(shared, "deployment")
-	}
-	if caps1.Environments && caps2.Environments {
-		shared = append(shared, "environments")
-	}
-	if caps1.Backups && caps2.Backups {
-		shared = append(shared, "backups")
-	}
-	if caps1.RemoteExecution && caps2.RemoteExecution {
-		shared = append(shared, "remote_execution")
-	}
-	if caps1.MediaManagement && caps2.MediaManagement {
-		shared = append(shared, "media_management")
-	}
-	if caps1.SSHAccess && caps2.SSHAccess {
-		shared = append(shared, "ssh_access")
-	}
-	if caps1.APIAccess && caps2.APIAccess {
-		shared = append(shared, "api_access")
-	}
-
-	return shared
-}
-
-// GetProviderRecommendation recommends a provider based on requirements
-func GetProviderRecommendation(requirements []string) (string, error) {
-	providers := GetAllProviders()
-
-	var bestMatch string
-	var bestScore int
-
-	for name, provider := range providers {
-		caps := provider.Capabilities()
-		score := 0
-
-		for _, req := range requirements {
-			if hasCapability(caps, req) {
-				score++
-			}
-		}
-
-		if score > bestScore {
-			bestScore = score
-			bestMatch = name
-		}
-	}
-
-	if bestMatch == "" {
-		return "", fmt.Errorf("no provider matches the requirements")
-	}
-
-	return bestMatch, nil
+	return fmt.Errorf("migration from %s to %s is %w (export the database and files from the source with 'stax db pull' and 'stax files pull', then import them on the target)",
+		m.currentProvider.Name(), options.TargetProvider.Name(), ErrNotImplemented)
 }
 
 // hasCapability checks if capabilities struct has a specific capability
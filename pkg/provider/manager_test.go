@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMigrateSiteNotImplemented(t *testing.T) {
+	m := NewManager(&stubProvider{name: "wpengine"})
+	err := m.MigrateSite(MigrateOptions{
+		SourceSite:     &Site{Name: "mysite"},
+		TargetProvider: &stubProvider{name: "aws"},
+	})
+
+	if !errors.Is(err, ErrNotImplemented) {
+		t.Fatalf("MigrateSite() = %v, want ErrNotImplemented", err)
+	}
+	if IsUnsupported(err) {
+		t.Error("a missing migration path is blamed on the target provider")
+	}
+	if !strings.Contains(err.Error(), "from wpengine to aws") {
+		t.Errorf("error does not name both providers: %v", err)
+	}
+}
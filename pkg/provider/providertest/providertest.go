@@ -0,0 +1,293 @@
+package providertest
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/firecrown-media/stax/pkg/provider"
+)
+
+// Config describes the fixture data the provider under test exposes
+type Config struct {
+	// SiteID, SiteName and SiteDomain identify one site the provider must be
+	// able to find through ListSites and GetSite. SiteID is required.
+	SiteID     string
+	SiteName   string
+	SiteDomain string
+
+	// RoundTrip enables the database export/import round-trip check.
+	// Only enable it for providers backed by fakes - it writes to the site's database.
+	RoundTrip bool
+
+	// NewUnauthenticated returns a fresh provider instance that has not been
+	// authenticated. When set, operations on it must fail with an error
+	// instead of panicking or returning data.
+	NewUnauthenticated func() provider.Provider
+}
+
+// nonexistentSite is an identifier no provider fixture should resolve
+const nonexistentSite = "providertest-nonexistent-site"
+
+// Run runs the full conformance suite against p
+// Providers call it from their own tests after authenticating against fakes
+func Run(t *testing.T, p provider.Provider, cfg Config) {
+	t.Helper()
+
+	if cfg.SiteID == "" {
+		t.Fatal("providertest: Config.SiteID is required")
+	}
+
+	t.Run("Metadata", func(t *testing.T) { testMetadata(t, p) })
+	t.Run("SiteLookup", func(t *testing.T) { testSiteLookup(t, p, cfg) })
+	t.Run("CoreCapabilities", func(t *testing.T) { testCoreCapabilities(t, p, cfg) })
+	t.Run("OptionalCapabilities", func(t *testing.T) { testOptionalCapabilities(t, p, cfg) })
+	t.Run("RoundTrip", func(t *testing.T) { testRoundTrip(t, p, cfg) })
+	t.Run("Errors", func(t *testing.T) { testErrors(t, p, cfg) })
+}
+
+// lookupSite resolves the fixture site or fails the test
+func lookupSite(t *testing.T, p provider.Provider, cfg Config) *provider.Site {
+	t.Helper()
+
+	site, err := p.GetSite(cfg.SiteID)
+	if err != nil {
+		t.Fatalf("GetSite(%q) failed: %v", cfg.SiteID, err)
+	}
+	if site == nil {
+		t.Fatalf("GetSite(%q) returned nil site without an error", cfg.SiteID)
+	}
+
+	return site
+}
+
+// testMetadata checks the provider describes itself
+func testMetadata(t *testing.T, p provider.Provider) {
+	if p.Name() == "" {
+		t.Error("Name() must not be empty")
+	}
+	if strings.ContainsAny(p.Name(), " \t\n") {
+		t.Errorf("Name() %q must not contain whitespace", p.Name())
+	}
+	if p.Description() == "" {
+		t.Error("Description() must not be empty")
+	}
+}
+
+// testSiteLookup checks sites can be found by ID, name and domain
+func testSiteLookup(t *testing.T, p provider.Provider, cfg Config) {
+	sites, err := p.ListSites()
+	if err != nil {
+		t.Fatalf("ListSites() failed: %v", err)
+	}
+
+	found := false
+	for _, site := range sites {
+		if site.ID == cfg.SiteID {
+			found = true
+		}
+		if site.Provider != "" && site.Provider != p.Name() {
+			t.Errorf("site %s reports provider %q, want %q", site.ID, site.Provider, p.Name())
+		}
+	}
+	if !found {
+		t.Errorf("ListSites() did not include site %q", cfg.SiteID)
+	}
+
+	lookups := map[string]string{
+		"id":     cfg.SiteID,
+		"name":   cfg.SiteName,
+		"domain": cfg.SiteDomain,
+	}
+	for kind, identifier := range lookups {
+		if identifier == "" {
+			continue
+		}
+
+		site, err := p.GetSite(identifier)
+		if err != nil {
+			t.Errorf("GetSite() by %s %q failed: %v", kind, identifier, err)
+			continue
+		}
+		if site == nil || site.ID != cfg.SiteID {
+			t.Errorf("GetSite() by %s %q returned %+v, want site %q", kind, identifier, site, cfg.SiteID)
+		}
+	}
+
+	site, err := p.GetSite(nonexistentSite)
+	if err == nil {
+		t.Errorf("GetSite(%q) returned %+v, want an error", nonexistentSite, site)
+	} else if site != nil {
+		t.Errorf("GetSite(%q) returned a site alongside error %v", nonexistentSite, err)
+	}
+}
+
+// testCoreCapabilities checks core capability flags agree with core method behavior
+func testCoreCapabilities(t *testing.T, p provider.Provider, cfg Config) {
+	site := lookupSite(t, p, cfg)
+	caps := p.Capabilities()
+
+	t.Run("DatabaseExport", func(t *testing.T) {
+		reader, err := p.ExportDatabase(site, provider.DatabaseExportOptions{})
+		if reader != nil {
+			defer reader.Close()
+		}
+		checkSupport(t, "database_export", "ExportDatabase", caps.DatabaseExport, err)
+		if err != nil && reader != nil {
+			t.Error("ExportDatabase() returned a reader alongside an error")
+		}
+	})
+
+	t.Run("DatabaseImport", func(t *testing.T) {
+		// Importing is destructive, so it is only exercised by the round-trip check
+		if caps.DatabaseImport {
+			t.Skip("import is exercised by the round-trip check")
+		}
+		err := p.ImportDatabase(site, strings.NewReader(""), provider.DatabaseImportOptions{})
+		checkSupport(t, "database_import", "ImportDatabase", false, err)
+	})
+
+	t.Run("FileSync", func(t *testing.T) {
+		err := p.SyncFiles(site, t.TempDir(), provider.SyncOptions{DryRun: true})
+		checkSupport(t, "file_sync", "SyncFiles", caps.FileSync, err)
+	})
+}
+
+// testOptionalCapabilities checks optional capability flags agree with the
+// optional interfaces the provider implements
+func testOptionalCapabilities(t *testing.T, p provider.Provider, cfg Config) {
+	site := lookupSite(t, p, cfg)
+	caps := p.Capabilities()
+
+	deployer, isDeployer := p.(provider.Deployer)
+	backups, isBackupManager := p.(provider.BackupManager)
+	executor, isExecutor := p.(provider.RemoteExecutor)
+	media, isMediaManager := p.(provider.MediaManager)
+
+	checks := []struct {
+		capability string
+		iface      string
+		flag       bool
+		implements bool
+		probe      func() error
+	}{
+		{"deployment", "Deployer", caps.Deployment, isDeployer, func() error {
+			_, err := deployer.ListDeployments(site)
+			return err
+		}},
+		{"backups", "BackupManager", caps.Backups, isBackupManager, func() error {
+			_, err := backups.ListBackups(site)
+			return err
+		}},
+		{"remote_execution", "RemoteExecutor", caps.RemoteExecution, isExecutor, func() error {
+			_, err := executor.ExecuteWPCLI(site, []string{"core", "version"})
+			return err
+		}},
+		{"media_management", "MediaManager", caps.MediaManagement, isMediaManager, func() error {
+			_, err := media.GetMediaURL(site)
+			return err
+		}},
+	}
+
+	for _, check := range checks {
+		t.Run(check.iface, func(t *testing.T) {
+			if !check.implements {
+				if check.flag {
+					t.Errorf("capability %s is advertised but provider does not implement provider.%s", check.capability, check.iface)
+				}
+				return
+			}
+
+			checkSupport(t, check.capability, check.iface, check.flag, check.probe())
+		})
+	}
+}
+
+// checkSupport verifies an operation's error agrees with its capability flag
+func checkSupport(t *testing.T, capability, operation string, supported bool, err error) {
+	t.Helper()
+
+	if supported && provider.IsUnsupported(err) {
+		t.Errorf("capability %s is advertised but %s reports it is unsupported: %v", capability, operation, err)
+	}
+	if !supported && err == nil {
+		t.Errorf("capability %s is not advertised but %s succeeded", capability, operation)
+	}
+	if !supported && err != nil && !provider.IsUnsupported(err) {
+		t.Errorf("capability %s is not advertised but %s failed with %T instead of *provider.ErrUnsupported: %v", capability, operation, err, err)
+	}
+}
+
+// testRoundTrip exports the database, imports it back and checks the data survives
+func testRoundTrip(t *testing.T, p provider.Provider, cfg Config) {
+	caps := p.Capabilities()
+	if !caps.DatabaseExport || !caps.DatabaseImport {
+		t.Skip("provider does not support both database export and import")
+	}
+	if !cfg.RoundTrip {
+		t.Skip("round-trip check not enabled for this provider")
+	}
+
+	site := lookupSite(t, p, cfg)
+
+	first := exportAll(t, p, site)
+	if len(first) == 0 {
+		t.Fatal("ExportDatabase() returned an empty dump")
+	}
+
+	if err := p.ImportDatabase(site, bytes.NewReader(first), provider.DatabaseImportOptions{}); err != nil {
+		t.Fatalf("ImportDatabase() failed: %v", err)
+	}
+
+	second := exportAll(t, p, site)
+	if !bytes.Equal(first, second) {
+		t.Errorf("database changed across an export/import round trip (%d bytes before, %d after)", len(first), len(second))
+	}
+}
+
+// exportAll exports a site's database and reads it fully
+func exportAll(t *testing.T, p provider.Provider, site *provider.Site) []byte {
+	t.Helper()
+
+	reader, err := p.ExportDatabase(site, provider.DatabaseExportOptions{})
+	if err != nil {
+		t.Fatalf("ExportDatabase() failed: %v", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read database export: %v", err)
+	}
+
+	return data
+}
+
+// testErrors checks unauthenticated providers fail cleanly
+func testErrors(t *testing.T, p provider.Provider, cfg Config) {
+	if cfg.NewUnauthenticated == nil {
+		t.Skip("no unauthenticated constructor configured")
+	}
+	if !p.Capabilities().Authentication {
+		t.Skip("provider does not require authentication")
+	}
+
+	fresh := cfg.NewUnauthenticated()
+
+	if sites, err := fresh.ListSites(); err == nil {
+		t.Errorf("ListSites() on an unauthenticated provider returned %d sites, want an error", len(sites))
+	}
+
+	if site, err := fresh.GetSite(cfg.SiteID); err == nil {
+		t.Errorf("GetSite() on an unauthenticated provider returned %+v, want an error", site)
+	}
+
+	if err := fresh.TestConnection(); err == nil {
+		t.Error("TestConnection() on an unauthenticated provider succeeded, want an error")
+	}
+
+	if err := fresh.ValidateCredentials(map[string]string{}); err == nil {
+		t.Error("ValidateCredentials() accepted empty credentials")
+	}
+}
@@ -0,0 +1,155 @@
+package providertest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/firecrown-media/stax/pkg/provider"
+)
+
+// memProvider is an in-memory provider used to exercise the suite itself
+type memProvider struct {
+	authenticated bool
+	sites         []provider.Site
+	databases     map[string][]byte
+}
+
+func newMemProvider() *memProvider {
+	return &memProvider{}
+}
+
+func (p *memProvider) Name() string        { return "memory" }
+func (p *memProvider) Description() string { return "In-memory test provider" }
+
+func (p *memProvider) Capabilities() provider.ProviderCapabilities {
+	return provider.ProviderCapabilities{
+		Authentication: true,
+		SiteManagement: true,
+		DatabaseExport: true,
+		DatabaseImport: true,
+		Backups:        true,
+	}
+}
+
+func (p *memProvider) Authenticate(credentials map[string]string) error {
+	if err := p.ValidateCredentials(credentials); err != nil {
+		return err
+	}
+	p.authenticated = true
+	p.sites = []provider.Site{
+		{ID: "1", Name: "alpha", PrimaryDomain: "alpha.example.com", Provider: "memory"},
+		{ID: "2", Name: "beta", PrimaryDomain: "beta.example.com", Provider: "memory"},
+	}
+	p.databases = map[string][]byte{"1": []byte("CREATE TABLE wp_options;"), "2": []byte("CREATE TABLE wp_posts;")}
+	return nil
+}
+
+func (p *memProvider) ValidateCredentials(credentials map[string]string) error {
+	if credentials["token"] == "" {
+		return fmt.Errorf("missing required credential: token")
+	}
+	return nil
+}
+
+func (p *memProvider) TestConnection() error {
+	if !p.authenticated {
+		return fmt.Errorf("not authenticated")
+	}
+	return nil
+}
+
+func (p *memProvider) ListSites() ([]provider.Site, error) {
+	if !p.authenticated {
+		return nil, fmt.Errorf("not authenticated")
+	}
+	return p.sites, nil
+}
+
+func (p *memProvider) GetSite(identifier string) (*provider.Site, error) {
+	sites, err := p.ListSites()
+	if err != nil {
+		return nil, err
+	}
+	for i := range sites {
+		if sites[i].ID == identifier || sites[i].Name == identifier || sites[i].PrimaryDomain == identifier {
+			return &sites[i], nil
+		}
+	}
+	return nil, fmt.Errorf("site not found: %s", identifier)
+}
+
+func (p *memProvider) GetSiteMetadata(site *provider.Site) (*provider.SiteMetadata, error) {
+	return &provider.SiteMetadata{Site: site}, nil
+}
+
+func (p *memProvider) ExportDatabase(site *provider.Site, options provider.DatabaseExportOptions) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(p.databases[site.ID])), nil
+}
+
+func (p *memProvider) ImportDatabase(site *provider.Site, data io.Reader, options provider.DatabaseImportOptions) error {
+	dump, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	p.databases[site.ID] = dump
+	return nil
+}
+
+func (p *memProvider) GetDatabaseCredentials(site *provider.Site) (*provider.DatabaseCredentials, error) {
+	return nil, provider.NewUnsupportedError("memory", "database credentials", "")
+}
+
+func (p *memProvider) SyncFiles(site *provider.Site, destination string, options provider.SyncOptions) error {
+	return provider.NewUnsupportedError("memory", "file sync", "")
+}
+
+func (p *memProvider) DownloadFile(site *provider.Site, remotePath string) (io.ReadCloser, error) {
+	return nil, provider.NewUnsupportedError("memory", "file download", "")
+}
+
+func (p *memProvider) UploadFile(site *provider.Site, localPath, remotePath string) error {
+	return provider.NewUnsupportedError("memory", "file upload", "")
+}
+
+func (p *memProvider) GetPHPVersion(site *provider.Site) (string, error)       { return "8.2", nil }
+func (p *memProvider) GetMySQLVersion(site *provider.Site) (string, error)     { return "8.0", nil }
+func (p *memProvider) GetWordPressVersion(site *provider.Site) (string, error) { return "6.5", nil }
+
+func (p *memProvider) ListBackups(site *provider.Site) ([]provider.Backup, error) {
+	return []provider.Backup{{ID: "b1", Status: "completed"}}, nil
+}
+
+func (p *memProvider) CreateBackup(site *provider.Site, description string) (*provider.Backup, error) {
+	return &provider.Backup{ID: "b2", Description: description, Status: "pending"}, nil
+}
+
+func (p *memProvider) RestoreBackup(site *provider.Site, backupID string, options provider.RestoreOptions) error {
+	return provider.NewUnsupportedError("memory", "backup restore", "")
+}
+
+func (p *memProvider) DeleteBackup(site *provider.Site, backupID string) error {
+	return provider.NewUnsupportedError("memory", "backup deletion", "")
+}
+
+func (p *memProvider) DownloadBackup(site *provider.Site, backupID string) (io.ReadCloser, error) {
+	return nil, provider.NewUnsupportedError("memory", "backup download", "")
+}
+
+func TestRunMemoryProvider(t *testing.T) {
+	p := newMemProvider()
+	if err := p.Authenticate(map[string]string{"token": "secret"}); err != nil {
+		t.Fatalf("Authenticate() failed: %v", err)
+	}
+
+	Run(t, p, Config{
+		SiteID:     "2",
+		SiteName:   "beta",
+		SiteDomain: "beta.example.com",
+		RoundTrip:  true,
+		NewUnauthenticated: func() provider.Provider {
+			return newMemProvider()
+		},
+	})
+}
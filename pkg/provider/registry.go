@@ -152,6 +152,13 @@ type ProviderInfo struct {
 	Description  string               `json:"description"`
 	Capabilities ProviderCapabilities `json:"capabilities"`
 	IsDefault    bool                 `json:"is_default"`
+	Plugin       string               `json:"plugin,omitempty"` // Plugin executable path for external providers
+}
+
+// Plugin is implemented by providers backed by an external plugin executable
+type Plugin interface {
+	// PluginPath returns the path of the plugin executable
+	PluginPath() string
 }
 
 // GetProviderInfo returns information about a specific provider
@@ -161,12 +168,18 @@ func GetProviderInfo(name string) (*ProviderInfo, error) {
 		return nil, err
 	}
 
-	return &ProviderInfo{
+	info := &ProviderInfo{
 		Name:         provider.Name(),
 		Description:  provider.Description(),
 		Capabilities: provider.Capabilities(),
 		IsDefault:    name == GetDefaultProvider(),
-	}, nil
+	}
+
+	if plugin, ok := provider.(Plugin); ok {
+		info.Plugin = plugin.PluginPath()
+	}
+
+	return info, nil
 }
 
 // GetAllProviderInfo returns information about all registered providers
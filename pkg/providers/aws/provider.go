@@ -1,7 +1,6 @@
 package aws
 
 import (
-	"fmt"
 	"io"
 
 	"github.com/firecrown-media/stax/pkg/provider"
@@ -22,6 +21,11 @@ func init() {
 	provider.RegisterProvider("aws", &AWSProvider{})
 }
 
+// notImplemented reports an operation the skeleton provider cannot perform yet
+func notImplemented(operation, alternative string) error {
+	return provider.NewUnsupportedError("aws", operation+" (the AWS provider is not implemented yet)", alternative)
+}
+
 // Name returns the provider's unique identifier
 func (p *AWSProvider) Name() string {
 	return "aws"
@@ -67,7 +71,7 @@ func (p *AWSProvider) ValidateCredentials(credentials map[string]string) error {
 	// - ssh_user (default: ubuntu for Ubuntu, ec2-user for Amazon Linux)
 	// - rds_endpoint (optional, if using RDS)
 
-	return fmt.Errorf("AWS provider not yet implemented - TODO")
+	return notImplemented("credential validation", "check the AWS credentials with: aws sts get-caller-identity")
 }
 
 // Authenticate authenticates with AWS
@@ -78,7 +82,7 @@ func (p *AWSProvider) Authenticate(credentials map[string]string) error {
 	// - Verify SSH key exists
 	// - Test RDS connection if endpoint provided
 
-	return fmt.Errorf("AWS provider not yet implemented - TODO")
+	return notImplemented("authentication", "use the AWS CLI and ssh directly")
 }
 
 // TestConnection tests the connection to AWS
@@ -88,7 +92,7 @@ func (p *AWSProvider) TestConnection() error {
 	// - Test SSH connection
 	// - Test RDS connection
 
-	return fmt.Errorf("AWS provider not yet implemented - TODO")
+	return notImplemented("connection testing", "test the instance with: ssh <user>@<instance>")
 }
 
 // ===== Site Management =====
@@ -100,7 +104,7 @@ func (p *AWSProvider) ListSites() ([]provider.Site, error) {
 	// - List Lightsail WordPress instances
 	// - Query each instance for WordPress installations
 
-	return nil, fmt.Errorf("AWS provider not yet implemented - TODO")
+	return nil, notImplemented("site listing", "list instances with: aws ec2 describe-instances or aws lightsail get-instances")
 }
 
 // GetSite retrieves information about a specific site
@@ -108,7 +112,7 @@ func (p *AWSProvider) GetSite(identifier string) (*provider.Site, error) {
 	// TODO: Implement site retrieval
 	// identifier could be instance ID, instance name, or domain
 
-	return nil, fmt.Errorf("AWS provider not yet implemented - TODO")
+	return nil, notImplemented("site lookup", "look the instance up with: aws ec2 describe-instances")
 }
 
 // GetSiteMetadata retrieves detailed metadata
@@ -120,7 +124,7 @@ func (p *AWSProvider) GetSiteMetadata(site *provider.Site) (*provider.SiteMetada
 	// - Detect WordPress version (wp core version)
 	// - Get disk usage (df -h)
 
-	return nil, fmt.Errorf("AWS provider not yet implemented - TODO")
+	return nil, notImplemented("site metadata", "run php -v and wp core version over ssh")
 }
 
 // ===== Database Operations =====
@@ -132,7 +136,7 @@ func (p *AWSProvider) ExportDatabase(site *provider.Site, options provider.Datab
 	// Option 2: RDS snapshot export
 	// Option 3: WP-CLI db export via SSH
 
-	return nil, fmt.Errorf("AWS provider not yet implemented - TODO")
+	return nil, notImplemented("database export", "export over ssh: wp db export - | gzip > db.sql.gz, then stax db import")
 }
 
 // ImportDatabase imports a database
@@ -141,7 +145,7 @@ func (p *AWSProvider) ImportDatabase(site *provider.Site, data io.Reader, option
 	// - Stream SQL to instance via SSH
 	// - Use mysql client or WP-CLI
 
-	return fmt.Errorf("AWS provider not yet implemented - TODO")
+	return notImplemented("database import", "import over ssh with wp db import")
 }
 
 // GetDatabaseCredentials retrieves database credentials
@@ -150,7 +154,7 @@ func (p *AWSProvider) GetDatabaseCredentials(site *provider.Site) (*provider.Dat
 	// - Parse wp-config.php via SSH
 	// - Or use AWS Secrets Manager
 
-	return nil, fmt.Errorf("AWS provider not yet implemented - TODO")
+	return nil, notImplemented("database credentials", "read DB_USER and DB_PASSWORD from wp-config.php over ssh")
 }
 
 // ===== File Operations =====
@@ -161,7 +165,7 @@ func (p *AWSProvider) SyncFiles(site *provider.Site, destination string, options
 	// - Use rsync over SSH
 	// - Or use AWS S3 sync if media on S3
 
-	return fmt.Errorf("AWS provider not yet implemented - TODO")
+	return notImplemented("file sync", "use rsync over ssh, or aws s3 sync for media on S3")
 }
 
 // DownloadFile downloads a single file
@@ -169,7 +173,7 @@ func (p *AWSProvider) DownloadFile(site *provider.Site, remotePath string) (io.R
 	// TODO: Implement file download
 	// - SCP or SFTP
 
-	return nil, fmt.Errorf("AWS provider not yet implemented - TODO")
+	return nil, notImplemented("file download", "use scp")
 }
 
 // UploadFile uploads a single file
@@ -177,7 +181,7 @@ func (p *AWSProvider) UploadFile(site *provider.Site, localPath, remotePath stri
 	// TODO: Implement file upload
 	// - SCP or SFTP
 
-	return fmt.Errorf("AWS provider not yet implemented - TODO")
+	return notImplemented("file upload", "use scp")
 }
 
 // ===== Environment Information =====
@@ -185,20 +189,20 @@ func (p *AWSProvider) UploadFile(site *provider.Site, localPath, remotePath stri
 // GetPHPVersion returns the PHP version
 func (p *AWSProvider) GetPHPVersion(site *provider.Site) (string, error) {
 	// TODO: SSH and run: php -v
-	return "", fmt.Errorf("AWS provider not yet implemented - TODO")
+	return "", notImplemented("PHP version detection", "run php -v over ssh")
 }
 
 // GetMySQLVersion returns the MySQL version
 func (p *AWSProvider) GetMySQLVersion(site *provider.Site) (string, error) {
 	// TODO: SSH and run: mysql --version
 	// Or query RDS API
-	return "", fmt.Errorf("AWS provider not yet implemented - TODO")
+	return "", notImplemented("MySQL version detection", "run mysql --version over ssh, or check the RDS console")
 }
 
 // GetWordPressVersion returns the WordPress version
 func (p *AWSProvider) GetWordPressVersion(site *provider.Site) (string, error) {
 	// TODO: SSH and run: wp core version
-	return "", fmt.Errorf("AWS provider not yet implemented - TODO")
+	return "", notImplemented("WordPress version detection", "run wp core version over ssh")
 }
 
 /*
@@ -0,0 +1,109 @@
+package external
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// chunkSize is the maximum number of bytes sent per stream notification
+const chunkSize = 32 * 1024
+
+// conn reads and writes newline-delimited JSON-RPC messages
+type conn struct {
+	dec *json.Decoder
+
+	writeMu sync.Mutex
+	w       io.Writer
+}
+
+// newConn creates a connection over a reader/writer pair
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		dec: json.NewDecoder(bufio.NewReader(r)),
+		w:   w,
+	}
+}
+
+// receive reads the next message
+func (c *conn) receive() (*message, error) {
+	var m message
+	if err := c.dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// send writes a message as a single line
+func (c *conn) send(m *message) error {
+	m.JSONRPC = "2.0"
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	data = append(data, '\n')
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err = c.w.Write(data)
+	return err
+}
+
+// notify sends a notification (a request without an ID)
+func (c *conn) notify(method string, params interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s params: %w", method, err)
+	}
+
+	return c.send(&message{Method: method, Params: data})
+}
+
+// sendStream copies r to the peer as stream notifications, then ends the stream
+// stop is polled between chunks so the copy can be abandoned early
+func (c *conn) sendStream(id uint64, r io.Reader, stop func() bool) error {
+	buf := make([]byte, chunkSize)
+
+	for {
+		if stop != nil && stop() {
+			return c.notify(notifyStreamEnd, streamChunk{Stream: id})
+		}
+
+		n, err := r.Read(buf)
+		if n > 0 {
+			if sendErr := c.notify(notifyStreamData, streamChunk{Stream: id, Data: buf[:n]}); sendErr != nil {
+				return sendErr
+			}
+		}
+
+		if err == io.EOF {
+			return c.notify(notifyStreamEnd, streamChunk{Stream: id})
+		}
+		if err != nil {
+			return c.notify(notifyStreamEnd, streamChunk{Stream: id, Error: err.Error()})
+		}
+	}
+}
+
+// streamWriter forwards writes to the peer as stream notifications
+type streamWriter struct {
+	c  *conn
+	id uint64
+}
+
+// Write sends p as a single stream chunk
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if err := s.c.notify(notifyStreamData, streamChunk{Stream: s.id, Data: p}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
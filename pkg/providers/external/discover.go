@@ -0,0 +1,129 @@
+package external
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/provider"
+)
+
+// ExecutablePrefix is the file name prefix of provider plugin executables
+const ExecutablePrefix = "stax-provider-"
+
+// Plugin describes a discovered provider plugin executable
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// SearchPaths returns the directories searched for plugins, in priority order:
+// ~/.stax/providers first, then each PATH entry
+func SearchPaths() []string {
+	var dirs []string
+
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".stax", "providers"))
+	}
+
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// Discover finds plugin executables in dirs
+// When a name appears in several directories the earliest directory wins
+func Discover(dirs []string) []Plugin {
+	found := make(map[string]string)
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok {
+				continue
+			}
+			if _, exists := found[name]; exists {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			found[name] = path
+		}
+	}
+
+	plugins := make([]Plugin, 0, len(found))
+	for name, path := range found {
+		plugins = append(plugins, Plugin{Name: name, Path: path})
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	return plugins
+}
+
+// pluginName extracts the provider name from a plugin file name
+func pluginName(fileName string) (string, bool) {
+	if !strings.HasPrefix(fileName, ExecutablePrefix) {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(fileName, ExecutablePrefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, ".exe")
+	}
+
+	if name == "" || strings.ContainsAny(name, " \t.") {
+		return "", false
+	}
+
+	return name, true
+}
+
+// isExecutable checks that path is a regular file the user can execute
+// Symlinks are followed
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	return info.Mode().Perm()&0111 != 0
+}
+
+// RegisterDiscovered registers every plugin found in the search paths
+// Built-in providers keep their names; a plugin with the same name is skipped
+func RegisterDiscovered() []error {
+	var errs []error
+
+	for _, plugin := range Discover(SearchPaths()) {
+		if provider.ProviderExists(plugin.Name) {
+			continue
+		}
+
+		if err := provider.RegisterProvider(plugin.Name, NewExternalProvider(plugin.Name, plugin.Path)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to register external provider %s: %w", plugin.Name, err))
+		}
+	}
+
+	return errs
+}
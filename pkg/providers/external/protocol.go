@@ -0,0 +1,182 @@
+package external
+
+import (
+	"encoding/json"
+
+	"github.com/firecrown-media/stax/pkg/provider"
+)
+
+// ProtocolVersion is the plugin protocol version spoken by this build of stax
+// Plugins report the version they implement in their Describe response
+const ProtocolVersion = 1
+
+// Method names mirror the provider.Provider and optional interface method names
+const (
+	methodDescribe               = "Describe"
+	methodAuthenticate           = "Authenticate"
+	methodTestConnection         = "TestConnection"
+	methodValidateCredentials    = "ValidateCredentials"
+	methodListSites              = "ListSites"
+	methodGetSite                = "GetSite"
+	methodGetSiteMetadata        = "GetSiteMetadata"
+	methodExportDatabase         = "ExportDatabase"
+	methodImportDatabase         = "ImportDatabase"
+	methodGetDatabaseCredentials = "GetDatabaseCredentials"
+	methodSyncFiles              = "SyncFiles"
+	methodDownloadFile           = "DownloadFile"
+	methodUploadFile             = "UploadFile"
+	methodGetPHPVersion          = "GetPHPVersion"
+	methodGetMySQLVersion        = "GetMySQLVersion"
+	methodGetWordPressVersion    = "GetWordPressVersion"
+
+	methodDeploy              = "Deploy"
+	methodGetDeploymentStatus = "GetDeploymentStatus"
+	methodListDeployments     = "ListDeployments"
+
+	methodListEnvironments  = "ListEnvironments"
+	methodGetEnvironment    = "GetEnvironment"
+	methodSwitchEnvironment = "SwitchEnvironment"
+	methodCreateEnvironment = "CreateEnvironment"
+	methodDeleteEnvironment = "DeleteEnvironment"
+
+	methodListBackups    = "ListBackups"
+	methodCreateBackup   = "CreateBackup"
+	methodRestoreBackup  = "RestoreBackup"
+	methodDeleteBackup   = "DeleteBackup"
+	methodDownloadBackup = "DownloadBackup"
+
+	methodExecuteCommand = "ExecuteCommand"
+	methodExecuteWPCLI   = "ExecuteWPCLI"
+	methodStreamCommand  = "StreamCommand"
+
+	methodGetMediaURL         = "GetMediaURL"
+	methodSupportsRemoteMedia = "SupportsRemoteMedia"
+	methodConfigureMedia      = "ConfigureMedia"
+	methodPurgeMediaCache     = "PurgeMediaCache"
+)
+
+// Stream notifications carry streamed request and response bodies
+const (
+	notifyStreamData   = "$/stream.data"
+	notifyStreamEnd    = "$/stream.end"
+	notifyStreamCancel = "$/stream.cancel"
+)
+
+// methodInterfaces maps optional methods to the interface that provides them
+var methodInterfaces = map[string]string{
+	methodDeploy:              provider.InterfaceDeployer,
+	methodGetDeploymentStatus: provider.InterfaceDeployer,
+	methodListDeployments:     provider.InterfaceDeployer,
+
+	methodListEnvironments:  provider.InterfaceEnvironmentManager,
+	methodGetEnvironment:    provider.InterfaceEnvironmentManager,
+	methodSwitchEnvironment: provider.InterfaceEnvironmentManager,
+	methodCreateEnvironment: provider.InterfaceEnvironmentManager,
+	methodDeleteEnvironment: provider.InterfaceEnvironmentManager,
+
+	methodListBackups:    provider.InterfaceBackupManager,
+	methodCreateBackup:   provider.InterfaceBackupManager,
+	methodRestoreBackup:  provider.InterfaceBackupManager,
+	methodDeleteBackup:   provider.InterfaceBackupManager,
+	methodDownloadBackup: provider.InterfaceBackupManager,
+
+	methodExecuteCommand: provider.InterfaceRemoteExecutor,
+	methodExecuteWPCLI:   provider.InterfaceRemoteExecutor,
+	methodStreamCommand:  provider.InterfaceRemoteExecutor,
+
+	methodGetMediaURL:         provider.InterfaceMediaManager,
+	methodSupportsRemoteMedia: provider.InterfaceMediaManager,
+	methodConfigureMedia:      provider.InterfaceMediaManager,
+	methodPurgeMediaCache:     provider.InterfaceMediaManager,
+}
+
+// JSON-RPC error codes
+const (
+	codeInvalidParams  = -32602
+	codeMethodNotFound = -32601
+	codeProviderError  = -32000
+	codeUnsupported    = -32001
+)
+
+// DescribeResult is the plugin handshake response
+type DescribeResult struct {
+	ProtocolVersion int                           `json:"protocol_version"`
+	Name            string                        `json:"name"`
+	Description     string                        `json:"description"`
+	Capabilities    provider.ProviderCapabilities `json:"capabilities"`
+	Interfaces      []string                      `json:"interfaces"`
+}
+
+// message is a JSON-RPC 2.0 request, response or notification
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *uint64         `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// unsupportedData is the error data sent with codeUnsupported
+type unsupportedData struct {
+	Provider    string `json:"provider"`
+	Operation   string `json:"operation"`
+	Alternative string `json:"alternative,omitempty"`
+}
+
+// streamChunk is the params of the stream notifications
+type streamChunk struct {
+	Stream uint64 `json:"stream"`
+	Data   []byte `json:"data,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// callParams holds the named parameters of every method
+// Each method only reads the fields that match its Go signature
+type callParams struct {
+	Credentials  map[string]string `json:"credentials,omitempty"`
+	Identifier   string            `json:"identifier,omitempty"`
+	Site         *provider.Site    `json:"site,omitempty"`
+	Destination  string            `json:"destination,omitempty"`
+	LocalPath    string            `json:"local_path,omitempty"`
+	RemotePath   string            `json:"remote_path,omitempty"`
+	Command      string            `json:"command,omitempty"`
+	Args         []string          `json:"args,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	DeploymentID string            `json:"deployment_id,omitempty"`
+	BackupID     string            `json:"backup_id,omitempty"`
+	Environment  string            `json:"environment,omitempty"`
+	Paths        []string          `json:"paths,omitempty"`
+
+	ExportOptions      *provider.DatabaseExportOptions `json:"export_options,omitempty"`
+	ImportOptions      *provider.DatabaseImportOptions `json:"import_options,omitempty"`
+	SyncOptions        *provider.SyncOptions           `json:"sync_options,omitempty"`
+	DeployOptions      *provider.DeployOptions         `json:"deploy_options,omitempty"`
+	EnvironmentOptions *provider.EnvironmentOptions    `json:"environment_options,omitempty"`
+	RestoreOptions     *provider.RestoreOptions        `json:"restore_options,omitempty"`
+	MediaOptions       *provider.MediaOptions          `json:"media_options,omitempty"`
+
+	// Stream identifies the streamed body of ExportDatabase, ImportDatabase,
+	// DownloadFile and DownloadBackup
+	Stream uint64 `json:"stream,omitempty"`
+
+	// StdoutStream and StderrStream identify the StreamCommand output streams
+	StdoutStream uint64 `json:"stdout_stream,omitempty"`
+	StderrStream uint64 `json:"stderr_stream,omitempty"`
+}
+
+// valueOf dereferences optional params, returning the zero value for nil
+func valueOf[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
@@ -0,0 +1,690 @@
+package external
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/firecrown-media/stax/pkg/provider"
+)
+
+// shutdownTimeout is how long Close waits for a plugin to exit before killing it
+const shutdownTimeout = 5 * time.Second
+
+// ExternalProvider is a provider backed by a stax-provider-<name> plugin process
+// The process is started on first use and speaks JSON-RPC over stdin/stdout
+type ExternalProvider struct {
+	name string
+	path string
+
+	readyOnce sync.Once
+	readyErr  error
+	info      *DescribeResult
+
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	conn  *conn
+
+	mu         sync.Mutex
+	nextID     uint64
+	nextStream uint64
+	pending    map[uint64]chan *message
+	streams    map[uint64]io.Writer
+	closedErr  error
+}
+
+// Ensure ExternalProvider implements the provider interfaces
+var (
+	_ provider.Provider           = (*ExternalProvider)(nil)
+	_ provider.Deployer           = (*ExternalProvider)(nil)
+	_ provider.EnvironmentManager = (*ExternalProvider)(nil)
+	_ provider.BackupManager      = (*ExternalProvider)(nil)
+	_ provider.RemoteExecutor     = (*ExternalProvider)(nil)
+	_ provider.MediaManager       = (*ExternalProvider)(nil)
+	_ provider.Plugin             = (*ExternalProvider)(nil)
+	_ provider.InterfaceChecker   = (*ExternalProvider)(nil)
+)
+
+// NewExternalProvider creates a provider for the plugin executable at path
+func NewExternalProvider(name, path string) *ExternalProvider {
+	return &ExternalProvider{
+		name:    name,
+		path:    path,
+		pending: make(map[uint64]chan *message),
+		streams: make(map[uint64]io.Writer),
+	}
+}
+
+// newConnectedProvider creates a provider talking to an already-running plugin
+func newConnectedProvider(name string, r io.Reader, w io.WriteCloser) *ExternalProvider {
+	p := NewExternalProvider(name, "")
+	p.stdin = w
+	p.conn = newConn(r, w)
+	go p.readLoop()
+
+	return p
+}
+
+// PluginPath returns the path of the plugin executable
+func (p *ExternalProvider) PluginPath() string {
+	return p.path
+}
+
+// Implements reports whether the plugin implements an optional interface
+func (p *ExternalProvider) Implements(iface string) bool {
+	if p.ready() != nil {
+		return false
+	}
+
+	for _, name := range p.info.Interfaces {
+		if name == iface {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Close stops the plugin process
+func (p *ExternalProvider) Close() error {
+	if p.stdin != nil {
+		p.stdin.Close()
+	}
+
+	if p.cmd == nil || p.cmd.Process == nil {
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- p.cmd.Wait() }()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(shutdownTimeout):
+		return p.cmd.Process.Kill()
+	}
+}
+
+// ready starts the plugin and performs the handshake once
+func (p *ExternalProvider) ready() error {
+	p.readyOnce.Do(func() {
+		p.readyErr = p.handshake()
+	})
+	return p.readyErr
+}
+
+// handshake starts the plugin process if needed and checks its Describe response
+func (p *ExternalProvider) handshake() error {
+	if p.conn == nil {
+		if err := p.start(); err != nil {
+			return err
+		}
+	}
+
+	var info DescribeResult
+	if err := p.invoke(methodDescribe, nil, &info, nil); err != nil {
+		return fmt.Errorf("external provider %s handshake failed: %w", p.name, err)
+	}
+
+	if info.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("external provider %s speaks protocol version %d, stax requires %d", p.name, info.ProtocolVersion, ProtocolVersion)
+	}
+
+	if info.Name != p.name {
+		return fmt.Errorf("external provider at %s reports name %q, expected %q", p.path, info.Name, p.name)
+	}
+
+	p.info = &info
+	return nil
+}
+
+// start launches the plugin process
+func (p *ExternalProvider) start() error {
+	cmd := exec.Command(p.path)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdin pipe for %s: %w", p.path, err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdout pipe for %s: %w", p.path, err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start external provider %s: %w", p.path, err)
+	}
+
+	p.cmd = cmd
+	p.stdin = stdin
+	p.conn = newConn(stdout, stdin)
+	go p.readLoop()
+
+	return nil
+}
+
+// readLoop dispatches responses and stream chunks until the plugin exits
+func (p *ExternalProvider) readLoop() {
+	for {
+		m, err := p.conn.receive()
+		if err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("external provider %s exited", p.name)
+			}
+			p.fail(err)
+			return
+		}
+
+		switch {
+		case m.Method == "" && m.ID != nil:
+			p.mu.Lock()
+			ch, ok := p.pending[*m.ID]
+			delete(p.pending, *m.ID)
+			p.mu.Unlock()
+
+			if ok {
+				ch <- m
+			}
+
+		case m.Method == notifyStreamData:
+			var chunk streamChunk
+			if json.Unmarshal(m.Params, &chunk) != nil {
+				continue
+			}
+
+			p.mu.Lock()
+			w := p.streams[chunk.Stream]
+			p.mu.Unlock()
+
+			if w == nil {
+				continue
+			}
+			if _, err := w.Write(chunk.Data); err != nil {
+				// The reader was closed early; drop the rest of the stream
+				p.removeStream(chunk.Stream)
+			}
+
+		case m.Method == notifyStreamEnd:
+			var chunk streamChunk
+			if json.Unmarshal(m.Params, &chunk) != nil {
+				continue
+			}
+
+			w := p.removeStream(chunk.Stream)
+			if pw, ok := w.(*io.PipeWriter); ok {
+				if chunk.Error != "" {
+					pw.CloseWithError(errors.New(chunk.Error))
+				} else {
+					pw.Close()
+				}
+			}
+		}
+	}
+}
+
+// fail aborts all in-flight calls and streams after the connection is lost
+func (p *ExternalProvider) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closedErr = err
+
+	for id, ch := range p.pending {
+		ch <- &message{Error: &rpcError{Code: codeProviderError, Message: err.Error()}}
+		delete(p.pending, id)
+	}
+
+	for id, w := range p.streams {
+		if pw, ok := w.(*io.PipeWriter); ok {
+			pw.CloseWithError(err)
+		}
+		delete(p.streams, id)
+	}
+}
+
+// addStream registers a writer for an incoming stream and returns its ID
+func (p *ExternalProvider) addStream(w io.Writer) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextStream++
+	p.streams[p.nextStream] = w
+
+	return p.nextStream
+}
+
+// removeStream unregisters a stream and returns its writer
+func (p *ExternalProvider) removeStream(id uint64) io.Writer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w := p.streams[id]
+	delete(p.streams, id)
+
+	return w
+}
+
+// call performs the handshake if needed and invokes a method
+func (p *ExternalProvider) call(method string, params *callParams, result interface{}) error {
+	if err := p.ready(); err != nil {
+		return err
+	}
+
+	return p.invoke(method, params, result, nil)
+}
+
+// invoke sends a request and waits for its response
+// afterSend, if set, runs once the request is sent and is told when a response has arrived
+func (p *ExternalProvider) invoke(method string, params *callParams, result interface{}, afterSend func(responded func() bool) error) error {
+	var raw json.RawMessage
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s params: %w", method, err)
+		}
+		raw = data
+	}
+
+	ch := make(chan *message, 1)
+
+	p.mu.Lock()
+	if p.closedErr != nil {
+		p.mu.Unlock()
+		return p.closedErr
+	}
+	p.nextID++
+	id := p.nextID
+	p.pending[id] = ch
+	p.mu.Unlock()
+
+	if err := p.conn.send(&message{ID: &id, Method: method, Params: raw}); err != nil {
+		p.mu.Lock()
+		delete(p.pending, id)
+		p.mu.Unlock()
+		return fmt.Errorf("failed to send %s to external provider %s: %w", method, p.name, err)
+	}
+
+	if afterSend != nil {
+		if err := afterSend(func() bool { return len(ch) > 0 }); err != nil {
+			return fmt.Errorf("failed to stream %s data to external provider %s: %w", method, p.name, err)
+		}
+	}
+
+	resp := <-ch
+	if resp.Error != nil {
+		return p.convertError(method, resp.Error)
+	}
+
+	if result != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", method, err)
+		}
+	}
+
+	return nil
+}
+
+// convertError turns a JSON-RPC error into a Go error
+func (p *ExternalProvider) convertError(method string, rpcErr *rpcError) error {
+	switch rpcErr.Code {
+	case codeUnsupported:
+		var data unsupportedData
+		if len(rpcErr.Data) > 0 && json.Unmarshal(rpcErr.Data, &data) == nil && data.Operation != "" {
+			return provider.NewUnsupportedError(p.name, data.Operation, data.Alternative)
+		}
+		return provider.NewUnsupportedError(p.name, method, "")
+	case codeMethodNotFound:
+		return provider.NewUnsupportedError(p.name, method, "")
+	default:
+		return errors.New(rpcErr.Message)
+	}
+}
+
+// callStream invokes a method whose response body is streamed back
+func (p *ExternalProvider) callStream(method string, params *callParams) (io.ReadCloser, error) {
+	if err := p.ready(); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	params.Stream = p.addStream(pw)
+
+	if err := p.invoke(method, params, nil, nil); err != nil {
+		p.removeStream(params.Stream)
+		pw.Close()
+		return nil, err
+	}
+
+	return &streamReader{PipeReader: pr, p: p, id: params.Stream}, nil
+}
+
+// streamReader is a streamed response body
+type streamReader struct {
+	*io.PipeReader
+	p  *ExternalProvider
+	id uint64
+}
+
+// Close stops the stream and tells the plugin to stop sending
+func (r *streamReader) Close() error {
+	if r.p.removeStream(r.id) != nil {
+		r.p.conn.notify(notifyStreamCancel, streamChunk{Stream: r.id})
+	}
+	return r.PipeReader.Close()
+}
+
+// ===== Metadata =====
+
+// Name returns the provider's unique identifier
+func (p *ExternalProvider) Name() string {
+	return p.name
+}
+
+// Description returns the plugin's description
+func (p *ExternalProvider) Description() string {
+	if err := p.ready(); err != nil {
+		return fmt.Sprintf("External provider (unavailable: %v)", err)
+	}
+	return p.info.Description
+}
+
+// Capabilities returns the plugin's capabilities
+// A plugin that fails to start reports no capabilities
+func (p *ExternalProvider) Capabilities() provider.ProviderCapabilities {
+	if p.ready() != nil {
+		return provider.ProviderCapabilities{}
+	}
+	return p.info.Capabilities
+}
+
+// ===== Authentication & Setup =====
+
+// Authenticate authenticates the plugin with credentials
+func (p *ExternalProvider) Authenticate(credentials map[string]string) error {
+	return p.call(methodAuthenticate, &callParams{Credentials: credentials}, nil)
+}
+
+// TestConnection tests the plugin's connection to its platform
+func (p *ExternalProvider) TestConnection() error {
+	return p.call(methodTestConnection, nil, nil)
+}
+
+// ValidateCredentials validates credentials without connecting
+func (p *ExternalProvider) ValidateCredentials(credentials map[string]string) error {
+	return p.call(methodValidateCredentials, &callParams{Credentials: credentials}, nil)
+}
+
+// ===== Site Management =====
+
+// ListSites lists the plugin's sites
+func (p *ExternalProvider) ListSites() ([]provider.Site, error) {
+	var sites []provider.Site
+	if err := p.call(methodListSites, nil, &sites); err != nil {
+		return nil, err
+	}
+	return sites, nil
+}
+
+// GetSite retrieves a site by identifier
+func (p *ExternalProvider) GetSite(identifier string) (*provider.Site, error) {
+	var site provider.Site
+	if err := p.call(methodGetSite, &callParams{Identifier: identifier}, &site); err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// GetSiteMetadata retrieves detailed site metadata
+func (p *ExternalProvider) GetSiteMetadata(site *provider.Site) (*provider.SiteMetadata, error) {
+	var metadata provider.SiteMetadata
+	if err := p.call(methodGetSiteMetadata, &callParams{Site: site}, &metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// ===== Database Operations =====
+
+// ExportDatabase streams a database export from the plugin
+func (p *ExternalProvider) ExportDatabase(site *provider.Site, options provider.DatabaseExportOptions) (io.ReadCloser, error) {
+	return p.callStream(methodExportDatabase, &callParams{Site: site, ExportOptions: &options})
+}
+
+// ImportDatabase streams a database dump to the plugin
+func (p *ExternalProvider) ImportDatabase(site *provider.Site, data io.Reader, options provider.DatabaseImportOptions) error {
+	if err := p.ready(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.nextStream++
+	stream := p.nextStream
+	p.mu.Unlock()
+
+	params := &callParams{Site: site, ImportOptions: &options, Stream: stream}
+	return p.invoke(methodImportDatabase, params, nil, func(responded func() bool) error {
+		// Stop sending once the plugin has answered, e.g. with an early error
+		return p.conn.sendStream(stream, data, responded)
+	})
+}
+
+// GetDatabaseCredentials retrieves database connection credentials
+func (p *ExternalProvider) GetDatabaseCredentials(site *provider.Site) (*provider.DatabaseCredentials, error) {
+	var creds provider.DatabaseCredentials
+	if err := p.call(methodGetDatabaseCredentials, &callParams{Site: site}, &creds); err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
+
+// ===== File Operations =====
+
+// SyncFiles asks the plugin to sync files to a local destination
+func (p *ExternalProvider) SyncFiles(site *provider.Site, destination string, options provider.SyncOptions) error {
+	return p.call(methodSyncFiles, &callParams{Site: site, Destination: destination, SyncOptions: &options}, nil)
+}
+
+// DownloadFile streams a single remote file from the plugin
+func (p *ExternalProvider) DownloadFile(site *provider.Site, remotePath string) (io.ReadCloser, error) {
+	return p.callStream(methodDownloadFile, &callParams{Site: site, RemotePath: remotePath})
+}
+
+// UploadFile asks the plugin to upload a local file
+func (p *ExternalProvider) UploadFile(site *provider.Site, localPath, remotePath string) error {
+	return p.call(methodUploadFile, &callParams{Site: site, LocalPath: localPath, RemotePath: remotePath}, nil)
+}
+
+// ===== Environment Information =====
+
+// GetPHPVersion returns the site's PHP version
+func (p *ExternalProvider) GetPHPVersion(site *provider.Site) (string, error) {
+	var version string
+	err := p.call(methodGetPHPVersion, &callParams{Site: site}, &version)
+	return version, err
+}
+
+// GetMySQLVersion returns the site's MySQL version
+func (p *ExternalProvider) GetMySQLVersion(site *provider.Site) (string, error) {
+	var version string
+	err := p.call(methodGetMySQLVersion, &callParams{Site: site}, &version)
+	return version, err
+}
+
+// GetWordPressVersion returns the site's WordPress version
+func (p *ExternalProvider) GetWordPressVersion(site *provider.Site) (string, error) {
+	var version string
+	err := p.call(methodGetWordPressVersion, &callParams{Site: site}, &version)
+	return version, err
+}
+
+// ===== Deployer =====
+
+// Deploy deploys code to the site
+func (p *ExternalProvider) Deploy(site *provider.Site, options provider.DeployOptions) (*provider.Deployment, error) {
+	var deployment provider.Deployment
+	if err := p.call(methodDeploy, &callParams{Site: site, DeployOptions: &options}, &deployment); err != nil {
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+// GetDeploymentStatus checks the status of a deployment
+func (p *ExternalProvider) GetDeploymentStatus(site *provider.Site, deploymentID string) (*provider.DeploymentStatus, error) {
+	var status provider.DeploymentStatus
+	if err := p.call(methodGetDeploymentStatus, &callParams{Site: site, DeploymentID: deploymentID}, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ListDeployments lists recent deployments
+func (p *ExternalProvider) ListDeployments(site *provider.Site) ([]provider.Deployment, error) {
+	var deployments []provider.Deployment
+	if err := p.call(methodListDeployments, &callParams{Site: site}, &deployments); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+// ===== EnvironmentManager =====
+
+// ListEnvironments lists a site's environments
+func (p *ExternalProvider) ListEnvironments(site *provider.Site) ([]provider.Environment, error) {
+	var environments []provider.Environment
+	if err := p.call(methodListEnvironments, &callParams{Site: site}, &environments); err != nil {
+		return nil, err
+	}
+	return environments, nil
+}
+
+// GetEnvironment retrieves a single environment
+func (p *ExternalProvider) GetEnvironment(site *provider.Site, environmentName string) (*provider.Environment, error) {
+	var environment provider.Environment
+	if err := p.call(methodGetEnvironment, &callParams{Site: site, Environment: environmentName}, &environment); err != nil {
+		return nil, err
+	}
+	return &environment, nil
+}
+
+// SwitchEnvironment switches to a different environment
+func (p *ExternalProvider) SwitchEnvironment(site *provider.Site, environmentName string) error {
+	return p.call(methodSwitchEnvironment, &callParams{Site: site, Environment: environmentName}, nil)
+}
+
+// CreateEnvironment creates a new environment
+func (p *ExternalProvider) CreateEnvironment(site *provider.Site, environmentName string, options provider.EnvironmentOptions) error {
+	return p.call(methodCreateEnvironment, &callParams{Site: site, Environment: environmentName, EnvironmentOptions: &options}, nil)
+}
+
+// DeleteEnvironment deletes an environment
+func (p *ExternalProvider) DeleteEnvironment(site *provider.Site, environmentName string) error {
+	return p.call(methodDeleteEnvironment, &callParams{Site: site, Environment: environmentName}, nil)
+}
+
+// ===== BackupManager =====
+
+// ListBackups lists a site's backups
+func (p *ExternalProvider) ListBackups(site *provider.Site) ([]provider.Backup, error) {
+	var backups []provider.Backup
+	if err := p.call(methodListBackups, &callParams{Site: site}, &backups); err != nil {
+		return nil, err
+	}
+	return backups, nil
+}
+
+// CreateBackup creates a manual backup
+func (p *ExternalProvider) CreateBackup(site *provider.Site, description string) (*provider.Backup, error) {
+	var backup provider.Backup
+	if err := p.call(methodCreateBackup, &callParams{Site: site, Description: description}, &backup); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+// RestoreBackup restores a site from a backup
+func (p *ExternalProvider) RestoreBackup(site *provider.Site, backupID string, options provider.RestoreOptions) error {
+	return p.call(methodRestoreBackup, &callParams{Site: site, BackupID: backupID, RestoreOptions: &options}, nil)
+}
+
+// DeleteBackup deletes a backup
+func (p *ExternalProvider) DeleteBackup(site *provider.Site, backupID string) error {
+	return p.call(methodDeleteBackup, &callParams{Site: site, BackupID: backupID}, nil)
+}
+
+// DownloadBackup streams a backup archive from the plugin
+func (p *ExternalProvider) DownloadBackup(site *provider.Site, backupID string) (io.ReadCloser, error) {
+	return p.callStream(methodDownloadBackup, &callParams{Site: site, BackupID: backupID})
+}
+
+// ===== RemoteExecutor =====
+
+// ExecuteCommand executes a shell command on the remote server
+func (p *ExternalProvider) ExecuteCommand(site *provider.Site, command string) (string, error) {
+	var output string
+	err := p.call(methodExecuteCommand, &callParams{Site: site, Command: command}, &output)
+	return output, err
+}
+
+// ExecuteWPCLI executes a WP-CLI command
+func (p *ExternalProvider) ExecuteWPCLI(site *provider.Site, args []string) (string, error) {
+	var output string
+	err := p.call(methodExecuteWPCLI, &callParams{Site: site, Args: args}, &output)
+	return output, err
+}
+
+// StreamCommand executes a command and streams its output to the given writers
+func (p *ExternalProvider) StreamCommand(site *provider.Site, command string, stdout, stderr io.Writer) error {
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+
+	params := &callParams{
+		Site:         site,
+		Command:      command,
+		StdoutStream: p.addStream(stdout),
+		StderrStream: p.addStream(stderr),
+	}
+	defer p.removeStream(params.StdoutStream)
+	defer p.removeStream(params.StderrStream)
+
+	return p.call(methodStreamCommand, params, nil)
+}
+
+// ===== MediaManager =====
+
+// GetMediaURL returns the media URL for a site
+func (p *ExternalProvider) GetMediaURL(site *provider.Site) (string, error) {
+	var mediaURL string
+	err := p.call(methodGetMediaURL, &callParams{Site: site}, &mediaURL)
+	return mediaURL, err
+}
+
+// SupportsRemoteMedia reports whether the plugin serves remote media
+func (p *ExternalProvider) SupportsRemoteMedia() bool {
+	var supported bool
+	if err := p.call(methodSupportsRemoteMedia, nil, &supported); err != nil {
+		return false
+	}
+	return supported
+}
+
+// ConfigureMedia configures media settings
+func (p *ExternalProvider) ConfigureMedia(site *provider.Site, options provider.MediaOptions) error {
+	return p.call(methodConfigureMedia, &callParams{Site: site, MediaOptions: &options}, nil)
+}
+
+// PurgeMediaCache purges the media cache
+func (p *ExternalProvider) PurgeMediaCache(site *provider.Site, paths []string) error {
+	return p.call(methodPurgeMediaCache, &callParams{Site: site, Paths: paths}, nil)
+}
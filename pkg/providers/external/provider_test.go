@@ -0,0 +1,410 @@
+package external
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/firecrown-media/stax/pkg/provider"
+	"github.com/firecrown-media/stax/pkg/provider/providertest"
+)
+
+// helperEnv makes the test binary act as a plugin executable
+const helperEnv = "STAX_EXTERNAL_PROVIDER_HELPER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) == "1" {
+		if err := ServeStdio(newFakeProvider()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// fakeProvider is an in-memory provider served over the plugin protocol
+type fakeProvider struct {
+	mu            sync.Mutex
+	authenticated bool
+	database      []byte
+	files         map[string][]byte
+}
+
+func newFakeProvider() *fakeProvider {
+	return &fakeProvider{
+		database: []byte("CREATE TABLE wp_options;"),
+		files:    map[string][]byte{"wp-content/uploads/big.bin": bytes.Repeat([]byte("0123456789"), 10000)},
+	}
+}
+
+var fakeSite = provider.Site{ID: "1", Name: "alpha", PrimaryDomain: "alpha.example.com", Provider: "fake"}
+
+func (p *fakeProvider) Name() string        { return "fake" }
+func (p *fakeProvider) Description() string { return "Fake external provider" }
+
+func (p *fakeProvider) Capabilities() provider.ProviderCapabilities {
+	return provider.ProviderCapabilities{
+		Authentication:  true,
+		SiteManagement:  true,
+		DatabaseExport:  true,
+		DatabaseImport:  true,
+		RemoteExecution: true,
+	}
+}
+
+func (p *fakeProvider) Authenticate(credentials map[string]string) error {
+	if err := p.ValidateCredentials(credentials); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.authenticated = true
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *fakeProvider) ValidateCredentials(credentials map[string]string) error {
+	if credentials["token"] == "" {
+		return fmt.Errorf("missing required credential: token")
+	}
+	return nil
+}
+
+func (p *fakeProvider) TestConnection() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.authenticated {
+		return fmt.Errorf("not authenticated")
+	}
+	return nil
+}
+
+func (p *fakeProvider) ListSites() ([]provider.Site, error) {
+	if err := p.TestConnection(); err != nil {
+		return nil, err
+	}
+	return []provider.Site{fakeSite}, nil
+}
+
+func (p *fakeProvider) GetSite(identifier string) (*provider.Site, error) {
+	if err := p.TestConnection(); err != nil {
+		return nil, err
+	}
+	if identifier == fakeSite.ID || identifier == fakeSite.Name || identifier == fakeSite.PrimaryDomain {
+		site := fakeSite
+		return &site, nil
+	}
+	return nil, fmt.Errorf("site not found: %s", identifier)
+}
+
+func (p *fakeProvider) GetSiteMetadata(site *provider.Site) (*provider.SiteMetadata, error) {
+	return &provider.SiteMetadata{Site: site, PHPVersion: "8.2"}, nil
+}
+
+func (p *fakeProvider) ExportDatabase(site *provider.Site, options provider.DatabaseExportOptions) (io.ReadCloser, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return io.NopCloser(bytes.NewReader(append([]byte(nil), p.database...))), nil
+}
+
+func (p *fakeProvider) ImportDatabase(site *provider.Site, data io.Reader, options provider.DatabaseImportOptions) error {
+	dump, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.database = dump
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *fakeProvider) GetDatabaseCredentials(site *provider.Site) (*provider.DatabaseCredentials, error) {
+	return nil, provider.NewUnsupportedError("fake", "database credentials", "use a tunnel")
+}
+
+func (p *fakeProvider) SyncFiles(site *provider.Site, destination string, options provider.SyncOptions) error {
+	return provider.NewUnsupportedError("fake", "file sync", "")
+}
+
+func (p *fakeProvider) DownloadFile(site *provider.Site, remotePath string) (io.ReadCloser, error) {
+	data, ok := p.files[remotePath]
+	if !ok {
+		return nil, fmt.Errorf("file not found: %s", remotePath)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (p *fakeProvider) UploadFile(site *provider.Site, localPath, remotePath string) error {
+	return provider.NewUnsupportedError("fake", "file upload", "")
+}
+
+func (p *fakeProvider) GetPHPVersion(site *provider.Site) (string, error)       { return "8.2", nil }
+func (p *fakeProvider) GetMySQLVersion(site *provider.Site) (string, error)     { return "8.0", nil }
+func (p *fakeProvider) GetWordPressVersion(site *provider.Site) (string, error) { return "6.5", nil }
+
+func (p *fakeProvider) ExecuteCommand(site *provider.Site, command string) (string, error) {
+	return "ran: " + command, nil
+}
+
+func (p *fakeProvider) ExecuteWPCLI(site *provider.Site, args []string) (string, error) {
+	return p.ExecuteCommand(site, "wp "+strings.Join(args, " "))
+}
+
+func (p *fakeProvider) StreamCommand(site *provider.Site, command string, stdout, stderr io.Writer) error {
+	fmt.Fprintf(stdout, "out: %s\n", command)
+	fmt.Fprintf(stderr, "err: %s\n", command)
+	return nil
+}
+
+// newPipeProvider connects an ExternalProvider to an in-process server
+func newPipeProvider(t *testing.T, p provider.Provider) *ExternalProvider {
+	t.Helper()
+
+	clientRead, serverWrite := io.Pipe()
+	serverRead, clientWrite := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(p, serverRead, serverWrite)
+		serverWrite.Close()
+	}()
+
+	client := newConnectedProvider(p.Name(), clientRead, clientWrite)
+	t.Cleanup(func() {
+		client.Close()
+		if err := <-done; err != nil {
+			t.Errorf("Serve() failed: %v", err)
+		}
+	})
+
+	return client
+}
+
+// newAuthenticatedProvider returns a pipe-connected provider that has authenticated
+func newAuthenticatedProvider(t *testing.T) *ExternalProvider {
+	t.Helper()
+
+	client := newPipeProvider(t, newFakeProvider())
+	if err := client.Authenticate(map[string]string{"token": "secret"}); err != nil {
+		t.Fatalf("Authenticate() failed: %v", err)
+	}
+
+	return client
+}
+
+func TestConformance(t *testing.T) {
+	client := newAuthenticatedProvider(t)
+
+	providertest.Run(t, client, providertest.Config{
+		SiteID:     "1",
+		SiteName:   "alpha",
+		SiteDomain: "alpha.example.com",
+		RoundTrip:  true,
+		NewUnauthenticated: func() provider.Provider {
+			return newPipeProvider(t, newFakeProvider())
+		},
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	client := newPipeProvider(t, newFakeProvider())
+
+	if client.Description() != "Fake external provider" {
+		t.Errorf("unexpected description: %q", client.Description())
+	}
+	if !client.Capabilities().RemoteExecution {
+		t.Error("expected remote_execution capability from the plugin")
+	}
+	if !client.Implements(provider.InterfaceRemoteExecutor) {
+		t.Error("expected plugin to implement the remote executor interface")
+	}
+	if client.Implements(provider.InterfaceBackupManager) {
+		t.Error("plugin does not implement the backup manager interface")
+	}
+}
+
+func TestNameMismatch(t *testing.T) {
+	clientRead, serverWrite := io.Pipe()
+	serverRead, clientWrite := io.Pipe()
+	go Serve(newFakeProvider(), serverRead, serverWrite)
+
+	client := newConnectedProvider("other", clientRead, clientWrite)
+	defer client.Close()
+
+	if _, err := client.ListSites(); err == nil || !strings.Contains(err.Error(), "reports name") {
+		t.Errorf("expected name mismatch error, got %v", err)
+	}
+}
+
+func TestStreamedDownload(t *testing.T) {
+	client := newAuthenticatedProvider(t)
+	want := newFakeProvider().files["wp-content/uploads/big.bin"]
+
+	reader, err := client.DownloadFile(&fakeSite, "wp-content/uploads/big.bin")
+	if err != nil {
+		t.Fatalf("DownloadFile() failed: %v", err)
+	}
+
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		t.Fatalf("failed to read download: %v", err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("download mismatch: got %d bytes, want %d", len(data), len(want))
+	}
+
+	// Closing a stream early must not wedge the connection
+	reader, err = client.DownloadFile(&fakeSite, "wp-content/uploads/big.bin")
+	if err != nil {
+		t.Fatalf("DownloadFile() failed: %v", err)
+	}
+	reader.Read(make([]byte, 10))
+	reader.Close()
+
+	if _, err := client.GetPHPVersion(&fakeSite); err != nil {
+		t.Errorf("call after early close failed: %v", err)
+	}
+
+	if _, err := client.DownloadFile(&fakeSite, "missing.txt"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestStreamCommand(t *testing.T) {
+	client := newAuthenticatedProvider(t)
+
+	var stdout, stderr bytes.Buffer
+	if err := client.StreamCommand(&fakeSite, "wp cron event run", &stdout, &stderr); err != nil {
+		t.Fatalf("StreamCommand() failed: %v", err)
+	}
+
+	if stdout.String() != "out: wp cron event run\n" {
+		t.Errorf("unexpected stdout: %q", stdout.String())
+	}
+	if stderr.String() != "err: wp cron event run\n" {
+		t.Errorf("unexpected stderr: %q", stderr.String())
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	client := newAuthenticatedProvider(t)
+
+	_, err := client.GetDatabaseCredentials(&fakeSite)
+	unsupported, ok := err.(*provider.ErrUnsupported)
+	if !ok {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+	if unsupported.Alternative != "use a tunnel" {
+		t.Errorf("alternative not carried over: %+v", unsupported)
+	}
+
+	// Optional interfaces the plugin does not implement are unsupported too
+	if _, err := client.ListBackups(&fakeSite); !provider.IsUnsupported(err) {
+		t.Errorf("expected ListBackups() to be unsupported, got %v", err)
+	}
+}
+
+func TestConvertMethodNotFound(t *testing.T) {
+	client := NewExternalProvider("fake", "")
+
+	err := client.convertError("FutureMethod", &rpcError{Code: codeMethodNotFound, Message: "method not found"})
+	if !provider.IsUnsupported(err) {
+		t.Errorf("expected method not found to map to ErrUnsupported, got %v", err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits are not used on Windows")
+	}
+
+	first := t.TempDir()
+	second := t.TempDir()
+
+	writeFile := func(dir, name string, mode os.FileMode) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(first, "stax-provider-inhouse", 0755)
+	writeFile(second, "stax-provider-inhouse", 0755)
+	writeFile(second, "stax-provider-other", 0755)
+	writeFile(second, "stax-provider-noexec", 0644)
+	writeFile(second, "stax-provider-", 0755)
+	writeFile(second, "unrelated", 0755)
+
+	plugins := Discover([]string{first, "", filepath.Join(first, "missing"), second})
+
+	if len(plugins) != 2 {
+		t.Fatalf("expected 2 plugins, got %+v", plugins)
+	}
+	if plugins[0].Name != "inhouse" || plugins[0].Path != filepath.Join(first, "stax-provider-inhouse") {
+		t.Errorf("expected earliest directory to win, got %+v", plugins[0])
+	}
+	if plugins[1].Name != "other" {
+		t.Errorf("unexpected second plugin: %+v", plugins[1])
+	}
+}
+
+func TestSubprocessPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin symlinks are not supported on Windows")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ExecutablePrefix+"fake")
+	if err := os.Symlink(os.Args[0], path); err != nil {
+		t.Fatalf("failed to link plugin: %v", err)
+	}
+	t.Setenv(helperEnv, "1")
+
+	plugins := Discover([]string{dir})
+	if len(plugins) != 1 || plugins[0].Name != "fake" {
+		t.Fatalf("expected to discover the fake plugin, got %+v", plugins)
+	}
+
+	client := NewExternalProvider(plugins[0].Name, plugins[0].Path)
+	defer client.Close()
+
+	if err := client.Authenticate(map[string]string{"token": "secret"}); err != nil {
+		t.Fatalf("Authenticate() failed: %v", err)
+	}
+
+	reader, err := client.ExportDatabase(&fakeSite, provider.DatabaseExportOptions{})
+	if err != nil {
+		t.Fatalf("ExportDatabase() failed: %v", err)
+	}
+	defer reader.Close()
+
+	data, _ := io.ReadAll(reader)
+	if string(data) != "CREATE TABLE wp_options;" {
+		t.Errorf("unexpected export: %q", string(data))
+	}
+
+	if client.PluginPath() != path {
+		t.Errorf("PluginPath() = %q, want %q", client.PluginPath(), path)
+	}
+}
+
+func TestUnavailablePlugin(t *testing.T) {
+	client := NewExternalProvider("missing", filepath.Join(t.TempDir(), "stax-provider-missing"))
+
+	if !strings.Contains(client.Description(), "unavailable") {
+		t.Errorf("expected unavailable description, got %q", client.Description())
+	}
+	if client.Capabilities() != (provider.ProviderCapabilities{}) {
+		t.Error("expected no capabilities for an unavailable plugin")
+	}
+	if _, err := client.ListSites(); err == nil {
+		t.Error("expected ListSites() to fail for an unavailable plugin")
+	}
+}
@@ -0,0 +1,390 @@
+package external
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/firecrown-media/stax/pkg/provider"
+)
+
+// server dispatches plugin requests to a provider implementation
+type server struct {
+	p    provider.Provider
+	conn *conn
+
+	mu       sync.Mutex
+	inputs   map[uint64]*io.PipeWriter
+	canceled map[uint64]bool
+}
+
+// ServeStdio serves p over stdin/stdout
+// Plugin executables call it from main; they must not write anything else to stdout
+func ServeStdio(p provider.Provider) error {
+	return Serve(p, os.Stdin, os.Stdout)
+}
+
+// Serve serves p over the plugin protocol until r is closed
+func Serve(p provider.Provider, r io.Reader, w io.Writer) error {
+	s := &server{
+		p:        p,
+		conn:     newConn(r, w),
+		inputs:   make(map[uint64]*io.PipeWriter),
+		canceled: make(map[uint64]bool),
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer s.closeInputs()
+
+	for {
+		m, err := s.conn.receive()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+
+		switch m.Method {
+		case notifyStreamData, notifyStreamEnd:
+			var chunk streamChunk
+			if json.Unmarshal(m.Params, &chunk) == nil {
+				s.receiveInput(m.Method, chunk)
+			}
+
+		case notifyStreamCancel:
+			var chunk streamChunk
+			if json.Unmarshal(m.Params, &chunk) == nil {
+				s.mu.Lock()
+				s.canceled[chunk.Stream] = true
+				s.mu.Unlock()
+			}
+
+		default:
+			if m.ID == nil {
+				continue
+			}
+
+			var params callParams
+			if len(m.Params) > 0 {
+				if err := json.Unmarshal(m.Params, &params); err != nil {
+					s.respond(*m.ID, nil, &rpcError{Code: codeInvalidParams, Message: err.Error()})
+					continue
+				}
+			}
+
+			// Register the input stream before reading on, so its chunks have somewhere to go
+			var input *io.PipeReader
+			if m.Method == methodImportDatabase {
+				pr, pw := io.Pipe()
+				s.mu.Lock()
+				s.inputs[params.Stream] = pw
+				s.mu.Unlock()
+				input = pr
+			}
+
+			wg.Add(1)
+			go func(id uint64, method string) {
+				defer wg.Done()
+				s.handle(id, method, &params, input)
+			}(*m.ID, m.Method)
+		}
+	}
+}
+
+// receiveInput feeds a client stream chunk to its request handler
+func (s *server) receiveInput(method string, chunk streamChunk) {
+	s.mu.Lock()
+	pw := s.inputs[chunk.Stream]
+	if method == notifyStreamEnd {
+		delete(s.inputs, chunk.Stream)
+	}
+	s.mu.Unlock()
+
+	if pw == nil {
+		return
+	}
+
+	if method == notifyStreamEnd {
+		if chunk.Error != "" {
+			pw.CloseWithError(errors.New(chunk.Error))
+		} else {
+			pw.Close()
+		}
+		return
+	}
+
+	if _, err := pw.Write(chunk.Data); err != nil {
+		// The handler stopped reading; drop the rest of the stream
+		s.mu.Lock()
+		delete(s.inputs, chunk.Stream)
+		s.mu.Unlock()
+	}
+}
+
+// closeInputs aborts input streams still open when the client disconnects
+func (s *server) closeInputs() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, pw := range s.inputs {
+		pw.CloseWithError(io.ErrUnexpectedEOF)
+		delete(s.inputs, id)
+	}
+}
+
+// handle runs one request and sends its response and any streamed body
+func (s *server) handle(id uint64, method string, params *callParams, input *io.PipeReader) {
+	if input != nil {
+		defer input.Close()
+	}
+
+	result, body, err := s.dispatch(method, params, input)
+	if err != nil {
+		s.respond(id, nil, toRPCError(err))
+		return
+	}
+
+	s.respond(id, result, nil)
+
+	if body != nil {
+		defer body.Close()
+		s.conn.sendStream(params.Stream, body, func() bool {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return s.canceled[params.Stream]
+		})
+
+		s.mu.Lock()
+		delete(s.canceled, params.Stream)
+		s.mu.Unlock()
+	}
+}
+
+// respond sends a result or error response
+func (s *server) respond(id uint64, result interface{}, rpcErr *rpcError) {
+	m := &message{ID: &id, Error: rpcErr}
+
+	if rpcErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			m.Error = &rpcError{Code: codeProviderError, Message: fmt.Sprintf("failed to marshal result: %v", err)}
+		} else {
+			m.Result = data
+		}
+	}
+
+	s.conn.send(m)
+}
+
+// toRPCError converts a provider error into a JSON-RPC error
+func toRPCError(err error) *rpcError {
+	var unsupported *provider.ErrUnsupported
+	if errors.As(err, &unsupported) {
+		data, _ := json.Marshal(unsupportedData{
+			Provider:    unsupported.Provider,
+			Operation:   unsupported.Operation,
+			Alternative: unsupported.Alternative,
+		})
+		return &rpcError{Code: codeUnsupported, Message: err.Error(), Data: data}
+	}
+
+	var notFound *methodNotFoundError
+	if errors.As(err, &notFound) {
+		return &rpcError{Code: codeMethodNotFound, Message: err.Error()}
+	}
+
+	return &rpcError{Code: codeProviderError, Message: err.Error()}
+}
+
+// describe builds the handshake response for the served provider
+func (s *server) describe() *DescribeResult {
+	interfaces := []string{}
+	if _, ok := s.p.(provider.Deployer); ok {
+		interfaces = append(interfaces, provider.InterfaceDeployer)
+	}
+	if _, ok := s.p.(provider.EnvironmentManager); ok {
+		interfaces = append(interfaces, provider.InterfaceEnvironmentManager)
+	}
+	if _, ok := s.p.(provider.BackupManager); ok {
+		interfaces = append(interfaces, provider.InterfaceBackupManager)
+	}
+	if _, ok := s.p.(provider.RemoteExecutor); ok {
+		interfaces = append(interfaces, provider.InterfaceRemoteExecutor)
+	}
+	if _, ok := s.p.(provider.MediaManager); ok {
+		interfaces = append(interfaces, provider.InterfaceMediaManager)
+	}
+
+	return &DescribeResult{
+		ProtocolVersion: ProtocolVersion,
+		Name:            s.p.Name(),
+		Description:     s.p.Description(),
+		Capabilities:    s.p.Capabilities(),
+		Interfaces:      interfaces,
+	}
+}
+
+// implements reports whether the served provider implements an optional interface
+func (s *server) implements(iface string) bool {
+	for _, name := range s.describe().Interfaces {
+		if name == iface {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch calls the provider method for a request
+// It returns the result and, for streamed responses, the body to send after it
+func (s *server) dispatch(method string, params *callParams, input io.Reader) (interface{}, io.ReadCloser, error) {
+	if iface, ok := methodInterfaces[method]; ok && !s.implements(iface) {
+		return nil, nil, provider.NewUnsupportedError(s.p.Name(), method, "")
+	}
+
+	p := s.p
+	site := params.Site
+
+	switch method {
+	case methodDescribe:
+		return s.describe(), nil, nil
+
+	case methodAuthenticate:
+		return nil, nil, p.Authenticate(params.Credentials)
+	case methodTestConnection:
+		return nil, nil, p.TestConnection()
+	case methodValidateCredentials:
+		return nil, nil, p.ValidateCredentials(params.Credentials)
+
+	case methodListSites:
+		sites, err := p.ListSites()
+		return sites, nil, err
+	case methodGetSite:
+		result, err := p.GetSite(params.Identifier)
+		return result, nil, err
+	case methodGetSiteMetadata:
+		metadata, err := p.GetSiteMetadata(site)
+		return metadata, nil, err
+
+	case methodExportDatabase:
+		return streamBody(p.ExportDatabase(site, valueOf(params.ExportOptions)))
+	case methodImportDatabase:
+		return nil, nil, p.ImportDatabase(site, input, valueOf(params.ImportOptions))
+	case methodGetDatabaseCredentials:
+		creds, err := p.GetDatabaseCredentials(site)
+		return creds, nil, err
+
+	case methodSyncFiles:
+		return nil, nil, p.SyncFiles(site, params.Destination, valueOf(params.SyncOptions))
+	case methodDownloadFile:
+		return streamBody(p.DownloadFile(site, params.RemotePath))
+	case methodUploadFile:
+		return nil, nil, p.UploadFile(site, params.LocalPath, params.RemotePath)
+
+	case methodGetPHPVersion:
+		version, err := p.GetPHPVersion(site)
+		return version, nil, err
+	case methodGetMySQLVersion:
+		version, err := p.GetMySQLVersion(site)
+		return version, nil, err
+	case methodGetWordPressVersion:
+		version, err := p.GetWordPressVersion(site)
+		return version, nil, err
+
+	case methodDeploy:
+		deployment, err := p.(provider.Deployer).Deploy(site, valueOf(params.DeployOptions))
+		return deployment, nil, err
+	case methodGetDeploymentStatus:
+		status, err := p.(provider.Deployer).GetDeploymentStatus(site, params.DeploymentID)
+		return status, nil, err
+	case methodListDeployments:
+		deployments, err := p.(provider.Deployer).ListDeployments(site)
+		return deployments, nil, err
+
+	case methodListEnvironments:
+		environments, err := p.(provider.EnvironmentManager).ListEnvironments(site)
+		return environments, nil, err
+	case methodGetEnvironment:
+		environment, err := p.(provider.EnvironmentManager).GetEnvironment(site, params.Environment)
+		return environment, nil, err
+	case methodSwitchEnvironment:
+		return nil, nil, p.(provider.EnvironmentManager).SwitchEnvironment(site, params.Environment)
+	case methodCreateEnvironment:
+		return nil, nil, p.(provider.EnvironmentManager).CreateEnvironment(site, params.Environment, valueOf(params.EnvironmentOptions))
+	case methodDeleteEnvironment:
+		return nil, nil, p.(provider.EnvironmentManager).DeleteEnvironment(site, params.Environment)
+
+	case methodListBackups:
+		backups, err := p.(provider.BackupManager).ListBackups(site)
+		return backups, nil, err
+	case methodCreateBackup:
+		backup, err := p.(provider.BackupManager).CreateBackup(site, params.Description)
+		return backup, nil, err
+	case methodRestoreBackup:
+		return nil, nil, p.(provider.BackupManager).RestoreBackup(site, params.BackupID, valueOf(params.RestoreOptions))
+	case methodDeleteBackup:
+		return nil, nil, p.(provider.BackupManager).DeleteBackup(site, params.BackupID)
+	case methodDownloadBackup:
+		return streamBody(p.(provider.BackupManager).DownloadBackup(site, params.BackupID))
+
+	case methodExecuteCommand:
+		output, err := p.(provider.RemoteExecutor).ExecuteCommand(site, params.Command)
+		return output, nil, err
+	case methodExecuteWPCLI:
+		output, err := p.(provider.RemoteExecutor).ExecuteWPCLI(site, params.Args)
+		return output, nil, err
+	case methodStreamCommand:
+		stdout := &streamWriter{c: s.conn, id: params.StdoutStream}
+		stderr := &streamWriter{c: s.conn, id: params.StderrStream}
+		err := p.(provider.RemoteExecutor).StreamCommand(site, params.Command, stdout, stderr)
+
+		// Output must be complete before the response arrives
+		s.conn.notify(notifyStreamEnd, streamChunk{Stream: params.StdoutStream})
+		s.conn.notify(notifyStreamEnd, streamChunk{Stream: params.StderrStream})
+		return nil, nil, err
+
+	case methodGetMediaURL:
+		mediaURL, err := p.(provider.MediaManager).GetMediaURL(site)
+		return mediaURL, nil, err
+	case methodSupportsRemoteMedia:
+		return p.(provider.MediaManager).SupportsRemoteMedia(), nil, nil
+	case methodConfigureMedia:
+		return nil, nil, p.(provider.MediaManager).ConfigureMedia(site, valueOf(params.MediaOptions))
+	case methodPurgeMediaCache:
+		return nil, nil, p.(provider.MediaManager).PurgeMediaCache(site, params.Paths)
+	}
+
+	return nil, nil, &methodNotFoundError{method: method}
+}
+
+// streamBody adapts a streamed provider result, always returning a body on success
+// so the client sees the stream end
+func streamBody(body io.ReadCloser, err error) (interface{}, io.ReadCloser, error) {
+	if err != nil {
+		if body != nil {
+			body.Close()
+		}
+		return nil, nil, err
+	}
+
+	if body == nil {
+		body = io.NopCloser(strings.NewReader(""))
+	}
+
+	return nil, body, nil
+}
+
+// methodNotFoundError is returned for unknown methods
+type methodNotFoundError struct {
+	method string
+}
+
+// Error implements the error interface
+func (e *methodNotFoundError) Error() string {
+	return fmt.Sprintf("method not found: %s", e.method)
+}
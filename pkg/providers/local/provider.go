@@ -1,8 +1,10 @@
 package local
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"os/exec"
 
 	"github.com/firecrown-media/stax/pkg/provider"
 )
@@ -111,7 +113,12 @@ func (p *LocalProvider) GetSite(identifier string) (*provider.Site, error) {
 		return nil, fmt.Errorf("no local site configured")
 	}
 
-	return &sites[0], nil
+	site := &sites[0]
+	if identifier != site.ID && identifier != site.Name && identifier != site.PrimaryDomain {
+		return nil, fmt.Errorf("site not found: %s", identifier)
+	}
+
+	return site, nil
 }
 
 // GetSiteMetadata retrieves detailed metadata about the local site
@@ -135,22 +142,80 @@ func (p *LocalProvider) GetSiteMetadata(site *provider.Site) (*provider.SiteMeta
 
 // ===== Database Operations =====
 
-// ExportDatabase exports the local database
+// ExportDatabase exports the local database via `ddev export-db`
 func (p *LocalProvider) ExportDatabase(site *provider.Site, options provider.DatabaseExportOptions) (io.ReadCloser, error) {
-	// TODO: Implement local database export via DDEV
-	// - ddev export-db
-	// - Or wp db export via ddev exec
+	if err := p.requireDDEV(); err != nil {
+		return nil, err
+	}
+
+	gzip := "--gzip=false"
+	if options.Compress {
+		gzip = "--gzip=true"
+	}
+
+	cmd := exec.Command("ddev", "export-db", gzip)
+	cmd.Dir = p.projectPath
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
 
-	return nil, fmt.Errorf("local database export not yet implemented - TODO")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start database export: %w", err)
+	}
+
+	return &commandReadCloser{ReadCloser: stdout, cmd: cmd, stderr: &stderr}, nil
 }
 
-// ImportDatabase imports a database to local
+// ImportDatabase imports a database to local via `ddev import-db`
 func (p *LocalProvider) ImportDatabase(site *provider.Site, data io.Reader, options provider.DatabaseImportOptions) error {
-	// TODO: Implement local database import via DDEV
-	// - ddev import-db
-	// - Or wp db import via ddev exec
+	if err := p.requireDDEV(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("ddev", "import-db")
+	cmd.Dir = p.projectPath
+	cmd.Stdin = data
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
 
-	return fmt.Errorf("local database import not yet implemented - TODO")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to import database: %w (stderr: %s)", err, stderr.String())
+	}
+
+	return nil
+}
+
+// requireDDEV returns an error if the project path or DDEV is unavailable
+func (p *LocalProvider) requireDDEV() error {
+	if p.projectPath == "" {
+		return fmt.Errorf("no local project path configured")
+	}
+	if _, err := exec.LookPath("ddev"); err != nil {
+		return fmt.Errorf("DDEV is not installed")
+	}
+	return nil
+}
+
+// commandReadCloser streams a command's stdout and waits for it on Close
+type commandReadCloser struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+}
+
+// Close waits for the command to exit and reports its failure, if any
+func (c *commandReadCloser) Close() error {
+	c.ReadCloser.Close()
+	if err := c.cmd.Wait(); err != nil {
+		return fmt.Errorf("database export failed: %w (stderr: %s)", err, c.stderr.String())
+	}
+	return nil
 }
 
 // GetDatabaseCredentials retrieves local database credentials
@@ -170,17 +235,17 @@ func (p *LocalProvider) GetDatabaseCredentials(site *provider.Site) (*provider.D
 
 // SyncFiles is not applicable for local-only provider
 func (p *LocalProvider) SyncFiles(site *provider.Site, destination string, options provider.SyncOptions) error {
-	return fmt.Errorf("file sync not applicable for local provider (no remote source)")
+	return provider.NewUnsupportedError("local", "file sync", "the local provider has no remote source")
 }
 
 // DownloadFile is not applicable for local-only provider
 func (p *LocalProvider) DownloadFile(site *provider.Site, remotePath string) (io.ReadCloser, error) {
-	return nil, fmt.Errorf("file download not applicable for local provider")
+	return nil, provider.NewUnsupportedError("local", "file download", "")
 }
 
 // UploadFile is not applicable for local-only provider
 func (p *LocalProvider) UploadFile(site *provider.Site, localPath, remotePath string) error {
-	return fmt.Errorf("file upload not applicable for local provider")
+	return provider.NewUnsupportedError("local", "file upload", "")
 }
 
 // ===== Environment Information =====
@@ -227,7 +292,7 @@ Use Cases:
 
 Future Enhancements:
 - [ ] DDEV integration for site listing
-- [ ] Local database export/import via DDEV
+- [x] Local database export/import via DDEV
 - [ ] Query actual PHP/MySQL/WordPress versions
 - [ ] Support for multiple local DDEV projects
 - [ ] Local backup management
@@ -0,0 +1,20 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/firecrown-media/stax/pkg/provider/providertest"
+)
+
+func TestConformance(t *testing.T) {
+	p := &LocalProvider{}
+	if err := p.Authenticate(map[string]string{"project_path": t.TempDir()}); err != nil {
+		t.Fatalf("Authenticate() failed: %v", err)
+	}
+
+	providertest.Run(t, p, providertest.Config{
+		SiteID:     "local",
+		SiteName:   "local-development",
+		SiteDomain: "localhost",
+	})
+}
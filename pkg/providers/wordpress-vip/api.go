@@ -0,0 +1,334 @@
+package wordpressvip
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultAPIURL is the default VIP GraphQL API endpoint
+	DefaultAPIURL = "https://api.wpvip.com/graphql"
+
+	// DefaultAPITimeout is the default HTTP client timeout for API requests
+	DefaultAPITimeout = 30 * time.Second
+)
+
+// APIClient handles WordPress VIP GraphQL API operations
+type APIClient struct {
+	apiURL     string
+	httpClient *http.Client
+	token      string
+}
+
+// NewAPIClient creates a new VIP GraphQL API client
+func NewAPIClient(token string) *APIClient {
+	return &APIClient{
+		apiURL: DefaultAPIURL,
+		httpClient: &http.Client{
+			Timeout: DefaultAPITimeout,
+		},
+		token: token,
+	}
+}
+
+// SetAPIURL overrides the GraphQL endpoint (used for testing and proxies)
+func (c *APIClient) SetAPIURL(apiURL string) {
+	c.apiURL = apiURL
+}
+
+// App represents a VIP application
+type App struct {
+	ID           int           `json:"id"`
+	Name         string        `json:"name"`
+	Repo         string        `json:"repo"`
+	Environments []Environment `json:"environments"`
+}
+
+// Environment represents a VIP application environment
+type Environment struct {
+	ID            int    `json:"id"`
+	AppID         int    `json:"appId"`
+	Name          string `json:"name"`
+	Type          string `json:"type"` // production, preprod, develop
+	Branch        string `json:"branch"`
+	IsMultisite   bool   `json:"isMultisite"`
+	PHPVersion    string `json:"phpVersion"`
+	WPVersion     string `json:"wpVersion"`
+	PrimaryDomain struct {
+		Name string `json:"name"`
+	} `json:"primaryDomain"`
+	Domains struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"domains"`
+}
+
+// DBBackup represents a VIP database backup
+type DBBackup struct {
+	ID        int    `json:"id"`
+	Type      string `json:"type"`
+	Size      int64  `json:"size"`
+	Filename  string `json:"filename"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// graphQLRequest is the body of a GraphQL request
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphQLResponse is the envelope of a GraphQL response
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+const environmentFields = `
+	id
+	appId
+	name
+	type
+	branch
+	isMultisite
+	phpVersion
+	wpVersion
+	primaryDomain { name }
+	domains { nodes { name } }
+`
+
+const queryMe = `query Me { me { id displayName } }`
+
+const queryApps = `query Apps($after: String) {
+	apps(first: 100, after: $after) {
+		pageInfo { hasNextPage endCursor }
+		nodes {
+			id
+			name
+			repo
+			environments {` + environmentFields + `}
+		}
+	}
+}`
+
+const queryApp = `query App($id: Int!) {
+	app(id: $id) {
+		id
+		name
+		repo
+		environments {` + environmentFields + `}
+	}
+}`
+
+const queryLatestBackup = `query LatestBackup($appId: Int!, $envId: Int!) {
+	app(id: $appId) {
+		environments(id: $envId) {
+			latestBackup { id type size filename createdAt }
+		}
+	}
+}`
+
+const mutationBackupCopyURL = `mutation BackupCopyURL($input: AppEnvironmentGenerateDBBackupCopyUrlInput!) {
+	generateDBBackupCopyUrl(input: $input) {
+		success
+		url
+	}
+}`
+
+// query executes a GraphQL query and decodes the data field into out
+func (c *APIClient) query(query string, variables map[string]interface{}, out interface{}) error {
+	body, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.apiURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("VIP API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("VIP API rejected the access token (HTTP %d)", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("VIP API error (%d): %s", resp.StatusCode, string(data))
+	}
+
+	var result graphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("VIP API error: %s", result.Errors[0].Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response data: %w", err)
+	}
+
+	return nil
+}
+
+// TestConnection verifies the access token by querying the current user
+func (c *APIClient) TestConnection() error {
+	var data struct {
+		Me *struct {
+			ID int `json:"id"`
+		} `json:"me"`
+	}
+
+	if err := c.query(queryMe, nil, &data); err != nil {
+		return err
+	}
+
+	if data.Me == nil {
+		return fmt.Errorf("VIP API did not return the current user")
+	}
+
+	return nil
+}
+
+// ListApps lists all applications visible to the access token, following
+// the cursor until the last page
+func (c *APIClient) ListApps() ([]App, error) {
+	var apps []App
+	variables := map[string]interface{}{}
+
+	for {
+		var data struct {
+			Apps struct {
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+				Nodes []App `json:"nodes"`
+			} `json:"apps"`
+		}
+
+		if err := c.query(queryApps, variables, &data); err != nil {
+			return nil, fmt.Errorf("failed to list apps: %w", err)
+		}
+		apps = append(apps, data.Apps.Nodes...)
+
+		pageInfo := data.Apps.PageInfo
+		// A repeated cursor would loop forever
+		if !pageInfo.HasNextPage || pageInfo.EndCursor == "" || pageInfo.EndCursor == variables["after"] {
+			return apps, nil
+		}
+		variables["after"] = pageInfo.EndCursor
+	}
+}
+
+// GetApp retrieves a single application and its environments
+func (c *APIClient) GetApp(appID int) (*App, error) {
+	var data struct {
+		App *App `json:"app"`
+	}
+
+	if err := c.query(queryApp, map[string]interface{}{"id": appID}, &data); err != nil {
+		return nil, fmt.Errorf("failed to get app %d: %w", appID, err)
+	}
+
+	if data.App == nil {
+		return nil, fmt.Errorf("app %d not found", appID)
+	}
+
+	return data.App, nil
+}
+
+// GetLatestBackup returns the most recent database backup for an environment
+func (c *APIClient) GetLatestBackup(appID, envID int) (*DBBackup, error) {
+	var data struct {
+		App *struct {
+			Environments []struct {
+				LatestBackup *DBBackup `json:"latestBackup"`
+			} `json:"environments"`
+		} `json:"app"`
+	}
+
+	vars := map[string]interface{}{"appId": appID, "envId": envID}
+	if err := c.query(queryLatestBackup, vars, &data); err != nil {
+		return nil, fmt.Errorf("failed to get latest backup: %w", err)
+	}
+
+	if data.App == nil || len(data.App.Environments) == 0 || data.App.Environments[0].LatestBackup == nil {
+		return nil, fmt.Errorf("no database backup available for environment %d", envID)
+	}
+
+	return data.App.Environments[0].LatestBackup, nil
+}
+
+// GenerateBackupCopyURL requests a signed download URL for a database backup
+func (c *APIClient) GenerateBackupCopyURL(appID, envID, backupID int) (string, error) {
+	var data struct {
+		GenerateDBBackupCopyURL struct {
+			Success bool   `json:"success"`
+			URL     string `json:"url"`
+		} `json:"generateDBBackupCopyUrl"`
+	}
+
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{
+			"id":            appID,
+			"environmentId": envID,
+			"backupId":      backupID,
+		},
+	}
+	if err := c.query(mutationBackupCopyURL, vars, &data); err != nil {
+		return "", fmt.Errorf("failed to generate backup download URL: %w", err)
+	}
+
+	if !data.GenerateDBBackupCopyURL.Success || data.GenerateDBBackupCopyURL.URL == "" {
+		return "", fmt.Errorf("VIP API did not return a backup download URL")
+	}
+
+	return data.GenerateDBBackupCopyURL.URL, nil
+}
+
+// Download opens a streaming GET request to a URL returned by the API
+// The caller is responsible for closing the returned body
+func (c *APIClient) Download(url string) (io.ReadCloser, error) {
+	// Downloads can be large, so they don't share the API timeout
+	client := &http.Client{Transport: c.httpClient.Transport}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download failed: HTTP %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+// environmentSiteID returns the site identifier for an environment
+func environmentSiteID(env Environment) string {
+	return strconv.Itoa(env.ID)
+}
@@ -0,0 +1,111 @@
+package wordpressvip
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/security"
+)
+
+// DefaultCLIBinary is the name of the VIP-CLI executable
+const DefaultCLIBinary = "vip"
+
+// CLI wraps VIP-CLI operations
+// VIP-CLI keeps its own session, so users must have run `vip login` first
+type CLI struct {
+	binary string
+}
+
+// NewCLI creates a new VIP-CLI wrapper
+func NewCLI() *CLI {
+	return &CLI{
+		binary: DefaultCLIBinary,
+	}
+}
+
+// IsInstalled checks if VIP-CLI is available
+func (c *CLI) IsInstalled() bool {
+	_, err := exec.LookPath(c.binary)
+	return err == nil
+}
+
+// alias returns the VIP-CLI environment alias (e.g. @1234.production)
+func alias(appID int, envType string) string {
+	return fmt.Sprintf("@%d.%s", appID, envType)
+}
+
+// command builds a VIP-CLI command
+func (c *CLI) command(args ...string) *exec.Cmd {
+	return exec.Command(c.binary, args...)
+}
+
+// wpArgs returns sanitized VIP-CLI arguments for running WP-CLI on an environment
+func wpArgs(appID int, envType string, args []string) ([]string, error) {
+	sanitizedArgs, err := security.SanitizeWPCLIArgs(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WP-CLI arguments: %w", err)
+	}
+
+	return append([]string{alias(appID, envType), "--yes", "--", "wp"}, sanitizedArgs...), nil
+}
+
+// WP runs a WP-CLI command through `vip @app.env -- wp` and returns the output
+func (c *CLI) WP(appID int, envType string, args []string) (string, error) {
+	vipArgs, err := wpArgs(appID, envType, args)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := c.command(vipArgs...)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("vip wp command failed: %w (stderr: %s)", err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
+// StreamWP runs a WP-CLI command through VIP-CLI and streams output to the given writers
+func (c *CLI) StreamWP(appID int, envType string, args []string, stdout, stderr io.Writer) error {
+	vipArgs, err := wpArgs(appID, envType, args)
+	if err != nil {
+		return err
+	}
+
+	cmd := c.command(vipArgs...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("vip wp command failed: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeURLs purges URLs from the VIP edge cache
+func (c *CLI) PurgeURLs(appID int, envType string, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	args := append([]string{alias(appID, envType), "cache", "purge-url"}, urls...)
+	cmd := c.command(args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("vip cache purge-url failed: %w (stderr: %s)", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -1,8 +1,12 @@
 package wordpressvip
 
 import (
+	"compress/gzip"
 	"fmt"
 	"io"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/firecrown-media/stax/pkg/provider"
 )
@@ -13,14 +17,31 @@ type WordPressVIPProvider struct {
 	org         string
 	environment string
 	accessToken string
-	// TODO: Add VIP API client
+	apiEndpoint string
+	apiClient   *APIClient
+	cli         *CLI
 }
 
+// Ensure WordPressVIPProvider implements all required interfaces
+var (
+	_ provider.Provider       = (*WordPressVIPProvider)(nil)
+	_ provider.RemoteExecutor = (*WordPressVIPProvider)(nil)
+	_ provider.MediaManager   = (*WordPressVIPProvider)(nil)
+)
+
 func init() {
 	// Register WordPress VIP provider
 	provider.RegisterProvider("wordpress-vip", &WordPressVIPProvider{})
 }
 
+// NewWordPressVIPProvider creates a VIP provider that talks to the given API endpoint
+// An empty apiURL uses the public VIP GraphQL API
+func NewWordPressVIPProvider(apiURL string) *WordPressVIPProvider {
+	return &WordPressVIPProvider{
+		apiEndpoint: apiURL,
+	}
+}
+
 // Name returns the provider's unique identifier
 func (p *WordPressVIPProvider) Name() string {
 	return "wordpress-vip"
@@ -38,14 +59,14 @@ func (p *WordPressVIPProvider) Capabilities() provider.ProviderCapabilities {
 		SiteManagement:  true,
 		DatabaseExport:  true,
 		DatabaseImport:  false, // VIP uses controlled imports
-		FileSync:        true,
-		Deployment:      true,  // Git-based deployments
+		FileSync:        false, // Code is deployed from Git, media is served from the VIP file service
+		Deployment:      false, // Git-based deployments not yet implemented
 		Environments:    true,  // production, develop, preprod
-		Backups:         true,  // Managed backups
-		RemoteExecution: true,  // VIP-CLI
-		MediaManagement: true,  // Photon CDN
+		Backups:         false, // Managed backups not yet exposed
+		RemoteExecution: true,  // WP-CLI via VIP-CLI
+		MediaManagement: true,  // VIP file service / edge cache
 		SSHAccess:       false, // VIP doesn't provide direct SSH
-		APIAccess:       true,  // VIP Dashboard API
+		APIAccess:       true,  // VIP GraphQL API
 		Scaling:         true,  // Automatic scaling
 		Monitoring:      true,  // Built-in monitoring
 		Logging:         true,  // Centralized logging
@@ -55,138 +76,545 @@ func (p *WordPressVIPProvider) Capabilities() provider.ProviderCapabilities {
 // ===== Authentication & Setup =====
 
 // ValidateCredentials validates WordPress VIP credentials
+// Expected credentials:
+// - access_token (VIP API token)
+// - app_id (VIP application ID, optional - limits operations to one app)
+// - org (VIP organization slug, optional)
+// - environment (production, develop, preprod, optional)
 func (p *WordPressVIPProvider) ValidateCredentials(credentials map[string]string) error {
-	// TODO: Implement VIP credential validation
-	// Expected credentials:
-	// - access_token (VIP Dashboard API token)
-	// - app_id (VIP application ID)
-	// - org (VIP organization slug)
-	// - environment (production, develop, preprod)
+	if credentials["access_token"] == "" {
+		return fmt.Errorf("missing required credential: access_token")
+	}
+
+	if appID := credentials["app_id"]; appID != "" {
+		if _, err := strconv.Atoi(appID); err != nil {
+			return fmt.Errorf("app_id must be numeric, got: %s", appID)
+		}
+	}
 
-	return fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	return nil
 }
 
 // Authenticate authenticates with WordPress VIP
 func (p *WordPressVIPProvider) Authenticate(credentials map[string]string) error {
-	// TODO: Implement VIP authentication
-	// - Initialize VIP API client with access token
-	// - Verify app access
-	// - Verify organization membership
+	if err := p.ValidateCredentials(credentials); err != nil {
+		return err
+	}
 
-	return fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	p.accessToken = credentials["access_token"]
+	p.org = credentials["org"]
+	p.environment = credentials["environment"]
+	p.appID = 0
+	if appID := credentials["app_id"]; appID != "" {
+		p.appID, _ = strconv.Atoi(appID)
+	}
+
+	// api_url overrides the endpoint (e.g. for a proxy)
+	if apiURL := credentials["api_url"]; apiURL != "" {
+		p.apiEndpoint = apiURL
+	}
+
+	p.apiClient = NewAPIClient(p.accessToken)
+	if p.apiEndpoint != "" {
+		p.apiClient.SetAPIURL(p.apiEndpoint)
+	}
+	p.cli = NewCLI()
+
+	return nil
 }
 
 // TestConnection tests the connection to WordPress VIP
 func (p *WordPressVIPProvider) TestConnection() error {
-	// TODO: Implement connection test
-	// - Test VIP API connectivity
-	// - Verify app exists and is accessible
+	if err := p.requireAuth(); err != nil {
+		return err
+	}
+
+	if err := p.apiClient.TestConnection(); err != nil {
+		return fmt.Errorf("API connection test failed: %w", err)
+	}
 
-	return fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	if p.appID != 0 {
+		if _, err := p.apiClient.GetApp(p.appID); err != nil {
+			return fmt.Errorf("app %d not accessible: %w", p.appID, err)
+		}
+	}
+
+	return nil
+}
+
+// requireAuth returns an error if Authenticate has not been called
+func (p *WordPressVIPProvider) requireAuth() error {
+	if p.apiClient == nil || p.accessToken == "" {
+		return fmt.Errorf("not authenticated")
+	}
+	return nil
+}
+
+// requireCLI returns an error if VIP-CLI is not available
+func (p *WordPressVIPProvider) requireCLI() error {
+	if err := p.requireAuth(); err != nil {
+		return err
+	}
+	if !p.cli.IsInstalled() {
+		return fmt.Errorf("VIP-CLI not found in PATH (install with: npm install -g @automattic/vip)")
+	}
+	return nil
 }
 
 // ===== Site Management =====
 
-// ListSites lists VIP applications
+// ListSites lists VIP application environments
+// Each environment of an app is returned as a separate site
 func (p *WordPressVIPProvider) ListSites() ([]provider.Site, error) {
-	// TODO: Implement site listing
-	// - List all VIP apps in organization
-	// - Get environments for each app
+	if err := p.requireAuth(); err != nil {
+		return nil, err
+	}
+
+	var apps []App
+	if p.appID != 0 {
+		app, err := p.apiClient.GetApp(p.appID)
+		if err != nil {
+			return nil, err
+		}
+		apps = []App{*app}
+	} else {
+		var err error
+		apps, err = p.apiClient.ListApps()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sites := []provider.Site{}
+	for _, app := range apps {
+		for _, env := range app.Environments {
+			if p.environment != "" && env.Type != p.environment {
+				continue
+			}
+			sites = append(sites, environmentToSite(app, env))
+		}
+	}
 
-	return nil, fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	return sites, nil
 }
 
-// GetSite retrieves information about a specific VIP app
+// GetSite retrieves information about a specific VIP environment
+// identifier can be the environment ID, "app.env" name, or a domain
 func (p *WordPressVIPProvider) GetSite(identifier string) (*provider.Site, error) {
-	// TODO: Implement site retrieval
-	// identifier could be app ID or app slug
+	sites, err := p.ListSites()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sites {
+		site := &sites[i]
+		if site.ID == identifier || site.Name == identifier || site.PrimaryDomain == identifier {
+			return site, nil
+		}
+		for _, domain := range strings.Split(site.Metadata["domains"], ",") {
+			if domain != "" && domain == identifier {
+				return site, nil
+			}
+		}
+	}
 
-	return nil, fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	return nil, fmt.Errorf("site not found: %s", identifier)
 }
 
 // GetSiteMetadata retrieves detailed metadata
 func (p *WordPressVIPProvider) GetSiteMetadata(site *provider.Site) (*provider.SiteMetadata, error) {
-	// TODO: Implement metadata retrieval
-	// - Query VIP API for app details
-	// - Get PHP version from app config
-	// - Get WordPress version
-	// - Get resource usage stats
+	current, err := p.GetSite(site.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := []string{}
+	for _, domain := range strings.Split(current.Metadata["domains"], ",") {
+		if domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+
+	return &provider.SiteMetadata{
+		Site:             current,
+		PHPVersion:       current.Metadata["php_version"],
+		WordPressVersion: current.Metadata["wordpress_version"],
+		Domains:          domains,
+		Features:         []string{"ssl", "cdn", "edge-cache"}, // no "backups", the provider does not manage them
+	}, nil
+}
+
+// environmentToSite converts a VIP app environment into a provider site
+func environmentToSite(app App, env Environment) provider.Site {
+	domains := make([]string, 0, len(env.Domains.Nodes))
+	for _, domain := range env.Domains.Nodes {
+		domains = append(domains, domain.Name)
+	}
+
+	return provider.Site{
+		ID:            environmentSiteID(env),
+		Name:          fmt.Sprintf("%s.%s", app.Name, env.Type),
+		PrimaryDomain: env.PrimaryDomain.Name,
+		Environment:   env.Type,
+		Status:        "active",
+		Provider:      "wordpress-vip",
+		Metadata: map[string]string{
+			"app_id":            strconv.Itoa(app.ID),
+			"app_name":          app.Name,
+			"environment_id":    environmentSiteID(env),
+			"branch":            env.Branch,
+			"php_version":       env.PHPVersion,
+			"wordpress_version": env.WPVersion,
+			"multisite":         strconv.FormatBool(env.IsMultisite),
+			"domains":           strings.Join(domains, ","),
+		},
+	}
+}
+
+// siteIDs extracts the numeric app and environment IDs from a site
+func siteIDs(site *provider.Site) (int, int, error) {
+	if site == nil {
+		return 0, 0, fmt.Errorf("site is required")
+	}
+
+	appID, err := strconv.Atoi(site.Metadata["app_id"])
+	if err != nil {
+		return 0, 0, fmt.Errorf("site %s has no VIP app ID", site.Name)
+	}
 
-	return nil, fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	envID, err := strconv.Atoi(site.ID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("site %s has an invalid environment ID", site.Name)
+	}
+
+	return appID, envID, nil
 }
 
 // ===== Database Operations =====
 
 // ExportDatabase exports the database
+// VIP doesn't allow live dumps, so this streams the latest backup through
+// the same export/download flow `vip export sql` uses
+// The backup is a full dump, so tables cannot be left out of it
 func (p *WordPressVIPProvider) ExportDatabase(site *provider.Site, options provider.DatabaseExportOptions) (io.ReadCloser, error) {
-	// TODO: Implement database export
-	// - Use VIP-CLI: vip @app.env db export
-	// - Or use VIP Dashboard export feature
+	if len(options.ExcludeTables) > 0 || options.SkipLogs || options.SkipTransients || options.SkipSpam {
+		return nil, provider.NewUnsupportedError("wordpress-vip", "excluding tables or data from a database export",
+			"export the full database and remove the data locally, e.g. with wp transient delete --all")
+	}
 
-	return nil, fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	if err := p.requireAuth(); err != nil {
+		return nil, err
+	}
+
+	appID, envID, err := siteIDs(site)
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := p.apiClient.GetLatestBackup(appID, envID)
+	if err != nil {
+		return nil, err
+	}
+
+	downloadURL, err := p.apiClient.GenerateBackupCopyURL(appID, envID, backup.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// VIP backups are always gzip-compressed SQL
+	body, err := p.apiClient.Download(downloadURL)
+	if err != nil || options.Compress {
+		return body, err
+	}
+
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("failed to decompress backup: %w", err)
+	}
+	return &gzipReadCloser{Reader: gz, body: body}, nil
+}
+
+// gzipReadCloser decompresses a download and closes it with the reader
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (r *gzipReadCloser) Close() error {
+	r.Reader.Close()
+	return r.body.Close()
 }
 
 // ImportDatabase imports a database
 func (p *WordPressVIPProvider) ImportDatabase(site *provider.Site, data io.Reader, options provider.DatabaseImportOptions) error {
-	// TODO: Implement database import
 	// VIP requires database imports through support tickets for security
-
-	return fmt.Errorf("WordPress VIP database imports must be requested through VIP support")
+	return provider.NewUnsupportedError("wordpress-vip", "database import", "request imports through VIP support: https://docs.wpvip.com/databases/import-a-database/")
 }
 
 // GetDatabaseCredentials retrieves database credentials
 func (p *WordPressVIPProvider) GetDatabaseCredentials(site *provider.Site) (*provider.DatabaseCredentials, error) {
 	// VIP doesn't expose database credentials directly
-	return nil, fmt.Errorf("database credentials not exposed by WordPress VIP")
+	return nil, provider.NewUnsupportedError("wordpress-vip", "database credentials", "run queries with: vip @app.env -- wp db query")
 }
 
 // ===== File Operations =====
 
 // SyncFiles synchronizes files
 func (p *WordPressVIPProvider) SyncFiles(site *provider.Site, destination string, options provider.SyncOptions) error {
-	// TODO: Implement file sync
-	// VIP uses Git for code deployment
-	// Media files are managed separately via VIP Dashboard
-
-	return fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	// VIP uses Git for code deployment and serves media from its file service
+	return provider.NewUnsupportedError("wordpress-vip", "file sync", "clone the Git repository and use the media proxy for uploads")
 }
 
-// DownloadFile downloads a single file
+// DownloadFile downloads a single media file from the VIP file service
+// remotePath is relative to the site root (e.g. wp-content/uploads/2024/01/image.jpg)
 func (p *WordPressVIPProvider) DownloadFile(site *provider.Site, remotePath string) (io.ReadCloser, error) {
-	// TODO: Implement file download
-	// - Code files: clone from Git
-	// - Media files: download from Photon CDN
+	if err := p.requireAuth(); err != nil {
+		return nil, err
+	}
 
-	return nil, fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	mediaURL, err := p.fileURL(site, remotePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.apiClient.Download(mediaURL)
 }
 
 // UploadFile uploads a single file
 func (p *WordPressVIPProvider) UploadFile(site *provider.Site, localPath, remotePath string) error {
-	// TODO: Implement file upload
-	// - Code: Git push
-	// - Media: VIP Dashboard upload or API
+	return provider.NewUnsupportedError("wordpress-vip", "file upload", "deploy code by pushing to the environment's Git branch")
+}
+
+// fileURL builds the public URL for a file on a site's primary domain
+func (p *WordPressVIPProvider) fileURL(site *provider.Site, remotePath string) (string, error) {
+	if site == nil || site.PrimaryDomain == "" {
+		return "", fmt.Errorf("site has no primary domain")
+	}
+
+	cleanPath := strings.TrimPrefix(remotePath, "/")
+	if cleanPath == "" || strings.Contains(cleanPath, "..") {
+		return "", fmt.Errorf("invalid remote path: %s", remotePath)
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   site.PrimaryDomain,
+		Path:   "/" + cleanPath,
+	}
 
-	return fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	return u.String(), nil
 }
 
 // ===== Environment Information =====
 
 // GetPHPVersion returns the PHP version
 func (p *WordPressVIPProvider) GetPHPVersion(site *provider.Site) (string, error) {
-	// TODO: Query VIP app config
-	return "", fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	if site.Metadata["php_version"] != "" {
+		return site.Metadata["php_version"], nil
+	}
+
+	metadata, err := p.GetSiteMetadata(site)
+	if err != nil {
+		return "", err
+	}
+
+	return metadata.PHPVersion, nil
 }
 
 // GetMySQLVersion returns the MySQL version
 func (p *WordPressVIPProvider) GetMySQLVersion(site *provider.Site) (string, error) {
-	// VIP uses managed MariaDB, version may not be exposed
-	return "", fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	if err := p.requireCLI(); err != nil {
+		return "", err
+	}
+
+	appID, _, err := siteIDs(site)
+	if err != nil {
+		return "", err
+	}
+
+	// VIP uses managed MariaDB and doesn't expose the version via the API
+	output, err := p.cli.WP(appID, site.Environment, []string{"db", "query", "SELECT VERSION()", "--skip-column-names"})
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(output), nil
 }
 
 // GetWordPressVersion returns the WordPress version
 func (p *WordPressVIPProvider) GetWordPressVersion(site *provider.Site) (string, error) {
-	// TODO: Use VIP-CLI or query via WP-CLI on VIP
-	return "", fmt.Errorf("WordPress VIP provider not yet implemented - TODO")
+	if site.Metadata["wordpress_version"] != "" {
+		return site.Metadata["wordpress_version"], nil
+	}
+
+	metadata, err := p.GetSiteMetadata(site)
+	if err != nil {
+		return "", err
+	}
+
+	return metadata.WordPressVersion, nil
+}
+
+// ===== RemoteExecutor Interface =====
+
+// ExecuteCommand executes a shell command
+func (p *WordPressVIPProvider) ExecuteCommand(site *provider.Site, command string) (string, error) {
+	args, err := wpCommandArgs(command)
+	if err != nil {
+		return "", err
+	}
+
+	return p.ExecuteWPCLI(site, args)
+}
+
+// ExecuteWPCLI executes a WP-CLI command via `vip @app.env -- wp`
+func (p *WordPressVIPProvider) ExecuteWPCLI(site *provider.Site, args []string) (string, error) {
+	if err := p.requireCLI(); err != nil {
+		return "", err
+	}
+
+	appID, _, err := siteIDs(site)
+	if err != nil {
+		return "", err
+	}
+
+	return p.cli.WP(appID, site.Environment, args)
+}
+
+// StreamCommand executes a command and streams output
+func (p *WordPressVIPProvider) StreamCommand(site *provider.Site, command string, stdout, stderr io.Writer) error {
+	args, err := wpCommandArgs(command)
+	if err != nil {
+		return err
+	}
+
+	if err := p.requireCLI(); err != nil {
+		return err
+	}
+
+	appID, _, err := siteIDs(site)
+	if err != nil {
+		return err
+	}
+
+	return p.cli.StreamWP(appID, site.Environment, args, stdout, stderr)
+}
+
+// wpCommandArgs splits a "wp ..." command into WP-CLI arguments
+// VIP has no shell access, so only WP-CLI commands can be executed remotely
+func wpCommandArgs(command string) ([]string, error) {
+	fields, err := splitShellWords(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command: %w", err)
+	}
+	if len(fields) == 0 || fields[0] != "wp" {
+		return nil, provider.NewUnsupportedError("wordpress-vip", "shell command execution", "only WP-CLI commands can be run")
+	}
+
+	return fields[1:], nil
+}
+
+// splitShellWords splits a command into arguments the way a POSIX shell
+// does, keeping quoted strings together
+// Only quoting and escaping are interpreted; there is no expansion
+func splitShellWords(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune("$`\"\\", runes[i+1]):
+				i++
+				word.WriteRune(runes[i])
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// ===== MediaManager Interface =====
+
+// GetMediaURL returns the URL media is served from for a site
+func (p *WordPressVIPProvider) GetMediaURL(site *provider.Site) (string, error) {
+	return p.fileURL(site, "wp-content/uploads")
+}
+
+// SupportsRemoteMedia indicates if provider supports remote media serving
+func (p *WordPressVIPProvider) SupportsRemoteMedia() bool {
+	return true
+}
+
+// ConfigureMedia configures media settings
+func (p *WordPressVIPProvider) ConfigureMedia(site *provider.Site, options provider.MediaOptions) error {
+	// The VIP file service and edge cache are managed by VIP
+	return provider.NewUnsupportedError("wordpress-vip", "media configuration", "media settings are managed by WordPress VIP")
+}
+
+// PurgeMediaCache purges media URLs from the VIP edge cache
+func (p *WordPressVIPProvider) PurgeMediaCache(site *provider.Site, paths []string) error {
+	if err := p.requireCLI(); err != nil {
+		return err
+	}
+
+	appID, _, err := siteIDs(site)
+	if err != nil {
+		return err
+	}
+
+	urls := make([]string, 0, len(paths))
+	for _, path := range paths {
+		fileURL, err := p.fileURL(site, path)
+		if err != nil {
+			return err
+		}
+		urls = append(urls, fileURL)
+	}
+
+	return p.cli.PurgeURLs(appID, site.Environment, urls)
 }
 
 /*
@@ -195,19 +623,19 @@ IMPLEMENTATION ROADMAP
 =========================
 
 Phase 1: VIP API Integration
-- [ ] VIP Dashboard API client
-- [ ] Authentication with access tokens
-- [ ] App listing and details
+- [x] VIP GraphQL API client
+- [x] Authentication with access tokens
+- [x] App listing and details
 - [ ] Organization management
 
 Phase 2: VIP-CLI Integration
-- [ ] Detect/install VIP-CLI locally
-- [ ] Wrapper for VIP-CLI commands
-- [ ] Environment selection (production, develop, preprod)
-- [ ] WP-CLI command execution via VIP-CLI
+- [x] Detect VIP-CLI locally
+- [x] Wrapper for VIP-CLI commands
+- [x] Environment selection (production, develop, preprod)
+- [x] WP-CLI command execution via VIP-CLI
 
 Phase 3: Database Operations
-- [ ] Database export via VIP-CLI
+- [x] Database export via backup copy download
 - [ ] Database migration coordination with VIP support
 - [ ] Search/replace operations
 
@@ -218,8 +646,8 @@ Phase 4: Deployment & Git
 - [ ] Deploy status monitoring
 
 Phase 5: Advanced Features
-- [ ] Photon CDN integration
-- [ ] VIP Cache purging
+- [x] Media served from the VIP file service
+- [x] VIP Cache purging
 - [ ] Query Monitor integration
 - [ ] Log streaming
 - [ ] Performance monitoring
@@ -252,7 +680,7 @@ VIP Environment Mapping:
 - develop: Development environment (develop branch)
 
 References:
-- VIP Dashboard API: https://docs.wpvip.com/vip-dashboard-api/
+- VIP GraphQL API: https://api.wpvip.com/graphql
 - VIP-CLI: https://docs.wpvip.com/vip-cli/
 - VIP Go: https://docs.wpvip.com/
 */
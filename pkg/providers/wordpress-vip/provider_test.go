@@ -0,0 +1,413 @@
+package wordpressvip
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/firecrown-media/stax/pkg/provider"
+	"github.com/firecrown-media/stax/pkg/provider/providertest"
+)
+
+// fakeVIPAPI is an in-process stand-in for the VIP GraphQL API and file service
+type fakeVIPAPI struct {
+	server *httptest.Server
+	token  string
+}
+
+func newFakeVIPAPI(t *testing.T) *fakeVIPAPI {
+	t.Helper()
+
+	f := &fakeVIPAPI{token: "test-token"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", f.handleGraphQL)
+	mux.HandleFunc("/backups/db.sql.gz", func(w http.ResponseWriter, r *http.Request) {
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte("-- VIP database dump"))
+		gz.Close()
+	})
+	mux.HandleFunc("/wp-content/uploads/2024/01/image.jpg", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("jpeg-bytes"))
+	})
+
+	f.server = httptest.NewTLSServer(mux)
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+func (f *fakeVIPAPI) host() string {
+	return strings.TrimPrefix(f.server.URL, "https://")
+}
+
+func (f *fakeVIPAPI) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+f.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	environments := []map[string]interface{}{
+		{
+			"id": 501, "appId": 42, "name": "production", "type": "production", "branch": "master",
+			"phpVersion": "8.2", "wpVersion": "6.5.2",
+			"primaryDomain": map[string]string{"name": f.host()},
+			"domains":       map[string]interface{}{"nodes": []map[string]string{{"name": f.host()}, {"name": "www.example.com"}}},
+		},
+		{
+			"id": 502, "appId": 42, "name": "preprod", "type": "preprod", "branch": "preprod",
+			"phpVersion": "8.3", "wpVersion": "6.6",
+			"primaryDomain": map[string]string{"name": "example-preprod.go-vip.net"},
+			"domains":       map[string]interface{}{"nodes": []map[string]string{}},
+		},
+	}
+	app := map[string]interface{}{"id": 42, "name": "example", "repo": "wpcomvip/example", "environments": environments}
+
+	var data interface{}
+	switch {
+	case strings.Contains(req.Query, "query Me"):
+		data = map[string]interface{}{"me": map[string]interface{}{"id": 1, "displayName": "Test"}}
+	case strings.Contains(req.Query, "query Apps"):
+		// Two pages; the second holds an app without environments
+		if after, _ := req.Variables["after"].(string); after == "page-2" {
+			data = map[string]interface{}{"apps": map[string]interface{}{
+				"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": "page-2"},
+				"nodes":    []interface{}{map[string]interface{}{"id": 43, "name": "empty", "environments": []interface{}{}}},
+			}}
+		} else {
+			data = map[string]interface{}{"apps": map[string]interface{}{
+				"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": "page-2"},
+				"nodes":    []interface{}{app},
+			}}
+		}
+	case strings.Contains(req.Query, "query LatestBackup"):
+		data = map[string]interface{}{"app": map[string]interface{}{"environments": []interface{}{
+			map[string]interface{}{"latestBackup": map[string]interface{}{"id": 9001, "type": "db", "filename": "db.sql.gz"}},
+		}}}
+	case strings.Contains(req.Query, "mutation BackupCopyURL"):
+		data = map[string]interface{}{"generateDBBackupCopyUrl": map[string]interface{}{
+			"success": true, "url": f.server.URL + "/backups/db.sql.gz",
+		}}
+	case strings.Contains(req.Query, "query App"):
+		if id, _ := req.Variables["id"].(float64); id != 42 {
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"app": nil}})
+			return
+		}
+		data = map[string]interface{}{"app": app}
+	default:
+		json.NewEncoder(w).Encode(map[string]interface{}{"errors": []map[string]string{{"message": "unknown query"}}})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+// newTestProvider returns an authenticated provider wired to the fake API
+func newTestProvider(t *testing.T, f *fakeVIPAPI, credentials map[string]string) *WordPressVIPProvider {
+	t.Helper()
+
+	p := NewWordPressVIPProvider(f.server.URL + "/graphql")
+	if credentials == nil {
+		credentials = map[string]string{}
+	}
+	if _, ok := credentials["access_token"]; !ok {
+		credentials["access_token"] = f.token
+	}
+
+	if err := p.Authenticate(credentials); err != nil {
+		t.Fatalf("Authenticate() failed: %v", err)
+	}
+	p.apiClient.httpClient = f.server.Client()
+
+	return p
+}
+
+// mustSite looks up a site by identifier or fails the test
+func mustSite(t *testing.T, p *WordPressVIPProvider, identifier string) *provider.Site {
+	t.Helper()
+
+	site, err := p.GetSite(identifier)
+	if err != nil {
+		t.Fatalf("GetSite(%q) failed: %v", identifier, err)
+	}
+
+	return site
+}
+
+func TestValidateCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials map[string]string
+		wantErr     bool
+	}{
+		{"token only", map[string]string{"access_token": "abc"}, false},
+		{"token and app", map[string]string{"access_token": "abc", "app_id": "42"}, false},
+		{"missing token", map[string]string{"app_id": "42"}, true},
+		{"non-numeric app", map[string]string{"access_token": "abc", "app_id": "example"}, true},
+	}
+
+	p := &WordPressVIPProvider{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.ValidateCredentials(tt.credentials)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTestConnection(t *testing.T) {
+	f := newFakeVIPAPI(t)
+
+	p := newTestProvider(t, f, map[string]string{"app_id": "42"})
+	if err := p.TestConnection(); err != nil {
+		t.Errorf("TestConnection() failed: %v", err)
+	}
+
+	bad := newTestProvider(t, f, map[string]string{"access_token": "wrong"})
+	if err := bad.TestConnection(); err == nil {
+		t.Error("expected TestConnection() to fail with an invalid token")
+	}
+
+	missing := newTestProvider(t, f, map[string]string{"app_id": "7"})
+	if err := missing.TestConnection(); err == nil {
+		t.Error("expected TestConnection() to fail for an inaccessible app")
+	}
+}
+
+func TestListSites(t *testing.T) {
+	f := newFakeVIPAPI(t)
+	p := newTestProvider(t, f, nil)
+
+	sites, err := p.ListSites()
+	if err != nil {
+		t.Fatalf("ListSites() failed: %v", err)
+	}
+
+	if len(sites) != 2 {
+		t.Fatalf("expected 2 sites, got %d", len(sites))
+	}
+
+	if sites[0].ID != "501" || sites[0].Name != "example.production" || sites[0].Environment != "production" {
+		t.Errorf("unexpected first site: %+v", sites[0])
+	}
+	if sites[0].Metadata["app_id"] != "42" {
+		t.Errorf("expected app_id metadata 42, got %q", sites[0].Metadata["app_id"])
+	}
+
+	filtered := newTestProvider(t, f, map[string]string{"app_id": "42", "environment": "preprod"})
+	sites, err = filtered.ListSites()
+	if err != nil {
+		t.Fatalf("ListSites() failed: %v", err)
+	}
+	if len(sites) != 1 || sites[0].Environment != "preprod" {
+		t.Errorf("expected only the preprod environment, got %+v", sites)
+	}
+}
+
+func TestListAppsPagination(t *testing.T) {
+	f := newFakeVIPAPI(t)
+	p := newTestProvider(t, f, nil)
+
+	apps, err := p.apiClient.ListApps()
+	if err != nil {
+		t.Fatalf("ListApps() failed: %v", err)
+	}
+	if len(apps) != 2 || apps[0].ID != 42 || apps[1].ID != 43 {
+		t.Errorf("expected apps 42 and 43 from both pages, got %+v", apps)
+	}
+}
+
+func TestGetSite(t *testing.T) {
+	f := newFakeVIPAPI(t)
+	p := newTestProvider(t, f, nil)
+
+	for _, identifier := range []string{"501", "example.production", f.host(), "www.example.com"} {
+		site, err := p.GetSite(identifier)
+		if err != nil {
+			t.Errorf("GetSite(%q) failed: %v", identifier, err)
+			continue
+		}
+		if site.ID != "501" {
+			t.Errorf("GetSite(%q) returned site %s, want 501", identifier, site.ID)
+		}
+	}
+
+	if _, err := p.GetSite("does-not-exist"); err == nil {
+		t.Error("expected error for unknown site")
+	}
+}
+
+func TestGetSiteMetadata(t *testing.T) {
+	f := newFakeVIPAPI(t)
+	p := newTestProvider(t, f, nil)
+
+	metadata, err := p.GetSiteMetadata(mustSite(t, p, "502"))
+	if err != nil {
+		t.Fatalf("GetSiteMetadata() failed: %v", err)
+	}
+
+	if metadata.PHPVersion != "8.3" || metadata.WordPressVersion != "6.6" {
+		t.Errorf("unexpected versions: php=%s wp=%s", metadata.PHPVersion, metadata.WordPressVersion)
+	}
+
+	// Features must not claim what the capabilities deny
+	for _, feature := range metadata.Features {
+		if feature == "backups" && !p.Capabilities().Backups {
+			t.Errorf("features list backups, but the Backups capability is false")
+		}
+	}
+}
+
+func TestExportDatabase(t *testing.T) {
+	f := newFakeVIPAPI(t)
+	p := newTestProvider(t, f, nil)
+
+	reader, err := p.ExportDatabase(mustSite(t, p, "501"), provider.DatabaseExportOptions{})
+	if err != nil {
+		t.Fatalf("ExportDatabase() failed: %v", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+
+	if string(data) != "-- VIP database dump" {
+		t.Errorf("unexpected export contents: %q", string(data))
+	}
+
+	// Compress keeps the backup gzipped
+	reader, err = p.ExportDatabase(mustSite(t, p, "501"), provider.DatabaseExportOptions{Compress: true})
+	if err != nil {
+		t.Fatalf("ExportDatabase() failed: %v", err)
+	}
+	gz, err := gzip.NewReader(reader)
+	if err != nil {
+		t.Fatalf("compressed export is not gzip: %v", err)
+	}
+	data, _ = io.ReadAll(gz)
+	reader.Close()
+	if string(data) != "-- VIP database dump" {
+		t.Errorf("unexpected compressed export contents: %q", string(data))
+	}
+
+	// The backup is a full dump, so exclusions are unsupported
+	for _, options := range []provider.DatabaseExportOptions{
+		{ExcludeTables: []string{"wp_options"}},
+		{SkipTransients: true},
+	} {
+		if _, err := p.ExportDatabase(mustSite(t, p, "501"), options); !provider.IsUnsupported(err) {
+			t.Errorf("ExportDatabase(%+v) = %v, want an unsupported error", options, err)
+		}
+	}
+}
+
+func TestDownloadFileAndMediaURL(t *testing.T) {
+	f := newFakeVIPAPI(t)
+	p := newTestProvider(t, f, nil)
+	site := mustSite(t, p, "501")
+
+	reader, err := p.DownloadFile(site, "/wp-content/uploads/2024/01/image.jpg")
+	if err != nil {
+		t.Fatalf("DownloadFile() failed: %v", err)
+	}
+	defer reader.Close()
+
+	data, _ := io.ReadAll(reader)
+	if string(data) != "jpeg-bytes" {
+		t.Errorf("unexpected file contents: %q", string(data))
+	}
+
+	if _, err := p.DownloadFile(site, "../wp-config.php"); err == nil {
+		t.Error("expected error for path traversal")
+	}
+
+	mediaURL, err := p.GetMediaURL(site)
+	if err != nil {
+		t.Fatalf("GetMediaURL() failed: %v", err)
+	}
+	if mediaURL != "https://"+f.host()+"/wp-content/uploads" {
+		t.Errorf("unexpected media URL: %s", mediaURL)
+	}
+}
+
+func TestWPCommandArgs(t *testing.T) {
+	args, err := wpCommandArgs("wp plugin list --status=active")
+	if err != nil {
+		t.Fatalf("wpCommandArgs() failed: %v", err)
+	}
+	if strings.Join(args, " ") != "plugin list --status=active" {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	if _, err := wpCommandArgs("ls -la"); err == nil {
+		t.Error("expected error for non WP-CLI command")
+	}
+
+	// Quoted arguments stay together
+	args, err = wpCommandArgs(`wp post create --post_title="Hello \"World\"" --post_content='<p>$1 a\b</p>' a\ b ""`)
+	if err != nil {
+		t.Fatalf("wpCommandArgs() failed: %v", err)
+	}
+	want := []string{"post", "create", `--post_title=Hello "World"`, `--post_content=<p>$1 a\b</p>`, "a b", ""}
+	if strings.Join(args, "|") != strings.Join(want, "|") {
+		t.Errorf("wpCommandArgs() = %q, want %q", args, want)
+	}
+
+	if _, err := wpCommandArgs(`wp option update blogname "unterminated`); err == nil {
+		t.Error("expected error for an unterminated quote")
+	}
+}
+
+func TestWPArgs(t *testing.T) {
+	args, err := wpArgs(42, "production", []string{"option", "get", "home"})
+	if err != nil {
+		t.Fatalf("wpArgs() failed: %v", err)
+	}
+
+	want := "@42.production --yes -- wp option get home"
+	if strings.Join(args, " ") != want {
+		t.Errorf("wpArgs() = %q, want %q", strings.Join(args, " "), want)
+	}
+
+	if _, err := wpArgs(42, "production", []string{"eval", "$(rm -rf /)"}); err == nil {
+		t.Error("expected error for command substitution")
+	}
+}
+
+func TestNotAuthenticated(t *testing.T) {
+	p := &WordPressVIPProvider{}
+
+	if _, err := p.ListSites(); err == nil {
+		t.Error("expected ListSites() to fail when not authenticated")
+	}
+	if err := p.TestConnection(); err == nil {
+		t.Error("expected TestConnection() to fail when not authenticated")
+	}
+}
+
+func TestConformance(t *testing.T) {
+	f := newFakeVIPAPI(t)
+	p := newTestProvider(t, f, nil)
+
+	providertest.Run(t, p, providertest.Config{
+		SiteID:     "501",
+		SiteName:   "example.production",
+		SiteDomain: "www.example.com",
+		NewUnauthenticated: func() provider.Provider {
+			return NewWordPressVIPProvider(f.server.URL + "/graphql")
+		},
+	})
+}
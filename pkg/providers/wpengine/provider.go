@@ -9,10 +9,33 @@ import (
 	"github.com/firecrown-media/stax/pkg/wpengine"
 )
 
+// APIClient is the subset of the WPEngine API client used by the provider
+type APIClient interface {
+	TestConnection() error
+	ListInstalls() ([]wpengine.Install, error)
+	GetInstall(installID string) (*wpengine.InstallDetails, error)
+	GetInstallByName(name string) (*wpengine.InstallDetails, error)
+	ListBackups(installID string) ([]wpengine.Backup, error)
+	CreateBackup(installID, description string) (string, error)
+	PurgeCache(installID, cacheType string) error
+}
+
+// SSHClient is the subset of the WPEngine SSH client used by the provider
+type SSHClient interface {
+	TestConnection() error
+	ExecuteCommand(cmd string) (string, error)
+	ExecuteCommandWithOutput(cmd string, stdout, stderr io.Writer) error
+	GetWPCLI(args []string) (string, error)
+	GetDatabaseCredentials() (*wpengine.DatabaseCredentials, error)
+	ExportDatabase(options wpengine.DatabaseOptions) (io.ReadCloser, error)
+	SyncWPContent(destination string, options wpengine.SyncOptions) error
+	Close() error
+}
+
 // WPEngineProvider implements the Provider interface for WPEngine
 type WPEngineProvider struct {
-	apiClient   *wpengine.Client
-	sshClient   *wpengine.SSHClient
+	apiClient   APIClient
+	sshClient   SSHClient
 	install     string
 	apiUser     string
 	apiPassword string
@@ -25,6 +48,7 @@ var (
 	_ provider.Provider       = (*WPEngineProvider)(nil)
 	_ provider.BackupManager  = (*WPEngineProvider)(nil)
 	_ provider.RemoteExecutor = (*WPEngineProvider)(nil)
+	_ provider.MediaManager   = (*WPEngineProvider)(nil)
 )
 
 func init() {
@@ -32,6 +56,16 @@ func init() {
 	provider.RegisterProvider("wpengine", &WPEngineProvider{})
 }
 
+// NewWPEngineProviderWithClients creates an already-authenticated provider
+// from existing clients. sshClient may be nil for API-only use.
+func NewWPEngineProviderWithClients(install string, apiClient APIClient, sshClient SSHClient) *WPEngineProvider {
+	return &WPEngineProvider{
+		apiClient: apiClient,
+		sshClient: sshClient,
+		install:   install,
+	}
+}
+
 // Name returns the provider's unique identifier
 func (p *WPEngineProvider) Name() string {
 	return "wpengine"
@@ -166,7 +200,11 @@ func (p *WPEngineProvider) GetSite(identifier string) (*provider.Site, error) {
 		// Try by ID
 		details, err = p.apiClient.GetInstall(identifier)
 		if err != nil {
-			return nil, fmt.Errorf("site not found: %s", identifier)
+			// Finally try by primary domain
+			details, err = p.getInstallByDomain(identifier)
+			if err != nil {
+				return nil, fmt.Errorf("site not found: %s", identifier)
+			}
 		}
 	}
 
@@ -187,6 +225,22 @@ func (p *WPEngineProvider) GetSite(identifier string) (*provider.Site, error) {
 	return site, nil
 }
 
+// getInstallByDomain finds an installation by its primary domain
+func (p *WPEngineProvider) getInstallByDomain(domain string) (*wpengine.InstallDetails, error) {
+	installs, err := p.apiClient.ListInstalls()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, install := range installs {
+		if install.PrimaryDomain == domain {
+			return p.apiClient.GetInstall(install.ID)
+		}
+	}
+
+	return nil, fmt.Errorf("install with domain %s not found", domain)
+}
+
 // GetSiteMetadata retrieves detailed metadata about a site
 func (p *WPEngineProvider) GetSiteMetadata(site *provider.Site) (*provider.SiteMetadata, error) {
 	if p.apiClient == nil {
@@ -238,7 +292,7 @@ func (p *WPEngineProvider) ExportDatabase(site *provider.Site, options provider.
 func (p *WPEngineProvider) ImportDatabase(site *provider.Site, data io.Reader, options provider.DatabaseImportOptions) error {
 	// WPEngine doesn't support direct database import via SSH for security
 	// This would need to be done through WPEngine's portal or support
-	return fmt.Errorf("database import not supported by WPEngine provider (use WPEngine portal)")
+	return provider.NewUnsupportedError("wpengine", "database import", "import the database from the WPEngine User Portal: https://my.wpengine.com/")
 }
 
 // GetDatabaseCredentials retrieves database credentials
@@ -247,9 +301,23 @@ func (p *WPEngineProvider) GetDatabaseCredentials(site *provider.Site) (*provide
 		return nil, fmt.Errorf("SSH client not configured")
 	}
 
-	// WPEngine doesn't expose database credentials directly
-	// Users should use WP-CLI or wp-config.php
-	return nil, fmt.Errorf("database credentials not exposed by WPEngine (use wp-config.php)")
+	// The API doesn't expose them, so read the install's wp-config.php
+	creds, err := p.sshClient.GetDatabaseCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	host := creds.Host
+	if creds.Socket != "" {
+		host = creds.Host + ":" + creds.Socket
+	}
+	return &provider.DatabaseCredentials{
+		Host:     host,
+		Port:     creds.Port,
+		Database: creds.Database,
+		Username: creds.Username,
+		Password: creds.Password,
+	}, nil
 }
 
 // ===== File Operations =====
@@ -307,7 +375,7 @@ func (p *WPEngineProvider) DownloadFile(site *provider.Site, remotePath string)
 // UploadFile uploads a single file to WPEngine
 func (p *WPEngineProvider) UploadFile(site *provider.Site, localPath, remotePath string) error {
 	// WPEngine doesn't support file uploads via SSH (read-only filesystem)
-	return fmt.Errorf("file upload not supported by WPEngine (use Git deployments)")
+	return provider.NewUnsupportedError("wpengine", "file upload", "deploy code with Git push: https://wpengine.com/support/git/")
 }
 
 // ===== Environment Information =====
@@ -405,19 +473,19 @@ func (p *WPEngineProvider) CreateBackup(site *provider.Site, description string)
 // RestoreBackup restores from a backup
 func (p *WPEngineProvider) RestoreBackup(site *provider.Site, backupID string, options provider.RestoreOptions) error {
 	// WPEngine requires backup restoration through the portal
-	return fmt.Errorf("backup restoration must be done through WPEngine portal")
+	return provider.NewUnsupportedError("wpengine", "backup restore", "restore backups from the WPEngine User Portal: https://my.wpengine.com/")
 }
 
 // DeleteBackup deletes a backup
 func (p *WPEngineProvider) DeleteBackup(site *provider.Site, backupID string) error {
 	// WPEngine doesn't allow manual backup deletion
-	return fmt.Errorf("backup deletion not supported by WPEngine")
+	return provider.NewUnsupportedError("wpengine", "backup deletion", "")
 }
 
 // DownloadBackup downloads a backup archive
 func (p *WPEngineProvider) DownloadBackup(site *provider.Site, backupID string) (io.ReadCloser, error) {
 	// WPEngine doesn't provide backup download API
-	return nil, fmt.Errorf("backup download not supported by WPEngine API")
+	return nil, provider.NewUnsupportedError("wpengine", "backup download", "download backups from the WPEngine User Portal: https://my.wpengine.com/")
 }
 
 // ===== RemoteExecutor Interface =====
@@ -449,6 +517,46 @@ func (p *WPEngineProvider) StreamCommand(site *provider.Site, command string, st
 	return p.sshClient.ExecuteCommandWithOutput(command, stdout, stderr)
 }
 
+// ===== MediaManager Interface =====
+
+// GetMediaURL returns the URL media is served from for a site
+func (p *WPEngineProvider) GetMediaURL(site *provider.Site) (string, error) {
+	if site == nil {
+		return "", fmt.Errorf("site is required")
+	}
+
+	domain := site.PrimaryDomain
+	if domain == "" {
+		domain = fmt.Sprintf("%s.wpengine.com", site.Name)
+	}
+
+	return fmt.Sprintf("https://%s/wp-content/uploads", domain), nil
+}
+
+// SupportsRemoteMedia indicates if provider supports remote media serving
+func (p *WPEngineProvider) SupportsRemoteMedia() bool {
+	return true
+}
+
+// ConfigureMedia configures media settings
+func (p *WPEngineProvider) ConfigureMedia(site *provider.Site, options provider.MediaOptions) error {
+	// CDN settings are managed in the WPEngine portal
+	return provider.NewUnsupportedError("wpengine", "media configuration", "configure the CDN from the WPEngine User Portal: https://my.wpengine.com/")
+}
+
+// PurgeMediaCache purges the install's CDN cache
+// The WPEngine API purges the whole CDN, so paths are not used
+func (p *WPEngineProvider) PurgeMediaCache(site *provider.Site, paths []string) error {
+	if p.apiClient == nil {
+		return fmt.Errorf("not authenticated")
+	}
+	if site == nil {
+		return fmt.Errorf("site is required")
+	}
+
+	return p.apiClient.PurgeCache(site.ID, wpengine.CacheTypeCDN)
+}
+
 // Close closes any open connections
 func (p *WPEngineProvider) Close() error {
 	if p.sshClient != nil {
@@ -0,0 +1,88 @@
+package wpengine
+
+import (
+	"testing"
+
+	"github.com/firecrown-media/stax/pkg/provider"
+	"github.com/firecrown-media/stax/pkg/provider/providertest"
+	"github.com/firecrown-media/stax/test/mocks"
+)
+
+func TestConformance(t *testing.T) {
+	p := NewWPEngineProviderWithClients("testinstall", mocks.NewMockWPEngineAPI(), mocks.NewMockWPEngineSSH())
+
+	providertest.Run(t, p, providertest.Config{
+		SiteID:     "inst-1",
+		SiteName:   "testinstall",
+		SiteDomain: "testinstall.wpengine.com",
+		NewUnauthenticated: func() provider.Provider {
+			return &WPEngineProvider{}
+		},
+	})
+}
+
+func TestExecuteWPCLI(t *testing.T) {
+	ssh := mocks.NewMockWPEngineSSH()
+	p := NewWPEngineProviderWithClients("testinstall", mocks.NewMockWPEngineAPI(), ssh)
+
+	site, err := p.GetSite("testinstall")
+	if err != nil {
+		t.Fatalf("GetSite() failed: %v", err)
+	}
+
+	if _, err := p.ExecuteWPCLI(site, []string{"plugin", "list"}); err != nil {
+		t.Fatalf("ExecuteWPCLI() failed: %v", err)
+	}
+
+	if len(ssh.Commands) != 1 || ssh.Commands[0] != "wp plugin list" {
+		t.Errorf("unexpected remote commands: %v", ssh.Commands)
+	}
+}
+
+func TestWithoutSSH(t *testing.T) {
+	p := NewWPEngineProviderWithClients("testinstall", mocks.NewMockWPEngineAPI(), nil)
+
+	site, err := p.GetSite("inst-1")
+	if err != nil {
+		t.Fatalf("GetSite() failed: %v", err)
+	}
+
+	if _, err := p.ExportDatabase(site, provider.DatabaseExportOptions{}); err == nil {
+		t.Error("expected ExportDatabase() to fail without an SSH client")
+	}
+}
+
+func TestPurgeMediaCache(t *testing.T) {
+	api := mocks.NewMockWPEngineAPI()
+	p := NewWPEngineProviderWithClients("testinstall", api, nil)
+
+	site, err := p.GetSite("testinstall")
+	if err != nil {
+		t.Fatalf("GetSite() failed: %v", err)
+	}
+
+	if err := p.PurgeMediaCache(site, nil); err != nil {
+		t.Fatalf("PurgeMediaCache() failed: %v", err)
+	}
+
+	if len(api.Purges) != 1 || api.Purges[0] != "inst-1:cdn" {
+		t.Errorf("unexpected purges: %v", api.Purges)
+	}
+}
+
+func TestGetDatabaseCredentials(t *testing.T) {
+	p := NewWPEngineProviderWithClients("testinstall", mocks.NewMockWPEngineAPI(), mocks.NewMockWPEngineSSH())
+
+	site, err := p.GetSite("testinstall")
+	if err != nil {
+		t.Fatalf("GetSite() failed: %v", err)
+	}
+
+	creds, err := p.GetDatabaseCredentials(site)
+	if err != nil {
+		t.Fatalf("GetDatabaseCredentials() failed: %v", err)
+	}
+	if creds.Host != "127.0.0.1" || creds.Port != 3306 || creds.Database != "wp_testinstall" || creds.Username != "testinstall" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+}
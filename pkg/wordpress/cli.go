@@ -134,6 +134,33 @@ func (c *CLI) SetOption(option, value string, blogID int) error {
 	return c.Execute(args...)
 }
 
+// SetConstant defines a constant in wp-config.php
+func (c *CLI) SetConstant(name string, value interface{}) error {
+	return c.Execute(ConstantArgs(name, value)...)
+}
+
+// DeleteConstant removes a constant from wp-config.php, if it is defined
+func (c *CLI) DeleteConstant(name string) error {
+	if _, err := c.ExecuteWithOutput("config", "has", name, "--type=constant"); err != nil {
+		return nil
+	}
+	return c.Execute("config", "delete", name, "--type=constant")
+}
+
+// ConstantArgs returns the WP-CLI arguments that define a constant
+// Values other than strings are written raw, so true and 6379 are not quoted
+func ConstantArgs(name string, value interface{}) []string {
+	args := []string{"config", "set", name}
+	switch v := value.(type) {
+	case string:
+		return append(args, v, "--type=constant")
+	case nil:
+		return append(args, "null", "--raw", "--type=constant")
+	default:
+		return append(args, fmt.Sprint(v), "--raw", "--type=constant")
+	}
+}
+
 // FlushCache flushes the WordPress object cache
 func (c *CLI) FlushCache() error {
 	return c.Execute("cache", "flush")
@@ -2,6 +2,7 @@ package wordpress
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -182,3 +183,20 @@ func TestSiteStruct(t *testing.T) {
 		t.Errorf("expected URL 'https://example.local', got %q", site.URL)
 	}
 }
+
+func TestConstantArgs(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{"redis", "config set WP_TEST redis --type=constant"},
+		{6379, "config set WP_TEST 6379 --raw --type=constant"},
+		{true, "config set WP_TEST true --raw --type=constant"},
+		{nil, "config set WP_TEST null --raw --type=constant"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(ConstantArgs("WP_TEST", tt.value), " "); got != tt.want {
+			t.Errorf("ConstantArgs(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
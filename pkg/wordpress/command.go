@@ -0,0 +1,57 @@
+package wordpress
+
+import (
+	"slices"
+	"strings"
+)
+
+// readOnlyVerbs are WP-CLI subcommands that never change the site
+var readOnlyVerbs = map[string]bool{
+	"get": true, "list": true, "status": true, "exists": true, "has": true,
+	"is-installed": true, "is-active": true, "is-multisite": true, "version": true,
+	"path": true, "pluck": true, "type": true, "count": true, "search": true,
+	"check": true, "check-update": true, "verify-checksums": true, "size": true,
+	"tables": true, "prefix": true, "columns": true, "export": true, "test": true,
+	"help": true, "info": true, "cli": true,
+}
+
+// mutatingVerbs are WP-CLI subcommands that change the site
+var mutatingVerbs = map[string]bool{
+	"add": true, "update": true, "delete": true, "set": true, "create": true,
+	"install": true, "uninstall": true, "activate": true, "deactivate": true,
+	"toggle": true, "import": true, "reset": true, "drop": true, "clean": true,
+	"flush": true, "patch": true, "generate": true, "regenerate": true, "run": true,
+	"query": true, "eval": true, "eval-file": true, "shell": true, "optimize": true,
+	"repair": true, "rename": true, "remove": true, "edit": true, "spam": true,
+	"trash": true, "approve": true, "unapprove": true, "schedule": true,
+	"unschedule": true, "shuffle-salts": true, "download": true, "convert": true,
+	"search-replace": true, "structure": true, "migrate": true, "archive": true,
+}
+
+// IsMutatingCommand reports whether WP-CLI args may change the site; the
+// first recognised subcommand decides and unknown commands count as mutating
+func IsMutatingCommand(args []string) bool {
+	var words []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			words = append(words, arg)
+		}
+	}
+
+	// Bare flags such as --info or --version only print information
+	if len(words) == 0 {
+		return false
+	}
+
+	for _, word := range words[:min(len(words), 3)] {
+		switch {
+		case word == "search-replace":
+			return !slices.Contains(args, "--dry-run")
+		case mutatingVerbs[word]:
+			return true
+		case readOnlyVerbs[word]:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package wordpress
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsMutatingCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{"--info"}, false},
+		{[]string{"plugin", "list", "--status=active"}, false},
+		{[]string{"option", "get", "siteurl"}, false},
+		{[]string{"core", "is-installed"}, false},
+		{[]string{"db", "export", "-"}, false},
+		{[]string{"cron", "event", "list"}, false},
+		{[]string{"search-replace", "old", "new", "--dry-run"}, false},
+		{[]string{"plugin", "update", "--all"}, true},
+		{[]string{"option", "update", "list", "1"}, true},
+		{[]string{"search-replace", "old", "new"}, true},
+		{[]string{"db", "query", "SELECT 1"}, true},
+		{[]string{"cache", "flush"}, true},
+		{[]string{"eval", "echo 1;"}, true},
+		{[]string{"some-package", "thing"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if got := IsMutatingCommand(tt.args); got != tt.want {
+				t.Errorf("IsMutatingCommand(%q) = %t, want %t", tt.args, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package wpengine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SetBaseURL overrides the API base URL
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// ===== Accounts =====
+
+// ListAccounts lists the accounts the API user has access to
+func (c *Client) ListAccounts() ([]Account, error) {
+	return c.ListAccountsContext(context.Background())
+}
+
+// ListAccountsContext lists the accounts the API user has access to
+func (c *Client) ListAccountsContext(ctx context.Context) ([]Account, error) {
+	accounts, err := newIterator[Account](ctx, c, "/accounts").All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+// GetAccount gets an account by ID
+func (c *Client) GetAccount(accountID string) (*Account, error) {
+	return c.GetAccountContext(context.Background(), accountID)
+}
+
+// GetAccountContext gets an account by ID
+func (c *Client) GetAccountContext(ctx context.Context, accountID string) (*Account, error) {
+	var account Account
+	if err := c.doJSON(ctx, "GET", "/accounts/"+url.PathEscape(accountID), nil, &account); err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	return &account, nil
+}
+
+// ListAccountUsers lists the users with access to an account
+func (c *Client) ListAccountUsers(accountID string) ([]AccountUser, error) {
+	return c.ListAccountUsersContext(context.Background(), accountID)
+}
+
+// ListAccountUsersContext lists the users with access to an account
+func (c *Client) ListAccountUsersContext(ctx context.Context, accountID string) ([]AccountUser, error) {
+	path := fmt.Sprintf("/accounts/%s/account_users", url.PathEscape(accountID))
+	users, err := newIterator[AccountUser](ctx, c, path).All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account users: %w", err)
+	}
+	return users, nil
+}
+
+// ===== Sites =====
+
+// ListSites lists all sites across the API user's accounts
+func (c *Client) ListSites() ([]Site, error) {
+	return c.ListSitesContext(context.Background())
+}
+
+// ListSitesContext lists all sites across the API user's accounts
+func (c *Client) ListSitesContext(ctx context.Context) ([]Site, error) {
+	sites, err := newIterator[Site](ctx, c, "/sites").All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sites: %w", err)
+	}
+	return sites, nil
+}
+
+// GetSite gets a site by ID
+func (c *Client) GetSite(siteID string) (*Site, error) {
+	return c.GetSiteContext(context.Background(), siteID)
+}
+
+// GetSiteContext gets a site by ID
+func (c *Client) GetSiteContext(ctx context.Context, siteID string) (*Site, error) {
+	var site Site
+	if err := c.doJSON(ctx, "GET", "/sites/"+url.PathEscape(siteID), nil, &site); err != nil {
+		return nil, fmt.Errorf("failed to get site: %w", err)
+	}
+	return &site, nil
+}
+
+// ===== Installs =====
+
+// ResolveInstallID returns the ID of an install given its name or ID
+func (c *Client) ResolveInstallID(nameOrID string) (string, error) {
+	return c.ResolveInstallIDContext(context.Background(), nameOrID)
+}
+
+// ResolveInstallIDContext returns the ID of an install given its name or ID
+func (c *Client) ResolveInstallIDContext(ctx context.Context, nameOrID string) (string, error) {
+	it := c.Installs(ctx)
+	for it.Next() {
+		if install := it.Value(); install.Name == nameOrID || install.ID == nameOrID {
+			return install.ID, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return "", fmt.Errorf("failed to list installs: %w", err)
+	}
+
+	return "", fmt.Errorf("install %s not found", nameOrID)
+}
+
+// ===== Domains =====
+
+// ListDomains lists the domains attached to an install
+func (c *Client) ListDomains(installID string) ([]Domain, error) {
+	return c.ListDomainsContext(context.Background(), installID)
+}
+
+// ListDomainsContext lists the domains attached to an install
+func (c *Client) ListDomainsContext(ctx context.Context, installID string) ([]Domain, error) {
+	path := fmt.Sprintf("/installs/%s/domains", url.PathEscape(installID))
+	domains, err := newIterator[Domain](ctx, c, path).All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domains: %w", err)
+	}
+	return domains, nil
+}
+
+// AddDomain attaches a domain to an install
+func (c *Client) AddDomain(installID, name string, primary bool) (*Domain, error) {
+	return c.AddDomainContext(context.Background(), installID, name, primary)
+}
+
+// AddDomainContext attaches a domain to an install
+func (c *Client) AddDomainContext(ctx context.Context, installID, name string, primary bool) (*Domain, error) {
+	request := AddDomainRequest{Name: name, Primary: primary}
+
+	var domain Domain
+	path := fmt.Sprintf("/installs/%s/domains", url.PathEscape(installID))
+	if err := c.doJSON(ctx, "POST", path, request, &domain, http.StatusOK, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("failed to add domain %s: %w", name, err)
+	}
+	return &domain, nil
+}
+
+// SetPrimaryDomain makes a domain the primary domain of an install
+func (c *Client) SetPrimaryDomain(installID, domainID string) (*Domain, error) {
+	return c.SetPrimaryDomainContext(context.Background(), installID, domainID)
+}
+
+// SetPrimaryDomainContext makes a domain the primary domain of an install
+func (c *Client) SetPrimaryDomainContext(ctx context.Context, installID, domainID string) (*Domain, error) {
+	request := UpdateDomainRequest{Primary: true}
+
+	var domain Domain
+	path := fmt.Sprintf("/installs/%s/domains/%s", url.PathEscape(installID), url.PathEscape(domainID))
+	if err := c.doJSON(ctx, "PATCH", path, request, &domain); err != nil {
+		return nil, fmt.Errorf("failed to set primary domain: %w", err)
+	}
+	return &domain, nil
+}
+
+// FindDomain finds a domain of an install by name or ID
+func (c *Client) FindDomain(installID, nameOrID string) (*Domain, error) {
+	return c.FindDomainContext(context.Background(), installID, nameOrID)
+}
+
+// FindDomainContext finds a domain of an install by name or ID
+func (c *Client) FindDomainContext(ctx context.Context, installID, nameOrID string) (*Domain, error) {
+	domains, err := c.ListDomainsContext(ctx, installID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range domains {
+		if strings.EqualFold(domains[i].Name, nameOrID) || domains[i].ID == nameOrID {
+			return &domains[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("domain %s not found", nameOrID)
+}
+
+// ===== SSH Keys =====
+
+// ListSSHKeys lists the SSH keys registered with the API user
+func (c *Client) ListSSHKeys() ([]SSHKey, error) {
+	return c.ListSSHKeysContext(context.Background())
+}
+
+// ListSSHKeysContext lists the SSH keys registered with the API user
+func (c *Client) ListSSHKeysContext(ctx context.Context) ([]SSHKey, error) {
+	keys, err := newIterator[SSHKey](ctx, c, "/ssh_keys").All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSH keys: %w", err)
+	}
+	return keys, nil
+}
+
+// AddSSHKey registers a public key with the API user
+func (c *Client) AddSSHKey(publicKey string) (*SSHKey, error) {
+	return c.AddSSHKeyContext(context.Background(), publicKey)
+}
+
+// AddSSHKeyContext registers a public key with the API user
+func (c *Client) AddSSHKeyContext(ctx context.Context, publicKey string) (*SSHKey, error) {
+	request := AddSSHKeyRequest{PublicKey: strings.TrimSpace(publicKey)}
+
+	var key SSHKey
+	if err := c.doJSON(ctx, "POST", "/ssh_keys", request, &key, http.StatusOK, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("failed to add SSH key: %w", err)
+	}
+	return &key, nil
+}
+
+// DeleteSSHKey removes an SSH key by UUID
+func (c *Client) DeleteSSHKey(uuid string) error {
+	return c.DeleteSSHKeyContext(context.Background(), uuid)
+}
+
+// DeleteSSHKeyContext removes an SSH key by UUID
+func (c *Client) DeleteSSHKeyContext(ctx context.Context, uuid string) error {
+	if err := c.doJSON(ctx, "DELETE", "/ssh_keys/"+url.PathEscape(uuid), nil, nil, http.StatusOK, http.StatusNoContent); err != nil {
+		return fmt.Errorf("failed to delete SSH key: %w", err)
+	}
+	return nil
+}
+
+// ===== Cache =====
+
+// PurgeCache purges one of an install's caches (object, page or cdn)
+func (c *Client) PurgeCache(installID, cacheType string) error {
+	return c.PurgeCacheContext(context.Background(), installID, cacheType)
+}
+
+// PurgeCacheContext purges one of an install's caches (object, page or cdn)
+func (c *Client) PurgeCacheContext(ctx context.Context, installID, cacheType string) error {
+	valid := false
+	for _, t := range CacheTypes {
+		if t == cacheType {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid cache type %q (valid types: %s)", cacheType, strings.Join(CacheTypes, ", "))
+	}
+
+	request := PurgeCacheRequest{Type: cacheType}
+	path := fmt.Sprintf("/installs/%s/purge_cache", url.PathEscape(installID))
+	if err := c.doJSON(ctx, "POST", path, request, nil, http.StatusAccepted, http.StatusOK); err != nil {
+		return fmt.Errorf("failed to purge %s cache: %w", cacheType, err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package wpengine
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAccountsAndUsers(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	accounts, err := client.ListAccounts()
+	if err != nil {
+		t.Fatalf("ListAccounts() failed: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].ID != "acct-1" {
+		t.Fatalf("unexpected accounts: %+v", accounts)
+	}
+
+	account, err := client.GetAccount("acct-1")
+	if err != nil || account.Name != "firecrown" {
+		t.Fatalf("GetAccount() = %+v, %v", account, err)
+	}
+
+	users, err := client.ListAccountUsers("acct-1")
+	if err != nil {
+		t.Fatalf("ListAccountUsers() failed: %v", err)
+	}
+	if len(users) != 2 || users[0].Email != "ada@example.com" || !users[0].MFAEnabled {
+		t.Errorf("unexpected users: %+v", users)
+	}
+
+	if _, err := client.ListAccountUsers("missing"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected 404 for unknown account, got %v", err)
+	}
+}
+
+func TestListUsersAndBackupsPagination(t *testing.T) {
+	api, client := newFakeAPI(t)
+	for i := 0; i < 148; i++ {
+		api.users["acct-1"] = append(api.users["acct-1"], AccountUser{UserID: fmt.Sprintf("gen-%d", i)})
+		api.backups["inst-1"] = append(api.backups["inst-1"], Backup{ID: fmt.Sprintf("backup-%d", i)})
+	}
+
+	users, err := client.ListAccountUsers("acct-1")
+	if err != nil {
+		t.Fatalf("ListAccountUsers() failed: %v", err)
+	}
+	if len(users) != 150 || users[149].UserID != "gen-147" {
+		t.Errorf("expected 150 users across pages, got %d", len(users))
+	}
+
+	backups, err := client.ListBackups("inst-1")
+	if err != nil {
+		t.Fatalf("ListBackups() failed: %v", err)
+	}
+	if len(backups) != 148 || backups[147].ID != "backup-147" {
+		t.Errorf("expected 148 backups across pages, got %d", len(backups))
+	}
+
+	// Two pages each
+	if api.requests != 4 {
+		t.Errorf("expected 4 page requests, got %d", api.requests)
+	}
+}
+
+func TestSites(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	sites, err := client.ListSites()
+	if err != nil {
+		t.Fatalf("ListSites() failed: %v", err)
+	}
+	if len(sites) != 1 || len(sites[0].Installs) != 2 || sites[0].Account.ID != "acct-1" {
+		t.Fatalf("unexpected sites: %+v", sites)
+	}
+
+	site, err := client.GetSite("site-1")
+	if err != nil || site.Installs[1].Environment != "staging" {
+		t.Errorf("GetSite() = %+v, %v", site, err)
+	}
+}
+
+func TestDomains(t *testing.T) {
+	api, client := newFakeAPI(t)
+
+	installID, err := client.ResolveInstallID("mysite")
+	if err != nil || installID != "inst-1" {
+		t.Fatalf("ResolveInstallID() = %q, %v", installID, err)
+	}
+	if _, err := client.ResolveInstallID("missing"); err == nil {
+		t.Error("expected error resolving unknown install")
+	}
+
+	domains, err := client.ListDomains(installID)
+	if err != nil || len(domains) != 2 {
+		t.Fatalf("ListDomains() = %+v, %v", domains, err)
+	}
+
+	added, err := client.AddDomain(installID, "shop.example.com", false)
+	if err != nil {
+		t.Fatalf("AddDomain() failed: %v", err)
+	}
+	if added.Primary {
+		t.Error("expected added domain not to be primary")
+	}
+	if _, err := client.AddDomain(installID, "shop.example.com", false); err == nil {
+		t.Error("expected error adding a duplicate domain")
+	}
+
+	domain, err := client.FindDomain(installID, "WWW.example.com")
+	if err != nil {
+		t.Fatalf("FindDomain() failed: %v", err)
+	}
+	if _, err := client.SetPrimaryDomain(installID, domain.ID); err != nil {
+		t.Fatalf("SetPrimaryDomain() failed: %v", err)
+	}
+
+	for _, d := range api.domains[installID] {
+		if d.Primary != (d.Name == "www.example.com") {
+			t.Errorf("domain %s primary = %v after SetPrimaryDomain", d.Name, d.Primary)
+		}
+	}
+}
+
+func TestSSHKeys(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	key, err := client.AddSSHKey("ssh-ed25519 AAAAC3Nza test@example\n")
+	if err != nil {
+		t.Fatalf("AddSSHKey() failed: %v", err)
+	}
+
+	keys, err := client.ListSSHKeys()
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("ListSSHKeys() = %+v, %v", keys, err)
+	}
+
+	if err := client.DeleteSSHKey(key.UUID); err != nil {
+		t.Fatalf("DeleteSSHKey() failed: %v", err)
+	}
+	if err := client.DeleteSSHKey(key.UUID); err == nil {
+		t.Error("expected error deleting a missing key")
+	}
+
+	keys, _ = client.ListSSHKeys()
+	if len(keys) != 1 || keys[0].UUID != "key-1" {
+		t.Errorf("unexpected keys after delete: %+v", keys)
+	}
+}
+
+func TestPurgeCache(t *testing.T) {
+	api, client := newFakeAPI(t)
+
+	for _, cacheType := range CacheTypes {
+		if err := client.PurgeCache("inst-1", cacheType); err != nil {
+			t.Fatalf("PurgeCache(%s) failed: %v", cacheType, err)
+		}
+	}
+
+	want := "inst-1:object,inst-1:page,inst-1:cdn"
+	if got := strings.Join(api.purges, ","); got != want {
+		t.Errorf("purges = %s, want %s", got, want)
+	}
+
+	if err := client.PurgeCache("inst-1", "opcache"); err == nil {
+		t.Error("expected error for invalid cache type")
+	}
+}
+
+func TestAuthenticationFailure(t *testing.T) {
+	_, client := newFakeAPI(t)
+	client.apiPassword = "wrong"
+
+	_, err := client.ListAccounts()
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected 401 error, got %v", err)
+	}
+}
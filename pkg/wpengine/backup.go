@@ -0,0 +1,100 @@
+package wpengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Backup status values reported by the API
+const (
+	BackupStatusRequested = "requested"
+	BackupStatusInitiated = "initiated"
+	BackupStatusCompleted = "completed"
+	BackupStatusFailed    = "failed"
+)
+
+const (
+	// DefaultBackupPollInterval is how often WaitForBackup checks backup status
+	DefaultBackupPollInterval = 10 * time.Second
+
+	// DefaultBackupTimeout is how long WaitForBackup waits for a backup to complete
+	DefaultBackupTimeout = 30 * time.Minute
+)
+
+// ErrBackupTimeout is returned when a backup does not complete in time
+var ErrBackupTimeout = errors.New("timed out waiting for backup")
+
+// WaitOptions control how WaitForBackup polls
+type WaitOptions struct {
+	Interval time.Duration // Defaults to DefaultBackupPollInterval
+	Timeout  time.Duration // Defaults to DefaultBackupTimeout
+	OnStatus func(*Backup) // Called after every poll
+}
+
+// GetBackup gets a backup by ID
+func (c *Client) GetBackup(installID, backupID string) (*Backup, error) {
+	return c.GetBackupContext(context.Background(), installID, backupID)
+}
+
+// GetBackupContext gets a backup by ID
+func (c *Client) GetBackupContext(ctx context.Context, installID, backupID string) (*Backup, error) {
+	var backup Backup
+	path := fmt.Sprintf("/installs/%s/backups/%s", url.PathEscape(installID), url.PathEscape(backupID))
+	if err := c.doJSON(ctx, "GET", path, nil, &backup); err != nil {
+		return nil, fmt.Errorf("failed to get backup: %w", err)
+	}
+	return &backup, nil
+}
+
+// WaitForBackup polls a backup until it completes, fails or the timeout expires
+func (c *Client) WaitForBackup(ctx context.Context, installID, backupID string, opts WaitOptions) (*Backup, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultBackupPollInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultBackupTimeout
+	}
+
+	deadline := time.Now().Add(opts.Timeout)
+	status := ""
+
+	for {
+		backup, err := c.GetBackupContext(ctx, installID, backupID)
+		if err != nil {
+			return nil, err
+		}
+
+		status = backup.Status
+		if opts.OnStatus != nil {
+			opts.OnStatus(backup)
+		}
+
+		switch backup.Status {
+		case BackupStatusCompleted:
+			return backup, nil
+		case BackupStatusFailed:
+			return backup, fmt.Errorf("backup %s failed", backupID)
+		}
+
+		wait := min(opts.Interval, time.Until(deadline))
+		if wait <= 0 {
+			return backup, fmt.Errorf("%w %s after %s (last status: %s)", ErrBackupTimeout, backupID, opts.Timeout, status)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// CreateCheckpoint creates a backup and waits for it to complete
+func (c *Client) CreateCheckpoint(ctx context.Context, installID, description string, notificationEmails []string, opts WaitOptions) (*Backup, error) {
+	backupID, err := c.CreateBackupContext(ctx, installID, description, notificationEmails...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.WaitForBackup(ctx, installID, backupID, opts)
+}
@@ -0,0 +1,86 @@
+package wpengine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateCheckpoint(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.backupPolls = 2
+
+	var seen []string
+	opts := WaitOptions{
+		Interval: time.Millisecond,
+		Timeout:  time.Second,
+		OnStatus: func(b *Backup) { seen = append(seen, b.Status) },
+	}
+
+	backup, err := client.CreateCheckpoint(context.Background(), "inst-1", "before push", []string{"ops@example.com"}, opts)
+	if err != nil {
+		t.Fatalf("CreateCheckpoint() failed: %v", err)
+	}
+	if backup.Status != BackupStatusCompleted {
+		t.Errorf("expected completed backup, got %s", backup.Status)
+	}
+	if strings.Join(seen, ",") != "initiated,initiated,completed" {
+		t.Errorf("unexpected status sequence: %v", seen)
+	}
+
+	backups, err := client.ListBackups("inst-1")
+	if err != nil {
+		t.Fatalf("ListBackups() failed: %v", err)
+	}
+	if len(backups) != 1 || backups[0].Description != "before push" {
+		t.Errorf("unexpected backups: %+v", backups)
+	}
+}
+
+func TestWaitForBackupFailed(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.backupFails = true
+
+	id, err := client.CreateBackup("inst-1", "doomed")
+	if err != nil {
+		t.Fatalf("CreateBackup() failed: %v", err)
+	}
+
+	backup, err := client.WaitForBackup(context.Background(), "inst-1", id, WaitOptions{Interval: time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Fatalf("expected failure, got %v", err)
+	}
+	if backup == nil || backup.Status != BackupStatusFailed {
+		t.Errorf("expected failed backup, got %+v", backup)
+	}
+}
+
+func TestWaitForBackupTimeout(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.backupPolls = 1000
+
+	id, err := client.CreateBackup("inst-1", "slow")
+	if err != nil {
+		t.Fatalf("CreateBackup() failed: %v", err)
+	}
+
+	_, err = client.WaitForBackup(context.Background(), "inst-1", id, WaitOptions{Interval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond})
+	if !errors.Is(err, ErrBackupTimeout) {
+		t.Fatalf("expected ErrBackupTimeout, got %v", err)
+	}
+	if !strings.Contains(err.Error(), BackupStatusInitiated) {
+		t.Errorf("expected last status in error, got %v", err)
+	}
+}
+
+func TestWaitForBackupNotFound(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	_, err := client.WaitForBackup(context.Background(), "inst-1", "missing", WaitOptions{Interval: time.Millisecond})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+		t.Errorf("expected 404 APIError, got %v", err)
+	}
+}
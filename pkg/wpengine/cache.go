@@ -0,0 +1,252 @@
+package wpengine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached install inventory is considered fresh
+const DefaultCacheTTL = 1 * time.Hour
+
+// ErrCacheMiss is returned when nothing is cached for a request
+var ErrCacheMiss = errors.New("not cached")
+
+// DefaultCacheDir returns ~/.stax/cache/wpengine
+func DefaultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".stax", "cache", "wpengine"), nil
+}
+
+// Cache stores install inventory on disk, one directory per API user
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// cacheEntry is the on-disk format of a cached value
+type cacheEntry struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewCache creates a cache for an API user under dir
+func NewCache(dir, apiUser string, ttl time.Duration) *Cache {
+	// Hash the user so account names don't end up in paths
+	sum := sha256.Sum256([]byte(apiUser))
+
+	return &Cache{
+		dir: filepath.Join(dir, hex.EncodeToString(sum[:8])),
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// Dir returns the cache directory
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Clear removes everything cached for the API user
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// Fresh reports whether a value fetched at fetchedAt is within the TTL
+func (c *Cache) Fresh(fetchedAt time.Time) bool {
+	return c.now().Sub(fetchedAt) < c.ttl
+}
+
+// load reads a cached value, returning when it was fetched
+func (c *Cache) load(name string, v interface{}) (time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, ErrCacheMiss
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		// A corrupt entry is treated as missing and rewritten on the next fetch
+		return time.Time{}, ErrCacheMiss
+	}
+	if err := json.Unmarshal(entry.Data, v); err != nil {
+		return time.Time{}, ErrCacheMiss
+	}
+
+	return entry.FetchedAt, nil
+}
+
+// save writes a cached value atomically
+func (c *Cache) save(name string, v interface{}) error {
+	path := filepath.Join(c.dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	data, err = json.Marshal(cacheEntry{FetchedAt: c.now(), Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// LoadInstalls returns the cached install list and when it was fetched
+func (c *Cache) LoadInstalls() ([]Install, time.Time, error) {
+	var installs []Install
+	fetchedAt, err := c.load("installs.json", &installs)
+	return installs, fetchedAt, err
+}
+
+// SaveInstalls caches the install list
+func (c *Cache) SaveInstalls(installs []Install) error {
+	return c.save("installs.json", installs)
+}
+
+// LoadInstallDetails returns cached details for an install name
+func (c *Cache) LoadInstallDetails(name string) (*InstallDetails, time.Time, error) {
+	var details InstallDetails
+	fetchedAt, err := c.load(installDetailsFile(name), &details)
+	if err != nil {
+		return nil, fetchedAt, err
+	}
+	return &details, fetchedAt, nil
+}
+
+// SaveInstallDetails caches the details of an install
+func (c *Cache) SaveInstallDetails(details *InstallDetails) error {
+	return c.save(installDetailsFile(details.Name), details)
+}
+
+// installDetailsFile returns the cache file of an install, escaping the user-supplied name
+func installDetailsFile(name string) string {
+	return filepath.Join("installs", url.PathEscape(name)+".json")
+}
+
+// CacheStatus describes where the last cached client result came from
+type CacheStatus struct {
+	FromCache bool      // Served from the cache instead of the API
+	Stale     bool      // Cached value was past its TTL (API unreachable or offline)
+	Offline   bool      // Served from the cache because the client is offline
+	FetchedAt time.Time // When the served value was fetched from the API
+	APIError  error     // Why the API was not used, when it was unreachable
+}
+
+// CachedClient serves install inventory from the cache while it is fresh
+// and falls back to stale cached data when the API is unreachable
+type CachedClient struct {
+	*Client
+	cache *Cache
+
+	// Refresh bypasses fresh cache entries and always asks the API
+	Refresh bool
+
+	// Offline never contacts the API for cached requests
+	Offline bool
+
+	status CacheStatus
+}
+
+// NewCachedClient wraps a client with an install inventory cache
+func NewCachedClient(client *Client, cache *Cache) *CachedClient {
+	return &CachedClient{Client: client, cache: cache}
+}
+
+// Status reports where the last ListInstalls or GetInstallByName result came from
+func (c *CachedClient) Status() CacheStatus {
+	return c.status
+}
+
+// ListInstalls lists installs, using the cache when possible
+func (c *CachedClient) ListInstalls() ([]Install, error) {
+	return c.ListInstallsContext(context.Background())
+}
+
+// ListInstallsContext lists installs, using the cache when possible
+func (c *CachedClient) ListInstallsContext(ctx context.Context) ([]Install, error) {
+	cached, fetchedAt, cacheErr := c.cache.LoadInstalls()
+	return cachedFetch(c, ctx, cached, fetchedAt, cacheErr, c.Client.ListInstallsContext, c.cache.SaveInstalls)
+}
+
+// GetInstallByName gets install details, using the cache when possible
+func (c *CachedClient) GetInstallByName(name string) (*InstallDetails, error) {
+	return c.GetInstallByNameContext(context.Background(), name)
+}
+
+// GetInstallByNameContext gets install details, using the cache when possible
+func (c *CachedClient) GetInstallByNameContext(ctx context.Context, name string) (*InstallDetails, error) {
+	cached, fetchedAt, cacheErr := c.cache.LoadInstallDetails(name)
+
+	fetch := func(ctx context.Context) (*InstallDetails, error) {
+		return c.Client.GetInstallByNameContext(ctx, name)
+	}
+	return cachedFetch(c, ctx, cached, fetchedAt, cacheErr, fetch, c.cache.SaveInstallDetails)
+}
+
+// cachedFetch applies the cache policy to one request
+func cachedFetch[T any](c *CachedClient, ctx context.Context, cached T, fetchedAt time.Time, cacheErr error,
+	fetch func(context.Context) (T, error), save func(T) error) (T, error) {
+	var zero T
+	hit := cacheErr == nil
+
+	if hit && (c.Offline || (!c.Refresh && c.cache.Fresh(fetchedAt))) {
+		c.status = CacheStatus{FromCache: true, Stale: !c.cache.Fresh(fetchedAt), Offline: c.Offline, FetchedAt: fetchedAt}
+		return cached, nil
+	}
+	if c.Offline {
+		return zero, fmt.Errorf("offline and %w: run again without --offline to fetch from the WPEngine API", cacheErr)
+	}
+
+	value, err := fetch(ctx)
+	if err != nil {
+		if hit && IsUnreachable(err) && ctx.Err() == nil {
+			c.status = CacheStatus{FromCache: true, Stale: true, FetchedAt: fetchedAt, APIError: err}
+			return cached, nil
+		}
+		return zero, err
+	}
+
+	c.status = CacheStatus{FetchedAt: c.cache.now()}
+	// A cache write failure should not fail the command
+	_ = save(value)
+
+	return value, nil
+}
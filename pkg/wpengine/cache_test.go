@@ -0,0 +1,137 @@
+package wpengine
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newCachedFakeAPI returns a cached client for the fake API with a controllable clock
+func newCachedFakeAPI(t *testing.T) (*fakeAPI, *CachedClient, *time.Time) {
+	t.Helper()
+
+	api, client := newFakeAPI(t)
+	cache := NewCache(t.TempDir(), fakeAPIUser, time.Hour)
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	return api, NewCachedClient(client, cache), &now
+}
+
+func TestCachedListInstalls(t *testing.T) {
+	api, client, now := newCachedFakeAPI(t)
+
+	if _, err := client.ListInstalls(); err != nil {
+		t.Fatalf("ListInstalls() failed: %v", err)
+	}
+	if client.Status().FromCache {
+		t.Error("expected first call to hit the API")
+	}
+
+	requests := api.requests
+	installs, err := client.ListInstalls()
+	if err != nil || len(installs) != 2 {
+		t.Fatalf("cached ListInstalls() = %v, %v", installs, err)
+	}
+	if !client.Status().FromCache || api.requests != requests {
+		t.Error("expected second call to be served from the cache")
+	}
+
+	// --refresh bypasses a fresh cache
+	client.Refresh = true
+	if _, err := client.ListInstalls(); err != nil {
+		t.Fatalf("ListInstalls() with refresh failed: %v", err)
+	}
+	if client.Status().FromCache || api.requests == requests {
+		t.Error("expected refresh to hit the API")
+	}
+	client.Refresh = false
+
+	// Expired entries are refetched
+	api.addInstalls(1)
+	*now = now.Add(2 * time.Hour)
+	installs, _ = client.ListInstalls()
+	if len(installs) != 3 || client.Status().FromCache {
+		t.Errorf("expected expired cache to be refetched, got %d installs", len(installs))
+	}
+}
+
+func TestCachedClientUnreachable(t *testing.T) {
+	_, client, now := newCachedFakeAPI(t)
+
+	if _, err := client.GetInstallByName("mysite"); err != nil {
+		t.Fatalf("GetInstallByName() failed: %v", err)
+	}
+
+	// Expire the cache and take the API away
+	*now = now.Add(24 * time.Hour)
+	client.SetBaseURL("http://127.0.0.1:1")
+
+	details, err := client.GetInstallByName("mysite")
+	if err != nil {
+		t.Fatalf("expected stale cache fallback, got %v", err)
+	}
+
+	status := client.Status()
+	if details.ID != "inst-1" || !status.FromCache || !status.Stale || status.APIError == nil {
+		t.Errorf("unexpected fallback result %+v, status %+v", details, status)
+	}
+
+	if _, err := client.GetInstallByName("mysitestg"); err == nil {
+		t.Error("expected error for an uncached install when unreachable")
+	}
+}
+
+func TestCachedClientAPIErrorNotMasked(t *testing.T) {
+	_, client, now := newCachedFakeAPI(t)
+
+	if _, err := client.ListInstalls(); err != nil {
+		t.Fatalf("ListInstalls() failed: %v", err)
+	}
+
+	*now = now.Add(24 * time.Hour)
+	client.apiPassword = "wrong"
+
+	var apiErr *APIError
+	if _, err := client.ListInstalls(); !errors.As(err, &apiErr) || apiErr.StatusCode != 401 {
+		t.Errorf("expected the 401 to be returned rather than stale data, got %v", err)
+	}
+}
+
+func TestCachedClientOffline(t *testing.T) {
+	api, client, now := newCachedFakeAPI(t)
+	client.Offline = true
+
+	if _, err := client.ListInstalls(); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("expected ErrCacheMiss offline with an empty cache, got %v", err)
+	}
+	if api.requests != 0 {
+		t.Errorf("expected no API requests offline, got %d", api.requests)
+	}
+
+	client.Offline = false
+	client.ListInstalls()
+	client.Offline = true
+	*now = now.Add(24 * time.Hour)
+
+	installs, err := client.ListInstalls()
+	if err != nil || len(installs) != 2 || !client.Status().Stale {
+		t.Errorf("expected stale cached installs offline, got %v, %v", installs, err)
+	}
+}
+
+func TestCacheCorruptEntry(t *testing.T) {
+	cache := NewCache(t.TempDir(), fakeAPIUser, time.Hour)
+
+	if err := cache.save("installs.json", "not a list"); err != nil {
+		t.Fatalf("save() failed: %v", err)
+	}
+	if _, _, err := cache.LoadInstalls(); !errors.Is(err, ErrCacheMiss) {
+		t.Errorf("expected corrupt entry to be a miss, got %v", err)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear() failed: %v", err)
+	}
+}
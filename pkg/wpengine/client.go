@@ -2,10 +2,15 @@ package wpengine
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,6 +20,19 @@ const (
 
 	// DefaultTimeout is the default HTTP client timeout
 	DefaultTimeout = 30 * time.Second
+
+	// DefaultRetryDelay is the initial delay before retrying a failed request
+	DefaultRetryDelay = 1 * time.Second
+
+	// DefaultRateLimitWait is the wait used when a 429 response has no usable Retry-After header
+	DefaultRateLimitWait = 5 * time.Second
+
+	// MaxRateLimitRetries is the number of times a rate limited request is retried
+	MaxRateLimitRetries = 5
+
+	// MaxRateLimitWait is the longest Retry-After delay that is waited out
+	// Longer delays fail with the rate limit error instead of hanging the command
+	MaxRateLimitWait = 5 * time.Minute
 )
 
 // Client handles WPEngine API operations
@@ -24,6 +42,7 @@ type Client struct {
 	apiUser     string
 	apiPassword string
 	install     string
+	retryDelay  time.Duration // Initial backoff between retries of failed requests
 }
 
 // NewClient creates a new WPEngine API client
@@ -36,6 +55,7 @@ func NewClient(apiUser, apiPassword, install string) *Client {
 		apiUser:     apiUser,
 		apiPassword: apiPassword,
 		install:     install,
+		retryDelay:  DefaultRetryDelay,
 	}
 }
 
@@ -45,7 +65,7 @@ func (c *Client) SetTimeout(timeout time.Duration) {
 }
 
 // makeRequest makes an HTTP request to the WPEngine API
-func (c *Client) makeRequest(method, path string, body interface{}) (*http.Response, error) {
+func (c *Client) makeRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	var buf io.Reader
 	if body != nil {
 		data, err := json.Marshal(body)
@@ -56,7 +76,7 @@ func (c *Client) makeRequest(method, path string, body interface{}) (*http.Respo
 	}
 
 	url := c.baseURL + path
-	req, err := http.NewRequest(method, url, buf)
+	req, err := http.NewRequestWithContext(ctx, method, url, buf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -69,23 +89,62 @@ func (c *Client) makeRequest(method, path string, body interface{}) (*http.Respo
 }
 
 // makeRequestWithRetry makes an HTTP request with retry logic
-func (c *Client) makeRequestWithRetry(method, path string, body interface{}, maxAttempts int) (*http.Response, error) {
+// Server errors are retried with exponential backoff. Rate limited requests
+// are retried after the Retry-After delay without using up an attempt, unless
+// the delay is longer than MaxRateLimitWait.
+// POST and PATCH requests are not retried on server errors.
+func (c *Client) makeRequestWithRetry(ctx context.Context, method, path string, body interface{}, maxAttempts int) (*http.Response, error) {
 	var lastErr error
-	delay := 1 * time.Second
+	delay := c.retryDelay
+	rateLimited := 0
 
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		resp, err := c.makeRequest(method, path, body)
+		resp, err := c.makeRequest(ctx, method, path, body)
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+			wait := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if rateLimited == MaxRateLimitRetries || wait > MaxRateLimitWait {
+				return resp, nil
+			}
+			rateLimited++
+
+			resp.Body.Close()
+			err := sleepContext(ctx, wait)
+			if err != nil {
+				return nil, err
+			}
+			attempt--
+			continue
+		}
+
 		if err == nil && resp.StatusCode < 500 {
 			return resp, nil
 		}
 
+		// A non-idempotent request that reached the server may have been
+		// applied, so only a connection that failed before sending is retried
+		if !isIdempotent(method) {
+			if err == nil {
+				return resp, nil
+			}
+			if !isNotSent(err) {
+				return nil, err
+			}
+		}
+
 		lastErr = err
 		if err == nil {
+			resp.Body.Close()
 			lastErr = fmt.Errorf("server error: %d", resp.StatusCode)
 		}
 
 		if attempt < maxAttempts {
-			time.Sleep(delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, err
+			}
 			delay *= 2 // Exponential backoff
 		}
 	}
@@ -95,99 +154,88 @@ func (c *Client) makeRequestWithRetry(method, path string, body interface{}, max
 
 // ListInstalls lists all WPEngine installations for the account
 func (c *Client) ListInstalls() ([]Install, error) {
-	resp, err := c.makeRequestWithRetry("GET", "/installs", nil, 3)
+	return c.ListInstallsContext(context.Background())
+}
+
+// ListInstallsContext lists all installations, following every page of results
+func (c *Client) ListInstallsContext(ctx context.Context) ([]Install, error) {
+	installs, err := c.Installs(ctx).All()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list installs: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleErrorResponse(resp)
-	}
-
-	var result ListInstallsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+	return installs, nil
+}
 
-	return result.Results, nil
+// Installs returns an iterator over all installations
+func (c *Client) Installs(ctx context.Context) *Iterator[Install] {
+	return newIterator[Install](ctx, c, "/installs")
 }
 
 // GetInstall gets detailed information about a specific installation
 func (c *Client) GetInstall(installID string) (*InstallDetails, error) {
-	resp, err := c.makeRequestWithRetry("GET", fmt.Sprintf("/installs/%s", installID), nil, 3)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get install: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleErrorResponse(resp)
-	}
+	return c.GetInstallContext(context.Background(), installID)
+}
 
+// GetInstallContext gets detailed information about a specific installation
+func (c *Client) GetInstallContext(ctx context.Context, installID string) (*InstallDetails, error) {
 	var details InstallDetails
-	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.doJSON(ctx, "GET", fmt.Sprintf("/installs/%s", installID), nil, &details); err != nil {
+		return nil, fmt.Errorf("failed to get install: %w", err)
 	}
-
 	return &details, nil
 }
 
 // GetInstallByName gets installation details by name
 func (c *Client) GetInstallByName(name string) (*InstallDetails, error) {
-	installs, err := c.ListInstalls()
-	if err != nil {
-		return nil, err
-	}
+	return c.GetInstallByNameContext(context.Background(), name)
+}
 
-	for _, install := range installs {
-		if install.Name == name {
-			return c.GetInstall(install.ID)
+// GetInstallByNameContext gets installation details by name
+// Paging stops at the first page containing the install
+func (c *Client) GetInstallByNameContext(ctx context.Context, name string) (*InstallDetails, error) {
+	it := c.Installs(ctx)
+	for it.Next() {
+		if install := it.Value(); install.Name == name {
+			return c.GetInstallContext(ctx, install.ID)
 		}
 	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list installs: %w", err)
+	}
 
 	return nil, fmt.Errorf("install %s not found", name)
 }
 
 // ListBackups lists available backups for an installation
 func (c *Client) ListBackups(installID string) ([]Backup, error) {
-	resp, err := c.makeRequestWithRetry("GET", fmt.Sprintf("/installs/%s/backups", installID), nil, 3)
+	return c.ListBackupsContext(context.Background(), installID)
+}
+
+// ListBackupsContext lists available backups for an installation
+func (c *Client) ListBackupsContext(ctx context.Context, installID string) ([]Backup, error) {
+	backups, err := newIterator[Backup](ctx, c, fmt.Sprintf("/installs/%s/backups", installID)).All()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list backups: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleErrorResponse(resp)
-	}
-
-	var result ListBackupsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return result.Results, nil
+	return backups, nil
 }
 
 // CreateBackup creates a manual backup
 func (c *Client) CreateBackup(installID, description string) (string, error) {
-	request := CreateBackupRequest{
-		Description: description,
-	}
-
-	resp, err := c.makeRequestWithRetry("POST", fmt.Sprintf("/installs/%s/backups", installID), request, 3)
-	if err != nil {
-		return "", fmt.Errorf("failed to create backup: %w", err)
-	}
-	defer resp.Body.Close()
+	return c.CreateBackupContext(context.Background(), installID, description)
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return "", c.handleErrorResponse(resp)
+// CreateBackupContext creates a manual backup, optionally emailing addresses when it completes
+func (c *Client) CreateBackupContext(ctx context.Context, installID, description string, notificationEmails ...string) (string, error) {
+	request := CreateBackupRequest{
+		Description:        description,
+		NotificationEmails: notificationEmails,
 	}
 
 	var result CreateBackupResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+	path := fmt.Sprintf("/installs/%s/backups", installID)
+	if err := c.doJSON(ctx, "POST", path, request, &result, http.StatusOK, http.StatusCreated, http.StatusAccepted); err != nil {
+		return "", fmt.Errorf("failed to create backup: %w", err)
 	}
 
 	return result.ID, nil
@@ -201,6 +249,51 @@ func (c *Client) GetInstallInfo() (*InstallDetails, error) {
 	return c.GetInstallByName(c.install)
 }
 
+// doJSON makes an API request and decodes the response into out
+// out may be nil for responses without a body
+func (c *Client) doJSON(ctx context.Context, method, path string, body, out interface{}, expected ...int) error {
+	resp, err := c.makeRequestWithRetry(ctx, method, path, body, 3)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if len(expected) == 0 {
+		expected = []int{http.StatusOK}
+	}
+
+	ok := false
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return c.handleErrorResponse(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// APIError is an error response returned by the WPEngine API
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("WPEngine API error (%d): %s", e.StatusCode, e.Message)
+}
+
 // handleErrorResponse handles API error responses
 func (c *Client) handleErrorResponse(resp *http.Response) error {
 	body, err := io.ReadAll(resp.Body)
@@ -210,36 +303,98 @@ func (c *Client) handleErrorResponse(resp *http.Response) error {
 
 	var errorResp ErrorResponse
 	if err := json.Unmarshal(body, &errorResp); err != nil {
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
 	}
 
-	return fmt.Errorf("WPEngine API error (%d): %s", resp.StatusCode, errorResp.Message)
+	return &APIError{StatusCode: resp.StatusCode, Message: errorResp.Message}
 }
 
-// handleRateLimit handles API rate limiting
-func (c *Client) handleRateLimit(resp *http.Response) error {
-	if resp.StatusCode != http.StatusTooManyRequests {
-		return nil
+// IsUnreachable reports whether err means the API could not be reached:
+// the connection could not be made or timed out
+// API errors and responses that fail to decode are not
+func IsUnreachable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
 	}
 
-	retryAfter := resp.Header.Get("Retry-After")
-	if retryAfter == "" {
-		return fmt.Errorf("rate limited but no Retry-After header")
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
 	}
+	return isNotSent(err)
+}
 
-	var waitSeconds int
-	if _, err := fmt.Sscanf(retryAfter, "%d", &waitSeconds); err != nil {
-		return fmt.Errorf("invalid Retry-After header: %s", retryAfter)
+// isNotSent reports whether err is a connection failure that happened
+// before the request was sent: a DNS lookup or dial failure
+func isNotSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
 	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
 
-	time.Sleep(time.Duration(waitSeconds) * time.Second)
-	return nil
+// isIdempotent reports whether a request with method can be repeated safely
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header value
+// Retry-After may be given in seconds or as an HTTP date
+func retryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return DefaultRateLimitWait
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+
+	return DefaultRateLimitWait
+}
+
+// sleepContext sleeps for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // TestConnection tests the WPEngine API connection
 func (c *Client) TestConnection() error {
-	_, err := c.ListInstalls()
-	if err != nil {
+	return c.TestConnectionContext(context.Background())
+}
+
+// TestConnectionContext tests the WPEngine API connection with a single page request
+func (c *Client) TestConnectionContext(ctx context.Context) error {
+	var result ListInstallsResponse
+	if err := c.doJSON(ctx, "GET", "/installs?limit=1", nil, &result); err != nil {
 		return fmt.Errorf("connection test failed: %w", err)
 	}
 	return nil
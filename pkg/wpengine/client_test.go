@@ -0,0 +1,239 @@
+package wpengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// addInstalls adds n generated installs to the fake API
+func (a *fakeAPI) addInstalls(n int) {
+	for i := 0; i < n; i++ {
+		a.installs = append(a.installs, InstallDetails{
+			ID:          fmt.Sprintf("gen-%d", i),
+			Name:        fmt.Sprintf("generated%03d", i),
+			Environment: "development",
+		})
+	}
+}
+
+func TestListInstallsPagination(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.addInstalls(248)
+
+	installs, err := client.ListInstalls()
+	if err != nil {
+		t.Fatalf("ListInstalls() failed: %v", err)
+	}
+
+	if len(installs) != 250 {
+		t.Fatalf("expected 250 installs across pages, got %d", len(installs))
+	}
+	if installs[0].Name != "mysite" || installs[249].Name != "generated247" {
+		t.Errorf("unexpected install order: first %s, last %s", installs[0].Name, installs[249].Name)
+	}
+	if api.requests != 3 {
+		t.Errorf("expected 3 page requests, got %d", api.requests)
+	}
+}
+
+func TestInstallsIteratorStopsEarly(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.addInstalls(248)
+
+	details, err := client.GetInstallByName("mysitestg")
+	if err != nil {
+		t.Fatalf("GetInstallByName() failed: %v", err)
+	}
+	if details.ID != "inst-2" {
+		t.Errorf("expected inst-2, got %s", details.ID)
+	}
+
+	// One page request plus the install details request
+	if api.requests != 2 {
+		t.Errorf("expected 2 requests, got %d", api.requests)
+	}
+
+	if _, err := client.GetInstallByName("generated200"); err != nil {
+		t.Errorf("GetInstallByName() on a later page failed: %v", err)
+	}
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.failures = []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusServiceUnavailable}
+	api.retryAfter = "0"
+
+	installs, err := client.ListInstalls()
+	if err != nil {
+		t.Fatalf("ListInstalls() failed: %v", err)
+	}
+	if len(installs) != 2 {
+		t.Errorf("expected 2 installs, got %d", len(installs))
+	}
+	if api.requests != 4 {
+		t.Errorf("expected 4 requests, got %d", api.requests)
+	}
+}
+
+func TestRateLimitExhausted(t *testing.T) {
+	api, client := newFakeAPI(t)
+	for i := 0; i <= MaxRateLimitRetries; i++ {
+		api.failures = append(api.failures, http.StatusTooManyRequests)
+	}
+	api.retryAfter = "0"
+
+	_, err := client.ListAccounts()
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected 429 error, got %v", err)
+	}
+	if api.requests != MaxRateLimitRetries+1 {
+		t.Errorf("expected %d requests, got %d", MaxRateLimitRetries+1, api.requests)
+	}
+}
+
+func TestRateLimitWaitTooLong(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.failures = []int{http.StatusTooManyRequests}
+	api.retryAfter = strconv.Itoa(int((MaxRateLimitWait + time.Second) / time.Second))
+
+	start := time.Now()
+	_, err := client.ListAccounts()
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected 429 error, got %v", err)
+	}
+	if api.requests != 1 {
+		t.Errorf("expected 1 request, got %d", api.requests)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waited %s for a delay past the cap", elapsed)
+	}
+}
+
+func TestNonIdempotentRetry(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.failures = []int{http.StatusServiceUnavailable}
+
+	// The purge may have happened, so the 503 is returned rather than retried
+	var apiErr *APIError
+	if err := client.PurgeCache("inst-1", "object"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the 503 to be returned, got %v", err)
+	}
+	if api.requests != 1 || len(api.purges) != 0 {
+		t.Errorf("expected 1 request and no purge, got %d requests, purges %v", api.requests, api.purges)
+	}
+
+	// A 429 was not applied, so it is retried
+	api.requests = 0
+	api.failures = []int{http.StatusTooManyRequests}
+	api.retryAfter = "0"
+	if err := client.PurgeCache("inst-1", "object"); err != nil {
+		t.Fatalf("PurgeCache() failed: %v", err)
+	}
+	if api.requests != 2 || len(api.purges) != 1 {
+		t.Errorf("expected 2 requests and 1 purge, got %d requests, purges %v", api.requests, api.purges)
+	}
+}
+
+func TestIsUnreachable(t *testing.T) {
+	_, client := newFakeAPI(t)
+	client.SetBaseURL("http://127.0.0.1:1")
+	_, dialErr := client.ListInstalls()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"dial", dialErr, true},
+		{"timeout", &url.Error{Op: "Get", URL: "https://api", Err: &net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}}, true},
+		{"dns", fmt.Errorf("wrapped: %w", &net.DNSError{Err: "no such host", Name: "api"}), true},
+		{"api error", &APIError{StatusCode: http.StatusBadGateway}, false},
+		{"decode", fmt.Errorf("failed to decode response: %w", io.ErrUnexpectedEOF), false},
+		{"reset after sending", &url.Error{Op: "Post", URL: "https://api", Err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}}, false},
+		{"canceled", context.Canceled, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsUnreachable(tt.err); got != tt.want {
+			t.Errorf("IsUnreachable(%s: %v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	api, client := newFakeAPI(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.ListInstallsContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	// Cancellation interrupts a Retry-After wait
+	api.failures = []int{http.StatusTooManyRequests}
+	api.retryAfter = "60"
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.ListBackupsContext(ctx, "inst-1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancellation took %s", elapsed)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", DefaultRateLimitWait},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second},
+		{now.Add(-10 * time.Second).Format(http.TimeFormat), 0},
+		{"soon", DefaultRateLimitWait},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := retryAfter(tt.value, now); got != tt.want {
+				t.Errorf("retryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativePath(t *testing.T) {
+	client := NewClient("user", "password", "")
+
+	got, err := client.relativePath("https://api.wpengineapi.com/v1/installs?limit=100&offset=100")
+	if err != nil {
+		t.Fatalf("relativePath() failed: %v", err)
+	}
+	if got != "/installs?limit=100&offset=100" {
+		t.Errorf("relativePath() = %s", got)
+	}
+
+	if got, _ := client.relativePath(""); got != "" {
+		t.Errorf("relativePath(\"\") = %s, want empty", got)
+	}
+}
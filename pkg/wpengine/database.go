@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/firecrown-media/stax/pkg/security"
-	"golang.org/x/crypto/ssh"
 )
 
 var (
@@ -140,9 +139,9 @@ func (c *SSHClient) ExportDatabase(options DatabaseOptions) (io.ReadCloser, erro
 	cmd += " -"
 
 	// Create SSH session for streaming
-	session, err := c.client.NewSession()
+	session, err := c.newSession()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create SSH session: %w", err)
+		return nil, err
 	}
 
 	stdout, err := session.StdoutPipe()
@@ -166,7 +165,7 @@ func (c *SSHClient) ExportDatabase(options DatabaseOptions) (io.ReadCloser, erro
 // exportReadCloser wraps an io.Reader and closes the SSH session when done
 type exportReadCloser struct {
 	reader  io.Reader
-	session *ssh.Session
+	session *sshSession
 }
 
 func (e *exportReadCloser) Read(p []byte) (n int, err error) {
@@ -0,0 +1,333 @@
+package wpengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	fakeAPIUser     = "api-user"
+	fakeAPIPassword = "api-password"
+)
+
+// fakeAPI is an in-memory WPEngine API v1 served over httptest
+type fakeAPI struct {
+	mu       sync.Mutex
+	accounts []Account
+	users    map[string][]AccountUser
+	sites    []Site
+	installs []InstallDetails
+	domains  map[string][]Domain
+	sshKeys  []SSHKey
+	purges   []string // "<install>:<type>" in request order
+	backups  map[string][]Backup
+	nextID   int
+
+	// backupPolls is how many status polls a new backup stays in progress for
+	backupPolls int
+	// backupFails makes new backups fail instead of completing
+	backupFails bool
+	polls       map[string]int
+
+	// failures are returned, in order, before any request is handled
+	failures   []int
+	retryAfter string
+	requests   int
+}
+
+// newFakeAPI starts a fake API and returns a client pointed at it
+func newFakeAPI(t *testing.T) (*fakeAPI, *Client) {
+	t.Helper()
+
+	api := &fakeAPI{
+		accounts: []Account{{ID: "acct-1", Name: "firecrown"}},
+		users: map[string][]AccountUser{
+			"acct-1": {
+				{UserID: "user-1", AccountID: "acct-1", FirstName: "Ada", LastName: "Admin", Email: "ada@example.com", InviteAccepted: true, MFAEnabled: true, Roles: "owner"},
+				{UserID: "user-2", AccountID: "acct-1", FirstName: "Dev", LastName: "Eloper", Email: "dev@example.com", Roles: "partial"},
+			},
+		},
+		installs: []InstallDetails{
+			{ID: "inst-1", Name: "mysite", PrimaryDomain: "mysite.wpengine.com", Environment: "production", PHPVersion: "8.2"},
+			{ID: "inst-2", Name: "mysitestg", PrimaryDomain: "mysitestg.wpengine.com", Environment: "staging", PHPVersion: "8.2"},
+		},
+		domains: map[string][]Domain{
+			"inst-1": {
+				{ID: "dom-1", Name: "mysite.wpengine.com", Primary: true},
+				{ID: "dom-2", Name: "www.example.com"},
+			},
+		},
+		sshKeys: []SSHKey{
+			{UUID: "key-1", Comment: "laptop", Fingerprint: "SHA256:abc", CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+		backups: map[string][]Backup{},
+		polls:   map[string]int{},
+		nextID:  100,
+	}
+
+	site := Site{ID: "site-1", Name: "My Site", Installs: []SiteInstall{
+		{ID: "inst-1", Name: "mysite", Environment: "production", CName: "mysite.wpengine.com"},
+		{ID: "inst-2", Name: "mysitestg", Environment: "staging", CName: "mysitestg.wpengine.com"},
+	}}
+	site.Account.ID = "acct-1"
+	api.sites = []Site{site}
+
+	server := httptest.NewServer(api.handler())
+	t.Cleanup(server.Close)
+
+	client := NewClient(fakeAPIUser, fakeAPIPassword, "")
+	client.SetBaseURL(server.URL + "/v1")
+	client.retryDelay = time.Millisecond
+	return api, client
+}
+
+// handler routes the supported endpoints
+func (a *fakeAPI) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /installs", func(w http.ResponseWriter, r *http.Request) {
+		installs := make([]Install, len(a.installs))
+		for i, d := range a.installs {
+			installs[i] = Install{ID: d.ID, Name: d.Name, PrimaryDomain: d.PrimaryDomain, PHPVersion: d.PHPVersion, Environment: d.Environment}
+		}
+		writeJSON(w, http.StatusOK, paginate(r, installs))
+	})
+	mux.HandleFunc("GET /installs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		for _, d := range a.installs {
+			if d.ID == r.PathValue("id") {
+				writeJSON(w, http.StatusOK, d)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "install not found")
+	})
+
+	mux.HandleFunc("GET /accounts", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, paginate(r, a.accounts))
+	})
+	mux.HandleFunc("GET /accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		for _, account := range a.accounts {
+			if account.ID == r.PathValue("id") {
+				writeJSON(w, http.StatusOK, account)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "account not found")
+	})
+	mux.HandleFunc("GET /accounts/{id}/account_users", func(w http.ResponseWriter, r *http.Request) {
+		users, ok := a.users[r.PathValue("id")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "account not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, paginate(r, users))
+	})
+
+	mux.HandleFunc("GET /sites", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, paginate(r, a.sites))
+	})
+	mux.HandleFunc("GET /sites/{id}", func(w http.ResponseWriter, r *http.Request) {
+		for _, site := range a.sites {
+			if site.ID == r.PathValue("id") {
+				writeJSON(w, http.StatusOK, site)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "site not found")
+	})
+
+	mux.HandleFunc("GET /installs/{id}/domains", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, paginate(r, a.domains[r.PathValue("id")]))
+	})
+	mux.HandleFunc("POST /installs/{id}/domains", func(w http.ResponseWriter, r *http.Request) {
+		var req AddDomainRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" {
+			writeError(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		id := r.PathValue("id")
+		for _, d := range a.domains[id] {
+			if d.Name == req.Name {
+				writeError(w, http.StatusBadRequest, "domain already exists")
+				return
+			}
+		}
+		domain := Domain{ID: a.newID("dom"), Name: req.Name}
+		a.domains[id] = append(a.domains[id], domain)
+		if req.Primary {
+			a.setPrimary(id, domain.ID)
+			domain.Primary = true
+		}
+		writeJSON(w, http.StatusCreated, domain)
+	})
+	mux.HandleFunc("PATCH /installs/{id}/domains/{domain}", func(w http.ResponseWriter, r *http.Request) {
+		var req UpdateDomainRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		id, domainID := r.PathValue("id"), r.PathValue("domain")
+		if req.Primary {
+			a.setPrimary(id, domainID)
+		}
+		for _, d := range a.domains[id] {
+			if d.ID == domainID {
+				writeJSON(w, http.StatusOK, d)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "domain not found")
+	})
+
+	mux.HandleFunc("GET /ssh_keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, paginate(r, a.sshKeys))
+	})
+	mux.HandleFunc("POST /ssh_keys", func(w http.ResponseWriter, r *http.Request) {
+		var req AddSSHKeyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PublicKey == "" {
+			writeError(w, http.StatusBadRequest, "public_key is required")
+			return
+		}
+		key := SSHKey{UUID: a.newID("key"), Comment: "added", Fingerprint: "SHA256:new", CreatedAt: time.Now().UTC()}
+		a.sshKeys = append(a.sshKeys, key)
+		writeJSON(w, http.StatusCreated, key)
+	})
+	mux.HandleFunc("DELETE /ssh_keys/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		for i, key := range a.sshKeys {
+			if key.UUID == r.PathValue("uuid") {
+				a.sshKeys = append(a.sshKeys[:i], a.sshKeys[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "ssh key not found")
+	})
+
+	mux.HandleFunc("POST /installs/{id}/purge_cache", func(w http.ResponseWriter, r *http.Request) {
+		var req PurgeCacheRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		a.purges = append(a.purges, r.PathValue("id")+":"+req.Type)
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	mux.HandleFunc("GET /installs/{id}/backups", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, paginate(r, a.backups[r.PathValue("id")]))
+	})
+	mux.HandleFunc("POST /installs/{id}/backups", func(w http.ResponseWriter, r *http.Request) {
+		var req CreateBackupRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Description == "" {
+			writeError(w, http.StatusBadRequest, "description is required")
+			return
+		}
+		backup := Backup{ID: a.newID("backup"), Description: req.Description, Status: BackupStatusRequested, CreatedAt: time.Now().UTC()}
+		id := r.PathValue("id")
+		a.backups[id] = append(a.backups[id], backup)
+		writeJSON(w, http.StatusAccepted, CreateBackupResponse{ID: backup.ID, Status: backup.Status})
+	})
+	mux.HandleFunc("GET /installs/{id}/backups/{backup}", func(w http.ResponseWriter, r *http.Request) {
+		backups := a.backups[r.PathValue("id")]
+		for i := range backups {
+			if backups[i].ID != r.PathValue("backup") {
+				continue
+			}
+			// Each poll advances an in-progress backup until it finishes
+			a.polls[backups[i].ID]++
+			switch {
+			case a.polls[backups[i].ID] <= a.backupPolls:
+				backups[i].Status = BackupStatusInitiated
+			case a.backupFails:
+				backups[i].Status = BackupStatusFailed
+			default:
+				backups[i].Status = BackupStatusCompleted
+			}
+			writeJSON(w, http.StatusOK, backups[i])
+			return
+		}
+		writeError(w, http.StatusNotFound, "backup not found")
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != fakeAPIUser || password != fakeAPIPassword {
+			writeError(w, http.StatusUnauthorized, "Authentication credentials were not provided")
+			return
+		}
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		a.requests++
+		if len(a.failures) > 0 {
+			status := a.failures[0]
+			a.failures = a.failures[1:]
+			if status == http.StatusTooManyRequests && a.retryAfter != "" {
+				w.Header().Set("Retry-After", a.retryAfter)
+			}
+			writeError(w, status, http.StatusText(status))
+			return
+		}
+
+		r.URL.Path = strings.TrimPrefix(r.URL.Path, "/v1")
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// paginate returns the page of items selected by the limit and offset parameters
+func paginate[T any](r *http.Request, items []T) Page[T] {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = DefaultPageSize
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	offset = min(max(offset, 0), len(items))
+	end := min(offset+limit, len(items))
+
+	page := Page[T]{Results: items[offset:end], Count: len(items)}
+	if page.Results == nil {
+		page.Results = []T{}
+	}
+
+	pageURL := func(offset int) string {
+		return fmt.Sprintf("http://%s/v1%s?limit=%d&offset=%d", r.Host, r.URL.Path, limit, offset)
+	}
+	if end < len(items) {
+		page.Next = pageURL(end)
+	}
+	if offset > 0 {
+		page.Previous = pageURL(max(offset-limit, 0))
+	}
+	return page
+}
+
+// setPrimary marks one domain of an install as primary
+func (a *fakeAPI) setPrimary(installID, domainID string) {
+	for i := range a.domains[installID] {
+		a.domains[installID][i].Primary = a.domains[installID][i].ID == domainID
+	}
+}
+
+// newID allocates a unique resource ID
+func (a *fakeAPI) newID(prefix string) string {
+	a.nextID++
+	return fmt.Sprintf("%s-%d", prefix, a.nextID)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Message: message, Code: status})
+}
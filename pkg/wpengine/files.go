@@ -115,8 +115,11 @@ func (c *SSHClient) Rsync(options SyncOptions) error {
 		args = append(args, "--dry-run")
 	}
 
-	// Add SSH key if available
-	if c.config.PrivateKey != "" {
+	// Reuse the shared connection through its control socket when possible,
+	// otherwise rsync opens its own connection with the key
+	if shell, ok := c.controlShell(); ok {
+		args = append(args, "-e", shell)
+	} else if c.config.PrivateKey != "" {
 		// Write private key to temp file (now secure)
 		tmpKey, err := writePrivateKeyToTempFile(c.config.PrivateKey)
 		if err != nil {
@@ -149,6 +152,20 @@ func (c *SSHClient) Rsync(options SyncOptions) error {
 	return nil
 }
 
+// controlShell returns the rsync remote shell for the connection's control
+// socket, if the connection has a control helper configured
+func (c *SSHClient) controlShell() (string, bool) {
+	if c.conn == nil || len(c.conn.opts.ControlHelper) == 0 {
+		return "", false
+	}
+
+	socket, err := c.ControlSocket()
+	if err != nil {
+		return "", false
+	}
+	return ControlShell(c.conn.opts.ControlHelper, socket), true
+}
+
 // GetExcludePatterns returns default exclusion patterns
 func GetExcludePatterns() []string {
 	return DefaultRsyncExclusions
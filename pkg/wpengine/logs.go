@@ -0,0 +1,74 @@
+package wpengine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/logs"
+	"github.com/firecrown-media/stax/pkg/security"
+)
+
+// LogPath returns where an install writes a log on WPEngine; PHP errors
+// reach the nginx error log through FastCGI
+func LogPath(install string, typ logs.Type) string {
+	switch typ {
+	case logs.TypeAccess:
+		return fmt.Sprintf("/var/log/nginx/%s.access.log", install)
+	case logs.TypeWPDebug:
+		return fmt.Sprintf("/sites/%s/wp-content/debug.log", install)
+	default:
+		return fmt.Sprintf("/var/log/nginx/%s.error.log", install)
+	}
+}
+
+// TailOptions configure TailLog
+type TailOptions struct {
+	Lines  int  // lines from the end to start with; 0 reads the whole file
+	Follow bool // keep streaming new lines until ctx is done
+}
+
+// TailLog streams a remote log file to w until it ends or ctx is done
+func (c *SSHClient) TailLog(ctx context.Context, path string, opts TailOptions, w io.Writer) error {
+	safePath, err := security.SanitizeForShell(path)
+	if err != nil {
+		return fmt.Errorf("invalid log path: %w", err)
+	}
+
+	args := []string{"tail", "-n", "+1"}
+	if opts.Lines > 0 {
+		args[2] = strconv.Itoa(opts.Lines)
+	}
+	if opts.Follow {
+		// -F keeps following across log rotation
+		args = append(args, "-F")
+	}
+	args = append(args, "--", safePath)
+
+	session, err := c.newSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	var stderr bytes.Buffer
+	session.Stdout = w
+	session.Stderr = &stderr
+
+	stop := context.AfterFunc(ctx, func() { session.Close() })
+	defer stop()
+
+	if err := session.Run(strings.Join(args, " ")); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("failed to read %s: %s", path, msg)
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package wpengine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/firecrown-media/stax/pkg/logs"
+)
+
+func TestLogPath(t *testing.T) {
+	tests := map[logs.Type]string{
+		logs.TypeAccess:   "/var/log/nginx/mysite.access.log",
+		logs.TypePHPError: "/var/log/nginx/mysite.error.log",
+		logs.TypeWPDebug:  "/sites/mysite/wp-content/debug.log",
+	}
+	for typ, want := range tests {
+		if got := LogPath("mysite", typ); got != want {
+			t.Errorf("LogPath(%s) = %s, want %s", typ, got, want)
+		}
+	}
+}
+
+func TestTailLog(t *testing.T) {
+	server := newTestSSHServer(t, func(command string, stdin io.Reader, stdout, stderr io.Writer) int {
+		switch command {
+		case "tail -n 2 -- /var/log/nginx/mysite.access.log":
+			fmt.Fprintln(stdout, "line 1\nline 2")
+			return 0
+		case "tail -n +1 -F -- /sites/mysite/wp-content/debug.log":
+			fmt.Fprintln(stdout, "first")
+			// Follow until the client goes away
+			io.Copy(io.Discard, stdin)
+			return 0
+		}
+		fmt.Fprintln(stderr, "tail: cannot open 'missing.log' for reading: No such file or directory")
+		return 1
+	})
+	m := server.manager(SSHManagerOptions{})
+	defer m.Close()
+
+	client, err := m.Client(SSHConfig{Install: "mysite"})
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := client.TailLog(context.Background(), LogPath("mysite", logs.TypeAccess), TailOptions{Lines: 2}, &out); err != nil {
+		t.Fatalf("TailLog() failed: %v", err)
+	}
+	if out.String() != "line 1\nline 2\n" {
+		t.Errorf("TailLog() output = %q", out.String())
+	}
+
+	err = client.TailLog(context.Background(), "missing.log", TailOptions{}, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "No such file") {
+		t.Errorf("expected the remote error, got %v", err)
+	}
+
+	if err := client.TailLog(context.Background(), "/tmp/$(id)", TailOptions{}, io.Discard); err == nil {
+		t.Error("expected an unsafe path to be rejected")
+	}
+
+	// Following stops cleanly when the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	r, w := io.Pipe()
+	result := make(chan error, 1)
+	go func() {
+		result <- client.TailLog(ctx, LogPath("mysite", logs.TypeWPDebug), TailOptions{Follow: true}, w)
+		w.Close()
+	}()
+
+	line := make([]byte, 6)
+	if _, err := io.ReadFull(r, line); err != nil || string(line) != "first\n" {
+		t.Fatalf("followed output = %q, %v", line, err)
+	}
+	cancel()
+	go io.Copy(io.Discard, r)
+
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("TailLog() after cancel = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("TailLog() kept following after cancellation")
+	}
+}
@@ -0,0 +1,113 @@
+package wpengine
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DefaultPageSize is the number of results requested per page (the API maximum)
+const DefaultPageSize = 100
+
+// Iterator pages through a paginated API list, fetching pages as needed
+type Iterator[T any] struct {
+	ctx    context.Context
+	client *Client
+	next   string // Path of the next page, empty when exhausted
+	buf    []T
+	cur    T
+	err    error
+}
+
+// newIterator creates an iterator starting at the first page of path
+func newIterator[T any](ctx context.Context, c *Client, path string) *Iterator[T] {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	return &Iterator[T]{
+		ctx:    ctx,
+		client: c,
+		next:   fmt.Sprintf("%s%slimit=%d&offset=0", path, sep, DefaultPageSize),
+	}
+}
+
+// Next advances to the next result, fetching the next page when needed
+// It returns false when the results are exhausted or an error occurred
+func (it *Iterator[T]) Next() bool {
+	for len(it.buf) == 0 {
+		if it.err != nil || it.next == "" {
+			return false
+		}
+		it.fetch()
+	}
+
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Value returns the current result
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All collects every remaining result
+func (it *Iterator[T]) All() ([]T, error) {
+	results := []T{}
+	for it.Next() {
+		results = append(results, it.Value())
+	}
+	return results, it.Err()
+}
+
+// fetch loads the next page into the buffer
+func (it *Iterator[T]) fetch() {
+	var page Page[T]
+	if err := it.client.doJSON(it.ctx, "GET", it.next, nil, &page); err != nil {
+		it.err = err
+		return
+	}
+
+	next, err := it.client.relativePath(page.Next)
+	if err != nil {
+		it.err = err
+		return
+	}
+	if next == it.next {
+		it.err = fmt.Errorf("pagination loop: next page is %s again", next)
+		return
+	}
+
+	it.buf = page.Results
+	it.next = next
+}
+
+// relativePath converts an absolute next/previous page URL into a request path
+func (c *Client) relativePath(pageURL string) (string, error) {
+	if pageURL == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid page URL %q: %w", pageURL, err)
+	}
+
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", c.baseURL, err)
+	}
+
+	path := strings.TrimPrefix(u.Path, strings.TrimSuffix(base.Path, "/"))
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path, nil
+}
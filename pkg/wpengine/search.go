@@ -0,0 +1,92 @@
+package wpengine
+
+import (
+	"sort"
+	"strings"
+)
+
+// SearchInstalls fuzzy matches installs by name, primary domain and environment
+// Every whitespace-separated term must match one of the fields; results are
+// ranked best match first. An empty query returns all installs.
+func SearchInstalls(installs []Install, query string) []Install {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return installs
+	}
+
+	type match struct {
+		install Install
+		score   int
+	}
+
+	var matches []match
+	for _, install := range installs {
+		fields := []string{
+			strings.ToLower(install.Name),
+			strings.ToLower(install.PrimaryDomain),
+			strings.ToLower(install.Environment),
+		}
+
+		total := 0
+		for _, term := range terms {
+			best := 0
+			for _, field := range fields {
+				best = max(best, fuzzyScore(term, field))
+			}
+			if best == 0 {
+				total = 0
+				break
+			}
+			total += best
+		}
+
+		if total > 0 {
+			matches = append(matches, match{install, total})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].install.Name < matches[j].install.Name
+	})
+
+	results := make([]Install, len(matches))
+	for i, m := range matches {
+		results[i] = m.install
+	}
+	return results
+}
+
+// fuzzyScore scores how well term matches s, 0 meaning no match
+// Exact matches beat prefixes, prefixes beat substrings, and substrings beat
+// subsequences, which score higher the more compact they are
+func fuzzyScore(term, s string) int {
+	switch {
+	case term == "" || s == "":
+		return 0
+	case s == term:
+		return 1000
+	case strings.HasPrefix(s, term):
+		return 500 + len(term)
+	case strings.Contains(s, term):
+		return 250 + len(term)
+	}
+
+	// Subsequence: every rune of term appears in order
+	start, pos := -1, 0
+	for _, r := range term {
+		i := strings.IndexRune(s[pos:], r)
+		if i < 0 {
+			return 0
+		}
+		if start < 0 {
+			start = pos + i
+		}
+		pos += i + len(string(r))
+	}
+
+	span := pos - start
+	return max(1, 100-(span-len(term)))
+}
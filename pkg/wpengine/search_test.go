@@ -0,0 +1,59 @@
+package wpengine
+
+import (
+	"testing"
+)
+
+func TestSearchInstalls(t *testing.T) {
+	installs := []Install{
+		{Name: "acmeprod", PrimaryDomain: "www.acme.com", Environment: "production"},
+		{Name: "acmestg", PrimaryDomain: "acmestg.wpengine.com", Environment: "staging"},
+		{Name: "globex", PrimaryDomain: "globex.example.org", Environment: "production"},
+		{Name: "initech", PrimaryDomain: "initech.wpengine.com", Environment: "development"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"acmeprod", "acmestg", "globex", "initech"}},
+		{"acme", []string{"acmeprod", "acmestg"}},
+		{"globex", []string{"globex"}},
+		{"example.org", []string{"globex"}},
+		{"acme staging", []string{"acmestg"}},
+		{"production", []string{"acmeprod", "globex"}},
+		{"acmstg", []string{"acmestg"}},
+		{"INITECH", []string{"initech"}},
+		{"zzz", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := SearchInstalls(installs, tt.query)
+			if len(got) != len(tt.want) {
+				t.Fatalf("SearchInstalls(%q) returned %d results, want %v", tt.query, len(got), tt.want)
+			}
+			for i, name := range tt.want {
+				if got[i].Name != name {
+					t.Errorf("SearchInstalls(%q)[%d] = %s, want %s", tt.query, i, got[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestFuzzyScoreRanking(t *testing.T) {
+	exact := fuzzyScore("acme", "acme")
+	prefix := fuzzyScore("acme", "acmeprod")
+	substring := fuzzyScore("acme", "theacmesite")
+	compact := fuzzyScore("acm", "axcm")
+	sparse := fuzzyScore("acm", "axxxxxxxcxxxxm")
+
+	if !(exact > prefix && prefix > substring && substring > compact && compact > sparse && sparse > 0) {
+		t.Errorf("unexpected ranking: exact=%d prefix=%d substring=%d compact=%d sparse=%d", exact, prefix, substring, compact, sparse)
+	}
+
+	if fuzzyScore("mca", "acme") != 0 {
+		t.Error("expected out-of-order runes not to match")
+	}
+}
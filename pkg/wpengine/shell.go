@@ -0,0 +1,83 @@
+package wpengine
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// WindowSize is a terminal size in character cells
+type WindowSize struct {
+	Width  int
+	Height int
+}
+
+// ShellOptions configure an interactive session
+type ShellOptions struct {
+	Command string // empty starts a login shell
+	Term    string // TERM for the remote PTY (default xterm-256color)
+	Size    WindowSize
+	Resize  <-chan WindowSize // new sizes to forward to the PTY
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Shell runs an interactive session on a remote PTY; a non-zero exit is
+// returned as an *ssh.ExitError
+func (c *SSHClient) Shell(opts ShellOptions) error {
+	if opts.Term == "" {
+		opts.Term = "xterm-256color"
+	}
+	if opts.Size.Width <= 0 || opts.Size.Height <= 0 {
+		opts.Size = WindowSize{Width: 80, Height: 24}
+	}
+
+	session, err := c.newSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 14400,
+		ssh.TTY_OP_OSPEED: 14400,
+	}
+	if err := session.RequestPty(opts.Term, opts.Size.Height, opts.Size.Width, modes); err != nil {
+		return fmt.Errorf("failed to request PTY: %w", err)
+	}
+
+	session.Stdin = opts.Stdin
+	session.Stdout = opts.Stdout
+	session.Stderr = opts.Stderr
+
+	if opts.Command == "" {
+		err = session.Shell()
+	} else {
+		err = session.Start(opts.Command)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to start remote shell: %w", err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case size, ok := <-opts.Resize:
+				if !ok {
+					return
+				}
+				session.WindowChange(size.Height, size.Width)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return session.Wait()
+}
@@ -0,0 +1,230 @@
+package wpengine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestShell(t *testing.T) {
+	started := make(chan struct{})
+	server := newTestSSHServer(t, func(command string, stdin io.Reader, stdout, stderr io.Writer) int {
+		if command != "" {
+			return 127
+		}
+		close(started)
+		io.Copy(stdout, stdin)
+		return 7
+	})
+	m := server.manager(SSHManagerOptions{})
+	defer m.Close()
+
+	client, err := m.Client(SSHConfig{Install: "mysite"})
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	stdinReader, stdinWriter := io.Pipe()
+	resize := make(chan WindowSize)
+	var stdout bytes.Buffer
+
+	result := make(chan error, 1)
+	go func() {
+		result <- client.Shell(ShellOptions{
+			Term:   "xterm",
+			Size:   WindowSize{Width: 120, Height: 40},
+			Resize: resize,
+			Stdin:  stdinReader,
+			Stdout: &stdout,
+			Stderr: io.Discard,
+		})
+	}()
+
+	<-started
+	resize <- WindowSize{Width: 100, Height: 30}
+	stdinWriter.Write([]byte("ls\n"))
+	stdinWriter.Close()
+
+	err = <-result
+	var exitErr *ssh.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitStatus() != 7 {
+		t.Fatalf("expected exit status 7, got %v", err)
+	}
+	if stdout.String() != "ls\n" {
+		t.Errorf("Shell() output = %q", stdout.String())
+	}
+
+	// The window change may arrive after the shell exits
+	deadline := time.Now().Add(time.Second)
+	want := []WindowSize{{120, 40}, {100, 30}}
+	for !reflect.DeepEqual(server.windows(), want) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := server.windows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("window sizes = %v, want %v", got, want)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.ptyTerm != "xterm" {
+		t.Errorf("PTY term = %q", server.ptyTerm)
+	}
+}
+
+func TestForward(t *testing.T) {
+	// A stand-in for the remote database
+	database, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer database.Close()
+	go func() {
+		for {
+			conn, err := database.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	server := newTestSSHServer(t, echoHandler)
+	m := server.manager(SSHManagerOptions{})
+	defer m.Close()
+
+	client, err := m.Client(SSHConfig{Install: "mysite"})
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- client.Forward(ctx, listener, "tcp", database.Addr().String(), func(err error) {
+			t.Errorf("forwarded connection failed: %v", err)
+		})
+	}()
+
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to connect to tunnel: %v", err)
+		}
+		conn.Write([]byte("ping"))
+		reply := make([]byte, 4)
+		if _, err := io.ReadFull(conn, reply); err != nil || string(reply) != "ping" {
+			t.Errorf("tunnel reply = %q, %v", reply, err)
+		}
+		conn.Close()
+	}
+
+	cancel()
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("Forward() failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Forward() did not stop after cancellation")
+	}
+
+	if n := server.accepted(); n != 1 {
+		t.Errorf("expected forwarding to reuse 1 connection, got %d", n)
+	}
+}
+
+func TestGetDatabaseCredentials(t *testing.T) {
+	server := newTestSSHServer(t, func(command string, stdin io.Reader, stdout, stderr io.Writer) int {
+		if command != "cat /sites/mysite/wp-config.php" {
+			return 1
+		}
+		io.WriteString(stdout, "<?php\ndefine( 'DB_NAME', 'wp_mysite' );\ndefine( 'DB_USER', 'mysite' );\ndefine( 'DB_PASSWORD', 'secret' );\ndefine( 'DB_HOST', '127.0.0.1:3306' );\n")
+		return 0
+	})
+	m := server.manager(SSHManagerOptions{})
+	defer m.Close()
+
+	client, err := m.Client(SSHConfig{Install: "mysite"})
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	creds, err := client.GetDatabaseCredentials()
+	if err != nil {
+		t.Fatalf("GetDatabaseCredentials() failed: %v", err)
+	}
+	if creds.Database != "wp_mysite" || creds.Username != "mysite" || creds.Password != "secret" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+	if network, addr := creds.Address(); network != "tcp" || addr != "127.0.0.1:3306" {
+		t.Errorf("Address() = %s %s", network, addr)
+	}
+}
+
+func TestParseWPConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *DatabaseCredentials
+		wantErr bool
+	}{
+		{
+			name: "double quotes, escapes and socket",
+			content: `<?php
+// define('DB_NAME', 'commented_out');
+define("DB_NAME", "site_db");
+define('DB_USER', 'site');
+define('DB_PASSWORD', 'it\'s "quoted"');
+define('DB_HOST', 'localhost:/var/run/mysqld/mysqld.sock');
+$table_prefix = 'wp_2_';`,
+			want: &DatabaseCredentials{Host: "localhost", Port: 3306, Socket: "/var/run/mysqld/mysqld.sock", Database: "site_db", Username: "site", Password: `it's "quoted"`, TablePrefix: "wp_2_"},
+		},
+		{
+			name:    "defaults",
+			content: "define('DB_NAME','db');define('DB_USER','user');",
+			want:    &DatabaseCredentials{Port: 3306, Database: "db", Username: "user", TablePrefix: "wp_"},
+		},
+		{
+			name:    "first definition wins",
+			content: "define('DB_NAME', 'first');\ndefine('DB_NAME', 'second');\ndefine('DB_USER', 'user');",
+			want:    &DatabaseCredentials{Port: 3306, Database: "first", Username: "user", TablePrefix: "wp_"},
+		},
+		{
+			name:    "invalid port",
+			content: "define('DB_NAME', 'db');\ndefine('DB_USER', 'user');\ndefine('DB_HOST', 'db:port');",
+			wantErr: true,
+		},
+		{
+			name:    "missing settings",
+			content: "<?php\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWPConfig(strings.TrimSpace(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWPConfig() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWPConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,71 +27,123 @@ const (
 
 // SSHClient represents an SSH connection to WPEngine gateway
 type SSHClient struct {
-	client *ssh.Client
+	conn   *sshConn
 	config SSHConfig
+	shared bool // connection is owned by an SSHManager
 }
 
-// NewSSHClient creates a new SSH client for WPEngine
+// NewSSHClient creates a new SSH client for WPEngine with its own connection
 func NewSSHClient(config SSHConfig) (*SSHClient, error) {
-	// Parse private key
-	signer, err := ssh.ParsePrivateKey([]byte(config.PrivateKey))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	config = withSSHDefaults(config)
+	opts := SSHManagerOptions{MaxSessions: DefaultMaxSessions, KeepAliveInterval: DefaultKeepAliveInterval}
+
+	conn := newSSHConn(config, opts, dialSSH)
+	if _, err := conn.connect(); err != nil {
+		return nil, err
 	}
 
-	// Set defaults
+	return &SSHClient{
+		conn:   conn,
+		config: config,
+	}, nil
+}
+
+// withSSHDefaults fills in the default gateway host
+// The port is resolved at dial time so ~/.ssh/config can supply it
+func withSSHDefaults(config SSHConfig) SSHConfig {
 	if config.Host == "" {
 		config.Host = DefaultSSHGateway
 	}
-	if config.Port == 0 {
-		config.Port = DefaultSSHPort
+	return config
+}
+
+// dialSSH authenticates to the gateway using the agent, the configured key
+// and the ~/.ssh/config settings for the host
+func dialSSH(config SSHConfig) (*ssh.Client, error) {
+	configFile := config.ConfigFile
+	if configFile == "" {
+		configFile = DefaultSSHConfigFile()
+	}
+	settings, err := LoadSSHHostSettings(configFile, config.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	host := config.Host
+	if settings.HostName != "" {
+		host = settings.HostName
+	}
+	port := config.Port
+	if port == 0 {
+		port = settings.Port
+	}
+	if port == 0 {
+		port = DefaultSSHPort
 	}
 
 	// SSH user format: installname@installname
 	user := fmt.Sprintf("%s@%s", config.Install, config.Install)
+	if settings.User != "" {
+		user = settings.User
+	}
 
-	// Initialize known hosts manager for secure host key verification
-	khManager, err := security.NewKnownHostsManager()
+	auth, err := resolveSSHAuth(config, settings)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize known hosts manager: %w", err)
+		return nil, err
+	}
+	// The agent is only needed while authenticating
+	defer auth.Close()
+
+	hostKeyCallback := config.HostKeyCallback
+	if hostKeyCallback == nil {
+		// Initialize known hosts manager for secure host key verification
+		khManager, err := security.NewKnownHostsManager()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize known hosts manager: %w", err)
+		}
+		hostKeyCallback = khManager.GetHostKeyCallback()
 	}
 
-	// Create SSH client config with proper host key verification
 	sshConfig := &ssh.ClientConfig{
 		User: user,
 		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
+			ssh.PublicKeys(auth.signers...),
 		},
-		HostKeyCallback: khManager.GetHostKeyCallback(),
+		HostKeyCallback: hostKeyCallback,
 		Timeout:         DefaultSSHTimeout,
 	}
 
 	// Connect to SSH gateway
-	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
 	client, err := ssh.Dial("tcp", addr, sshConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to SSH gateway: %w", err)
 	}
 
-	return &SSHClient{
-		client: client,
-		config: config,
-	}, nil
+	return client, nil
 }
 
-// Close closes the SSH connection
+// Close closes the SSH connection unless it is shared through an SSHManager
 func (c *SSHClient) Close() error {
-	if c.client != nil {
-		return c.client.Close()
+	if c.conn == nil || c.shared {
+		return nil
 	}
-	return nil
+	return c.conn.close()
+}
+
+// newSession opens a session on the connection
+func (c *SSHClient) newSession() (*sshSession, error) {
+	if c.conn == nil {
+		return nil, fmt.Errorf("failed to create SSH session: not connected")
+	}
+	return c.conn.session()
 }
 
 // ExecuteCommand executes a command via SSH and returns the output
 func (c *SSHClient) ExecuteCommand(cmd string) (string, error) {
-	session, err := c.client.NewSession()
+	session, err := c.newSession()
 	if err != nil {
-		return "", fmt.Errorf("failed to create SSH session: %w", err)
+		return "", err
 	}
 	defer session.Close()
 
@@ -107,9 +161,9 @@ func (c *SSHClient) ExecuteCommand(cmd string) (string, error) {
 
 // ExecuteCommandWithOutput executes a command and streams output to given writers
 func (c *SSHClient) ExecuteCommandWithOutput(cmd string, stdout, stderr io.Writer) error {
-	session, err := c.client.NewSession()
+	session, err := c.newSession()
 	if err != nil {
-		return fmt.Errorf("failed to create SSH session: %w", err)
+		return err
 	}
 	defer session.Close()
 
@@ -123,6 +177,11 @@ func (c *SSHClient) ExecuteCommandWithOutput(cmd string, stdout, stderr io.Write
 	return nil
 }
 
+// Install returns the install the client connects to
+func (c *SSHClient) Install() string {
+	return c.config.Install
+}
+
 // GetWPCLI executes a WP-CLI command on the remote server
 func (c *SSHClient) GetWPCLI(args []string) (string, error) {
 	// Sanitize WP-CLI arguments to prevent command injection
@@ -135,6 +194,24 @@ func (c *SSHClient) GetWPCLI(args []string) (string, error) {
 	return c.ExecuteCommand(cmd)
 }
 
+// StreamWPCLI runs a WP-CLI command on the remote server, streaming its
+// output; a non-zero exit is returned as an *ssh.ExitError
+func (c *SSHClient) StreamWPCLI(args []string, stdout, stderr io.Writer) error {
+	sanitizedArgs, err := security.SanitizeWPCLIArgs(args)
+	if err != nil {
+		return fmt.Errorf("invalid WP-CLI arguments: %w", err)
+	}
+
+	// Quote each argument so values with spaces reach WP-CLI intact;
+	// sanitisation already rejects quotes
+	quoted := make([]string, len(sanitizedArgs))
+	for i, arg := range sanitizedArgs {
+		quoted[i] = "'" + arg + "'"
+	}
+
+	return c.ExecuteCommandWithOutput("wp "+strings.Join(quoted, " "), stdout, stderr)
+}
+
 // DownloadFile downloads a file from the remote server
 func (c *SSHClient) DownloadFile(remotePath, localPath string) error {
 	// Validate and sanitize remote path to prevent path traversal
@@ -155,9 +232,9 @@ func (c *SSHClient) DownloadFile(remotePath, localPath string) error {
 		return fmt.Errorf("invalid local path: %w", err)
 	}
 
-	session, err := c.client.NewSession()
+	session, err := c.newSession()
 	if err != nil {
-		return fmt.Errorf("failed to create SSH session: %w", err)
+		return err
 	}
 	defer session.Close()
 
@@ -256,9 +333,9 @@ func (c *SSHClient) UploadFile(localPath, remotePath string) error {
 	}
 
 	// Create SSH session for upload
-	session, err := c.client.NewSession()
+	session, err := c.newSession()
 	if err != nil {
-		return fmt.Errorf("failed to create SSH session: %w", err)
+		return err
 	}
 	defer session.Close()
 
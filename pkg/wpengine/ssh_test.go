@@ -0,0 +1,49 @@
+package wpengine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestStreamWPCLI(t *testing.T) {
+	server := newTestSSHServer(t, func(command string, stdin io.Reader, stdout, stderr io.Writer) int {
+		if command == "wp 'post' 'list' '--s=hello world'" {
+			fmt.Fprintln(stdout, "1 Hello")
+			return 0
+		}
+		fmt.Fprintln(stderr, "Error: unexpected command "+command)
+		return 2
+	})
+	m := server.manager(SSHManagerOptions{})
+	defer m.Close()
+
+	client, err := m.Client(SSHConfig{Install: "mysite"})
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := client.StreamWPCLI([]string{"post", "list", "--s=hello world"}, &stdout, &stderr); err != nil {
+		t.Fatalf("StreamWPCLI() failed: %v (stderr %q)", err, stderr.String())
+	}
+	if stdout.String() != "1 Hello\n" {
+		t.Errorf("StreamWPCLI() output = %q", stdout.String())
+	}
+
+	// The remote exit status is available to callers
+	err = client.StreamWPCLI([]string{"plugin", "list"}, &stdout, &stderr)
+	var exitErr *ssh.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitStatus() != 2 {
+		t.Errorf("expected exit status 2, got %v", err)
+	}
+
+	// Injection attempts never reach the server
+	if err := client.StreamWPCLI([]string{"option", "get", "$(id)"}, &stdout, &stderr); err == nil {
+		t.Error("expected command substitution to be rejected")
+	}
+}
@@ -0,0 +1,296 @@
+package wpengine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// AgentAvailable reports whether an SSH agent is reachable through SSH_AUTH_SOCK
+func AgentAvailable() bool {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return false
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// sshAuth is the auth chain of one connection attempt
+type sshAuth struct {
+	signers   []ssh.Signer
+	agentConn net.Conn
+}
+
+// Close releases the agent connection
+func (a *sshAuth) Close() {
+	if a.agentConn != nil {
+		a.agentConn.Close()
+	}
+}
+
+// identity is a key from the credentials or an IdentityFile
+type identity struct {
+	name   string
+	path   string // empty for the configured key
+	signer ssh.Signer
+}
+
+// resolveSSHAuth builds the signers for a connection, best first:
+// identities held by the agent, the remaining agent keys (unless
+// IdentitiesOnly), then identities loaded from disk, each preceded by its
+// certificate when one exists
+func resolveSSHAuth(config SSHConfig, settings *SSHHostSettings) (*sshAuth, error) {
+	auth := &sshAuth{}
+	var problems []error
+
+	var identities []identity
+	if config.PrivateKey != "" {
+		signer, err := loadSSHKey([]byte(config.PrivateKey), "the configured key", "", config.Passphrase)
+		if err != nil {
+			problems = append(problems, err)
+		} else {
+			identities = append(identities, identity{name: "the configured key", signer: signer})
+		}
+	}
+	for _, path := range settings.IdentityFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// OpenSSH also skips identity files that don't exist
+			if !errors.Is(err, os.ErrNotExist) {
+				problems = append(problems, fmt.Errorf("failed to read %s: %w", path, err))
+			}
+			continue
+		}
+		signer, err := loadSSHKey(data, path, path+".pub", config.Passphrase)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		identities = append(identities, identity{name: path, path: path, signer: signer})
+	}
+
+	var agentSigners []ssh.Signer
+	if socket := agentSocket(config, settings); socket != "" {
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("failed to connect to SSH agent: %w", err))
+		} else {
+			auth.agentConn = conn
+			agentSigners, err = agent.NewClient(conn).Signers()
+			if err != nil {
+				problems = append(problems, fmt.Errorf("failed to list SSH agent keys: %w", err))
+			}
+		}
+	}
+
+	certs, err := loadSSHCertificates(settings.CertificateFiles, identities)
+	if err != nil {
+		problems = append(problems, err)
+	}
+
+	used := make([]bool, len(agentSigners))
+	add := func(signer ssh.Signer) {
+		for _, cert := range certs {
+			if sameSSHKey(cert.Key, signer.PublicKey()) {
+				if certSigner, err := ssh.NewCertSigner(cert, signer); err == nil {
+					auth.signers = append(auth.signers, certSigner)
+				}
+			}
+		}
+		auth.signers = append(auth.signers, signer)
+	}
+
+	// Identities the agent holds sign through the agent, avoiding prompts
+	var fromDisk []identity
+	for _, id := range identities {
+		found := false
+		for i, signer := range agentSigners {
+			if !used[i] && sameSSHKey(signer.PublicKey(), id.signer.PublicKey()) {
+				used[i], found = true, true
+				add(signer)
+			}
+		}
+		if !found {
+			fromDisk = append(fromDisk, id)
+		}
+	}
+	if !settings.IdentitiesOnly || len(identities) == 0 {
+		for i, signer := range agentSigners {
+			if !used[i] {
+				add(signer)
+			}
+		}
+	}
+	for _, id := range fromDisk {
+		add(id.signer)
+	}
+
+	if len(auth.signers) == 0 {
+		auth.Close()
+		if len(problems) > 0 {
+			return nil, fmt.Errorf("no usable SSH keys: %w", errors.Join(problems...))
+		}
+		return nil, errors.New("no SSH keys available: configure a key, add one to ssh-agent or set IdentityFile in ~/.ssh/config")
+	}
+	return auth, nil
+}
+
+// agentSocket returns the agent socket to use, or "" when disabled
+func agentSocket(config SSHConfig, settings *SSHHostSettings) string {
+	switch settings.IdentityAgent {
+	case "none":
+		return ""
+	case "", "SSH_AUTH_SOCK":
+	default:
+		// e.g. the 1Password agent
+		return settings.IdentityAgent
+	}
+
+	if config.AgentSocket != "" {
+		return config.AgentSocket
+	}
+	return os.Getenv("SSH_AUTH_SOCK")
+}
+
+// loadSSHKey parses a private key; encrypted keys whose public half is known
+// are decrypted only when the server accepts them
+func loadSSHKey(data []byte, name, pubPath string, passphrase func(string) (string, error)) (ssh.Signer, error) {
+	signer, err := ssh.ParsePrivateKey(data)
+	if err == nil {
+		return signer, nil
+	}
+
+	var missing *ssh.PassphraseMissingError
+	if !errors.As(err, &missing) {
+		if pub := readSSHPublicKey(pubPath); pub != nil && strings.HasPrefix(pub.Type(), "sk-") {
+			return nil, fmt.Errorf("%s is a hardware-backed key: add it to ssh-agent (ssh-add %s) to use it", name, strings.TrimSuffix(pubPath, ".pub"))
+		}
+		return nil, fmt.Errorf("failed to parse private key %s: %w", name, err)
+	}
+	if passphrase == nil {
+		return nil, fmt.Errorf("private key %s is encrypted: add it to ssh-agent or run stax interactively", name)
+	}
+
+	decrypt := func() (ssh.Signer, error) {
+		secret, err := passphrase(fmt.Sprintf("Enter passphrase for %s", name))
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.ParsePrivateKeyWithPassphrase(data, []byte(secret))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt private key %s: %w", name, err)
+		}
+		return signer, nil
+	}
+
+	pub := missing.PublicKey
+	if pub == nil {
+		pub = readSSHPublicKey(pubPath)
+	}
+	if pub == nil {
+		// Without the public key the server can't be asked first
+		return decrypt()
+	}
+	return &lazySigner{pub: pub, load: decrypt}, nil
+}
+
+// loadSSHCertificates reads CertificateFile entries and the <identity>-cert.pub
+// files next to identities
+func loadSSHCertificates(files []string, identities []identity) ([]*ssh.Certificate, error) {
+	var certs []*ssh.Certificate
+	for _, id := range identities {
+		if id.path == "" {
+			continue
+		}
+		if cert, ok := readSSHPublicKey(id.path + "-cert.pub").(*ssh.Certificate); ok {
+			certs = append(certs, cert)
+		}
+	}
+
+	for _, file := range files {
+		cert, ok := readSSHPublicKey(file).(*ssh.Certificate)
+		if !ok {
+			return certs, fmt.Errorf("%s is not an SSH certificate", file)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// readSSHPublicKey reads an authorized_keys format key, or nil
+func readSSHPublicKey(path string) ssh.PublicKey {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil
+	}
+	return pub
+}
+
+// sameSSHKey reports whether two public keys are equal
+func sameSSHKey(a, b ssh.PublicKey) bool {
+	return bytes.Equal(a.Marshal(), b.Marshal())
+}
+
+// lazySigner offers a public key and decrypts the private key on first use
+type lazySigner struct {
+	pub  ssh.PublicKey
+	load func() (ssh.Signer, error)
+
+	once   sync.Once
+	signer ssh.Signer
+	err    error
+}
+
+func (s *lazySigner) get() (ssh.Signer, error) {
+	s.once.Do(func() { s.signer, s.err = s.load() })
+	return s.signer, s.err
+}
+
+// PublicKey returns the key without decrypting it
+func (s *lazySigner) PublicKey() ssh.PublicKey {
+	return s.pub
+}
+
+// Sign decrypts the key if needed and signs data
+func (s *lazySigner) Sign(rand io.Reader, data []byte) (*ssh.Signature, error) {
+	signer, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(rand, data)
+}
+
+// SignWithAlgorithm decrypts the key if needed and signs data with algorithm
+func (s *lazySigner) SignWithAlgorithm(rand io.Reader, data []byte, algorithm string) (*ssh.Signature, error) {
+	signer, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	if as, ok := signer.(ssh.AlgorithmSigner); ok {
+		return as.SignWithAlgorithm(rand, data, algorithm)
+	}
+	if algorithm != "" && algorithm != signer.PublicKey().Type() {
+		return nil, fmt.Errorf("key does not support algorithm %s", algorithm)
+	}
+	return signer.Sign(rand, data)
+}
@@ -0,0 +1,278 @@
+package wpengine
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// newTestKey generates an ed25519 key
+func newTestKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+// encodeTestKey returns key in OpenSSH PEM format, encrypted when passphrase is set
+func encodeTestKey(t *testing.T, key ed25519.PrivateKey, passphrase string) []byte {
+	t.Helper()
+	var block *pem.Block
+	var err error
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(key, "test")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "test", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(block)
+}
+
+// newTestAgent serves an in-process agent holding keys on a unix socket
+func newTestAgent(t *testing.T, keys ...ed25519.PrivateKey) string {
+	t.Helper()
+
+	keyring := agent.NewKeyring()
+	for _, key := range keys {
+		if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+			t.Fatalf("failed to add key to agent: %v", err)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(dir, "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+		os.RemoveAll(dir)
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+	return socket
+}
+
+// authServer accepts the given public keys and records the users that logged in
+type authServer struct {
+	*testSSHServer
+	mu    sync.Mutex
+	users []string
+}
+
+func newAuthServer(t *testing.T, accept func(user string, key ssh.PublicKey) bool) *authServer {
+	s := &authServer{}
+	s.testSSHServer = newTestSSHServer(t, echoHandler, func(config *ssh.ServerConfig) {
+		config.PublicKeyCallback = func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if !accept(conn.User(), key) {
+				return nil, errors.New("key not accepted")
+			}
+			s.mu.Lock()
+			s.users = append(s.users, conn.User())
+			s.mu.Unlock()
+			return nil, nil
+		}
+	})
+	return s
+}
+
+// sshConfig returns a config that dials the server with no ambient SSH setup
+func (s *authServer) sshConfig(t *testing.T) SSHConfig {
+	host, port, _ := net.SplitHostPort(s.addr)
+	var portNum int
+	fmt.Sscanf(port, "%d", &portNum)
+
+	return SSHConfig{
+		Host:            host,
+		Port:            portNum,
+		Install:         "mysite",
+		ConfigFile:      filepath.Join(t.TempDir(), "missing"),
+		AgentSocket:     "",
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	}
+}
+
+func acceptKey(key ed25519.PrivateKey) func(string, ssh.PublicKey) bool {
+	pub, _ := ssh.NewPublicKey(key.Public())
+	return func(_ string, offered ssh.PublicKey) bool {
+		return sameSSHKey(pub, offered)
+	}
+}
+
+func TestDialWithAgent(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	key := newTestKey(t)
+	server := newAuthServer(t, acceptKey(key))
+
+	config := server.sshConfig(t)
+	config.AgentSocket = newTestAgent(t, newTestKey(t), key)
+
+	client, err := dialSSH(config)
+	if err != nil {
+		t.Fatalf("dialSSH() failed: %v", err)
+	}
+	defer client.Close()
+
+	if len(server.users) != 1 || server.users[0] != "mysite@mysite" {
+		t.Errorf("unexpected logins: %v", server.users)
+	}
+}
+
+func TestDialEncryptedKey(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	agentKey := newTestKey(t)
+	fileKey := newTestKey(t)
+
+	prompts := 0
+	passphrase := func(prompt string) (string, error) {
+		prompts++
+		return "hunter2", nil
+	}
+
+	// The agent key is accepted, so the encrypted key is never decrypted
+	server := newAuthServer(t, acceptKey(agentKey))
+	config := server.sshConfig(t)
+	config.PrivateKey = string(encodeTestKey(t, fileKey, "hunter2"))
+	config.AgentSocket = newTestAgent(t, agentKey)
+	config.Passphrase = passphrase
+
+	client, err := dialSSH(config)
+	if err != nil {
+		t.Fatalf("dialSSH() with agent key failed: %v", err)
+	}
+	client.Close()
+	if prompts != 0 {
+		t.Errorf("expected no passphrase prompt, got %d", prompts)
+	}
+
+	// Only the encrypted key is accepted, so it is decrypted once
+	server = newAuthServer(t, acceptKey(fileKey))
+	config.Host, config.Port = server.sshConfig(t).Host, server.sshConfig(t).Port
+
+	client, err = dialSSH(config)
+	if err != nil {
+		t.Fatalf("dialSSH() with encrypted key failed: %v", err)
+	}
+	client.Close()
+	if prompts != 1 {
+		t.Errorf("expected 1 passphrase prompt, got %d", prompts)
+	}
+
+	// Without a way to ask, encrypted keys are reported
+	config.Passphrase = nil
+	config.AgentSocket = ""
+	if _, err := dialSSH(config); err == nil {
+		t.Error("expected an error for an encrypted key without a passphrase prompt")
+	}
+}
+
+func TestDialCertificateFromSSHConfig(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	key := newTestKey(t)
+	caSigner, err := ssh.NewSignerFromKey(newTestKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The server trusts certificates from the CA for the deploy principal
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool { return sameSSHKey(auth, caSigner.PublicKey()) },
+	}
+	server := newAuthServer(t, func(user string, offered ssh.PublicKey) bool {
+		_, err := checker.Authenticate(fakeConnMetadata(user), offered)
+		return err == nil
+	})
+
+	pub, _ := ssh.NewPublicKey(key.Public())
+	cert := &ssh.Certificate{
+		Key:             pub,
+		CertType:        ssh.UserCert,
+		KeyId:           "deploy",
+		ValidPrincipals: []string{"deploy"},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err := cert.SignCert(rand.Reader, caSigner); err != nil {
+		t.Fatalf("failed to sign certificate: %v", err)
+	}
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "id_deploy")
+	os.WriteFile(keyPath, encodeTestKey(t, key, ""), 0600)
+	os.WriteFile(keyPath+"-cert.pub", ssh.MarshalAuthorizedKey(cert), 0644)
+
+	host, port, _ := net.SplitHostPort(server.addr)
+	configFile := filepath.Join(dir, "config")
+	os.WriteFile(configFile, []byte(fmt.Sprintf(`Host *.example.com
+  User nobody
+
+Host wpe
+  HostName %s
+  Port %s
+  User deploy
+  IdentityFile %s
+  IdentitiesOnly yes
+`, host, port, keyPath)), 0600)
+
+	client, err := dialSSH(SSHConfig{
+		Host:            "wpe",
+		Install:         "mysite",
+		ConfigFile:      configFile,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		t.Fatalf("dialSSH() failed: %v", err)
+	}
+	client.Close()
+
+	if len(server.users) != 1 || server.users[0] != "deploy" {
+		t.Errorf("unexpected logins: %v", server.users)
+	}
+}
+
+func TestDialNoKeys(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	server := newAuthServer(t, func(string, ssh.PublicKey) bool { return true })
+
+	if _, err := dialSSH(server.sshConfig(t)); err == nil {
+		t.Error("expected an error without any keys")
+	}
+}
+
+// fakeConnMetadata satisfies ssh.ConnMetadata for certificate checks
+type fakeConnMetadata string
+
+func (m fakeConnMetadata) User() string          { return string(m) }
+func (m fakeConnMetadata) SessionID() []byte     { return nil }
+func (m fakeConnMetadata) ClientVersion() []byte { return nil }
+func (m fakeConnMetadata) ServerVersion() []byte { return nil }
+func (m fakeConnMetadata) RemoteAddr() net.Addr  { return nil }
+func (m fakeConnMetadata) LocalAddr() net.Addr   { return nil }
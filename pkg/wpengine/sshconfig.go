@@ -0,0 +1,192 @@
+package wpengine
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SSHHostSettings are the ~/.ssh/config settings that apply to one host
+type SSHHostSettings struct {
+	HostName         string
+	User             string
+	Port             int
+	IdentityFiles    []string
+	CertificateFiles []string
+	IdentityAgent    string // socket path, "SSH_AUTH_SOCK" or "none"
+	IdentitiesOnly   bool
+}
+
+// DefaultSSHConfigFile returns ~/.ssh/config
+func DefaultSSHConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// LoadSSHHostSettings reads the settings for host from an OpenSSH client
+// config file; a missing file yields empty settings
+func LoadSSHHostSettings(file, host string) (*SSHHostSettings, error) {
+	if file == "" {
+		return &SSHHostSettings{}, nil
+	}
+
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return &SSHHostSettings{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SSH config: %w", err)
+	}
+	defer f.Close()
+
+	settings, err := parseSSHConfig(f, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return settings, nil
+}
+
+// parseSSHConfig applies Host blocks matching host, keeping the first value
+// of each keyword like OpenSSH; Match blocks and Include are not supported
+func parseSSHConfig(r io.Reader, host string) (*SSHHostSettings, error) {
+	settings := &SSHHostSettings{}
+	seen := map[string]bool{}
+	active := true
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		keyword, args := splitSSHConfigLine(scanner.Text())
+		if keyword == "" {
+			continue
+		}
+
+		switch keyword {
+		case "host":
+			active = matchSSHHost(host, args)
+			continue
+		case "match":
+			active = false
+			continue
+		}
+		if !active || len(args) == 0 {
+			continue
+		}
+
+		value := args[0]
+		switch keyword {
+		case "identityfile":
+			settings.IdentityFiles = append(settings.IdentityFiles, expandSSHPath(value, host))
+			continue
+		case "certificatefile":
+			settings.CertificateFiles = append(settings.CertificateFiles, expandSSHPath(value, host))
+			continue
+		}
+
+		if seen[keyword] {
+			continue
+		}
+		seen[keyword] = true
+
+		switch keyword {
+		case "hostname":
+			settings.HostName = strings.ReplaceAll(value, "%h", host)
+		case "user":
+			settings.User = value
+		case "port":
+			port, err := strconv.Atoi(value)
+			if err != nil || port <= 0 || port > 65535 {
+				return nil, fmt.Errorf("line %d: invalid port %q", line, value)
+			}
+			settings.Port = port
+		case "identityagent":
+			settings.IdentityAgent = value
+			if value != "none" && value != "SSH_AUTH_SOCK" {
+				settings.IdentityAgent = expandSSHPath(value, host)
+			}
+		case "identitiesonly":
+			settings.IdentitiesOnly = strings.EqualFold(value, "yes")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// splitSSHConfigLine returns the lower-cased keyword and arguments of a line
+func splitSSHConfigLine(line string) (string, []string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil
+	}
+
+	// The keyword may be separated from its arguments by whitespace or "="
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return strings.ToLower(line), nil
+	}
+	keyword := strings.ToLower(line[:end])
+	rest := strings.TrimLeft(line[end:], " \t")
+	rest = strings.TrimLeft(strings.TrimPrefix(rest, "="), " \t")
+
+	var args []string
+	for rest != "" {
+		if rest[0] == '"' {
+			closing := strings.IndexByte(rest[1:], '"')
+			if closing < 0 {
+				args = append(args, rest[1:])
+				break
+			}
+			args = append(args, rest[1:closing+1])
+			rest = strings.TrimLeft(rest[closing+2:], " \t")
+			continue
+		}
+
+		next := strings.IndexAny(rest, " \t")
+		if next < 0 {
+			args = append(args, rest)
+			break
+		}
+		args = append(args, rest[:next])
+		rest = strings.TrimLeft(rest[next:], " \t")
+	}
+	return keyword, args
+}
+
+// matchSSHHost reports whether host matches a Host line's patterns; a
+// matching negated pattern excludes the host
+func matchSSHHost(host string, patterns []string) bool {
+	host = strings.ToLower(host)
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.ToLower(strings.TrimPrefix(pattern, "!"))
+
+		if ok, _ := path.Match(pattern, host); ok {
+			if negated {
+				return false
+			}
+			matched = true
+		}
+	}
+	return matched
+}
+
+// expandSSHPath expands ~ and the %d (home) and %h (host) tokens
+func expandSSHPath(p, host string) string {
+	home, _ := os.UserHomeDir()
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		p = home + p[1:]
+	}
+	return strings.NewReplacer("%d", home, "%h", host, "%%", "%").Replace(p)
+}
@@ -0,0 +1,95 @@
+package wpengine
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSSHConfig(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	config := `# Global defaults come last in OpenSSH order
+Host ssh.wpengine.net *.ssh.wpengine.net
+    User mysite
+    Port=2222
+    IdentityFile ~/.ssh/wpengine_ed25519
+    IdentityFile "~/.ssh/with space"
+    CertificateFile ~/.ssh/wpengine-cert.pub
+    IdentityAgent "~/Library/Group Containers/op/agent.sock"
+    IdentitiesOnly yes
+
+Host *.wpengine.net !ssh.wpengine.net
+    User excluded
+
+Match host ssh.wpengine.net
+    User matched
+
+Host *
+    User fallback
+    Port 22
+    IdentityFile ~/.ssh/id_ed25519
+`
+
+	tests := []struct {
+		host string
+		want SSHHostSettings
+	}{
+		{
+			host: "ssh.wpengine.net",
+			want: SSHHostSettings{
+				User:             "mysite",
+				Port:             2222,
+				IdentityFiles:    []string{home + "/.ssh/wpengine_ed25519", home + "/.ssh/with space", home + "/.ssh/id_ed25519"},
+				CertificateFiles: []string{home + "/.ssh/wpengine-cert.pub"},
+				IdentityAgent:    home + "/Library/Group Containers/op/agent.sock",
+				IdentitiesOnly:   true,
+			},
+		},
+		{
+			host: "other.wpengine.net",
+			want: SSHHostSettings{
+				User:          "excluded",
+				Port:          22,
+				IdentityFiles: []string{home + "/.ssh/id_ed25519"},
+			},
+		},
+		{
+			host: "example.com",
+			want: SSHHostSettings{
+				User:          "fallback",
+				Port:          22,
+				IdentityFiles: []string{home + "/.ssh/id_ed25519"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			got, err := parseSSHConfig(strings.NewReader(config), tt.host)
+			if err != nil {
+				t.Fatalf("parseSSHConfig() failed: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("parseSSHConfig() =\n%+v\nwant\n%+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSSHConfigInvalidPort(t *testing.T) {
+	if _, err := parseSSHConfig(strings.NewReader("Port nope\n"), "host"); err == nil {
+		t.Error("expected an error for an invalid port")
+	}
+}
+
+func TestLoadSSHHostSettingsMissingFile(t *testing.T) {
+	settings, err := LoadSSHHostSettings(filepath.Join(t.TempDir(), "config"), "ssh.wpengine.net")
+	if err != nil {
+		t.Fatalf("LoadSSHHostSettings() failed: %v", err)
+	}
+	if !reflect.DeepEqual(*settings, SSHHostSettings{}) {
+		t.Errorf("expected empty settings, got %+v", *settings)
+	}
+}
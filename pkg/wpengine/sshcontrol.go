@@ -0,0 +1,298 @@
+package wpengine
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// A control socket lets subprocesses such as rsync run remote commands over
+// the shared connection instead of opening their own, like OpenSSH's
+// ControlMaster. Each socket connection carries one command using frames of
+// a type byte, a big-endian uint32 length and the payload.
+const (
+	frameCommand byte = iota + 1 // client -> server: command line
+	frameStdin                   // client -> server: stdin data, empty at EOF
+	frameStdout                  // server -> client: stdout data
+	frameStderr                  // server -> client: stderr data
+	frameExit                    // server -> client: uint32 exit status
+)
+
+// maxFrameSize bounds frames read from the socket
+const maxFrameSize = 1 << 20
+
+// controlSocket serves commands for one connection on a local unix socket
+type controlSocket struct {
+	dir      string
+	path     string
+	listener net.Listener
+}
+
+// ControlSocket returns the path of a local socket that runs commands over
+// this client's connection, starting it on first use
+func (c *SSHClient) ControlSocket() (string, error) {
+	if c.conn == nil {
+		return "", errors.New("not connected")
+	}
+	return c.conn.controlSocket()
+}
+
+// controlSocket starts the connection's control socket if needed
+func (c *sshConn) controlSocket() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return "", errors.New("SSH connection is closed")
+	}
+	if c.control != nil {
+		return c.control.path, nil
+	}
+
+	// The private directory keeps other users away from the socket
+	dir, err := os.MkdirTemp("", "stax-ssh-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create control socket directory: %w", err)
+	}
+	path := filepath.Join(dir, "control.sock")
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to create control socket: %w", err)
+	}
+
+	c.control = &controlSocket{dir: dir, path: path, listener: listener}
+	go c.serveControl(listener)
+
+	return path, nil
+}
+
+// close stops accepting commands and removes the socket
+func (s *controlSocket) close() {
+	s.listener.Close()
+	os.RemoveAll(s.dir)
+}
+
+// serveControl accepts control connections until the listener closes
+func (c *sshConn) serveControl(listener net.Listener) {
+	for {
+		nc, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go c.handleControl(nc)
+	}
+}
+
+// handleControl runs one command for a control connection
+func (c *sshConn) handleControl(nc net.Conn) {
+	defer nc.Close()
+	out := &frameWriter{w: nc}
+
+	typ, command, err := readFrame(nc)
+	if err != nil || typ != frameCommand {
+		return
+	}
+
+	status, err := c.runControlCommand(string(command), nc, out)
+	if err != nil {
+		out.writeFrame(frameStderr, []byte(fmt.Sprintf("stax: %v\n", err)))
+	}
+
+	var payload [4]byte
+	binary.BigEndian.PutUint32(payload[:], uint32(status))
+	out.writeFrame(frameExit, payload[:])
+}
+
+// runControlCommand runs a command, relaying stdin frames from r and output to out
+func (c *sshConn) runControlCommand(command string, r io.Reader, out *frameWriter) (int, error) {
+	session, err := c.session()
+	if err != nil {
+		return 255, err
+	}
+	defer session.Close()
+
+	session.Stdout = out.stream(frameStdout)
+	session.Stderr = out.stream(frameStderr)
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return 255, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+
+	if err := session.Start(command); err != nil {
+		return 255, fmt.Errorf("failed to start command: %w", err)
+	}
+
+	go func() {
+		defer stdin.Close()
+		for {
+			typ, data, err := readFrame(r)
+			if err != nil || typ != frameStdin || len(data) == 0 {
+				return
+			}
+			if _, err := stdin.Write(data); err != nil {
+				return
+			}
+		}
+	}()
+
+	return exitStatus(session.Wait()), nil
+}
+
+// exitStatus converts a session result into a process exit status
+func exitStatus(err error) int {
+	var exitErr *ssh.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.ExitStatus()
+	default:
+		return 255
+	}
+}
+
+// RunControlCommand runs a command through a control socket, relaying stdio,
+// and returns the remote exit status
+func RunControlCommand(socket, command string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	nc, err := net.Dial("unix", socket)
+	if err != nil {
+		return 255, fmt.Errorf("failed to connect to control socket: %w", err)
+	}
+	defer nc.Close()
+
+	in := &frameWriter{w: nc}
+	if err := in.writeFrame(frameCommand, []byte(command)); err != nil {
+		return 255, fmt.Errorf("failed to send command: %w", err)
+	}
+
+	go func() {
+		io.Copy(in.stream(frameStdin), stdin)
+		in.writeFrame(frameStdin, nil)
+	}()
+
+	for {
+		typ, data, err := readFrame(nc)
+		if err != nil {
+			return 255, fmt.Errorf("control connection closed: %w", err)
+		}
+
+		switch typ {
+		case frameStdout:
+			if _, err := stdout.Write(data); err != nil {
+				return 255, err
+			}
+		case frameStderr:
+			if _, err := stderr.Write(data); err != nil {
+				return 255, err
+			}
+		case frameExit:
+			if len(data) != 4 {
+				return 255, errors.New("malformed exit status")
+			}
+			return int(binary.BigEndian.Uint32(data)), nil
+		}
+	}
+}
+
+// ControlShell returns the rsync remote shell (-e) that runs commands through
+// a control socket using the helper command
+func ControlShell(helper []string, socket string) string {
+	words := make([]string, 0, len(helper)+2)
+	for _, word := range slices.Concat(helper, []string{"--socket", socket}) {
+		// rsync splits the remote shell on whitespace outside quotes
+		if strings.ContainsAny(word, " \t\"'\\") {
+			word = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(word) + `"`
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+// ParseControlArgs extracts the remote command from the arguments rsync
+// passes to its remote shell: [-l user] [options] host command...
+func ParseControlArgs(args []string) (string, error) {
+	i := 0
+	for i < len(args) && strings.HasPrefix(args[i], "-") {
+		switch args[i] {
+		case "-l", "-p", "-o", "-i", "-F":
+			// Options with a value are skipped with their value
+			i++
+		}
+		i++
+	}
+	if i >= len(args)-1 {
+		return "", errors.New("usage: ssh-control --socket <path> [options] host command...")
+	}
+	return strings.Join(args[i+1:], " "), nil
+}
+
+// frameWriter serializes frames written from several goroutines
+type frameWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (f *frameWriter) writeFrame(typ byte, data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var header [5]byte
+	header[0] = typ
+	binary.BigEndian.PutUint32(header[1:], uint32(len(data)))
+	if _, err := f.w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := f.w.Write(data)
+	return err
+}
+
+// stream returns a writer that sends everything written as frames of typ
+func (f *frameWriter) stream(typ byte) io.Writer {
+	return frameStream{f, typ}
+}
+
+type frameStream struct {
+	f   *frameWriter
+	typ byte
+}
+
+func (s frameStream) Write(p []byte) (int, error) {
+	for chunk := p; len(chunk) > 0; {
+		n := min(len(chunk), maxFrameSize)
+		if err := s.f.writeFrame(s.typ, chunk[:n]); err != nil {
+			return 0, err
+		}
+		chunk = chunk[n:]
+	}
+	return len(p), nil
+}
+
+// readFrame reads one frame
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxFrameSize {
+		return 0, nil, fmt.Errorf("frame of %d bytes exceeds limit", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+	return header[0], data, nil
+}
@@ -0,0 +1,258 @@
+package wpengine
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// DefaultMaxSessions limits concurrent sessions on one connection; the
+	// gateway rejects channels beyond its per-connection limit
+	DefaultMaxSessions = 4
+
+	// DefaultKeepAliveInterval is how often connections are probed
+	DefaultKeepAliveInterval = 30 * time.Second
+)
+
+// SSHManagerOptions configure an SSHManager
+type SSHManagerOptions struct {
+	// MaxSessions limits concurrent sessions per connection (default DefaultMaxSessions)
+	MaxSessions int
+
+	// KeepAliveInterval is how often connections are probed (default DefaultKeepAliveInterval)
+	KeepAliveInterval time.Duration
+
+	// ControlHelper is the command rsync runs as its remote shell to reach a
+	// control socket, e.g. stax ssh-control; empty makes rsync connect itself
+	ControlHelper []string
+}
+
+// SSHManager keeps one authenticated connection per install and shares it
+// between every SSHClient it hands out
+type SSHManager struct {
+	opts SSHManagerOptions
+	dial func(SSHConfig) (*ssh.Client, error)
+
+	mu     sync.Mutex
+	conns  map[string]*sshConn
+	closed bool
+}
+
+// NewSSHManager creates a connection manager
+func NewSSHManager(opts SSHManagerOptions) *SSHManager {
+	if opts.MaxSessions <= 0 {
+		opts.MaxSessions = DefaultMaxSessions
+	}
+	if opts.KeepAliveInterval <= 0 {
+		opts.KeepAliveInterval = DefaultKeepAliveInterval
+	}
+
+	return &SSHManager{
+		opts:  opts,
+		dial:  dialSSH,
+		conns: make(map[string]*sshConn),
+	}
+}
+
+// Client returns a client for the install, connecting on first use
+// Closing the returned client is a no-op; the manager owns the connection
+func (m *SSHManager) Client(config SSHConfig) (*SSHClient, error) {
+	config = withSSHDefaults(config)
+	key := fmt.Sprintf("%s@%s:%d", config.Install, config.Host, config.Port)
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, errors.New("SSH manager is closed")
+	}
+	conn, ok := m.conns[key]
+	if !ok {
+		conn = newSSHConn(config, m.opts, m.dial)
+		m.conns[key] = conn
+	}
+	m.mu.Unlock()
+
+	// Connect eagerly so authentication errors surface here
+	if _, err := conn.connect(); err != nil {
+		return nil, err
+	}
+
+	return &SSHClient{conn: conn, config: config, shared: true}, nil
+}
+
+// Close closes every managed connection
+func (m *SSHManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	var errs []error
+	for key, conn := range m.conns {
+		if err := conn.close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(m.conns, key)
+	}
+	return errors.Join(errs...)
+}
+
+// sshConn is one shared connection that reconnects when it drops
+type sshConn struct {
+	config    SSHConfig
+	opts      SSHManagerOptions
+	dial      func(SSHConfig) (*ssh.Client, error)
+	sessions  chan struct{}
+	keepAlive time.Duration
+
+	mu      sync.Mutex
+	client  *ssh.Client
+	closed  bool
+	control *controlSocket
+}
+
+func newSSHConn(config SSHConfig, opts SSHManagerOptions, dial func(SSHConfig) (*ssh.Client, error)) *sshConn {
+	return &sshConn{
+		config:    config,
+		opts:      opts,
+		dial:      dial,
+		sessions:  make(chan struct{}, opts.MaxSessions),
+		keepAlive: opts.KeepAliveInterval,
+	}
+}
+
+// connect returns the live client, dialing when there is none
+func (c *sshConn) connect() (*ssh.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, errors.New("SSH connection is closed")
+	}
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	client, err := c.dial(c.config)
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+
+	go func() {
+		// Wait returns as soon as the transport dies
+		client.Wait()
+		c.drop(client)
+	}()
+	go c.probe(client)
+
+	return client, nil
+}
+
+// drop discards a dead client so the next session reconnects
+func (c *sshConn) drop(client *ssh.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == client {
+		c.client = nil
+	}
+	client.Close()
+}
+
+// probe sends keepalives until the client is dropped, closing it when the
+// server stops answering so half-open connections are detected
+func (c *sshConn) probe(client *ssh.Client) {
+	ticker := time.NewTicker(c.keepAlive)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.mu.Lock()
+		current := c.client == client
+		c.mu.Unlock()
+		if !current {
+			return
+		}
+
+		replied := make(chan error, 1)
+		go func() {
+			_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+			replied <- err
+		}()
+
+		select {
+		case err := <-replied:
+			if err != nil {
+				c.drop(client)
+				return
+			}
+		case <-time.After(c.keepAlive):
+			c.drop(client)
+			return
+		}
+	}
+}
+
+// session opens a session, waiting for a free slot and reconnecting once if
+// the connection has dropped
+func (c *sshConn) session() (*sshSession, error) {
+	c.sessions <- struct{}{}
+	release := sync.OnceFunc(func() { <-c.sessions })
+
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		client, err := c.connect()
+		if err != nil {
+			release()
+			return nil, err
+		}
+
+		session, err := client.NewSession()
+		if err == nil {
+			return &sshSession{Session: session, release: release}, nil
+		}
+		lastErr = err
+
+		// A rejected channel means the connection is alive but refused us
+		var openErr *ssh.OpenChannelError
+		if errors.As(err, &openErr) {
+			break
+		}
+		c.drop(client)
+	}
+
+	release()
+	return nil, fmt.Errorf("failed to create SSH session: %w", lastErr)
+}
+
+// close closes the connection and its control socket
+func (c *sshConn) close() error {
+	c.mu.Lock()
+	client, control := c.client, c.control
+	c.client, c.control = nil, nil
+	c.closed = true
+	c.mu.Unlock()
+
+	if control != nil {
+		control.close()
+	}
+	if client != nil {
+		return client.Close()
+	}
+	return nil
+}
+
+// sshSession is a session holding one of its connection's session slots
+type sshSession struct {
+	*ssh.Session
+	release func()
+}
+
+// Close closes the session and frees its slot
+func (s *sshSession) Close() error {
+	defer s.release()
+	return s.Session.Close()
+}
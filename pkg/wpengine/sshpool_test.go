@@ -0,0 +1,268 @@
+package wpengine
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// echoHandler echoes the command, or stdin for "cat"
+func echoHandler(command string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if command == "cat" {
+		io.Copy(stdout, stdin)
+		return 0
+	}
+	fmt.Fprintln(stdout, command)
+	return 0
+}
+
+func TestSSHManagerSharesConnection(t *testing.T) {
+	server := newTestSSHServer(t, echoHandler)
+	m := server.manager(SSHManagerOptions{})
+	defer m.Close()
+
+	config := SSHConfig{Host: "gateway.test", Install: "mysite"}
+	first, err := m.Client(config)
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+	second, err := m.Client(config)
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	for _, client := range []*SSHClient{first, second} {
+		output, err := client.ExecuteCommand("hello")
+		if err != nil {
+			t.Fatalf("ExecuteCommand() failed: %v", err)
+		}
+		if output != "hello\n" {
+			t.Errorf("ExecuteCommand() = %q", output)
+		}
+	}
+
+	// Closing a shared client leaves the connection up for the others
+	first.Close()
+	if _, err := second.ExecuteCommand("again"); err != nil {
+		t.Errorf("ExecuteCommand() after closing another client failed: %v", err)
+	}
+
+	if n := server.accepted(); n != 1 {
+		t.Errorf("expected 1 connection, got %d", n)
+	}
+
+	// A different install gets its own connection
+	if _, err := m.Client(SSHConfig{Host: "gateway.test", Install: "othersite"}); err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+	if n := server.accepted(); n != 2 {
+		t.Errorf("expected 2 connections, got %d", n)
+	}
+}
+
+func TestSSHManagerSessionLimit(t *testing.T) {
+	var active, peak int32
+	server := newTestSSHServer(t, func(command string, stdin io.Reader, stdout, stderr io.Writer) int {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return 0
+	})
+
+	m := server.manager(SSHManagerOptions{MaxSessions: 2})
+	defer m.Close()
+
+	client, err := m.Client(SSHConfig{Install: "mysite"})
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 6)
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.ExecuteCommand("work"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("ExecuteCommand() failed: %v", err)
+	}
+	if peak > 2 {
+		t.Errorf("expected at most 2 concurrent sessions, got %d", peak)
+	}
+}
+
+func TestSSHManagerReconnect(t *testing.T) {
+	server := newTestSSHServer(t, echoHandler)
+	m := server.manager(SSHManagerOptions{})
+	defer m.Close()
+
+	client, err := m.Client(SSHConfig{Install: "mysite"})
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+	if _, err := client.ExecuteCommand("before"); err != nil {
+		t.Fatalf("ExecuteCommand() failed: %v", err)
+	}
+
+	server.dropAll()
+
+	// The drop is noticed asynchronously or on the next session
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		output, err := client.ExecuteCommand("after")
+		if err == nil {
+			if output != "after\n" {
+				t.Errorf("ExecuteCommand() = %q", output)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("ExecuteCommand() did not recover: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if n := server.accepted(); n != 2 {
+		t.Errorf("expected 2 connections after reconnect, got %d", n)
+	}
+}
+
+func TestSSHManagerKeepAlive(t *testing.T) {
+	server := newTestSSHServer(t, echoHandler)
+	m := server.manager(SSHManagerOptions{KeepAliveInterval: 5 * time.Millisecond})
+	defer m.Close()
+
+	client, err := m.Client(SSHConfig{Install: "mysite"})
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	// Answered keepalives keep the connection
+	time.Sleep(50 * time.Millisecond)
+	if _, err := client.ExecuteCommand("still here"); err != nil {
+		t.Fatalf("ExecuteCommand() failed: %v", err)
+	}
+	if n := server.accepted(); n != 1 {
+		t.Errorf("expected keepalives to keep 1 connection, got %d", n)
+	}
+}
+
+func TestSSHManagerClosed(t *testing.T) {
+	server := newTestSSHServer(t, echoHandler)
+	m := server.manager(SSHManagerOptions{})
+
+	client, err := m.Client(SSHConfig{Install: "mysite"})
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+	m.Close()
+
+	if _, err := client.ExecuteCommand("late"); err == nil {
+		t.Error("expected ExecuteCommand() to fail after the manager closed")
+	}
+	if _, err := m.Client(SSHConfig{Install: "mysite"}); err == nil {
+		t.Error("expected Client() to fail after the manager closed")
+	}
+}
+
+func TestControlSocket(t *testing.T) {
+	server := newTestSSHServer(t, func(command string, stdin io.Reader, stdout, stderr io.Writer) int {
+		if command == "rsync --server -e.LsfxC . /sites/mysite/" {
+			io.Copy(stdout, stdin)
+			fmt.Fprint(stderr, "done")
+			return 3
+		}
+		return 127
+	})
+	m := server.manager(SSHManagerOptions{ControlHelper: []string{"/usr/local/bin/stax", "ssh-control"}})
+	defer m.Close()
+
+	client, err := m.Client(SSHConfig{Install: "mysite"})
+	if err != nil {
+		t.Fatalf("Client() failed: %v", err)
+	}
+
+	shell, ok := client.controlShell()
+	if !ok {
+		t.Fatal("expected a control shell")
+	}
+	socket, _ := client.ControlSocket()
+	if shell != "/usr/local/bin/stax ssh-control --socket "+socket {
+		t.Errorf("controlShell() = %q", shell)
+	}
+
+	// rsync invokes the remote shell as: [-l user] host command...
+	command, err := ParseControlArgs([]string{"-l", "mysite@mysite", "ssh.wpengine.net", "rsync", "--server", "-e.LsfxC", ".", "/sites/mysite/"})
+	if err != nil {
+		t.Fatalf("ParseControlArgs() failed: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	status, err := RunControlCommand(socket, command, strings.NewReader("file data"), &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("RunControlCommand() failed: %v", err)
+	}
+	if status != 3 {
+		t.Errorf("expected exit status 3, got %d", status)
+	}
+	if stdout.String() != "file data" || stderr.String() != "done" {
+		t.Errorf("unexpected output: stdout %q, stderr %q", stdout.String(), stderr.String())
+	}
+
+	if n := server.accepted(); n != 1 {
+		t.Errorf("expected the control socket to reuse 1 connection, got %d", n)
+	}
+}
+
+func TestControlShell(t *testing.T) {
+	got := ControlShell([]string{"/Applications/My Tools/stax", "ssh-control"}, "/tmp/stax-ssh-1/control.sock")
+	want := `"/Applications/My Tools/stax" ssh-control --socket /tmp/stax-ssh-1/control.sock`
+	if got != want {
+		t.Errorf("ControlShell() = %s, want %s", got, want)
+	}
+}
+
+func TestParseControlArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{[]string{"host", "uptime"}, "uptime", false},
+		{[]string{"-l", "user", "-p", "22", "host", "rsync", "--server", "."}, "rsync --server .", false},
+		{[]string{"-C", "host", "ls"}, "ls", false},
+		{[]string{"host"}, "", true},
+		{[]string{"-l", "user"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := ParseControlArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseControlArgs() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseControlArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
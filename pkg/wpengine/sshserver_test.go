@@ -0,0 +1,239 @@
+package wpengine
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// execHandler runs a command for the test server, returning its exit status
+type execHandler func(command string, stdin io.Reader, stdout, stderr io.Writer) int
+
+// testSSHServer is an in-process SSH server that runs exec requests
+type testSSHServer struct {
+	addr     string
+	config   *ssh.ServerConfig
+	listener net.Listener
+	handler  execHandler
+
+	mu          sync.Mutex
+	conns       []net.Conn
+	connections int          // connections accepted
+	ptyTerm     string       // TERM of the last PTY request
+	windowSizes []WindowSize // initial PTY size followed by window changes
+}
+
+// newTestSSHServer starts a server that accepts any client unless configure
+// installs client authentication
+func newTestSSHServer(t *testing.T, handler execHandler, configure ...func(*ssh.ServerConfig)) *testSSHServer {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate host key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("failed to create host signer: %v", err)
+	}
+
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+	for _, c := range configure {
+		config.NoClientAuth = false
+		c(config)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &testSSHServer{addr: listener.Addr().String(), config: config, listener: listener, handler: handler}
+	go s.serve()
+	t.Cleanup(func() {
+		listener.Close()
+		s.dropAll()
+	})
+	return s
+}
+
+// dial connects to the server without host key checks
+func (s *testSSHServer) dial(config SSHConfig) (*ssh.Client, error) {
+	return ssh.Dial("tcp", s.addr, &ssh.ClientConfig{
+		User:            config.Install,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+}
+
+// manager returns an SSH manager that dials the server
+func (s *testSSHServer) manager(opts SSHManagerOptions) *SSHManager {
+	m := NewSSHManager(opts)
+	m.dial = s.dial
+	return m
+}
+
+// dropAll closes every open connection, as a network failure would
+func (s *testSSHServer) dropAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+// windows returns the PTY sizes the server has seen
+func (s *testSSHServer) windows() []WindowSize {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]WindowSize(nil), s.windowSizes...)
+}
+
+// accepted returns how many connections the server has accepted
+func (s *testSSHServer) accepted() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+func (s *testSSHServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.connections++
+		s.mu.Unlock()
+
+		go s.handleConn(conn)
+	}
+}
+
+func (s *testSSHServer) handleConn(conn net.Conn) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		switch newChannel.ChannelType() {
+		case "session":
+			channel, requests, err := newChannel.Accept()
+			if err != nil {
+				continue
+			}
+			go s.handleSession(channel, requests)
+		case "direct-tcpip":
+			go s.handleDirectTCPIP(newChannel)
+		default:
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+		}
+	}
+}
+
+// handleDirectTCPIP connects a forwarded channel to its target
+func (s *testSSHServer) handleDirectTCPIP(newChannel ssh.NewChannel) {
+	var target struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, "invalid payload")
+		return
+	}
+
+	conn, err := net.Dial("tcp", net.JoinHostPort(target.Host, fmt.Sprint(target.Port)))
+	if err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	defer conn.Close()
+
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		return
+	}
+	defer channel.Close()
+	go ssh.DiscardRequests(requests)
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(conn, channel)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(channel, conn)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+func (s *testSSHServer) handleSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+
+	for req := range requests {
+		var payload struct{ Command string }
+		switch req.Type {
+		case "pty-req":
+			var pty struct {
+				Term                         string
+				Columns, Rows, Width, Height uint32
+				Modes                        string
+			}
+			if err := ssh.Unmarshal(req.Payload, &pty); err != nil {
+				req.Reply(false, nil)
+				continue
+			}
+			s.mu.Lock()
+			s.ptyTerm = pty.Term
+			s.windowSizes = append(s.windowSizes, WindowSize{Width: int(pty.Columns), Height: int(pty.Rows)})
+			s.mu.Unlock()
+			req.Reply(true, nil)
+			continue
+		case "window-change":
+			var size struct{ Columns, Rows, Width, Height uint32 }
+			if err := ssh.Unmarshal(req.Payload, &size); err == nil {
+				s.mu.Lock()
+				s.windowSizes = append(s.windowSizes, WindowSize{Width: int(size.Columns), Height: int(size.Rows)})
+				s.mu.Unlock()
+			}
+			continue
+		case "shell":
+			// The handler sees a shell as an empty command
+		case "exec":
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				req.Reply(false, nil)
+				return
+			}
+		default:
+			req.Reply(false, nil)
+			continue
+		}
+		req.Reply(true, nil)
+
+		// Keep serving requests such as window changes while the command runs
+		go func() {
+			status := s.handler(payload.Command, channel, channel, channel.Stderr())
+
+			var exit [4]byte
+			binary.BigEndian.PutUint32(exit[:], uint32(status))
+			channel.SendRequest("exit-status", false, exit[:])
+			channel.Close()
+		}()
+	}
+}
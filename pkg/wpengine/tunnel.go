@@ -0,0 +1,77 @@
+package wpengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// DialRemote opens a connection to addr as seen from the remote server
+func (c *SSHClient) DialRemote(network, addr string) (net.Conn, error) {
+	client, err := c.conn.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.Dial(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open forwarded connection to %s: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// Forward relays connections accepted on listener to addr on the remote side
+// until ctx is done; failures of single connections go to onError
+func (c *SSHClient) Forward(ctx context.Context, listener net.Listener, network, addr string, onError func(error)) error {
+	stop := context.AfterFunc(ctx, func() { listener.Close() })
+	defer stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		local, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer local.Close()
+
+			remote, err := c.DialRemote(network, addr)
+			if err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				return
+			}
+			defer remote.Close()
+
+			// Close both ends on cancellation so the copies return
+			stop := context.AfterFunc(ctx, func() {
+				local.Close()
+				remote.Close()
+			})
+			defer stop()
+
+			copied := make(chan struct{}, 2)
+			go func() {
+				io.Copy(remote, local)
+				copied <- struct{}{}
+			}()
+			go func() {
+				io.Copy(local, remote)
+				copied <- struct{}{}
+			}()
+			<-copied
+		}()
+	}
+}
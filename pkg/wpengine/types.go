@@ -2,6 +2,8 @@ package wpengine
 
 import (
 	"time"
+
+	"golang.org/x/crypto/ssh"
 )
 
 // Install represents a WPEngine installation
@@ -31,20 +33,33 @@ type InstallDetails struct {
 
 // Backup represents a WPEngine backup
 type Backup struct {
-	ID        string    `json:"id"`
-	Type      string    `json:"type"`
-	CreatedAt time.Time `json:"created_at"`
-	Size      int64     `json:"size"`
-	Status    string    `json:"status"`
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	Size        int64     `json:"size"`
+	Status      string    `json:"status"`
 }
 
 // SSHConfig represents SSH connection configuration
 type SSHConfig struct {
 	Host       string
-	Port       int
+	Port       int // Defaults to the Port in ~/.ssh/config, then 22
 	User       string
-	PrivateKey string
+	PrivateKey string // Optional when the agent or ~/.ssh/config supplies keys
 	Install    string
+
+	// ConfigFile is the OpenSSH client config read for the host (default ~/.ssh/config)
+	ConfigFile string
+
+	// AgentSocket overrides SSH_AUTH_SOCK
+	AgentSocket string
+
+	// Passphrase asks for the passphrase of an encrypted key once the server accepts it
+	Passphrase func(prompt string) (string, error)
+
+	// HostKeyCallback overrides verification against ~/.ssh/known_hosts
+	HostKeyCallback ssh.HostKeyCallback
 }
 
 // DatabaseOptions represents database export options
@@ -79,23 +94,25 @@ type ExportOptions struct {
 	Compress       bool
 }
 
-// ListInstallsResponse represents the API response for listing installs
-type ListInstallsResponse struct {
-	Results []Install `json:"results"`
-	Count   int       `json:"count"`
-	Next    string    `json:"next,omitempty"`
-	Prev    string    `json:"prev,omitempty"`
+// Page is one page of a paginated API list response
+// Next and Previous are absolute URLs, empty on the last and first page
+type Page[T any] struct {
+	Results  []T    `json:"results"`
+	Count    int    `json:"count"` // Total number of results across all pages
+	Next     string `json:"next,omitempty"`
+	Previous string `json:"previous,omitempty"`
 }
 
+// ListInstallsResponse represents the API response for listing installs
+type ListInstallsResponse = Page[Install]
+
 // ListBackupsResponse represents the API response for listing backups
-type ListBackupsResponse struct {
-	Results []Backup `json:"results"`
-	Count   int      `json:"count"`
-}
+type ListBackupsResponse = Page[Backup]
 
 // CreateBackupRequest represents the request to create a backup
 type CreateBackupRequest struct {
-	Description string `json:"description"`
+	Description        string   `json:"description"`
+	NotificationEmails []string `json:"notification_emails,omitempty"`
 }
 
 // CreateBackupResponse represents the response from creating a backup
@@ -104,6 +121,113 @@ type CreateBackupResponse struct {
 	Status string `json:"status"`
 }
 
+// Account represents a WPEngine account
+type Account struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Site represents a WPEngine site, which groups the installs of each environment
+type Site struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Account struct {
+		ID string `json:"id"`
+	} `json:"account"`
+	Installs []SiteInstall `json:"installs"`
+}
+
+// SiteInstall is the install summary embedded in a site
+type SiteInstall struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Environment string `json:"environment"`
+	CName       string `json:"cname"`
+	PHPVersion  string `json:"php_version"`
+}
+
+// Domain represents a domain attached to an install
+type Domain struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Duplicate   bool             `json:"duplicate"`
+	Primary     bool             `json:"primary"`
+	RedirectsTo []DomainRedirect `json:"redirects_to,omitempty"`
+}
+
+// DomainRedirect is the target of a domain redirect
+type DomainRedirect struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AccountUser represents a user with access to an account
+type AccountUser struct {
+	UserID         string `json:"user_id"`
+	AccountID      string `json:"account_id"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	Email          string `json:"email"`
+	Phone          string `json:"phone,omitempty"`
+	InviteAccepted bool   `json:"invite_accepted"`
+	MFAEnabled     bool   `json:"mfa_enabled"`
+	Roles          string `json:"roles"`
+}
+
+// SSHKey represents an SSH key registered with the API user
+type SSHKey struct {
+	UUID        string    `json:"uuid"`
+	Comment     string    `json:"comment"`
+	Fingerprint string    `json:"fingerprint"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Cache types accepted by PurgeCache
+const (
+	CacheTypeObject = "object"
+	CacheTypePage   = "page"
+	CacheTypeCDN    = "cdn"
+)
+
+// CacheTypes lists every purgeable cache type
+var CacheTypes = []string{CacheTypeObject, CacheTypePage, CacheTypeCDN}
+
+// ListAccountsResponse represents the API response for listing accounts
+type ListAccountsResponse = Page[Account]
+
+// ListSitesResponse represents the API response for listing sites
+type ListSitesResponse = Page[Site]
+
+// ListDomainsResponse represents the API response for listing domains
+type ListDomainsResponse = Page[Domain]
+
+// ListAccountUsersResponse represents the API response for listing account users
+type ListAccountUsersResponse = Page[AccountUser]
+
+// ListSSHKeysResponse represents the API response for listing SSH keys
+type ListSSHKeysResponse = Page[SSHKey]
+
+// AddDomainRequest represents the request to add a domain
+type AddDomainRequest struct {
+	Name    string `json:"name"`
+	Primary bool   `json:"primary"`
+}
+
+// UpdateDomainRequest represents the request to update a domain
+type UpdateDomainRequest struct {
+	Primary bool `json:"primary"`
+}
+
+// AddSSHKeyRequest represents the request to add an SSH key
+type AddSSHKeyRequest struct {
+	PublicKey string `json:"public_key"`
+}
+
+// PurgeCacheRequest represents the request to purge a cache
+type PurgeCacheRequest struct {
+	Type string `json:"type"`
+}
+
 // ErrorResponse represents an API error response
 type ErrorResponse struct {
 	Error   string `json:"error"`
@@ -0,0 +1,124 @@
+package wpengine
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/security"
+)
+
+// DatabaseCredentials are the database settings from wp-config.php
+type DatabaseCredentials struct {
+	Host        string
+	Port        int
+	Socket      string // set when DB_HOST names a unix socket
+	Database    string
+	Username    string
+	Password    string
+	TablePrefix string
+}
+
+// Address returns the network and address to reach the database at from
+// the remote server
+func (d *DatabaseCredentials) Address() (string, string) {
+	if d.Socket != "" {
+		return "unix", d.Socket
+	}
+	host := d.Host
+	if host == "" || host == "localhost" {
+		// Forwarded connections use TCP, so "localhost" means loopback
+		host = "127.0.0.1"
+	}
+	return "tcp", fmt.Sprintf("%s:%d", host, d.Port)
+}
+
+var (
+	wpConfigDefine = regexp.MustCompile(`(?m)(?:^|;)\s*define\(\s*['"](DB_NAME|DB_USER|DB_PASSWORD|DB_HOST)['"]\s*,\s*(?:'((?:[^'\\]|\\.)*)'|"((?:[^"\\]|\\.)*)")\s*\)`)
+	wpConfigPrefix = regexp.MustCompile(`(?m)^\s*\$table_prefix\s*=\s*['"]([^'"]*)['"]`)
+)
+
+// ParseWPConfig reads the database settings from wp-config.php contents;
+// commented-out defines are ignored
+func ParseWPConfig(content string) (*DatabaseCredentials, error) {
+	creds := &DatabaseCredentials{Port: 3306, TablePrefix: "wp_"}
+	found := map[string]bool{}
+
+	for _, match := range wpConfigDefine.FindAllStringSubmatch(content, -1) {
+		name := match[1]
+		if found[name] {
+			// PHP ignores redefinitions
+			continue
+		}
+		found[name] = true
+
+		value := match[2]
+		if match[3] != "" {
+			value = match[3]
+		}
+		value = strings.NewReplacer(`\'`, `'`, `\"`, `"`, `\\`, `\`).Replace(value)
+
+		switch name {
+		case "DB_NAME":
+			creds.Database = value
+		case "DB_USER":
+			creds.Username = value
+		case "DB_PASSWORD":
+			creds.Password = value
+		case "DB_HOST":
+			if err := creds.setHost(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if match := wpConfigPrefix.FindStringSubmatch(content); match != nil {
+		creds.TablePrefix = match[1]
+	}
+
+	if creds.Database == "" || creds.Username == "" {
+		return nil, fmt.Errorf("wp-config.php does not define DB_NAME and DB_USER")
+	}
+	return creds, nil
+}
+
+// setHost parses DB_HOST: host, host:port or host:/path/to/socket
+func (d *DatabaseCredentials) setHost(value string) error {
+	host, rest, ok := strings.Cut(value, ":")
+	d.Host = host
+	if !ok {
+		return nil
+	}
+	if strings.HasPrefix(rest, "/") {
+		d.Socket = rest
+		return nil
+	}
+
+	port, err := strconv.Atoi(rest)
+	if err != nil || port <= 0 || port > 65535 {
+		return fmt.Errorf("invalid DB_HOST %q in wp-config.php", value)
+	}
+	d.Port = port
+	return nil
+}
+
+// GetDatabaseCredentials reads the database settings from the install's
+// wp-config.php
+func (c *SSHClient) GetDatabaseCredentials() (*DatabaseCredentials, error) {
+	install, err := security.SanitizeForShell(c.config.Install)
+	if err != nil {
+		return nil, fmt.Errorf("invalid install name: %w", err)
+	}
+
+	content, err := c.ExecuteCommand(fmt.Sprintf("cat /sites/%s/wp-config.php", install))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wp-config.php: %w", err)
+	}
+
+	creds, err := ParseWPConfig(content)
+	if err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+
+	"github.com/firecrown-media/stax/pkg/wpengine"
 )
 
 // MockSSHClient is a mock implementation of an SSH client
@@ -251,3 +254,79 @@ func (m *MockProgressReader) Read(p []byte) (int, error) {
 	}
 	return n, err
 }
+
+// MockWPEngineSSH is a mock of the WPEngine SSH client used by the WPEngine provider
+type MockWPEngineSSH struct {
+	DatabaseDump string
+	WPConfig     string
+	ExecuteFunc  func(command string) (string, error)
+	SyncFunc     func(destination string, options wpengine.SyncOptions) error
+	Commands     []string
+}
+
+// NewMockWPEngineSSH creates a mock SSH client that returns a small database dump
+func NewMockWPEngineSSH() *MockWPEngineSSH {
+	return &MockWPEngineSSH{
+		DatabaseDump: "-- MySQL dump\nCREATE TABLE wp_options (option_id int);\n",
+		WPConfig:     "<?php\ndefine( 'DB_NAME', 'wp_testinstall' );\ndefine( 'DB_USER', 'testinstall' );\ndefine( 'DB_PASSWORD', 'password' );\ndefine( 'DB_HOST', '127.0.0.1:3306' );\n",
+		ExecuteFunc: func(command string) (string, error) {
+			return "Command executed", nil
+		},
+		SyncFunc: func(destination string, options wpengine.SyncOptions) error {
+			return nil
+		},
+	}
+}
+
+// TestConnection mocks testing the SSH connection
+func (m *MockWPEngineSSH) TestConnection() error {
+	_, err := m.ExecuteCommand("echo 'test'")
+	return err
+}
+
+// ExecuteCommand mocks executing a remote command
+func (m *MockWPEngineSSH) ExecuteCommand(cmd string) (string, error) {
+	m.Commands = append(m.Commands, cmd)
+	if m.ExecuteFunc != nil {
+		return m.ExecuteFunc(cmd)
+	}
+	return "", fmt.Errorf("ExecuteFunc not implemented")
+}
+
+// ExecuteCommandWithOutput mocks executing a remote command with streamed output
+func (m *MockWPEngineSSH) ExecuteCommandWithOutput(cmd string, stdout, stderr io.Writer) error {
+	output, err := m.ExecuteCommand(cmd)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(stdout, output)
+	return err
+}
+
+// GetWPCLI mocks executing a WP-CLI command
+func (m *MockWPEngineSSH) GetWPCLI(args []string) (string, error) {
+	return m.ExecuteCommand("wp " + strings.Join(args, " "))
+}
+
+// GetDatabaseCredentials mocks reading wp-config.php
+func (m *MockWPEngineSSH) GetDatabaseCredentials() (*wpengine.DatabaseCredentials, error) {
+	return wpengine.ParseWPConfig(m.WPConfig)
+}
+
+// ExportDatabase mocks exporting the remote database
+func (m *MockWPEngineSSH) ExportDatabase(options wpengine.DatabaseOptions) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(m.DatabaseDump)), nil
+}
+
+// SyncWPContent mocks syncing wp-content
+func (m *MockWPEngineSSH) SyncWPContent(destination string, options wpengine.SyncOptions) error {
+	if m.SyncFunc != nil {
+		return m.SyncFunc(destination, options)
+	}
+	return fmt.Errorf("SyncFunc not implemented")
+}
+
+// Close mocks closing the connection
+func (m *MockWPEngineSSH) Close() error {
+	return nil
+}
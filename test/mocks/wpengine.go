@@ -182,3 +182,113 @@ func (m *MockWPEngineClient) WithEmptyResults() *MockWPEngineClient {
 	}
 	return m
 }
+
+// MockWPEngineAPI is a mock of the WPEngine API client used by the WPEngine provider
+type MockWPEngineAPI struct {
+	Installs           []wpengine.InstallDetails
+	Backups            map[string][]wpengine.Backup
+	Purges             []string // "<install>:<type>" in call order
+	TestConnectionFunc func() error
+}
+
+// NewMockWPEngineAPI creates a mock API with a production and staging install
+func NewMockWPEngineAPI() *MockWPEngineAPI {
+	production := wpengine.InstallDetails{
+		ID:               "inst-1",
+		Name:             "testinstall",
+		PrimaryDomain:    "testinstall.wpengine.com",
+		PHPVersion:       "8.1",
+		MySQLVersion:     "8.0",
+		WordPressVersion: "6.4.2",
+		Environment:      "production",
+		Domains:          []string{"testinstall.wpengine.com", "www.example.com"},
+	}
+	staging := wpengine.InstallDetails{
+		ID:               "inst-2",
+		Name:             "testinstallstg",
+		PrimaryDomain:    "testinstallstg.wpengine.com",
+		PHPVersion:       "8.2",
+		MySQLVersion:     "8.0",
+		WordPressVersion: "6.4.2",
+		Environment:      "staging",
+		Domains:          []string{"testinstallstg.wpengine.com"},
+	}
+
+	return &MockWPEngineAPI{
+		Installs: []wpengine.InstallDetails{production, staging},
+		Backups: map[string][]wpengine.Backup{
+			"inst-1": {
+				{ID: "backup-1", Type: "automatic", Size: 1024000, Status: "completed"},
+			},
+		},
+	}
+}
+
+// TestConnection mocks testing the API connection
+func (m *MockWPEngineAPI) TestConnection() error {
+	if m.TestConnectionFunc != nil {
+		return m.TestConnectionFunc()
+	}
+	return nil
+}
+
+// ListInstalls mocks listing installs
+func (m *MockWPEngineAPI) ListInstalls() ([]wpengine.Install, error) {
+	installs := make([]wpengine.Install, len(m.Installs))
+	for i, details := range m.Installs {
+		installs[i] = wpengine.Install{
+			ID:            details.ID,
+			Name:          details.Name,
+			PrimaryDomain: details.PrimaryDomain,
+			PHPVersion:    details.PHPVersion,
+			Environment:   details.Environment,
+		}
+	}
+	return installs, nil
+}
+
+// GetInstall mocks getting an install by ID
+func (m *MockWPEngineAPI) GetInstall(installID string) (*wpengine.InstallDetails, error) {
+	for i := range m.Installs {
+		if m.Installs[i].ID == installID {
+			details := m.Installs[i]
+			return &details, nil
+		}
+	}
+	return nil, fmt.Errorf("WPEngine API error (404): install %s not found", installID)
+}
+
+// GetInstallByName mocks getting an install by name
+func (m *MockWPEngineAPI) GetInstallByName(name string) (*wpengine.InstallDetails, error) {
+	for i := range m.Installs {
+		if m.Installs[i].Name == name {
+			details := m.Installs[i]
+			return &details, nil
+		}
+	}
+	return nil, fmt.Errorf("install %s not found", name)
+}
+
+// ListBackups mocks listing backups for an install
+func (m *MockWPEngineAPI) ListBackups(installID string) ([]wpengine.Backup, error) {
+	return m.Backups[installID], nil
+}
+
+// CreateBackup mocks creating a backup
+func (m *MockWPEngineAPI) CreateBackup(installID, description string) (string, error) {
+	if m.Backups == nil {
+		m.Backups = make(map[string][]wpengine.Backup)
+	}
+	id := fmt.Sprintf("backup-%d", len(m.Backups[installID])+1)
+	m.Backups[installID] = append(m.Backups[installID], wpengine.Backup{ID: id, Type: "manual", Status: "pending"})
+	return id, nil
+}
+
+// PurgeCache mocks purging an install cache
+func (m *MockWPEngineAPI) PurgeCache(installID, cacheType string) error {
+	if _, err := m.GetInstall(installID); err != nil {
+		return err
+	}
+	m.Purges = append(m.Purges, installID+":"+cacheType)
+	return nil
+}